@@ -2,7 +2,7 @@ package main
 
 import (
 	"context"
-	"fmt"
+	"flag"
 	"log/slog"
 	"net/http"
 	"os"
@@ -16,6 +16,9 @@ import (
 )
 
 func main() {
+	rollback := flag.Int("rollback", 0, "roll back the last N applied migrations and exit")
+	flag.Parse()
+
 	// Initialize structured JSON logging
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
 		Level: slog.LevelInfo,
@@ -26,6 +29,34 @@ func main() {
 
 	// Load configuration
 	cfg := config.Load()
+	if err := cfg.Server.Validate(); err != nil {
+		slog.Error("invalid server configuration", "error", err)
+		os.Exit(1)
+	}
+	if err := cfg.Database.Validate(); err != nil {
+		slog.Error("invalid database configuration", "error", err)
+		os.Exit(1)
+	}
+	if err := cfg.Storage.Validate(); err != nil {
+		slog.Error("invalid storage configuration", "error", err)
+		os.Exit(1)
+	}
+	if err := cfg.JWT.Validate(); err != nil {
+		slog.Error("invalid JWT configuration", "error", err)
+		os.Exit(1)
+	}
+	if err := cfg.ValidateSecrets(); err != nil {
+		slog.Error("insecure JWT secret in production", "error", err)
+		os.Exit(1)
+	}
+	if err := cfg.JWT.CheckSecret(); err != nil {
+		slog.Warn("insecure JWT secret; tokens can be forged until JWT_SECRET is replaced",
+			"error", err, "env", cfg.Environment)
+	}
+	if err := cfg.Scoring.Validate(); err != nil {
+		slog.Error("invalid scoring configuration", "error", err)
+		os.Exit(1)
+	}
 
 	// Create temp upload directory
 	if err := os.MkdirAll(cfg.Upload.TempDir, 0755); err != nil {
@@ -35,33 +66,70 @@ func main() {
 
 	// Connect to database with retry
 	ctx := context.Background()
-	var dbPool = connectWithRetry(ctx, cfg, 30)
+	dbPool, err := db.ConnectWithRetry(ctx, cfg.Database, 30, 2*time.Second)
+	if err != nil {
+		slog.Error("failed to connect to database after retries", "error", err)
+		os.Exit(1)
+	}
 	defer dbPool.Close()
 
+	// Roll back instead of serving when asked to
+	if *rollback > 0 {
+		versions, err := db.RollbackMigrations(ctx, dbPool, *rollback)
+		if err != nil {
+			slog.Error("failed to roll back migrations", "error", err, "rolled_back", versions)
+			os.Exit(1)
+		}
+		slog.Info("migrations rolled back", "versions", versions)
+		return
+	}
+
 	// Run migrations
 	if err := db.RunMigrations(ctx, dbPool); err != nil {
 		slog.Error("failed to run migrations", "error", err)
 		os.Exit(1)
 	}
 
+	// Watch database health so requests fail fast while it is unreachable
+	monitorCtx, stopMonitor := context.WithCancel(ctx)
+	defer stopMonitor()
+	dbMonitor := db.NewMonitor(dbPool, cfg.Database.HealthInterval)
+	go dbMonitor.Run(monitorCtx)
+
 	// Initialize router with all dependencies
-	router := api.NewRouter(dbPool, cfg)
-
-	// Create HTTP server
-	srv := &http.Server{
-		Addr:         fmt.Sprintf(":%s", cfg.Server.Port),
-		Handler:      router,
-		ReadTimeout:  cfg.Server.ReadTimeout,
-		WriteTimeout: cfg.Server.WriteTimeout,
+	router, pipeline, processor := api.NewRouter(dbPool, dbMonitor, cfg)
+
+	// Start claiming queued runs, including those created on other instances
+	pipeline.StartWorkers(cfg.Scoring.QueueWorkers, cfg.Scoring.QueuePoll)
+
+	// Recover runs orphaned by a previous crash before accepting new work
+	if _, err := pipeline.RecoverOrphanedRuns(ctx, cfg.Scoring.OrphanAge, cfg.Scoring.RequeueOrphans); err != nil {
+		slog.Error("failed to recover orphaned runs", "error", err)
+	}
+	// Keep recovering runs orphaned by instances that die while this one runs
+	pipeline.StartOrphanSweep(cfg.Scoring.OrphanSweep, cfg.Scoring.OrphanAge, cfg.Scoring.RequeueOrphans)
+
+	// Create HTTP server (HTTPS when a certificate is configured)
+	srv, err := api.NewServer(router, cfg.Server)
+	if err != nil {
+		slog.Error("failed to configure server", "error", err)
+		os.Exit(1)
 	}
 
 	// Start server in goroutine
 	go func() {
 		slog.Info("server listening",
 			"port", cfg.Server.Port,
+			"tls", srv.TLSConfig != nil,
 			"service", "site-selection-iq",
 		)
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		var err error
+		if srv.TLSConfig != nil {
+			err = srv.ListenAndServeTLS("", "") // certificate loaded by NewServer
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			slog.Error("server failed", "error", err)
 			os.Exit(1)
 		}
@@ -72,30 +140,16 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	slog.Info("shutting down server...")
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	// Drain HTTP, then in-flight scoring runs and upload processing; any
+	// still running at the deadline are marked failed so they can be
+	// retried after restart.
+	gracefulShutdown(srv, cfg.Server.ShutdownTimeout,
+		worker{"scoring runs", pipeline},
+		worker{"upload processing", processor},
+	)
 
-	if err := srv.Shutdown(shutdownCtx); err != nil {
-		slog.Error("server forced shutdown", "error", err)
-	}
+	// Only now is nothing left using the database
+	stopMonitor()
+	dbPool.Close()
 	slog.Info("server exited")
 }
-
-func connectWithRetry(ctx context.Context, cfg *config.Config, maxRetries int) *db.Pool {
-	for i := 0; i < maxRetries; i++ {
-		pool, err := db.Connect(ctx, cfg.Database)
-		if err == nil {
-			return pool
-		}
-		slog.Warn("database not ready, retrying...",
-			"attempt", i+1,
-			"max_retries", maxRetries,
-			"error", err,
-		)
-		time.Sleep(2 * time.Second)
-	}
-	slog.Error("failed to connect to database after retries")
-	os.Exit(1)
-	return nil
-}
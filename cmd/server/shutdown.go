@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// drainable is background work the server waits for on shutdown, such as
+// the scoring pipeline and the upload processor.
+type drainable interface {
+	InFlight() int
+	Shutdown(ctx context.Context) error
+}
+
+// worker names a drainable for shutdown logs, e.g. "scoring runs".
+type worker struct {
+	name string
+	drainable
+}
+
+// httpServer is the part of *http.Server used to stop accepting requests.
+type httpServer interface {
+	Shutdown(ctx context.Context) error
+}
+
+// gracefulShutdown stops srv, then waits for each worker in turn, all
+// within a single timeout budget. Workers still busy at the deadline mark
+// their work failed so it can be retried after restart; they need the
+// database for that, so the caller must only close the pool after this
+// returns.
+func gracefulShutdown(srv httpServer, timeout time.Duration, workers ...worker) {
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	slog.Info("shutting down server...", "timeout", timeout)
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("server forced shutdown", "error", err)
+	}
+
+	for _, w := range workers {
+		slog.Info("waiting for in-flight "+w.name, "count", w.InFlight())
+		if err := w.Shutdown(shutdownCtx); err != nil {
+			slog.Error(w.name+" interrupted by shutdown", "error", err)
+		}
+	}
+}
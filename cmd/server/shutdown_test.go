@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDrainer records the deadline it was given and can block until it.
+type fakeDrainer struct {
+	block    bool
+	deadline time.Time
+	called   bool
+}
+
+func (f *fakeDrainer) InFlight() int { return 1 }
+
+func (f *fakeDrainer) Shutdown(ctx context.Context) error {
+	f.called = true
+	f.deadline, _ = ctx.Deadline()
+	if f.block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return nil
+}
+
+func TestGracefulShutdown_HonorsTimeout(t *testing.T) {
+	srv := &fakeDrainer{}
+	runs := &fakeDrainer{block: true}
+	uploads := &fakeDrainer{}
+	timeout := 150 * time.Millisecond
+
+	start := time.Now()
+	gracefulShutdown(srv, timeout, worker{"scoring runs", runs}, worker{"upload processing", uploads})
+	elapsed := time.Since(start)
+
+	// A worker that never drains is cut off at the configured timeout
+	assert.GreaterOrEqual(t, elapsed, timeout)
+	assert.Less(t, elapsed, timeout+time.Second)
+
+	// Every step shares the one deadline, and later workers still get called
+	require.True(t, uploads.called)
+	assert.WithinDuration(t, start.Add(timeout), srv.deadline, 50*time.Millisecond)
+	assert.Equal(t, srv.deadline, runs.deadline)
+	assert.Equal(t, srv.deadline, uploads.deadline)
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/workforce-ai/site-selection-iq/internal/api/response"
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+	"github.com/workforce-ai/site-selection-iq/internal/repository"
+)
+
+// auditRecorder persists audit entries; *repository.AuditRepository
+// satisfies it.
+type auditRecorder interface {
+	Record(ctx context.Context, entry *models.AuditEntry) error
+}
+
+// recordAudit writes an audit entry for a mutating action that has already
+// succeeded, taking the tenant, user and correlation ID from the request
+// context. A failed write is logged rather than failing the request, since
+// the action itself cannot be undone at this point.
+func recordAudit(c *gin.Context, recorder auditRecorder, action string, resourceID uuid.UUID) {
+	tenantID, _ := c.Get("tenant_id")
+	userID, _ := c.Get("user_id")
+	correlationID, _ := c.Get("correlation_id")
+
+	entry := &models.AuditEntry{
+		Action:     action,
+		ResourceID: &resourceID,
+	}
+	entry.TenantID, _ = tenantID.(uuid.UUID)
+	entry.UserID, _ = userID.(uuid.UUID)
+	entry.CorrelationID, _ = correlationID.(string)
+
+	if err := recorder.Record(c.Request.Context(), entry); err != nil {
+		slog.Error("failed to record audit entry",
+			slog.String("action", action),
+			slog.String("resource_id", resourceID.String()),
+			slog.String("correlation_id", entry.CorrelationID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// AuditHandler handles audit log endpoints.
+type AuditHandler struct {
+	auditRepo *repository.AuditRepository
+}
+
+// NewAuditHandler creates a new audit handler.
+func NewAuditHandler(auditRepo *repository.AuditRepository) *AuditHandler {
+	return &AuditHandler{auditRepo: auditRepo}
+}
+
+// HandleListAudit handles GET /api/v1/audit.
+// It returns the tenant's audit history, newest first.
+func (h *AuditHandler) HandleListAudit(c *gin.Context) {
+	tenantID := c.MustGet("tenant_id").(uuid.UUID)
+
+	page, pageSize, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	entries, totalCount, err := h.auditRepo.ListByTenant(c.Request.Context(), tenantID, page, pageSize)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to retrieve audit log: %v", err))
+		return
+	}
+
+	totalPages := (totalCount + pageSize - 1) / pageSize
+
+	response.Success(c, http.StatusOK, gin.H{
+		"entries": entries,
+		"pagination": models.Pagination{
+			Page:         page,
+			PageSize:     pageSize,
+			TotalResults: totalCount,
+			TotalPages:   totalPages,
+		},
+	})
+}
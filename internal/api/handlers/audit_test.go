@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+)
+
+type fakeAuditRecorder struct {
+	entries []models.AuditEntry
+	err     error
+}
+
+func (f *fakeAuditRecorder) Record(_ context.Context, entry *models.AuditEntry) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.entries = append(f.entries, *entry)
+	return nil
+}
+
+// auditContext mimics the context AuthMiddleware and CorrelationMiddleware
+// leave behind for a handler.
+func auditContext(tenantID, userID uuid.UUID) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/uploads", nil)
+	c.Set("tenant_id", tenantID)
+	c.Set("user_id", userID)
+	c.Set("correlation_id", "corr-123")
+	return c, w
+}
+
+func TestRecordAudit_UploadCreateEntry(t *testing.T) {
+	tenantID, userID, uploadID := uuid.New(), uuid.New(), uuid.New()
+	c, _ := auditContext(tenantID, userID)
+	recorder := &fakeAuditRecorder{}
+
+	recordAudit(c, recorder, models.AuditActionUploadCreate, uploadID)
+
+	require.Len(t, recorder.entries, 1)
+	entry := recorder.entries[0]
+	assert.Equal(t, tenantID, entry.TenantID)
+	assert.Equal(t, userID, entry.UserID)
+	assert.Equal(t, models.AuditActionUploadCreate, entry.Action)
+	require.NotNil(t, entry.ResourceID)
+	assert.Equal(t, uploadID, *entry.ResourceID)
+	assert.Equal(t, "corr-123", entry.CorrelationID)
+}
+
+func TestRecordAudit_FailureDoesNotFailRequest(t *testing.T) {
+	c, w := auditContext(uuid.New(), uuid.New())
+
+	recordAudit(c, &fakeAuditRecorder{err: errors.New("db down")}, models.AuditActionRunCreate, uuid.New())
+
+	assert.False(t, c.IsAborted())
+	assert.Empty(t, c.Errors)
+	assert.Equal(t, http.StatusOK, w.Code, "nothing written to the response")
+}
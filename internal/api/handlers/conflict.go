@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/workforce-ai/site-selection-iq/internal/api/response"
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+)
+
+// conflictPollInterval is how long clients are told to wait before polling
+// a resource that an idempotent retry found still being processed.
+const conflictPollInterval = 2 * time.Second
+
+// conflictDetails describes the existing resource behind an idempotency
+// conflict, so clients can tell "still processing, poll again" apart from
+// a finished duplicate.
+func conflictDetails(status string, inProgress bool) gin.H {
+	details := gin.H{
+		"resource_status": status,
+		"in_progress":     inProgress,
+	}
+	if inProgress {
+		details["poll_interval_seconds"] = int(conflictPollInterval / time.Second)
+	}
+	return details
+}
+
+// runConflictDetails reports a run as in progress while queued or running.
+// A nil run means the key was claimed but the run is not yet recorded.
+func runConflictDetails(run *models.ScoringRun) gin.H {
+	if run == nil {
+		return conflictDetails("queued", true)
+	}
+	return conflictDetails(run.Status, run.Status == "queued" || run.Status == "running")
+}
+
+// uploadConflictDetails reports an upload as in progress while pending.
+// A nil upload means the key was claimed but the upload is not yet recorded.
+func uploadConflictDetails(upload *models.Upload) gin.H {
+	if upload == nil {
+		return conflictDetails("pending", true)
+	}
+	return conflictDetails(upload.Status, upload.Status == "pending")
+}
+
+// respondConflict sends the 409 for an idempotency key match, adding a
+// Retry-After header when the existing resource is still in progress.
+func respondConflict(c *gin.Context, message string, existing interface{}, details gin.H) {
+	if inProgress, _ := details["in_progress"].(bool); inProgress {
+		c.Header("Retry-After", strconv.Itoa(int(conflictPollInterval/time.Second)))
+	}
+	response.Conflict(c, message, existing, details)
+}
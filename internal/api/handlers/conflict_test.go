@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+)
+
+func TestRunConflictDetails(t *testing.T) {
+	testCases := []struct {
+		status     string
+		inProgress bool
+	}{
+		{"queued", true},
+		{"running", true},
+		{"succeeded", false},
+		{"failed", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.status, func(t *testing.T) {
+			details := runConflictDetails(&models.ScoringRun{ID: uuid.New(), Status: tc.status})
+
+			assert.Equal(t, tc.status, details["resource_status"])
+			assert.Equal(t, tc.inProgress, details["in_progress"])
+			if tc.inProgress {
+				assert.Equal(t, 2, details["poll_interval_seconds"])
+			} else {
+				assert.NotContains(t, details, "poll_interval_seconds")
+			}
+		})
+	}
+
+	assert.Equal(t, true, runConflictDetails(nil)["in_progress"], "claimed key without a stored run yet")
+}
+
+func TestUploadConflictDetails(t *testing.T) {
+	assert.Equal(t, gin.H{"resource_status": "pending", "in_progress": true, "poll_interval_seconds": 2},
+		uploadConflictDetails(&models.Upload{Status: "pending"}))
+	assert.Equal(t, gin.H{"resource_status": "completed", "in_progress": false},
+		uploadConflictDetails(&models.Upload{Status: "completed"}))
+	assert.Equal(t, true, uploadConflictDetails(nil)["in_progress"])
+}
+
+func TestRespondConflict_BodyIncludesExistingStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	serve := func(run *models.ScoringRun) *httptest.ResponseRecorder {
+		r := gin.New()
+		r.POST("/runs", func(c *gin.Context) {
+			respondConflict(c, "duplicate scoring run (idempotency key match)", run, runConflictDetails(run))
+		})
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/runs", nil))
+		return w
+	}
+
+	running := &models.ScoringRun{ID: uuid.New(), Status: "running"}
+	w := serve(running)
+
+	require.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
+	var body struct {
+		Data struct {
+			RunID  uuid.UUID `json:"run_id"`
+			Status string    `json:"status"`
+		} `json:"data"`
+		Error struct {
+			Code    string                 `json:"code"`
+			Details map[string]interface{} `json:"details"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, running.ID, body.Data.RunID)
+	assert.Equal(t, "running", body.Data.Status)
+	assert.Equal(t, "DUPLICATE", body.Error.Code)
+	assert.Equal(t, "running", body.Error.Details["resource_status"])
+	assert.Equal(t, true, body.Error.Details["in_progress"])
+
+	w = serve(&models.ScoringRun{ID: uuid.New(), Status: "succeeded"})
+	require.Equal(t, http.StatusConflict, w.Code)
+	assert.Empty(t, w.Header().Get("Retry-After"), "finished duplicates need no retry hint")
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+)
+
+// runETag returns a strong ETag for a run's results, derived from its ID and
+// completion time. Results only stop changing once a run reaches a terminal
+// status, so runs that are still queued or running (or lack a completion
+// time) get no ETag.
+func runETag(run *models.ScoringRun) string {
+	switch run.Status {
+	case "succeeded", "completed_with_errors", "completed", "failed":
+	default:
+		return ""
+	}
+	if run.CompletedAt == nil {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(run.ID.String() + "|" + run.CompletedAt.UTC().Format(time.RFC3339Nano)))
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// notModified sets the ETag header and, when the request's If-None-Match
+// matches it, responds 304 Not Modified and returns true. An empty etag is
+// a no-op.
+func notModified(c *gin.Context, etag string) bool {
+	if etag == "" {
+		return false
+	}
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, no-cache")
+
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		c.Abort()
+		return true
+	}
+	return false
+}
+
+// etagMatches reports whether an If-None-Match header value matches etag,
+// using the weak comparison RFC 9110 requires for If-None-Match.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
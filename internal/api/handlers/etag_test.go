@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+)
+
+func TestRunETag(t *testing.T) {
+	completedAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	run := &models.ScoringRun{ID: uuid.New(), Status: "succeeded", CompletedAt: &completedAt}
+
+	etag := runETag(run)
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+	assert.Equal(t, etag, runETag(run), "stable for the same run")
+
+	later := completedAt.Add(time.Second)
+	assert.NotEqual(t, etag, runETag(&models.ScoringRun{ID: run.ID, Status: "succeeded", CompletedAt: &later}))
+
+	for _, status := range []string{"queued", "running"} {
+		assert.Empty(t, runETag(&models.ScoringRun{ID: run.ID, Status: status, CompletedAt: &completedAt}), status)
+	}
+	assert.Empty(t, runETag(&models.ScoringRun{ID: run.ID, Status: "succeeded"}), "no completion time")
+}
+
+func TestNotModified(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const etag = `"abc123"`
+
+	serve := func(etag, ifNoneMatch string) *httptest.ResponseRecorder {
+		r := gin.New()
+		r.GET("/results", func(c *gin.Context) {
+			if notModified(c, etag) {
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{"results": []int{1, 2, 3}})
+		})
+		req := httptest.NewRequest(http.MethodGet, "/results", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	testCases := []struct {
+		name        string
+		ifNoneMatch string
+		wantStatus  int
+	}{
+		{"no conditional header", "", http.StatusOK},
+		{"matching etag", etag, http.StatusNotModified},
+		{"weak match", `W/"abc123"`, http.StatusNotModified},
+		{"match in list", `"other", "abc123"`, http.StatusNotModified},
+		{"wildcard", "*", http.StatusNotModified},
+		{"stale etag", `"old"`, http.StatusOK},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := serve(etag, tc.ifNoneMatch)
+			require.Equal(t, tc.wantStatus, w.Code)
+			assert.Equal(t, etag, w.Header().Get("ETag"))
+			if tc.wantStatus == http.StatusNotModified {
+				assert.Empty(t, w.Body.String())
+			}
+		})
+	}
+
+	t.Run("no etag for running runs", func(t *testing.T) {
+		w := serve("", "*")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("ETag"))
+	})
+}
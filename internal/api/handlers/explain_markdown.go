@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
+)
+
+// renderExplanationMarkdown formats a recommendation's explanation as a
+// Markdown document: the score, the summary, a factor table and, for
+// category-weighted runs, a category table. It only reformats the stored
+// explanation; nothing is recomputed.
+func renderExplanationMarkdown(run *models.ScoringRun, rec *models.Recommendation, explanation models.Explanation, scale schema.ScoreScale) string {
+	var b strings.Builder
+
+	title := rec.SiteID
+	if rec.SiteName != "" && rec.SiteName != rec.SiteID {
+		title = fmt.Sprintf("%s (%s)", rec.SiteName, rec.SiteID)
+	}
+	fmt.Fprintf(&b, "# Site explanation: %s\n\n", markdownText(title))
+
+	fmt.Fprintf(&b, "- **Score:** %s / %s\n", formatNumber(rec.FinalScore), formatNumber(scale.Max()))
+	if rec.Ranking > 0 {
+		fmt.Fprintf(&b, "- **Rank:** %d\n", rec.Ranking)
+	}
+	fmt.Fprintf(&b, "- **Coverage:** %.0f%% of weighted factors\n", explanation.Coverage*100)
+	fmt.Fprintf(&b, "- **Model version:** %s\n", markdownText(run.ModelVersion))
+	fmt.Fprintf(&b, "- **Run:** %s\n", run.ID)
+	if run.CompletedAt != nil {
+		fmt.Fprintf(&b, "- **Scored at:** %s\n", run.CompletedAt.UTC().Format("2006-01-02 15:04 UTC"))
+	}
+
+	if explanation.Summary != "" {
+		fmt.Fprintf(&b, "\n## Summary\n\n%s\n", markdownText(explanation.Summary))
+	}
+
+	if len(explanation.Categories) > 0 {
+		b.WriteString("\n## Categories\n\n")
+		b.WriteString("| Category | Weight | Score | Contribution |\n")
+		b.WriteString("|---|---:|---:|---:|\n")
+		for _, cs := range explanation.Categories {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n",
+				markdownCell(cs.Name), formatNumber(cs.Weight), formatNumber(cs.Score), formatNumber(cs.Contribution))
+		}
+	}
+
+	b.WriteString("\n## Factors\n\n")
+	if len(explanation.Factors) == 0 {
+		b.WriteString("No factors contributed to this score.\n")
+		return b.String()
+	}
+	b.WriteString("| Factor | Value | Weight | Contribution | Direction | Reason |\n")
+	b.WriteString("|---|---:|---:|---:|---|---|\n")
+	for _, f := range explanation.Factors {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n",
+			markdownCell(f.Name), formatNumber(f.Value), formatNumber(f.Weight), formatNumber(f.Contribution),
+			markdownCell(f.Direction), markdownCell(f.Reason))
+	}
+	return b.String()
+}
+
+// formatNumber prints a float without trailing zeros.
+func formatNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// markdownEscaper escapes characters that would start Markdown formatting
+// or HTML. Underscores are left alone: GFM does not treat intraword ones
+// (as in field names like median_income) as emphasis.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "`", "\\`", "[", `\[`, "]", `\]`, "<", "&lt;", ">", "&gt;",
+)
+
+// markdownText escapes free text taken from the data (site names, summaries).
+func markdownText(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// markdownCell escapes text for a table cell, where pipes and line breaks
+// would also end the cell or row.
+func markdownCell(s string) string {
+	return strings.NewReplacer("\r\n", " ", "\n", " ", "|", `\|`).Replace(markdownText(s))
+}
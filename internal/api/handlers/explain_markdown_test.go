@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
+)
+
+func TestRenderExplanationMarkdown(t *testing.T) {
+	completed := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	run := &models.ScoringRun{ID: uuid.New(), ModelVersion: "site-selection-iq-v1.0", CompletedAt: &completed}
+	rec := &models.Recommendation{SiteID: "SITE-1", SiteName: "Austin, TX", Ranking: 2, FinalScore: 87.5}
+	explanation := models.Explanation{
+		Factors: []models.ExplanationFactor{
+			{Name: "population", Value: 800000, Weight: 1.5, Contribution: 1.2, Direction: "maximize", Reason: "High population"},
+			{Name: "median_income", Value: 52000, Weight: 1, Contribution: 0.55, Direction: "maximize", Reason: "Income | moderate"},
+		},
+		Summary:  "Final score is 87.5. Driven by population.",
+		Coverage: 1,
+	}
+
+	doc := renderExplanationMarkdown(run, rec, explanation, schema.ScaleHundred)
+
+	assert.True(t, strings.HasPrefix(doc, "# Site explanation: Austin, TX (SITE-1)\n"))
+	assert.Contains(t, doc, "- **Score:** 87.5 / 100\n")
+	assert.Contains(t, doc, "- **Rank:** 2\n")
+	assert.Contains(t, doc, "- **Coverage:** 100% of weighted factors\n")
+	assert.Contains(t, doc, "- **Scored at:** 2024-03-01 12:30 UTC\n")
+	assert.Contains(t, doc, "## Summary\n\nFinal score is 87.5. Driven by population.\n")
+	assert.Contains(t, doc, "| population | 800000 | 1.5 | 1.2 | maximize | High population |\n")
+	assert.Contains(t, doc, `| median_income | 52000 | 1 | 0.55 | maximize | Income \| moderate |`+"\n", "pipes in cells are escaped")
+	assert.NotContains(t, doc, "## Categories")
+}
+
+func TestRenderExplanationMarkdown_CategoriesAndScale(t *testing.T) {
+	run := &models.ScoringRun{ID: uuid.New(), ModelVersion: "v1"}
+	rec := &models.Recommendation{SiteID: "S1", SiteName: "S1", FinalScore: 0.63}
+	explanation := models.Explanation{
+		Categories: []models.CategoryScore{{Name: "Economic", Weight: 2, Score: 0.5, Contribution: 1}},
+		Summary:    "Weak *overall*",
+	}
+
+	doc := renderExplanationMarkdown(run, rec, explanation, schema.ScaleUnit)
+
+	assert.True(t, strings.HasPrefix(doc, "# Site explanation: S1\n"))
+	assert.Contains(t, doc, "- **Score:** 0.63 / 1\n")
+	assert.NotContains(t, doc, "Rank")
+	assert.Contains(t, doc, "| Economic | 2 | 0.5 | 1 |\n")
+	assert.Contains(t, doc, `Weak \*overall\*`)
+	assert.Contains(t, doc, "No factors contributed to this score.\n")
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/workforce-ai/site-selection-iq/internal/db"
+)
+
+// HealthHandler handles the readiness probe.
+type HealthHandler struct {
+	monitor *db.Monitor
+}
+
+// NewHealthHandler creates a new health handler.
+func NewHealthHandler(monitor *db.Monitor) *HealthHandler {
+	return &HealthHandler{monitor: monitor}
+}
+
+// HandleReady handles GET /ready.
+// It reports 200 while the database is reachable and 503 once the monitor
+// has marked it unreachable, with connection pool statistics either way.
+func (h *HealthHandler) HandleReady(c *gin.Context) {
+	report := h.monitor.Report()
+
+	status, code := "ready", http.StatusOK
+	if !report.Healthy {
+		status, code = "unavailable", http.StatusServiceUnavailable
+	}
+
+	c.JSON(code, gin.H{
+		"status":   status,
+		"service":  "site-selection-iq",
+		"database": report,
+	})
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/workforce-ai/site-selection-iq/internal/api/response"
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+)
+
+// parsePagination reads the page and page_size query parameters, defaulting
+// to page 1 of models.DefaultPageSize. page must be at least 1 and
+// page_size between 1 and models.MaxPageSize; a malformed or out-of-range
+// value is answered with 400 and ok is false.
+func parsePagination(c *gin.Context) (page, pageSize int, ok bool) {
+	page, pageSize = 1, models.DefaultPageSize
+
+	if pageParam := c.Query("page"); pageParam != "" {
+		parsed, err := strconv.Atoi(pageParam)
+		if err != nil || parsed < 1 {
+			response.BadRequest(c, "page must be a positive integer", nil)
+			return 0, 0, false
+		}
+		page = parsed
+	}
+
+	if pageSizeParam := c.Query("page_size"); pageSizeParam != "" {
+		parsed, err := strconv.Atoi(pageSizeParam)
+		if err != nil || parsed < 1 || parsed > models.MaxPageSize {
+			response.BadRequest(c, fmt.Sprintf("page_size must be an integer between 1 and %d", models.MaxPageSize), nil)
+			return 0, 0, false
+		}
+		pageSize = parsed
+	}
+
+	return page, pageSize, true
+}
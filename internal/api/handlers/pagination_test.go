@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+)
+
+func paginationContext(query string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/audit?"+query, nil)
+	return c, w
+}
+
+func TestParsePagination_Defaults(t *testing.T) {
+	c, _ := paginationContext("")
+
+	page, pageSize, ok := parsePagination(c)
+
+	assert.True(t, ok)
+	assert.Equal(t, 1, page)
+	assert.Equal(t, models.DefaultPageSize, pageSize)
+}
+
+func TestParsePagination_ValidValues(t *testing.T) {
+	c, _ := paginationContext("page=3&page_size=100")
+
+	page, pageSize, ok := parsePagination(c)
+
+	assert.True(t, ok)
+	assert.Equal(t, 3, page)
+	assert.Equal(t, 100, pageSize)
+}
+
+func TestParsePagination_InvalidValuesReturn400(t *testing.T) {
+	for _, query := range []string{
+		"page=0",
+		"page=-1",
+		"page=abc",
+		"page=2x",
+		"page=1.5",
+		"page_size=0",
+		"page_size=101",
+		"page_size=ten",
+	} {
+		t.Run(query, func(t *testing.T) {
+			c, w := paginationContext(query)
+
+			_, _, ok := parsePagination(c)
+
+			assert.False(t, ok)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), "VALIDATION_ERROR")
+		})
+	}
+}
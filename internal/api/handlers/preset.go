@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/workforce-ai/site-selection-iq/internal/api/response"
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+	"github.com/workforce-ai/site-selection-iq/internal/repository"
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
+)
+
+// presetNamePattern restricts preset names to URL-safe slugs such as
+// "cost-focused".
+var presetNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,63}$`)
+
+// WeightPresetHandler handles tenant weight preset endpoints.
+type WeightPresetHandler struct {
+	presetRepo       *repository.WeightPresetRepository
+	schemaConfigRepo *repository.SchemaConfigRepository
+	schemaResolver   *schema.Resolver
+	auditRepo        *repository.AuditRepository
+}
+
+// NewWeightPresetHandler creates a new weight preset handler.
+func NewWeightPresetHandler(
+	presetRepo *repository.WeightPresetRepository,
+	schemaConfigRepo *repository.SchemaConfigRepository,
+	schemaResolver *schema.Resolver,
+	auditRepo *repository.AuditRepository,
+) *WeightPresetHandler {
+	return &WeightPresetHandler{
+		presetRepo:       presetRepo,
+		schemaConfigRepo: schemaConfigRepo,
+		schemaResolver:   schemaResolver,
+		auditRepo:        auditRepo,
+	}
+}
+
+// presetRequest is the body for creating or replacing a preset. Name is
+// taken from the URL on update.
+type presetRequest struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Weights     map[string]float64 `json:"weights"`
+}
+
+// HandleListPresets handles GET /api/v1/weight-presets.
+func (h *WeightPresetHandler) HandleListPresets(c *gin.Context) {
+	tenantID := c.MustGet("tenant_id").(uuid.UUID)
+
+	presets, err := h.presetRepo.ListByTenant(c.Request.Context(), tenantID)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to list weight presets: %v", err))
+		return
+	}
+
+	response.Success(c, http.StatusOK, gin.H{"presets": presets})
+}
+
+// HandleGetPreset handles GET /api/v1/weight-presets/:name.
+func (h *WeightPresetHandler) HandleGetPreset(c *gin.Context) {
+	tenantID := c.MustGet("tenant_id").(uuid.UUID)
+
+	preset, err := h.presetRepo.GetByName(c.Request.Context(), tenantID, c.Param("name"))
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to retrieve weight preset: %v", err))
+		return
+	}
+	if preset == nil {
+		response.NotFound(c, "weight preset not found")
+		return
+	}
+
+	response.Success(c, http.StatusOK, preset)
+}
+
+// HandleCreatePreset handles POST /api/v1/weight-presets.
+// Every weight must name a numeric field of the tenant's resolved schema.
+func (h *WeightPresetHandler) HandleCreatePreset(c *gin.Context) {
+	tenantID := c.MustGet("tenant_id").(uuid.UUID)
+
+	var req presetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", nil)
+		return
+	}
+	if !presetNamePattern.MatchString(req.Name) {
+		response.BadRequest(c, "name must be 1-64 letters, digits, '.', '_' or '-', starting with a letter or digit",
+			gin.H{"field": "name"})
+		return
+	}
+	if !h.validateWeights(c, tenantID, req.Weights) {
+		return
+	}
+
+	preset := &models.WeightPreset{
+		TenantID:    tenantID,
+		Name:        req.Name,
+		Description: req.Description,
+		Weights:     req.Weights,
+	}
+	created, err := h.presetRepo.Create(c.Request.Context(), preset)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to save weight preset: %v", err))
+		return
+	}
+	if !created {
+		existing, _ := h.presetRepo.GetByName(c.Request.Context(), tenantID, req.Name)
+		response.Conflict(c, "a weight preset with this name already exists", existing, nil)
+		return
+	}
+
+	recordAudit(c, h.auditRepo, models.AuditActionPresetCreate, preset.ID)
+	response.Success(c, http.StatusCreated, preset)
+}
+
+// HandleUpdatePreset handles PUT /api/v1/weight-presets/:name.
+// The preset's description and weights are replaced.
+func (h *WeightPresetHandler) HandleUpdatePreset(c *gin.Context) {
+	tenantID := c.MustGet("tenant_id").(uuid.UUID)
+
+	var req presetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", nil)
+		return
+	}
+	if !h.validateWeights(c, tenantID, req.Weights) {
+		return
+	}
+
+	preset := &models.WeightPreset{
+		TenantID:    tenantID,
+		Name:        c.Param("name"),
+		Description: req.Description,
+		Weights:     req.Weights,
+	}
+	found, err := h.presetRepo.Update(c.Request.Context(), preset)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to save weight preset: %v", err))
+		return
+	}
+	if !found {
+		response.NotFound(c, "weight preset not found")
+		return
+	}
+
+	recordAudit(c, h.auditRepo, models.AuditActionPresetUpdate, preset.ID)
+	response.Success(c, http.StatusOK, preset)
+}
+
+// HandleDeletePreset handles DELETE /api/v1/weight-presets/:name.
+// Runs already created from the preset keep the weights they were given.
+func (h *WeightPresetHandler) HandleDeletePreset(c *gin.Context) {
+	tenantID := c.MustGet("tenant_id").(uuid.UUID)
+	name := c.Param("name")
+
+	id, err := h.presetRepo.Delete(c.Request.Context(), tenantID, name)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to delete weight preset: %v", err))
+		return
+	}
+	if id == nil {
+		response.NotFound(c, "weight preset not found")
+		return
+	}
+
+	recordAudit(c, h.auditRepo, models.AuditActionPresetDelete, *id)
+	response.Success(c, http.StatusOK, gin.H{"name": name, "deleted": true})
+}
+
+// validateWeights checks weights against the tenant's resolved schema,
+// sending a 400 or 500 and returning false when they are not acceptable.
+func (h *WeightPresetHandler) validateWeights(c *gin.Context, tenantID uuid.UUID, weights map[string]float64) bool {
+	if len(weights) == 0 {
+		response.BadRequest(c, "weights must name at least one field", gin.H{"field": "weights"})
+		return false
+	}
+
+	resolved, err := h.resolveTenantSchema(c.Request.Context(), tenantID)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to resolve schema: %v", err))
+		return false
+	}
+	if err := resolved.ValidateWeights(weights); err != nil {
+		response.BadRequest(c, fmt.Sprintf("invalid weights: %v", err), configErrorDetails(err))
+		return false
+	}
+	return true
+}
+
+// resolveTenantSchema resolves the active global schema with the tenant's
+// active override.
+func (h *WeightPresetHandler) resolveTenantSchema(ctx context.Context, tenantID uuid.UUID) (*schema.ResolvedSchema, error) {
+	resolution, err := h.schemaResolver.ResolveActive(ctx, h.schemaConfigRepo, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return resolution.Schema, nil
+}
@@ -1,15 +1,19 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/workforce-ai/site-selection-iq/internal/api/response"
 	"github.com/workforce-ai/site-selection-iq/internal/models"
 	"github.com/workforce-ai/site-selection-iq/internal/repository"
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
 )
 
 // RecommendationHandler handles recommendation and explanation endpoints.
@@ -44,22 +48,9 @@ func (h *RecommendationHandler) HandleGetRecommendations(c *gin.Context) {
 		return
 	}
 
-	// Parse pagination params
-	page := 1
-	pageSize := 20
-
-	if pageParam := c.Query("page"); pageParam != "" {
-		var p int
-		if _, err := fmt.Sscanf(pageParam, "%d", &p); err == nil && p > 0 {
-			page = p
-		}
-	}
-
-	if pageSizeParam := c.Query("page_size"); pageSizeParam != "" {
-		var ps int
-		if _, err := fmt.Sscanf(pageSizeParam, "%d", &ps); err == nil && ps > 0 && ps <= 100 {
-			pageSize = ps
-		}
+	page, pageSize, ok := parsePagination(c)
+	if !ok {
+		return
 	}
 
 	// Parse optional min_score filter
@@ -71,6 +62,13 @@ func (h *RecommendationHandler) HandleGetRecommendations(c *gin.Context) {
 		}
 	}
 
+	// Parse optional filter on the sites' uploaded data fields
+	filters, err := repository.ParseSiteDataFilters(c.Query("filter"))
+	if err != nil {
+		response.BadRequest(c, fmt.Sprintf("invalid filter: %v", err), nil)
+		return
+	}
+
 	// Verify run exists and belongs to tenant
 	run, err := h.runRepo.GetByID(c.Request.Context(), tenantID, runID)
 	if err != nil {
@@ -82,6 +80,43 @@ func (h *RecommendationHandler) HandleGetRecommendations(c *gin.Context) {
 		return
 	}
 
+	// Completed runs' results never change, so honour If-None-Match
+	if notModified(c, runETag(run)) {
+		return
+	}
+
+	// Cursor (keyset) paging: ?cursor= starts from the top, and each
+	// response's next_cursor fetches the following page
+	if cursorParam, ok := c.GetQuery("cursor"); ok {
+		var after *repository.RecommendationCursor
+		if cursorParam != "" {
+			after, err = repository.DecodeRecommendationCursor(cursorParam)
+			if err != nil {
+				response.BadRequest(c, "invalid cursor", nil)
+				return
+			}
+		}
+
+		recommendations, next, err := h.recommendationRepo.GetByRunCursor(c.Request.Context(), runID, after, pageSize, minScore, filters)
+		if err != nil {
+			response.InternalError(c, fmt.Sprintf("failed to retrieve recommendations: %v", err))
+			return
+		}
+
+		var nextCursor *string
+		if next != nil {
+			token := next.Encode()
+			nextCursor = &token
+		}
+
+		response.Success(c, http.StatusOK, gin.H{
+			"run_id":          runID,
+			"recommendations": recommendationResponses(recommendations),
+			"next_cursor":     nextCursor,
+		})
+		return
+	}
+
 	// Get paginated recommendations
 	recommendations, totalCount, err := h.recommendationRepo.GetByRun(
 		c.Request.Context(),
@@ -89,6 +124,7 @@ func (h *RecommendationHandler) HandleGetRecommendations(c *gin.Context) {
 		page,
 		pageSize,
 		minScore,
+		filters,
 	)
 	if err != nil {
 		response.InternalError(c, fmt.Sprintf("failed to retrieve recommendations: %v", err))
@@ -96,34 +132,7 @@ func (h *RecommendationHandler) HandleGetRecommendations(c *gin.Context) {
 	}
 
 	// Build recommendation response objects with inline explanations
-	recResponses := make([]gin.H, len(recommendations))
-	for i, rec := range recommendations {
-		// Parse component_scores into explanation
-		var explanation models.Explanation
-		if len(rec.ComponentScores) > 0 {
-			_ = json.Unmarshal(rec.ComponentScores, &explanation)
-		}
-
-		// Extract raw_score from metadata
-		var rawScore float64
-		if len(rec.Metadata) > 0 {
-			var meta map[string]interface{}
-			if json.Unmarshal(rec.Metadata, &meta) == nil {
-				if rs, ok := meta["raw_score"].(float64); ok {
-					rawScore = rs
-				}
-			}
-		}
-
-		recResponses[i] = gin.H{
-			"rank":        rec.Ranking,
-			"site_id":     rec.SiteID,
-			"site_name":   rec.SiteName,
-			"final_score": rec.FinalScore,
-			"raw_score":   rawScore,
-			"explanation": explanation,
-		}
-	}
+	recResponses := recommendationResponses(recommendations)
 
 	// Build pagination metadata
 	totalPages := 0
@@ -147,8 +156,69 @@ func (h *RecommendationHandler) HandleGetRecommendations(c *gin.Context) {
 	response.Success(c, http.StatusOK, result)
 }
 
-// HandleGetExplanation handles GET /api/v1/runs/:run_id/recommendations/:site_id/explain.
-func (h *RecommendationHandler) HandleGetExplanation(c *gin.Context) {
+// recommendationResponses builds the list-endpoint shape for each
+// recommendation, with its explanation inlined.
+func recommendationResponses(recommendations []models.Recommendation) []models.RecommendationResponse {
+	recResponses := make([]models.RecommendationResponse, len(recommendations))
+	for i, rec := range recommendations {
+		// Parse component_scores into explanation
+		var explanation models.Explanation
+		if len(rec.ComponentScores) > 0 {
+			_ = json.Unmarshal(rec.ComponentScores, &explanation)
+		}
+
+		meta := parseRecommendationMetadata(rec)
+		recResponses[i] = models.RecommendationResponse{
+			Rank:        rec.Ranking,
+			SiteID:      rec.SiteID,
+			SiteName:    rec.SiteName,
+			FinalScore:  rec.FinalScore,
+			RawScore:    meta.RawScore,
+			ScoreLow:    meta.ScoreLow,
+			ScoreHigh:   meta.ScoreHigh,
+			Explanation: explanation,
+		}
+	}
+	return recResponses
+}
+
+// recommendationMetadata is the part of a recommendation's metadata the
+// API reports.
+type recommendationMetadata struct {
+	RawScore  float64  `json:"raw_score"`
+	ScoreLow  *float64 `json:"score_low"`
+	ScoreHigh *float64 `json:"score_high"`
+}
+
+// parseRecommendationMetadata reads rec's metadata; missing or malformed
+// metadata yields zero values.
+func parseRecommendationMetadata(rec models.Recommendation) recommendationMetadata {
+	var meta recommendationMetadata
+	if len(rec.Metadata) > 0 {
+		_ = json.Unmarshal(rec.Metadata, &meta)
+	}
+	return meta
+}
+
+// Bounds for the n query parameter on top/bottom queries
+const (
+	defaultTopN = 10
+	maxTopN     = 100
+)
+
+// HandleGetTop handles GET /api/v1/runs/:run_id/recommendations/top.
+func (h *RecommendationHandler) HandleGetTop(c *gin.Context) {
+	h.handleTopN(c, false)
+}
+
+// HandleGetBottom handles GET /api/v1/runs/:run_id/recommendations/bottom.
+func (h *RecommendationHandler) HandleGetBottom(c *gin.Context) {
+	h.handleTopN(c, true)
+}
+
+// handleTopN returns the n best (or worst, when ascending) recommendations.
+// n defaults to 10 and is capped at 100.
+func (h *RecommendationHandler) handleTopN(c *gin.Context, ascending bool) {
 	tenantID := c.MustGet("tenant_id").(uuid.UUID)
 
 	// Parse run_id from URL
@@ -159,7 +229,18 @@ func (h *RecommendationHandler) HandleGetExplanation(c *gin.Context) {
 		return
 	}
 
-	siteID := c.Param("site_id")
+	n := defaultTopN
+	if nParam := c.Query("n"); nParam != "" {
+		parsed, err := strconv.Atoi(nParam)
+		if err != nil || parsed < 1 {
+			response.BadRequest(c, "n must be a positive integer", nil)
+			return
+		}
+		n = parsed
+	}
+	if n > maxTopN {
+		n = maxTopN
+	}
 
 	// Verify run exists and belongs to tenant
 	run, err := h.runRepo.GetByID(c.Request.Context(), tenantID, runID)
@@ -172,33 +253,281 @@ func (h *RecommendationHandler) HandleGetExplanation(c *gin.Context) {
 		return
 	}
 
-	// Get recommendation by run_id + site_id
-	rec, err := h.recommendationRepo.GetBySiteID(c.Request.Context(), runID, siteID)
+	recommendations, err := h.recommendationRepo.TopN(c.Request.Context(), runID, n, ascending)
 	if err != nil {
-		response.InternalError(c, fmt.Sprintf("failed to retrieve recommendation: %v", err))
+		response.InternalError(c, fmt.Sprintf("failed to retrieve recommendations: %v", err))
 		return
 	}
-	if rec == nil {
-		response.NotFound(c, "recommendation not found")
+
+	result := gin.H{
+		"run_id":          runID,
+		"recommendations": recommendationResponses(recommendations),
+	}
+
+	response.Success(c, http.StatusOK, result)
+}
+
+// Bounds for the top_n query parameter on the bulk explanations export
+const (
+	defaultExplanationsTopN = 50
+	maxExplanationsTopN     = 1000
+)
+
+// HandleGetExplanations handles GET /api/v1/runs/:run_id/explanations,
+// returning full explanations for the run's top_n sites (default 50, at
+// most 1000) in rank order so a combined report needs one call.
+func (h *RecommendationHandler) HandleGetExplanations(c *gin.Context) {
+	tenantID := c.MustGet("tenant_id").(uuid.UUID)
+
+	// Parse run_id from URL
+	runIDStr := c.Param("run_id")
+	runID, err := uuid.Parse(runIDStr)
+	if err != nil {
+		response.BadRequest(c, "invalid run_id format", nil)
 		return
 	}
 
-	// Parse component_scores as explanation factors
-	var explanation models.Explanation
-	if len(rec.ComponentScores) > 0 {
-		_ = json.Unmarshal(rec.ComponentScores, &explanation)
+	topN := defaultExplanationsTopN
+	if topNParam := c.Query("top_n"); topNParam != "" {
+		parsed, err := strconv.Atoi(topNParam)
+		if err != nil || parsed < 1 || parsed > maxExplanationsTopN {
+			response.BadRequest(c, fmt.Sprintf("top_n must be an integer between 1 and %d", maxExplanationsTopN), nil)
+			return
+		}
+		topN = parsed
 	}
 
-	// Extract raw_score from metadata
-	var rawScore float64
-	if len(rec.Metadata) > 0 {
-		var meta map[string]interface{}
-		if json.Unmarshal(rec.Metadata, &meta) == nil {
-			if rs, ok := meta["raw_score"].(float64); ok {
-				rawScore = rs
-			}
+	// Verify run exists and belongs to tenant
+	run, err := h.runRepo.GetByID(c.Request.Context(), tenantID, runID)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to retrieve run: %v", err))
+		return
+	}
+	if run == nil {
+		response.NotFound(c, "run not found")
+		return
+	}
+
+	// Completed runs' results never change, so honour If-None-Match
+	if notModified(c, runETag(run)) {
+		return
+	}
+
+	recommendations, err := h.recommendationRepo.TopN(c.Request.Context(), runID, topN, false)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to retrieve recommendations: %v", err))
+		return
+	}
+
+	response.Success(c, http.StatusOK, explanationsExport(run, recommendations, topN))
+}
+
+// explanationsExport builds the bulk explanations response: run metadata
+// plus each recommendation with its explanation inlined, in rank order.
+func explanationsExport(run *models.ScoringRun, recommendations []models.Recommendation, topN int) gin.H {
+	result := gin.H{
+		"run_id":        run.ID,
+		"model_version": run.ModelVersion,
+		"top_n":         topN,
+		"count":         len(recommendations),
+		"explanations":  recommendationResponses(recommendations),
+	}
+	if run.CompletedAt != nil {
+		result["scored_at"] = run.CompletedAt
+	}
+	return result
+}
+
+// Bounds for the histogram buckets query parameter
+const (
+	defaultHistogramBuckets = 10
+	minHistogramBuckets     = 2
+	maxHistogramBuckets     = 100
+)
+
+// HandleGetHistogram handles GET /api/v1/runs/:run_id/histogram.
+func (h *RecommendationHandler) HandleGetHistogram(c *gin.Context) {
+	tenantID := c.MustGet("tenant_id").(uuid.UUID)
+
+	// Parse run_id from URL
+	runIDStr := c.Param("run_id")
+	runID, err := uuid.Parse(runIDStr)
+	if err != nil {
+		response.BadRequest(c, "invalid run_id format", nil)
+		return
+	}
+
+	buckets := defaultHistogramBuckets
+	if bucketsParam := c.Query("buckets"); bucketsParam != "" {
+		b, err := strconv.Atoi(bucketsParam)
+		if err != nil || b < minHistogramBuckets || b > maxHistogramBuckets {
+			response.BadRequest(c, fmt.Sprintf("buckets must be an integer between %d and %d", minHistogramBuckets, maxHistogramBuckets), nil)
+			return
+		}
+		buckets = b
+	}
+
+	// Verify run exists and belongs to tenant
+	run, err := h.runRepo.GetByID(c.Request.Context(), tenantID, runID)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to retrieve run: %v", err))
+		return
+	}
+	if run == nil {
+		response.NotFound(c, "run not found")
+		return
+	}
+
+	scale, err := h.runScoreScale(c.Request.Context(), runID)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to retrieve run schema snapshot: %v", err))
+		return
+	}
+
+	histogram, err := h.recommendationRepo.Histogram(c.Request.Context(), runID, buckets, scale.Max())
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to compute histogram: %v", err))
+		return
+	}
+
+	result := gin.H{
+		"run_id":      runID,
+		"score_scale": scale.Max(),
+		"buckets":     histogram,
+	}
+
+	response.Success(c, http.StatusOK, result)
+}
+
+// HandleGetRollup handles GET /api/v1/runs/:run_id/rollup, summarising the
+// run's scores per value of the group_by site data field.
+func (h *RecommendationHandler) HandleGetRollup(c *gin.Context) {
+	tenantID := c.MustGet("tenant_id").(uuid.UUID)
+
+	// Parse run_id from URL
+	runIDStr := c.Param("run_id")
+	runID, err := uuid.Parse(runIDStr)
+	if err != nil {
+		response.BadRequest(c, "invalid run_id format", nil)
+		return
+	}
+
+	groupBy := c.Query("group_by")
+	if groupBy == "" {
+		response.BadRequest(c, "group_by is required", gin.H{"field": "group_by"})
+		return
+	}
+
+	var minScore *float64
+	if minScoreParam := c.Query("min_score"); minScoreParam != "" {
+		ms, err := strconv.ParseFloat(minScoreParam, 64)
+		if err != nil || ms < 0 {
+			response.BadRequest(c, "min_score must be a non-negative number", gin.H{"field": "min_score"})
+			return
+		}
+		minScore = &ms
+	}
+
+	// Verify run exists and belongs to tenant
+	run, err := h.runRepo.GetByID(c.Request.Context(), tenantID, runID)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to retrieve run: %v", err))
+		return
+	}
+	if run == nil {
+		response.NotFound(c, "run not found")
+		return
+	}
+
+	// Only fields of the schema the run was scored with can be grouped on;
+	// runs without a snapshot fall back to the plain field name check
+	fields, err := h.rollupFields(c.Request.Context(), runID)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to retrieve run schema snapshot: %v", err))
+		return
+	}
+	allowed := repository.ValidSiteDataField(groupBy)
+	if fields != nil {
+		i := sort.SearchStrings(fields, groupBy)
+		allowed = allowed && i < len(fields) && fields[i] == groupBy
+	}
+	if !allowed {
+		details := gin.H{"field": "group_by"}
+		if fields != nil {
+			details["allowed_fields"] = fields
+		}
+		response.BadRequest(c, fmt.Sprintf("cannot group by %q", groupBy), details)
+		return
+	}
+
+	groups, err := h.recommendationRepo.Rollup(c.Request.Context(), runID, groupBy, minScore)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to compute rollup: %v", err))
+		return
+	}
+
+	response.Success(c, http.StatusOK, gin.H{
+		"run_id":   runID,
+		"group_by": groupBy,
+		"groups":   groups,
+	})
+}
+
+// rollupFields returns, in name order, the schema fields a run's
+// recommendations can be grouped by: every field of its schema snapshot
+// except computed ones, which are not stored with the site data. It
+// returns nil when the run has no snapshot, and an error when the snapshot
+// cannot be read.
+func (h *RecommendationHandler) rollupFields(ctx context.Context, runID uuid.UUID) ([]string, error) {
+	snapshot, err := h.schemaConfigRepo.GetSnapshotByRun(ctx, runID)
+	if err != nil || snapshot == nil {
+		return nil, err
+	}
+
+	var resolved schema.ResolvedSchema
+	if err := json.Unmarshal(snapshot.SnapshotData, &resolved); err != nil {
+		return nil, fmt.Errorf("parse snapshot %s: %w", snapshot.ID, err)
+	}
+	return groupableFields(&resolved), nil
+}
+
+// groupableFields lists the non-computed fields of resolved in name order.
+func groupableFields(resolved *schema.ResolvedSchema) []string {
+	fields := make([]string, 0, len(resolved.Fields))
+	for name, def := range resolved.Fields {
+		if def.Type != schema.TypeComputed {
+			fields = append(fields, name)
 		}
 	}
+	sort.Strings(fields)
+	return fields
+}
+
+// runScoreScale returns the score scale a run was scored on, read from its
+// schema snapshot. Runs without a snapshot use the default 0-100 scale; an
+// unreadable snapshot is an error.
+func (h *RecommendationHandler) runScoreScale(ctx context.Context, runID uuid.UUID) (schema.ScoreScale, error) {
+	snapshot, err := h.schemaConfigRepo.GetSnapshotByRun(ctx, runID)
+	if err != nil || snapshot == nil {
+		return schema.ScaleHundred, err
+	}
+
+	var resolved schema.ResolvedSchema
+	if err := json.Unmarshal(snapshot.SnapshotData, &resolved); err != nil {
+		return schema.ScaleHundred, fmt.Errorf("parse snapshot %s: %w", snapshot.ID, err)
+	}
+	return resolved.Scoring.ScoreScale, nil
+}
+
+// HandleGetExplanation handles GET /api/v1/runs/:run_id/recommendations/:site_id/explain.
+func (h *RecommendationHandler) HandleGetExplanation(c *gin.Context) {
+	run, rec, ok := h.lookupRecommendation(c)
+	if !ok {
+		return
+	}
+	explanation := parseExplanation(rec)
+
+	meta := parseRecommendationMetadata(*rec)
 
 	// Build weights_applied from schema config snapshot (if available)
 	var weightsApplied gin.H
@@ -215,7 +544,7 @@ func (h *RecommendationHandler) HandleGetExplanation(c *gin.Context) {
 				weightsApplied = gin.H{
 					"source":                    "tenant_override",
 					"schema_config_snapshot_id": snapshot.ID,
-					"weight_set":               weightSet,
+					"weight_set":                weightSet,
 				}
 			}
 		}
@@ -228,8 +557,17 @@ func (h *RecommendationHandler) HandleGetExplanation(c *gin.Context) {
 	explanationObj := gin.H{
 		"factors":       explanation.Factors,
 		"summary":       explanation.Summary,
+		"coverage":      explanation.Coverage,
 		"model_version": run.ModelVersion,
 	}
+	if len(explanation.Categories) > 0 {
+		explanationObj["categories"] = explanation.Categories
+		explanationObj["category_scores"] = explanation.CategoryScores
+		explanationObj["category_summary"] = explanation.CategorySummary
+	}
+	if explanation.Freshness != nil {
+		explanationObj["freshness"] = explanation.Freshness
+	}
 	if run.CompletedAt != nil {
 		explanationObj["scored_at"] = run.CompletedAt
 	}
@@ -242,9 +580,13 @@ func (h *RecommendationHandler) HandleGetExplanation(c *gin.Context) {
 		"site_name":   rec.SiteName,
 		"run_id":      rec.RunID,
 		"final_score": rec.FinalScore,
-		"raw_score":   rawScore,
+		"raw_score":   meta.RawScore,
 		"explanation": explanationObj,
 	}
+	if meta.ScoreLow != nil && meta.ScoreHigh != nil {
+		result["score_low"] = *meta.ScoreLow
+		result["score_high"] = *meta.ScoreHigh
+	}
 
 	// Add narrative stub if requested (per case study: "may implement if time permits")
 	if includeNarrative {
@@ -262,3 +604,78 @@ func (h *RecommendationHandler) HandleGetExplanation(c *gin.Context) {
 
 	response.Success(c, http.StatusOK, result)
 }
+
+// HandleGetExplanationMarkdown handles GET
+// /api/v1/runs/:run_id/recommendations/:site_id/explain.md, rendering the
+// same explanation as a Markdown document for pasting into reports.
+func (h *RecommendationHandler) HandleGetExplanationMarkdown(c *gin.Context) {
+	run, rec, ok := h.lookupRecommendation(c)
+	if !ok {
+		return
+	}
+
+	scale, err := h.runScoreScale(c.Request.Context(), run.ID)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to retrieve run schema snapshot: %v", err))
+		return
+	}
+
+	doc := renderExplanationMarkdown(run, rec, parseExplanation(rec), scale)
+	c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(doc))
+}
+
+// lookupRecommendation loads the run and recommendation named by the
+// :run_id and :site_id params, writing the error response (or 304 for a
+// matching If-None-Match) and returning false when there is nothing to
+// render.
+func (h *RecommendationHandler) lookupRecommendation(c *gin.Context) (*models.ScoringRun, *models.Recommendation, bool) {
+	tenantID := c.MustGet("tenant_id").(uuid.UUID)
+
+	// Parse run_id from URL
+	runIDStr := c.Param("run_id")
+	runID, err := uuid.Parse(runIDStr)
+	if err != nil {
+		response.BadRequest(c, "invalid run_id format", nil)
+		return nil, nil, false
+	}
+
+	siteID := c.Param("site_id")
+
+	// Verify run exists and belongs to tenant
+	run, err := h.runRepo.GetByID(c.Request.Context(), tenantID, runID)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to retrieve run: %v", err))
+		return nil, nil, false
+	}
+	if run == nil {
+		response.NotFound(c, "run not found")
+		return nil, nil, false
+	}
+
+	// Completed runs' results never change, so honour If-None-Match
+	if notModified(c, runETag(run)) {
+		return nil, nil, false
+	}
+
+	// Get recommendation by run_id + site_id
+	rec, err := h.recommendationRepo.GetBySiteID(c.Request.Context(), runID, siteID)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to retrieve recommendation: %v", err))
+		return nil, nil, false
+	}
+	if rec == nil {
+		response.NotFound(c, "recommendation not found")
+		return nil, nil, false
+	}
+	return run, rec, true
+}
+
+// parseExplanation decodes a recommendation's stored explanation; a
+// missing or malformed one yields an empty explanation.
+func parseExplanation(rec *models.Recommendation) models.Explanation {
+	var explanation models.Explanation
+	if len(rec.ComponentScores) > 0 {
+		_ = json.Unmarshal(rec.ComponentScores, &explanation)
+	}
+	return explanation
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
+)
+
+func TestExplanationsExport_MultipleSites(t *testing.T) {
+	completed := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	run := &models.ScoringRun{ID: uuid.New(), ModelVersion: "site-selection-iq-v1.0", CompletedAt: &completed}
+
+	explanation := func(name string, contribution float64) json.RawMessage {
+		data, _ := json.Marshal(models.Explanation{
+			Factors:  []models.ExplanationFactor{{Name: name, Weight: 1, Contribution: contribution}},
+			Summary:  "Driven by " + name,
+			Coverage: 1,
+		})
+		return data
+	}
+	recs := []models.Recommendation{
+		{SiteID: "S1", SiteName: "Austin", Ranking: 1, FinalScore: 91, ComponentScores: explanation("population", 0.91), Metadata: json.RawMessage(`{"raw_score": 0.91}`)},
+		{SiteID: "S2", SiteName: "Denver", Ranking: 2, FinalScore: 84, ComponentScores: explanation("income", 0.84)},
+	}
+
+	result := explanationsExport(run, recs, 50)
+
+	assert.Equal(t, run.ID, result["run_id"])
+	assert.Equal(t, "site-selection-iq-v1.0", result["model_version"])
+	assert.Equal(t, 50, result["top_n"])
+	assert.Equal(t, 2, result["count"])
+	assert.Equal(t, &completed, result["scored_at"])
+
+	entries := result["explanations"].([]models.RecommendationResponse)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "S1", entries[0].SiteID)
+	assert.Equal(t, 1, entries[0].Rank)
+	assert.Equal(t, 0.91, entries[0].RawScore)
+	assert.Equal(t, "Driven by population", entries[0].Explanation.Summary)
+	assert.Equal(t, "population", entries[0].Explanation.Factors[0].Name)
+
+	assert.Equal(t, "S2", entries[1].SiteID)
+	assert.Equal(t, "income", entries[1].Explanation.Factors[0].Name)
+}
+
+func TestExplanationsExport_NoRecommendations(t *testing.T) {
+	result := explanationsExport(&models.ScoringRun{ID: uuid.New()}, nil, 10)
+	assert.Equal(t, 0, result["count"])
+	assert.Empty(t, result["explanations"])
+	assert.NotContains(t, result, "scored_at")
+}
+
+// TestRecommendationResponses_SerializedShape pins the v1 Recommendation
+// contract documented in openapi.yaml.
+func TestRecommendationResponses_SerializedShape(t *testing.T) {
+	explanation, _ := json.Marshal(models.Explanation{
+		Factors:  []models.ExplanationFactor{{Name: "population", Value: 800, Weight: 1, Contribution: 0.8, Direction: "maximize", Reason: "high"}},
+		Summary:  "Driven by population",
+		Coverage: 1,
+	})
+	recs := []models.Recommendation{{
+		SiteID: "S1", SiteName: "Austin", Ranking: 1, FinalScore: 80,
+		ComponentScores: explanation, Metadata: json.RawMessage(`{"raw_score": 0.8}`),
+	}}
+
+	data, err := json.Marshal(recommendationResponses(recs))
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{
+		"rank": 1,
+		"site_id": "S1",
+		"site_name": "Austin",
+		"final_score": 80,
+		"raw_score": 0.8,
+		"explanation": {
+			"factors": [{"name": "population", "value": 800, "weight": 1, "contribution": 0.8, "direction": "maximize", "reason": "high"}],
+			"summary": "Driven by population",
+			"coverage": 1
+		}
+	}]`, string(data))
+}
+
+func TestRecommendationResponses_MissingMetadata(t *testing.T) {
+	data, err := json.Marshal(recommendationResponses([]models.Recommendation{{SiteID: "S1", Ranking: 1}}))
+
+	require.NoError(t, err)
+	var entries []map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &entries))
+	for _, key := range []string{"rank", "site_id", "site_name", "final_score", "raw_score", "explanation"} {
+		assert.Contains(t, entries[0], key, "required field %s is always present", key)
+	}
+}
+
+func TestRecommendationResponses_ScoreInterval(t *testing.T) {
+	recs := []models.Recommendation{
+		{SiteID: "S1", Ranking: 1, FinalScore: 80, Metadata: json.RawMessage(`{"raw_score": 0.8, "score_low": 72.5, "score_high": 87.5}`)},
+		{SiteID: "S2", Ranking: 2, FinalScore: 70, Metadata: json.RawMessage(`{"raw_score": 0.7}`)},
+	}
+
+	data, err := json.Marshal(recommendationResponses(recs))
+
+	require.NoError(t, err)
+	var entries []map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &entries))
+	assert.Equal(t, 72.5, entries[0]["score_low"])
+	assert.Equal(t, 87.5, entries[0]["score_high"])
+	assert.NotContains(t, entries[1], "score_low", "no interval without uncertainty columns")
+	assert.NotContains(t, entries[1], "score_high")
+}
+
+func TestGroupableFields_SkipsComputed(t *testing.T) {
+	resolved := &schema.ResolvedSchema{Fields: map[string]schema.FieldDef{
+		"state":       {Type: schema.TypeText},
+		"population":  {Type: schema.TypePopulation},
+		"density":     {Type: schema.TypeComputed},
+		"county_code": {Type: schema.TypeIdentifier},
+	}}
+
+	assert.Equal(t, []string{"county_code", "population", "state"}, groupableFields(resolved))
+}
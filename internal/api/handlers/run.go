@@ -1,9 +1,10 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
@@ -11,8 +12,10 @@ import (
 	"github.com/google/uuid"
 	"github.com/workforce-ai/site-selection-iq/internal/api/response"
 	"github.com/workforce-ai/site-selection-iq/internal/config"
+	"github.com/workforce-ai/site-selection-iq/internal/i18n"
 	"github.com/workforce-ai/site-selection-iq/internal/models"
 	"github.com/workforce-ai/site-selection-iq/internal/repository"
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
 	"github.com/workforce-ai/site-selection-iq/internal/scoring"
 )
 
@@ -22,6 +25,11 @@ type RunHandler struct {
 	uploadRepo      *repository.UploadRepository
 	idempotencyRepo *repository.IdempotencyRepository
 	pipeline        *scoring.Pipeline
+	auditRepo       *repository.AuditRepository
+	recRepo         *repository.RecommendationRepository
+	schemaRepo      *repository.SchemaConfigRepository
+	presetRepo      *repository.WeightPresetRepository
+	skippedRepo     *repository.SkippedSiteRepository
 	cfg             *config.Config
 }
 
@@ -31,6 +39,11 @@ func NewRunHandler(
 	uploadRepo *repository.UploadRepository,
 	idempotencyRepo *repository.IdempotencyRepository,
 	pipeline *scoring.Pipeline,
+	auditRepo *repository.AuditRepository,
+	recRepo *repository.RecommendationRepository,
+	schemaRepo *repository.SchemaConfigRepository,
+	presetRepo *repository.WeightPresetRepository,
+	skippedRepo *repository.SkippedSiteRepository,
 	cfg *config.Config,
 ) *RunHandler {
 	return &RunHandler{
@@ -38,6 +51,11 @@ func NewRunHandler(
 		uploadRepo:      uploadRepo,
 		idempotencyRepo: idempotencyRepo,
 		pipeline:        pipeline,
+		auditRepo:       auditRepo,
+		recRepo:         recRepo,
+		schemaRepo:      schemaRepo,
+		presetRepo:      presetRepo,
+		skippedRepo:     skippedRepo,
 		cfg:             cfg,
 	}
 }
@@ -46,6 +64,7 @@ func NewRunHandler(
 type createRunRequest struct {
 	IdempotencyKey string          `json:"idempotency_key"`
 	ScoringConfig  json.RawMessage `json:"scoring_config"`
+	Force          bool            `json:"force"` // score again even if an identical run succeeded
 }
 
 // HandleCreateRun handles POST /api/v1/uploads/:upload_id/runs.
@@ -62,7 +81,23 @@ func (h *RunHandler) HandleCreateRun(c *gin.Context) {
 
 	// Parse optional request body (scoring_config + idempotency_key)
 	var req createRunRequest
-	_ = c.ShouldBindJSON(&req) // optional body; OK if missing
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) { // optional body; OK if missing
+		response.BadRequest(c, fmt.Sprintf("invalid request body: %v", err), nil)
+		return
+	}
+
+	scoringConfig, model, ok := h.prepareScoringConfig(c, tenantID, req.ScoringConfig)
+	if !ok {
+		return
+	}
+
+	// Without a locale in scoring_config, explanations follow the caller's
+	// Accept-Language when it names a supported one
+	scoringConfig, err = withRequestLocale(scoringConfig, c.GetHeader("Accept-Language"))
+	if err != nil {
+		response.BadRequest(c, fmt.Sprintf("invalid scoring_config: %v", err), nil)
+		return
+	}
 
 	// Determine idempotency key: header takes precedence, then body
 	idempotencyKey := c.GetHeader("Idempotency-Key")
@@ -81,67 +116,71 @@ func (h *RunHandler) HandleCreateRun(c *gin.Context) {
 		return
 	}
 
-	// Verify upload passed validation (422 per spec)
-	if upload.ValidationStatus != "valid" {
-		response.Error(c, http.StatusUnprocessableEntity, "UNPROCESSABLE",
-			"upload failed validation and cannot be scored", nil)
+	// Verify upload passed validation and has rows to score (422 per spec)
+	if reason := unscorableReason(upload); reason != "" {
+		response.Error(c, http.StatusUnprocessableEntity, response.CodeUnprocessable, reason, nil)
 		return
 	}
 
-	// Atomic idempotency claim — return 409 Conflict with existing run per spec
-	runID := uuid.New()
-	if idempotencyKey != "" {
-		claim, err := h.idempotencyRepo.Claim(c.Request.Context(), tenantID, idempotencyKey, "scoring_run", runID)
-		if err != nil {
-			response.InternalError(c, fmt.Sprintf("idempotency check failed: %v", err))
-			return
-		}
-		if claim.AlreadyExists {
-			existing, _ := h.runRepo.GetByID(c.Request.Context(), tenantID, claim.ResourceID)
-			response.Conflict(c, "duplicate scoring run (idempotency key match)", existing)
-			return
-		}
-	}
-
 	// Create scoring run record
 	now := time.Now()
+	runID := uuid.New()
 	var idempotencyKeyPtr *string
 	if idempotencyKey != "" {
 		idempotencyKeyPtr = &idempotencyKey
 	}
 
-	// Extract model_version from scoring_config if provided, else use default
-	modelVersion := "site-selection-iq-v1.0"
-	var scoringConfig json.RawMessage
-	if len(req.ScoringConfig) > 0 {
-		scoringConfig = req.ScoringConfig
-		// Try to extract model_version from request
-		var sc struct {
-			ModelVersion string `json:"model_version"`
-		}
-		if json.Unmarshal(req.ScoringConfig, &sc) == nil && sc.ModelVersion != "" {
-			if sc.ModelVersion != "latest" {
-				modelVersion = sc.ModelVersion
-			}
-		}
-	}
-
 	rowCount := upload.RowCount
+	correlationID, _ := c.Get("correlation_id")
+	correlationIDStr, _ := correlationID.(string)
 
 	run := &models.ScoringRun{
 		ID:             runID,
 		UploadID:       uploadID,
 		TenantID:       tenantID,
 		Status:         "queued",
-		ModelVersion:   modelVersion,
+		ModelVersion:   model.Version,
 		ScoringConfig:  scoringConfig,
-		InstanceID:     uuid.New(),
+		InstanceID:     h.pipeline.InstanceID(),
 		TransactionID:  uuid.New(),
 		RowCount:       &rowCount,
 		Attempt:        0,
 		IdempotencyKey: idempotencyKeyPtr,
 		CreatedAt:      now,
 		UpdatedAt:      now,
+		CorrelationID:  correlationIDStr,
+	}
+
+	// Reuse a completed run that scored the same content with the same
+	// effective schema, unless the caller forces a fresh one
+	prior, err := h.reusableRun(c, upload, run, req.Force)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to look up prior runs: %v", err))
+		return
+	}
+
+	// Atomic idempotency claim — return 409 Conflict with existing run per spec.
+	// A reused run is claimed in place of the new one.
+	if idempotencyKey != "" {
+		resourceID := runID
+		if prior != nil {
+			resourceID = prior.ID
+		}
+		claim, err := h.idempotencyRepo.Claim(c.Request.Context(), tenantID, idempotencyKey, "scoring_run", resourceID)
+		if err != nil {
+			response.InternalError(c, fmt.Sprintf("idempotency check failed: %v", err))
+			return
+		}
+		if claim.AlreadyExists {
+			existing, _ := h.runRepo.GetByID(c.Request.Context(), tenantID, claim.ResourceID)
+			respondConflict(c, "duplicate scoring run (idempotency key match)", existing, runConflictDetails(existing))
+			return
+		}
+	}
+
+	if prior != nil {
+		response.Success(c, http.StatusOK, prior)
+		return
 	}
 
 	if err := h.runRepo.Create(c.Request.Context(), run); err != nil {
@@ -149,15 +188,451 @@ func (h *RunHandler) HandleCreateRun(c *gin.Context) {
 		return
 	}
 
-	// Launch scoring pipeline asynchronously
-	go func() {
-		bgCtx := context.Background()
-		_ = h.pipeline.ExecuteWithRetry(bgCtx, run)
-	}()
+	// Launch scoring pipeline asynchronously (tracked for graceful shutdown)
+	h.pipeline.Dispatch(run)
 
+	recordAudit(c, h.auditRepo, models.AuditActionRunCreate, run.ID)
 	response.Success(c, http.StatusAccepted, run)
 }
 
+// reusableRun returns the most recent completed run of the upload whose
+// result key matches the one run would have if executed now, if any and
+// force is not set. The key is only a lookup: run's own is computed and
+// saved from the schema it is actually scored with. Uploads without a
+// content hash are never reused. If the key cannot be computed, e.g.
+// because the tenant's schema does not resolve, the run is left to execute
+// and report the problem itself.
+func (h *RunHandler) reusableRun(c *gin.Context, upload *models.Upload, run *models.ScoringRun, force bool) (*models.ScoringRun, error) {
+	if force || upload.ContentHash == nil || *upload.ContentHash == "" {
+		return nil, nil
+	}
+	key, err := h.pipeline.ResultKey(c.Request.Context(), run, *upload.ContentHash)
+	if err != nil {
+		return nil, nil
+	}
+	return h.runRepo.GetCompletedByResultKey(c.Request.Context(), run.TenantID, run.UploadID, key)
+}
+
+// HandleRescoreTenant handles POST /api/v1/schema-config/rescore. It queues
+// a new scoring run for each of the tenant's valid uploads, scored with the
+// tenant's current schema config and the default model, and returns the
+// created run IDs. The runs execute asynchronously, at most
+// Scoring.WorkerCount at a time.
+func (h *RunHandler) HandleRescoreTenant(c *gin.Context) {
+	tenantID := c.MustGet("tenant_id").(uuid.UUID)
+	ctx := c.Request.Context()
+
+	uploads, err := h.uploadRepo.ListByTenant(ctx, tenantID)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to list uploads: %v", err))
+		return
+	}
+
+	model, err := h.pipeline.Models().Lookup("")
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to resolve model: %v", err))
+		return
+	}
+
+	correlationID, _ := c.Get("correlation_id")
+	correlationIDStr, _ := correlationID.(string)
+	runs := rescoreRuns(uploads, model.Version, h.pipeline.InstanceID(), correlationIDStr, time.Now())
+
+	runIDs := make([]uuid.UUID, 0, len(runs))
+	var createErr error
+	for _, run := range runs {
+		if createErr = h.runRepo.Create(ctx, run); createErr != nil {
+			break
+		}
+		runIDs = append(runIDs, run.ID)
+		recordAudit(c, h.auditRepo, models.AuditActionRunCreate, run.ID)
+	}
+
+	// Runs already created are dispatched even if a later create failed, so
+	// none is left queued with no owner
+	h.pipeline.DispatchBatch(runs[:len(runIDs)], h.cfg.Scoring.WorkerCount)
+	if createErr != nil {
+		response.InternalError(c, fmt.Sprintf("failed to create run: %v", createErr))
+		return
+	}
+
+	response.Success(c, http.StatusAccepted, gin.H{
+		"run_ids":         runIDs,
+		"run_count":       len(runIDs),
+		"skipped_uploads": len(uploads) - len(runs),
+	})
+}
+
+// unscorableReason explains why an upload cannot be scored, or returns ""
+// if it can. Uploads stored before empty files were rejected at ingest may
+// be valid with no rows.
+func unscorableReason(upload *models.Upload) string {
+	if upload.ValidationStatus != "valid" {
+		return "upload failed validation and cannot be scored"
+	}
+	if upload.RowCount == 0 {
+		return "upload has no data rows to score"
+	}
+	return ""
+}
+
+// rescoreRuns builds a queued scoring run for each valid upload. Uploads
+// that failed, are still being validated or have no rows are skipped. The runs carry no
+// scoring_config, so each is scored with the tenant's schema as it stands
+// when the run executes.
+func rescoreRuns(uploads []models.Upload, modelVersion string, instanceID uuid.UUID, correlationID string, now time.Time) []*models.ScoringRun {
+	runs := make([]*models.ScoringRun, 0, len(uploads))
+	for _, upload := range uploads {
+		if unscorableReason(&upload) != "" {
+			continue
+		}
+		rowCount := upload.RowCount
+		runs = append(runs, &models.ScoringRun{
+			ID:            uuid.New(),
+			UploadID:      upload.ID,
+			TenantID:      upload.TenantID,
+			Status:        "queued",
+			ModelVersion:  modelVersion,
+			InstanceID:    instanceID,
+			TransactionID: uuid.New(),
+			RowCount:      &rowCount,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+			CorrelationID: correlationID,
+		})
+	}
+	return runs
+}
+
+// withPresetWeights returns scoringConfig with a preset's weights merged
+// into its "weights" object. Weights the config sets itself take precedence.
+func withPresetWeights(scoringConfig json.RawMessage, presetWeights map[string]float64) (json.RawMessage, error) {
+	cfg := map[string]json.RawMessage{}
+	if len(scoringConfig) > 0 {
+		if err := json.Unmarshal(scoringConfig, &cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	weights := make(map[string]float64, len(presetWeights))
+	for name, weight := range presetWeights {
+		weights[name] = weight
+	}
+	if own, ok := cfg["weights"]; ok {
+		var ownWeights map[string]float64
+		if err := json.Unmarshal(own, &ownWeights); err != nil {
+			return nil, err
+		}
+		for name, weight := range ownWeights {
+			weights[name] = weight
+		}
+	}
+
+	merged, err := json.Marshal(weights)
+	if err != nil {
+		return nil, err
+	}
+	cfg["weights"] = merged
+	return json.Marshal(cfg)
+}
+
+// withRequestLocale returns scoringConfig with its locale set to the best
+// supported match for an Accept-Language header. The config is returned
+// unchanged when it already sets a locale or nothing in the header is
+// supported.
+func withRequestLocale(scoringConfig json.RawMessage, acceptLanguage string) (json.RawMessage, error) {
+	locale := i18n.Negotiate(acceptLanguage)
+	if locale == "" {
+		return scoringConfig, nil
+	}
+
+	cfg := map[string]json.RawMessage{}
+	if len(scoringConfig) > 0 {
+		if err := json.Unmarshal(scoringConfig, &cfg); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := cfg["locale"]; ok {
+		return scoringConfig, nil
+	}
+
+	encoded, err := json.Marshal(locale)
+	if err != nil {
+		return nil, err
+	}
+	cfg["locale"] = encoded
+	return json.Marshal(cfg)
+}
+
+// prepareScoringConfig validates a run's scoring_config, resolves its
+// model_version (the default when absent or "latest") to the registered
+// model, checks any requested scorer exists and expands a named
+// weight_preset into the config's weights. It returns the config to store on
+// the run, nil when empty. On failure it sends the error response and
+// returns ok false.
+func (h *RunHandler) prepareScoringConfig(c *gin.Context, tenantID uuid.UUID, scoringConfig json.RawMessage) (json.RawMessage, scoring.Model, bool) {
+	if err := schema.ValidateScoringConfig(scoringConfig); err != nil {
+		response.BadRequest(c, fmt.Sprintf("invalid scoring_config: %v", err), configErrorDetails(err))
+		return nil, scoring.Model{}, false
+	}
+
+	var requested struct {
+		ModelVersion string `json:"model_version"`
+		Scorer       string `json:"scorer"`
+		WeightPreset string `json:"weight_preset"`
+	}
+	if len(scoringConfig) > 0 {
+		_ = json.Unmarshal(scoringConfig, &requested)
+	}
+	model, err := h.pipeline.Models().Lookup(requested.ModelVersion)
+	if err != nil {
+		response.BadRequest(c, fmt.Sprintf("invalid scoring_config: %v", err), gin.H{
+			"field":              "model_version",
+			"available_versions": h.pipeline.Models().Versions(),
+		})
+		return nil, scoring.Model{}, false
+	}
+	if requested.Scorer != "" {
+		if _, err := h.pipeline.ScoreFuncs().Lookup(requested.Scorer); err != nil {
+			response.BadRequest(c, fmt.Sprintf("invalid scoring_config: %v", err), gin.H{
+				"field":             "scorer",
+				"available_scorers": h.pipeline.ScoreFuncs().Names(),
+			})
+			return nil, scoring.Model{}, false
+		}
+	}
+
+	// A named weight preset is expanded into the run's own weights, so the
+	// run keeps them even if the preset later changes
+	if requested.WeightPreset != "" {
+		preset, err := h.presetRepo.GetByName(c.Request.Context(), tenantID, requested.WeightPreset)
+		if err != nil {
+			response.InternalError(c, fmt.Sprintf("failed to retrieve weight preset: %v", err))
+			return nil, scoring.Model{}, false
+		}
+		if preset == nil {
+			response.BadRequest(c, fmt.Sprintf("invalid scoring_config: unknown weight preset %q", requested.WeightPreset),
+				gin.H{"field": "weight_preset"})
+			return nil, scoring.Model{}, false
+		}
+		scoringConfig, err = withPresetWeights(scoringConfig, preset.Weights)
+		if err != nil {
+			response.BadRequest(c, fmt.Sprintf("invalid scoring_config: %v", err), nil)
+			return nil, scoring.Model{}, false
+		}
+	}
+
+	if len(scoringConfig) == 0 {
+		return nil, model, true
+	}
+	return scoringConfig, model, true
+}
+
+// sensitivityRequest is the body for a weight sensitivity analysis.
+type sensitivityRequest struct {
+	PerturbationPct float64         `json:"perturbation_pct"`
+	TopN            int             `json:"top_n"`
+	ScoringConfig   json.RawMessage `json:"scoring_config"`
+}
+
+// Bounds for the sensitivity top_n parameter
+const maxSensitivityTopN = 100
+
+// HandleSensitivity handles POST /api/v1/uploads/:upload_id/sensitivity.
+// It scores the upload in memory, without creating a run, and reports how
+// much the top-N ranking moves when each weight is raised and lowered by
+// perturbation_pct percent (default 10). Uploads larger than
+// Scoring.SensitivityMaxSites are rejected.
+func (h *RunHandler) HandleSensitivity(c *gin.Context) {
+	tenantID := c.MustGet("tenant_id").(uuid.UUID)
+
+	uploadID, err := uuid.Parse(c.Param("upload_id"))
+	if err != nil {
+		response.BadRequest(c, "invalid upload_id format", nil)
+		return
+	}
+
+	var req sensitivityRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) { // optional body; OK if missing
+		response.BadRequest(c, fmt.Sprintf("invalid request body: %v", err), nil)
+		return
+	}
+
+	if req.PerturbationPct < 0 || req.PerturbationPct >= 100 {
+		response.BadRequest(c, "perturbation_pct must be greater than 0 and less than 100", gin.H{"field": "perturbation_pct"})
+		return
+	}
+	if req.TopN < 0 || req.TopN > maxSensitivityTopN {
+		response.BadRequest(c, fmt.Sprintf("top_n must be between 1 and %d", maxSensitivityTopN), gin.H{"field": "top_n"})
+		return
+	}
+
+	scoringConfig, model, ok := h.prepareScoringConfig(c, tenantID, req.ScoringConfig)
+	if !ok {
+		return
+	}
+
+	upload, err := h.uploadRepo.GetByID(c.Request.Context(), tenantID, uploadID)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to retrieve upload: %v", err))
+		return
+	}
+	if upload == nil {
+		response.NotFound(c, "upload not found")
+		return
+	}
+	if reason := unscorableReason(upload); reason != "" {
+		response.Error(c, http.StatusUnprocessableEntity, response.CodeUnprocessable, reason, nil)
+		return
+	}
+	maxSites := h.cfg.Scoring.SensitivityMaxSites
+	if maxSites > 0 && upload.RowCount > maxSites {
+		response.Error(c, http.StatusUnprocessableEntity, response.CodeUnprocessable,
+			fmt.Sprintf("upload has %d sites; sensitivity analysis is limited to %d", upload.RowCount, maxSites),
+			gin.H{"row_count": upload.RowCount, "max_sites": maxSites})
+		return
+	}
+
+	correlationID, _ := c.Get("correlation_id")
+	correlationIDStr, _ := correlationID.(string)
+	dryRun := &models.ScoringRun{
+		ID:            uuid.New(),
+		UploadID:      uploadID,
+		TenantID:      tenantID,
+		ModelVersion:  model.Version,
+		ScoringConfig: scoringConfig,
+		InstanceID:    h.pipeline.InstanceID(),
+		TransactionID: uuid.New(),
+		CorrelationID: correlationIDStr,
+	}
+
+	report, err := h.pipeline.Sensitivity(c.Request.Context(), dryRun, scoring.SensitivityOptions{
+		Perturbation: req.PerturbationPct / 100,
+		TopN:         req.TopN,
+		MaxSites:     maxSites,
+	})
+	if err != nil {
+		if errors.Is(err, scoring.ErrTooManySites) {
+			response.Error(c, http.StatusUnprocessableEntity, response.CodeUnprocessable, err.Error(), nil)
+			return
+		}
+		response.InternalError(c, fmt.Sprintf("sensitivity analysis failed: %v", err))
+		return
+	}
+
+	response.Success(c, http.StatusOK, report)
+}
+
+// rerankRequest is the body for an in-memory rerank of a finished run.
+type rerankRequest struct {
+	Weights map[string]float64 `json:"weights"`
+	TopN    int                `json:"top_n"`
+}
+
+// Bounds for the rerank top_n parameter
+const maxRerankTopN = 1000
+
+// HandleRerankRun handles POST /api/v1/runs/:run_id/rerank. It re-scores a
+// completed run's sites in memory from its schema snapshot with the given
+// weight overrides and returns the new top_n ranking (default 50) next to
+// each site's stored rank. Stored recommendations are never touched. Runs
+// over more than Scoring.RerankMaxSites sites are rejected.
+func (h *RunHandler) HandleRerankRun(c *gin.Context) {
+	tenantID := c.MustGet("tenant_id").(uuid.UUID)
+	ctx := c.Request.Context()
+
+	runID, err := uuid.Parse(c.Param("run_id"))
+	if err != nil {
+		response.BadRequest(c, "invalid run_id format", nil)
+		return
+	}
+
+	var req rerankRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.BadRequest(c, fmt.Sprintf("invalid request body: %v", err), nil)
+		return
+	}
+	if req.TopN < 0 || req.TopN > maxRerankTopN {
+		response.BadRequest(c, fmt.Sprintf("top_n must be between 1 and %d", maxRerankTopN), gin.H{"field": "top_n"})
+		return
+	}
+
+	run, err := h.runRepo.GetByID(ctx, tenantID, runID)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to retrieve run: %v", err))
+		return
+	}
+	if run == nil {
+		response.NotFound(c, "run not found")
+		return
+	}
+	if !hasResults(run) {
+		response.Error(c, http.StatusUnprocessableEntity, response.CodeUnprocessable,
+			"only completed runs can be reranked", gin.H{"status": run.Status})
+		return
+	}
+
+	maxSites := h.cfg.Scoring.RerankMaxSites
+	upload, err := h.uploadRepo.GetByID(ctx, tenantID, run.UploadID)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to retrieve upload: %v", err))
+		return
+	}
+	if upload == nil {
+		response.NotFound(c, "upload not found")
+		return
+	}
+	if maxSites > 0 && upload.RowCount > maxSites {
+		response.Error(c, http.StatusUnprocessableEntity, response.CodeUnprocessable,
+			fmt.Sprintf("upload has %d sites; rerank is limited to %d", upload.RowCount, maxSites),
+			gin.H{"row_count": upload.RowCount, "max_sites": maxSites})
+		return
+	}
+
+	snapshot, err := h.schemaRepo.GetSnapshotByRun(ctx, runID)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to retrieve schema snapshot: %v", err))
+		return
+	}
+	if snapshot == nil {
+		response.Error(c, http.StatusUnprocessableEntity, response.CodeUnprocessable,
+			"run has no schema snapshot to rerank from", nil)
+		return
+	}
+
+	stored, err := h.recRepo.ListByRun(ctx, runID)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to retrieve recommendations: %v", err))
+		return
+	}
+
+	result, err := h.pipeline.Rerank(ctx, run, snapshot, stored, scoring.RerankOptions{
+		Weights:  req.Weights,
+		TopN:     req.TopN,
+		MaxSites: maxSites,
+	})
+	if err != nil {
+		var cfgErr *schema.ConfigError
+		switch {
+		case errors.As(err, &cfgErr):
+			response.BadRequest(c, fmt.Sprintf("invalid weights: %v", err), configErrorDetails(err))
+		case errors.Is(err, scoring.ErrTooManySites):
+			response.Error(c, http.StatusUnprocessableEntity, response.CodeUnprocessable, err.Error(), nil)
+		default:
+			response.InternalError(c, fmt.Sprintf("failed to rerank run: %v", err))
+		}
+		return
+	}
+
+	response.Success(c, http.StatusOK, result)
+}
+
+// hasResults reports whether run finished and stored recommendations: it
+// succeeded, or completed but skipped more sites than the skip tolerance.
+func hasResults(run *models.ScoringRun) bool {
+	return run.Status == "succeeded" || run.Status == "completed_with_errors"
+}
+
 // HandleGetRun handles GET /api/v1/runs/:run_id.
 func (h *RunHandler) HandleGetRun(c *gin.Context) {
 	tenantID := c.MustGet("tenant_id").(uuid.UUID)
@@ -181,3 +656,238 @@ func (h *RunHandler) HandleGetRun(c *gin.Context) {
 
 	response.Success(c, http.StatusOK, run)
 }
+
+// HandleListSkippedSites handles GET /api/v1/runs/:run_id/skipped. It lists
+// the sites the run could not parse or score, in upload order, with the
+// reason each was skipped. At most SCORING_MAX_SKIPPED_SITES are stored per
+// run, so the run's skipped_count may exceed the total listed.
+func (h *RunHandler) HandleListSkippedSites(c *gin.Context) {
+	tenantID := c.MustGet("tenant_id").(uuid.UUID)
+	ctx := c.Request.Context()
+
+	runIDStr := c.Param("run_id")
+	runID, err := uuid.Parse(runIDStr)
+	if err != nil {
+		response.BadRequest(c, "invalid run_id format", nil)
+		return
+	}
+
+	page, pageSize, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	run, err := h.runRepo.GetByID(ctx, tenantID, runID)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to retrieve run: %v", err))
+		return
+	}
+	if run == nil {
+		response.NotFound(c, "run not found")
+		return
+	}
+
+	sites, totalCount, err := h.skippedRepo.ListByRun(ctx, runID, page, pageSize)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to retrieve skipped sites: %v", err))
+		return
+	}
+
+	skippedCount := 0
+	if run.SkippedCount != nil {
+		skippedCount = *run.SkippedCount
+	}
+	totalPages := (totalCount + pageSize - 1) / pageSize
+
+	response.Success(c, http.StatusOK, gin.H{
+		"run_id":        run.ID,
+		"skipped_count": skippedCount,
+		"skipped_sites": sites,
+		"pagination": models.Pagination{
+			Page:         page,
+			PageSize:     pageSize,
+			TotalResults: totalCount,
+			TotalPages:   totalPages,
+		},
+	})
+}
+
+// HandleVerifyRun handles POST /api/v1/runs/:run_id/verify. It re-scores a
+// completed run from its schema snapshot and original site records and
+// reports any drift from the stored recommendations.
+func (h *RunHandler) HandleVerifyRun(c *gin.Context) {
+	tenantID := c.MustGet("tenant_id").(uuid.UUID)
+	ctx := c.Request.Context()
+
+	runIDStr := c.Param("run_id")
+	runID, err := uuid.Parse(runIDStr)
+	if err != nil {
+		response.BadRequest(c, "invalid run_id format", nil)
+		return
+	}
+
+	run, err := h.runRepo.GetByID(ctx, tenantID, runID)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to retrieve run: %v", err))
+		return
+	}
+	if run == nil {
+		response.NotFound(c, "run not found")
+		return
+	}
+	if !hasResults(run) {
+		response.Error(c, http.StatusUnprocessableEntity, response.CodeUnprocessable,
+			"only completed runs can be verified", gin.H{"status": run.Status})
+		return
+	}
+
+	snapshot, err := h.schemaRepo.GetSnapshotByRun(ctx, runID)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to retrieve schema snapshot: %v", err))
+		return
+	}
+	if snapshot == nil {
+		response.Error(c, http.StatusUnprocessableEntity, response.CodeUnprocessable,
+			"run has no schema snapshot to verify against", nil)
+		return
+	}
+
+	stored, err := h.recRepo.ListByRun(ctx, runID)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to retrieve recommendations: %v", err))
+		return
+	}
+
+	verification, err := h.pipeline.Verify(ctx, run, snapshot, stored)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to verify run: %v", err))
+		return
+	}
+
+	response.Success(c, http.StatusOK, verification)
+}
+
+// HandleListFailedRuns handles GET /api/v1/runs/failed. It lists the
+// tenant's runs that exhausted their retries, most recently failed first,
+// optionally narrowed by an error substring and a failure time window.
+func (h *RunHandler) HandleListFailedRuns(c *gin.Context) {
+	tenantID := c.MustGet("tenant_id").(uuid.UUID)
+
+	page, pageSize, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+	filter, ok := parseFailedRunFilter(c)
+	if !ok {
+		return
+	}
+
+	runs, totalCount, err := h.runRepo.ListFailed(c.Request.Context(), tenantID, filter, page, pageSize)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to retrieve failed runs: %v", err))
+		return
+	}
+
+	totalPages := (totalCount + pageSize - 1) / pageSize
+
+	response.Success(c, http.StatusOK, gin.H{
+		"runs": runs,
+		"pagination": models.Pagination{
+			Page:         page,
+			PageSize:     pageSize,
+			TotalResults: totalCount,
+			TotalPages:   totalPages,
+		},
+	})
+}
+
+// parseFailedRunFilter reads the error, since and until query parameters.
+// Times are RFC 3339 or a bare YYYY-MM-DD date, taken as midnight UTC. On
+// invalid input it writes a 400 response and returns ok=false.
+func parseFailedRunFilter(c *gin.Context) (filter repository.FailedRunFilter, ok bool) {
+	filter.ErrorContains = c.Query("error")
+
+	for _, param := range []struct {
+		name string
+		dst  *time.Time
+	}{
+		{"since", &filter.Since},
+		{"until", &filter.Until},
+	} {
+		value := c.Query(param.name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t, err = time.Parse(time.DateOnly, value)
+		}
+		if err != nil {
+			response.BadRequest(c, param.name+" must be an RFC 3339 timestamp or a YYYY-MM-DD date", nil)
+			return filter, false
+		}
+		*param.dst = t
+	}
+
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && !filter.Since.Before(filter.Until) {
+		response.BadRequest(c, "since must be before until", nil)
+		return filter, false
+	}
+
+	return filter, true
+}
+
+// HandleRequeueRun handles POST /api/v1/runs/:run_id/requeue. It resets a
+// failed run's attempt counter and error and dispatches it again, so it gets
+// a full set of retries.
+func (h *RunHandler) HandleRequeueRun(c *gin.Context) {
+	tenantID := c.MustGet("tenant_id").(uuid.UUID)
+	ctx := c.Request.Context()
+
+	runIDStr := c.Param("run_id")
+	runID, err := uuid.Parse(runIDStr)
+	if err != nil {
+		response.BadRequest(c, "invalid run_id format", nil)
+		return
+	}
+
+	run, err := h.runRepo.GetByID(ctx, tenantID, runID)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to retrieve run: %v", err))
+		return
+	}
+	if run == nil {
+		response.NotFound(c, "run not found")
+		return
+	}
+	if run.Status != "failed" {
+		response.Error(c, http.StatusConflict, response.CodeConflict,
+			"only failed runs can be requeued", gin.H{"status": run.Status})
+		return
+	}
+
+	// The status guard in Requeue settles a race with a concurrent requeue
+	correlationID, _ := c.Get("correlation_id")
+	correlationIDStr, _ := correlationID.(string)
+	requeued, err := h.runRepo.Requeue(ctx, tenantID, runID, h.pipeline.InstanceID(), correlationIDStr)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to requeue run: %v", err))
+		return
+	}
+	if !requeued {
+		response.Error(c, http.StatusConflict, response.CodeConflict,
+			"run was requeued concurrently", nil)
+		return
+	}
+
+	run, err = h.runRepo.GetByID(ctx, tenantID, runID)
+	if err != nil || run == nil {
+		response.InternalError(c, fmt.Sprintf("failed to retrieve requeued run: %v", err))
+		return
+	}
+
+	h.pipeline.Dispatch(run)
+
+	recordAudit(c, h.auditRepo, models.AuditActionRunRequeue, run.ID)
+	response.Success(c, http.StatusAccepted, run)
+}
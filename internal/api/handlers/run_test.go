@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+	"github.com/workforce-ai/site-selection-iq/internal/repository"
+)
+
+func TestRescoreRuns_OneRunPerValidUpload(t *testing.T) {
+	tenantID := uuid.New()
+	instanceID := uuid.New()
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	uploads := []models.Upload{
+		{ID: uuid.New(), TenantID: tenantID, ValidationStatus: "valid", RowCount: 120},
+		{ID: uuid.New(), TenantID: tenantID, ValidationStatus: "invalid", RowCount: 40},
+		{ID: uuid.New(), TenantID: tenantID, ValidationStatus: "valid", RowCount: 75},
+		{ID: uuid.New(), TenantID: tenantID, ValidationStatus: "pending"},
+	}
+
+	runs := rescoreRuns(uploads, "site-selection-iq-v1.0", instanceID, "corr-1", now)
+	require.Len(t, runs, 2)
+
+	assert.Equal(t, uploads[0].ID, runs[0].UploadID)
+	assert.Equal(t, uploads[2].ID, runs[1].UploadID)
+	assert.NotEqual(t, runs[0].ID, runs[1].ID)
+	for i, run := range runs {
+		assert.Equal(t, tenantID, run.TenantID)
+		assert.Equal(t, "queued", run.Status)
+		assert.Equal(t, "site-selection-iq-v1.0", run.ModelVersion)
+		assert.Equal(t, instanceID, run.InstanceID)
+		assert.Equal(t, "corr-1", run.CorrelationID)
+		assert.Equal(t, now, run.CreatedAt)
+		assert.Nil(t, run.ScoringConfig, "rescored runs use the tenant's current schema")
+		require.NotNil(t, run.RowCount)
+		assert.Equal(t, []int{120, 75}[i], *run.RowCount)
+	}
+}
+
+func TestRescoreRuns_NoValidUploads(t *testing.T) {
+	uploads := []models.Upload{{ID: uuid.New(), ValidationStatus: "invalid"}}
+	assert.Empty(t, rescoreRuns(uploads, "v", uuid.New(), "", time.Now()))
+	assert.Empty(t, rescoreRuns(nil, "v", uuid.New(), "", time.Now()))
+}
+
+func TestRescoreRuns_SkipsEmptyUploads(t *testing.T) {
+	uploads := []models.Upload{{ID: uuid.New(), ValidationStatus: "valid", RowCount: 0}}
+	assert.Empty(t, rescoreRuns(uploads, "v", uuid.New(), "", time.Now()))
+}
+
+func TestUnscorableReason(t *testing.T) {
+	assert.Equal(t, "", unscorableReason(&models.Upload{ValidationStatus: "valid", RowCount: 3}))
+	assert.Equal(t, "upload failed validation and cannot be scored",
+		unscorableReason(&models.Upload{ValidationStatus: "invalid", RowCount: 3}))
+	assert.Equal(t, "upload has no data rows to score",
+		unscorableReason(&models.Upload{ValidationStatus: "valid"}))
+}
+
+func TestWithPresetWeights_MergesUnderOwnWeights(t *testing.T) {
+	preset := map[string]float64{"avg_hourly_wage": 3, "unemployment_rate": 0.5}
+
+	merged, err := withPresetWeights(json.RawMessage(`{"weight_preset": "cost-focused", "weights": {"unemployment_rate": 2}, "score_scale": "ten"}`), preset)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"weight_preset": "cost-focused",
+		"weights": {"avg_hourly_wage": 3, "unemployment_rate": 2},
+		"score_scale": "ten"
+	}`, string(merged))
+
+	merged, err = withPresetWeights(nil, preset)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"weights": {"avg_hourly_wage": 3, "unemployment_rate": 0.5}}`, string(merged))
+}
+
+func TestWithRequestLocale(t *testing.T) {
+	localized, err := withRequestLocale(json.RawMessage(`{"score_scale": "ten"}`), "es-MX,en;q=0.8")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"score_scale": "ten", "locale": "es"}`, string(localized))
+
+	localized, err = withRequestLocale(json.RawMessage(`{"locale": "en"}`), "es")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"locale": "en"}`, string(localized), "the run's own locale wins")
+
+	localized, err = withRequestLocale(nil, "fr")
+	require.NoError(t, err)
+	assert.Nil(t, localized, "unsupported languages leave the config alone")
+}
+
+func TestPresetNamePattern(t *testing.T) {
+	for _, name := range []string{"cost-focused", "talent_focused", "v2.1", "A"} {
+		assert.True(t, presetNamePattern.MatchString(name), name)
+	}
+	for _, name := range []string{"", "-leading", "has space", "slash/name", string(make([]byte, 65))} {
+		assert.False(t, presetNamePattern.MatchString(name), name)
+	}
+}
+
+func TestOptionalRunBodies_RejectMalformedJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := &RunHandler{}
+	for name, handle := range map[string]gin.HandlerFunc{
+		"create run":  h.HandleCreateRun,
+		"sensitivity": h.HandleSensitivity,
+	} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"force": "yes"`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "upload_id", Value: uuid.NewString()}}
+		c.Set("tenant_id", uuid.New())
+
+		handle(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code, name)
+		assert.Contains(t, w.Body.String(), "invalid request body", name)
+	}
+}
+
+func TestParseFailedRunFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	parse := func(query string) (repository.FailedRunFilter, bool, int) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/runs/failed?"+query, nil)
+		filter, ok := parseFailedRunFilter(c)
+		return filter, ok, w.Code
+	}
+
+	filter, ok, _ := parse("")
+	assert.True(t, ok)
+	assert.Equal(t, repository.FailedRunFilter{}, filter)
+
+	filter, ok, _ = parse("error=timed+out&since=2026-03-10&until=2026-03-11T12:00:00Z")
+	assert.True(t, ok)
+	assert.Equal(t, "timed out", filter.ErrorContains)
+	assert.Equal(t, time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), filter.Since)
+	assert.Equal(t, time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC), filter.Until.UTC())
+
+	for _, query := range []string{"since=yesterday", "until=2026-13-01", "since=2026-03-11&until=2026-03-10"} {
+		_, ok, code := parse(query)
+		assert.False(t, ok, query)
+		assert.Equal(t, http.StatusBadRequest, code, query)
+	}
+}
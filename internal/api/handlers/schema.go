@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/workforce-ai/site-selection-iq/internal/api/response"
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+	"github.com/workforce-ai/site-selection-iq/internal/repository"
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
+)
+
+// SchemaHandler handles tenant schema configuration endpoints.
+type SchemaHandler struct {
+	schemaConfigRepo *repository.SchemaConfigRepository
+	schemaResolver   *schema.Resolver
+	auditRepo        *repository.AuditRepository
+}
+
+// NewSchemaHandler creates a new schema handler.
+func NewSchemaHandler(
+	schemaConfigRepo *repository.SchemaConfigRepository,
+	schemaResolver *schema.Resolver,
+	auditRepo *repository.AuditRepository,
+) *SchemaHandler {
+	return &SchemaHandler{
+		schemaConfigRepo: schemaConfigRepo,
+		schemaResolver:   schemaResolver,
+		auditRepo:        auditRepo,
+	}
+}
+
+// putTenantSchemaRequest is the body for replacing a tenant's schema override.
+type putTenantSchemaRequest struct {
+	Config      json.RawMessage `json:"config"`
+	Description string          `json:"description"`
+}
+
+// HandleGetTenantSchema handles GET /api/v1/schema-config.
+// It returns the tenant's active override (if any) and the resolved schema.
+func (h *SchemaHandler) HandleGetTenantSchema(c *gin.Context) {
+	tenantID := c.MustGet("tenant_id").(uuid.UUID)
+
+	resolution, err := h.schemaResolver.ResolveActive(c.Request.Context(), h.schemaConfigRepo, tenantID)
+	if err != nil {
+		respondResolveError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, gin.H{
+		"override": resolution.Tenant,
+		"resolved": resolution.Schema,
+	})
+}
+
+// HandlePutTenantSchema handles PUT /api/v1/schema-config.
+// The override is strictly validated and must resolve against the active
+// global config before it replaces the tenant's current override.
+func (h *SchemaHandler) HandlePutTenantSchema(c *gin.Context) {
+	tenantID := c.MustGet("tenant_id").(uuid.UUID)
+
+	var req putTenantSchemaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", nil)
+		return
+	}
+
+	if err := schema.ValidateTenantOverride(req.Config); err != nil {
+		response.BadRequest(c, fmt.Sprintf("invalid schema override: %v", err), configErrorDetails(err))
+		return
+	}
+
+	h.saveOverride(c, tenantID, req.Config, req.Description)
+}
+
+// HandleImportTenantSchema handles POST /api/v1/schema-config/import.
+// It builds a tenant override from a flat definition, one row per field,
+// sent as CSV (Content-Type text/csv) or as a JSON array of the same
+// columns, then validates and saves it like PUT does.
+func (h *SchemaHandler) HandleImportTenantSchema(c *gin.Context) {
+	tenantID := c.MustGet("tenant_id").(uuid.UUID)
+
+	var (
+		config json.RawMessage
+		err    error
+	)
+	switch c.ContentType() {
+	case "text/csv":
+		config, err = schema.ImportCSV(c.Request.Body)
+	case "application/json":
+		var raw json.RawMessage
+		raw, err = c.GetRawData()
+		if err != nil {
+			response.BadRequest(c, "invalid request body", nil)
+			return
+		}
+		config, err = schema.ImportJSON(raw)
+	default:
+		response.Error(c, http.StatusUnsupportedMediaType, response.CodeUnsupportedMedia,
+			"schema definition must be sent as text/csv or application/json", nil)
+		return
+	}
+	if err != nil {
+		response.BadRequest(c, fmt.Sprintf("invalid schema definition: %v", err), configErrorDetails(err))
+		return
+	}
+
+	h.saveOverride(c, tenantID, config, c.Query("description"))
+}
+
+// saveOverride resolves a validated tenant override against the active
+// global config and, if it resolves, replaces the tenant's override with
+// it and responds with the saved override and resolved schema.
+func (h *SchemaHandler) saveOverride(c *gin.Context, tenantID uuid.UUID, config json.RawMessage, description string) {
+	globalConfig, err := h.schemaConfigRepo.GetGlobalActive(c.Request.Context())
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to retrieve global schema config: %v", err))
+		return
+	}
+	if globalConfig == nil {
+		response.NotFound(c, "no active global schema config")
+		return
+	}
+
+	resolved, err := h.schemaResolver.Resolve(c.Request.Context(), globalConfig.Config, config)
+	if err != nil {
+		response.BadRequest(c, fmt.Sprintf("schema override does not resolve: %v", err), nil)
+		return
+	}
+
+	saved, err := h.schemaConfigRepo.UpsertTenant(c.Request.Context(), tenantID, config, description)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to save schema config: %v", err))
+		return
+	}
+	h.schemaResolver.Invalidate(tenantID)
+
+	recordAudit(c, h.auditRepo, models.AuditActionSchemaUpdate, saved.ID)
+	resp := gin.H{
+		"override": saved,
+		"resolved": resolved,
+	}
+	if warnings := resolved.WeightWarnings(); len(warnings) > 0 {
+		resp["warnings"] = warnings
+	}
+	response.Success(c, http.StatusOK, resp)
+}
+
+// HandlePreviewTenantSchema handles POST /api/v1/schema-config/preview.
+// It validates a proposed override like PUT does, resolves it alongside the
+// tenant's current override, and returns the field-level diff between the
+// two effective schemas without saving anything.
+func (h *SchemaHandler) HandlePreviewTenantSchema(c *gin.Context) {
+	tenantID := c.MustGet("tenant_id").(uuid.UUID)
+	ctx := c.Request.Context()
+
+	var req putTenantSchemaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", nil)
+		return
+	}
+
+	if err := schema.ValidateTenantOverride(req.Config); err != nil {
+		response.BadRequest(c, fmt.Sprintf("invalid schema override: %v", err), configErrorDetails(err))
+		return
+	}
+
+	current, err := h.schemaResolver.ResolveActive(ctx, h.schemaConfigRepo, tenantID)
+	if err != nil {
+		respondResolveError(c, err)
+		return
+	}
+	proposed, err := h.schemaResolver.Resolve(ctx, current.Global.Config, req.Config)
+	if err != nil {
+		response.BadRequest(c, fmt.Sprintf("schema override does not resolve: %v", err), nil)
+		return
+	}
+
+	resp := gin.H{
+		"diff":     schema.Diff(current.Schema, proposed),
+		"resolved": proposed,
+	}
+	if warnings := proposed.WeightWarnings(); len(warnings) > 0 {
+		resp["warnings"] = warnings
+	}
+	response.Success(c, http.StatusOK, resp)
+}
+
+// respondResolveError responds to a ResolveActive failure: 404 when no
+// global config is active, 500 otherwise.
+func respondResolveError(c *gin.Context, err error) {
+	if errors.Is(err, schema.ErrNoGlobalConfig) {
+		response.NotFound(c, "no active global schema config")
+		return
+	}
+	response.InternalError(c, err.Error())
+}
+
+// configErrorDetails returns response details naming the offending field of
+// a *schema.ConfigError, or nil when the error carries no field.
+func configErrorDetails(err error) interface{} {
+	var cfgErr *schema.ConfigError
+	if errors.As(err, &cfgErr) && cfgErr.Field != "" {
+		return gin.H{"field": cfgErr.Field}
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/workforce-ai/site-selection-iq/internal/api/middleware"
+	"github.com/workforce-ai/site-selection-iq/internal/api/response"
+	"github.com/workforce-ai/site-selection-iq/internal/config"
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+	"github.com/workforce-ai/site-selection-iq/internal/repository"
+	"github.com/workforce-ai/site-selection-iq/pkg/auth"
+)
+
+// maxServiceTokenBatch caps how many tokens one mint request may create.
+const maxServiceTokenBatch = 20
+
+// maxServiceTokenNameLength caps a service token's name, in characters.
+const maxServiceTokenNameLength = 100
+
+// ServiceTokenHandler handles service account token endpoints.
+type ServiceTokenHandler struct {
+	tokenRepo *repository.ServiceTokenRepository
+	auditRepo *repository.AuditRepository
+	jwtConfig config.JWTConfig
+}
+
+// NewServiceTokenHandler creates a new service token handler.
+func NewServiceTokenHandler(
+	tokenRepo *repository.ServiceTokenRepository,
+	auditRepo *repository.AuditRepository,
+	cfg *config.Config,
+) *ServiceTokenHandler {
+	return &ServiceTokenHandler{
+		tokenRepo: tokenRepo,
+		auditRepo: auditRepo,
+		jwtConfig: cfg.JWT,
+	}
+}
+
+// serviceTokenSpec describes one token to mint. Role defaults to viewer and
+// ExpiresInDays to the longest lifetime allowed.
+type serviceTokenSpec struct {
+	Name          string   `json:"name"`
+	Role          string   `json:"role"`
+	Scopes        []string `json:"scopes"`
+	ExpiresInDays int      `json:"expires_in_days"`
+}
+
+// mintServiceTokensRequest is the body of POST /api/v1/service-tokens.
+type mintServiceTokensRequest struct {
+	Tokens []serviceTokenSpec `json:"tokens"`
+}
+
+// mintedServiceToken is a recorded service token with the signed token,
+// which is only ever returned here.
+type mintedServiceToken struct {
+	models.ServiceToken
+	Token string `json:"token"`
+}
+
+// serviceTokenError reports which field of a mint request was rejected.
+type serviceTokenError struct {
+	field   string
+	message string
+}
+
+func (e *serviceTokenError) Error() string {
+	return fmt.Sprintf("%s: %s", e.field, e.message)
+}
+
+// HandleMintServiceTokens handles POST /api/v1/service-tokens.
+// It mints up to maxServiceTokenBatch long-lived tokens for the caller's
+// tenant and records them so they can be listed and revoked. Either every
+// token in the request is minted or none is.
+func (h *ServiceTokenHandler) HandleMintServiceTokens(c *gin.Context) {
+	tenantID := c.MustGet("tenant_id").(uuid.UUID)
+	callerID := c.MustGet("user_id").(uuid.UUID)
+
+	var req mintServiceTokensRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "invalid request body", nil)
+		return
+	}
+	if len(req.Tokens) == 0 || len(req.Tokens) > maxServiceTokenBatch {
+		response.BadRequest(c, fmt.Sprintf("tokens must list between 1 and %d tokens", maxServiceTokenBatch),
+			gin.H{"field": "tokens"})
+		return
+	}
+
+	// Every token of the batch is issued at the same second, so each
+	// recorded expires_at matches its token's exp claim exactly
+	now := time.Now().Truncate(time.Second)
+
+	minted := make([]mintedServiceToken, 0, len(req.Tokens))
+	records := make([]*models.ServiceToken, 0, len(req.Tokens))
+	for i, spec := range req.Tokens {
+		record, err := newServiceToken(spec, c.GetStringSlice("scopes"), h.jwtConfig.ServiceTokenMaxDays, now)
+		if err != nil {
+			var serr *serviceTokenError
+			if !errors.As(err, &serr) {
+				response.InternalError(c, fmt.Sprintf("failed to prepare service token: %v", err))
+				return
+			}
+			field := fmt.Sprintf("tokens.%d.%s", i, serr.field)
+			response.BadRequest(c, fmt.Sprintf("%s: %s", field, serr.message), gin.H{"field": field})
+			return
+		}
+		record.TenantID = tenantID
+		record.CreatedBy = callerID
+
+		token, err := signServiceToken(now, h.jwtConfig, record)
+		if err != nil {
+			response.InternalError(c, "failed to generate token")
+			return
+		}
+		records = append(records, record)
+		minted = append(minted, mintedServiceToken{Token: token})
+	}
+
+	if err := h.tokenRepo.CreateBatch(c.Request.Context(), records); err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to record service tokens: %v", err))
+		return
+	}
+
+	for i, record := range records {
+		minted[i].ServiceToken = *record
+		recordAudit(c, h.auditRepo, models.AuditActionServiceTokenCreate, record.ID)
+	}
+	response.Success(c, http.StatusCreated, gin.H{"tokens": minted})
+}
+
+// HandleListServiceTokens handles GET /api/v1/service-tokens.
+// Tokens are listed newest first, revoked and expired ones included; the
+// signed tokens themselves are never returned again.
+func (h *ServiceTokenHandler) HandleListServiceTokens(c *gin.Context) {
+	tenantID := c.MustGet("tenant_id").(uuid.UUID)
+
+	tokens, err := h.tokenRepo.ListByTenant(c.Request.Context(), tenantID)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to list service tokens: %v", err))
+		return
+	}
+
+	response.Success(c, http.StatusOK, gin.H{"service_tokens": tokens})
+}
+
+// HandleRevokeServiceToken handles DELETE /api/v1/service-tokens/:token_id.
+// A revoked token is rejected on its next request. Revoking it again is a
+// no-op that returns the token as revoked before.
+func (h *ServiceTokenHandler) HandleRevokeServiceToken(c *gin.Context) {
+	tenantID := c.MustGet("tenant_id").(uuid.UUID)
+
+	tokenID, err := uuid.Parse(c.Param("token_id"))
+	if err != nil {
+		response.BadRequest(c, "invalid token ID", nil)
+		return
+	}
+
+	token, err := h.tokenRepo.Revoke(c.Request.Context(), tenantID, tokenID)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to revoke service token: %v", err))
+		return
+	}
+	if token == nil {
+		response.NotFound(c, "service token not found")
+		return
+	}
+
+	recordAudit(c, h.auditRepo, models.AuditActionServiceTokenRevoke, token.ID)
+	response.Success(c, http.StatusOK, token)
+}
+
+// newServiceToken validates spec and returns the record of the token it
+// describes, issued at now. A caller whose own token carries scopes may
+// only mint scoped tokens within them, so minting cannot widen access.
+// Errors are *serviceTokenError values.
+func newServiceToken(spec serviceTokenSpec, callerScopes []string, maxDays int, now time.Time) (*models.ServiceToken, error) {
+	name := strings.TrimSpace(spec.Name)
+	if name == "" {
+		return nil, &serviceTokenError{"name", "is required"}
+	}
+	if len([]rune(name)) > maxServiceTokenNameLength {
+		return nil, &serviceTokenError{"name", fmt.Sprintf("must be at most %d characters", maxServiceTokenNameLength)}
+	}
+
+	role := spec.Role
+	if role == "" {
+		role = "viewer"
+	}
+	if !middleware.IsBuiltinRole(role) {
+		return nil, &serviceTokenError{"role", fmt.Sprintf("must be viewer, analyst or admin, got %q", spec.Role)}
+	}
+
+	held := make(map[string]bool, len(callerScopes))
+	for _, s := range callerScopes {
+		held[s] = true
+	}
+	if len(callerScopes) > 0 && len(spec.Scopes) == 0 {
+		return nil, &serviceTokenError{"scopes", "must be set, since the caller's token is scoped"}
+	}
+	seen := make(map[string]bool, len(spec.Scopes))
+	scopes := make([]string, 0, len(spec.Scopes))
+	for _, s := range spec.Scopes {
+		if !middleware.IsKnownScope(s) {
+			return nil, &serviceTokenError{"scopes", fmt.Sprintf("unknown scope %q; expected %s", s, strings.Join(middleware.KnownScopes(), ", "))}
+		}
+		if len(callerScopes) > 0 && !held[s] {
+			return nil, &serviceTokenError{"scopes", fmt.Sprintf("cannot grant %q, which the caller's token does not hold", s)}
+		}
+		if !seen[s] {
+			seen[s] = true
+			scopes = append(scopes, s)
+		}
+	}
+
+	days := spec.ExpiresInDays
+	if days == 0 {
+		days = maxDays
+	}
+	if days < 1 || days > maxDays {
+		return nil, &serviceTokenError{"expires_in_days", fmt.Sprintf("must be between 1 and %d, got %d", maxDays, spec.ExpiresInDays)}
+	}
+
+	return &models.ServiceToken{
+		ID:        uuid.New(),
+		Name:      name,
+		Role:      role,
+		Scopes:    scopes,
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Duration(days) * 24 * time.Hour),
+	}, nil
+}
+
+// signServiceToken signs the token record describes, issued at now and
+// expiring exactly at record.ExpiresAt. Its user_id and jti are the
+// record's ID.
+func signServiceToken(now time.Time, cfg config.JWTConfig, record *models.ServiceToken) (string, error) {
+	return auth.GenerateToken(cfg.Secret, cfg.Issuer, record.TenantID, record.ID, record.Role, 0,
+		auth.IssuedAt(now),
+		auth.ExpiresAt(record.ExpiresAt),
+		auth.ForAudience(cfg.Audience),
+		auth.GrantScopes(record.Scopes...),
+		auth.AsServiceAccount(record.ID),
+	)
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/api/middleware"
+	"github.com/workforce-ai/site-selection-iq/internal/config"
+	"github.com/workforce-ai/site-selection-iq/pkg/auth"
+)
+
+func TestNewServiceToken_Defaults(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	token, err := newServiceToken(serviceTokenSpec{Name: "  nightly export "}, nil, 365, now)
+	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, token.ID)
+	assert.Equal(t, "nightly export", token.Name)
+	assert.Equal(t, "viewer", token.Role, "least privilege by default")
+	assert.Empty(t, token.Scopes)
+	assert.Equal(t, now, token.CreatedAt)
+	assert.Equal(t, now.Add(365*24*time.Hour), token.ExpiresAt, "the longest lifetime by default")
+
+	token, err = newServiceToken(serviceTokenSpec{
+		Name:          "ingest bot",
+		Role:          "analyst",
+		Scopes:        []string{middleware.ScopeUploadsWrite, middleware.ScopeRunsWrite, middleware.ScopeUploadsWrite},
+		ExpiresInDays: 30,
+	}, nil, 365, now)
+	require.NoError(t, err)
+	assert.Equal(t, []string{middleware.ScopeUploadsWrite, middleware.ScopeRunsWrite}, token.Scopes, "duplicates are dropped")
+	assert.Equal(t, now.Add(30*24*time.Hour), token.ExpiresAt)
+}
+
+func TestNewServiceToken_Rejections(t *testing.T) {
+	scoped := []string{middleware.ScopeRunsWrite, middleware.ScopeTokensWrite}
+
+	tests := []struct {
+		name   string
+		spec   serviceTokenSpec
+		caller []string
+		field  string
+	}{
+		{"blank name", serviceTokenSpec{Name: " "}, nil, "name"},
+		{"unknown role", serviceTokenSpec{Name: "bot", Role: "owner"}, nil, "role"},
+		{"unknown scope", serviceTokenSpec{Name: "bot", Scopes: []string{"runs:delete"}}, nil, "scopes"},
+		{"lifetime over the maximum", serviceTokenSpec{Name: "bot", ExpiresInDays: 366}, nil, "expires_in_days"},
+		{"negative lifetime", serviceTokenSpec{Name: "bot", ExpiresInDays: -1}, nil, "expires_in_days"},
+		{"scoped caller minting an unscoped token", serviceTokenSpec{Name: "bot"}, scoped, "scopes"},
+		{"scoped caller granting a scope it lacks", serviceTokenSpec{Name: "bot", Scopes: []string{middleware.ScopeSchemaWrite}}, scoped, "scopes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newServiceToken(tt.spec, tt.caller, 365, time.Now())
+			var serr *serviceTokenError
+			require.True(t, errors.As(err, &serr), "got %v", err)
+			assert.Equal(t, tt.field, serr.field)
+		})
+	}
+
+	_, err := newServiceToken(serviceTokenSpec{Name: "bot", Scopes: []string{middleware.ScopeRunsWrite}}, scoped, 365, time.Now())
+	assert.NoError(t, err, "a scoped caller may grant scopes it holds")
+}
+
+func TestSignServiceToken(t *testing.T) {
+	cfg := config.JWTConfig{Secret: "service-token-test-secret", Issuer: "ssiq-test", Audience: "ssiq-api"}
+	issued := time.Now().Truncate(time.Second)
+
+	record, err := newServiceToken(serviceTokenSpec{Name: "ingest bot", Role: "analyst", Scopes: []string{middleware.ScopeUploadsWrite}, ExpiresInDays: 90}, nil, 365, issued)
+	require.NoError(t, err)
+	record.TenantID = uuid.New()
+
+	signed, err := signServiceToken(issued, cfg, record)
+	require.NoError(t, err)
+
+	claims, err := auth.ValidateToken(signed, cfg.Secret, auth.WithIssuer(cfg.Issuer), auth.WithAudience(cfg.Audience))
+	require.NoError(t, err)
+	assert.True(t, claims.ServiceAccount)
+	assert.Equal(t, record.ID.String(), claims.ID, "the jti is the recorded ID")
+	assert.Equal(t, record.ID, claims.UserID)
+	assert.Equal(t, record.TenantID, claims.TenantID)
+	assert.Equal(t, "analyst", claims.Role)
+	assert.Equal(t, []string{middleware.ScopeUploadsWrite}, claims.Scopes)
+	assert.True(t, record.ExpiresAt.Equal(claims.ExpiresAt.Time), "expires_at matches the exp claim")
+	assert.True(t, issued.Equal(claims.IssuedAt.Time))
+
+	// A stored expiry that is not a whole number of hours away is kept exact
+	record.ExpiresAt = issued.Add(90*24*time.Hour - 90*time.Minute)
+	signed, err = signServiceToken(issued, cfg, record)
+	require.NoError(t, err)
+	claims, err = auth.ValidateToken(signed, cfg.Secret, auth.WithIssuer(cfg.Issuer), auth.WithAudience(cfg.Audience))
+	require.NoError(t, err)
+	assert.True(t, record.ExpiresAt.Equal(claims.ExpiresAt.Time))
+}
@@ -1,15 +1,20 @@
 package handlers
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
+	"mime/multipart"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -20,6 +25,7 @@ import (
 	"github.com/workforce-ai/site-selection-iq/internal/models"
 	"github.com/workforce-ai/site-selection-iq/internal/repository"
 	"github.com/workforce-ai/site-selection-iq/internal/schema"
+	"github.com/workforce-ai/site-selection-iq/internal/storage"
 )
 
 // UploadHandler handles CSV file uploads.
@@ -28,29 +34,269 @@ type UploadHandler struct {
 	siteRecordRepo   *repository.SiteRecordRepository
 	schemaConfigRepo *repository.SchemaConfigRepository
 	idempotencyRepo  *repository.IdempotencyRepository
+	runRepo          *repository.RunRepository
 	schemaResolver   *schema.Resolver
+	processor        *ingest.Processor
+	scanner          ingest.MalwareScanner
+	auditRepo        *repository.AuditRepository
+	originals        storage.BlobStore // nil unless original files are retained
 	cfg              *config.Config
 }
 
-// NewUploadHandler creates a new upload handler.
+// NewUploadHandler creates a new upload handler. A nil scanner accepts
+// every file.
 func NewUploadHandler(
 	uploadRepo *repository.UploadRepository,
 	siteRecordRepo *repository.SiteRecordRepository,
 	schemaConfigRepo *repository.SchemaConfigRepository,
 	idempotencyRepo *repository.IdempotencyRepository,
+	runRepo *repository.RunRepository,
 	schemaResolver *schema.Resolver,
+	processor *ingest.Processor,
+	scanner ingest.MalwareScanner,
+	auditRepo *repository.AuditRepository,
 	cfg *config.Config,
 ) *UploadHandler {
+	if scanner == nil {
+		scanner = ingest.NoopScanner{}
+	}
 	return &UploadHandler{
 		uploadRepo:       uploadRepo,
 		siteRecordRepo:   siteRecordRepo,
 		schemaConfigRepo: schemaConfigRepo,
 		idempotencyRepo:  idempotencyRepo,
+		runRepo:          runRepo,
 		schemaResolver:   schemaResolver,
+		processor:        processor,
+		scanner:          scanner,
+		auditRepo:        auditRepo,
 		cfg:              cfg,
 	}
 }
 
+// SetOriginalStore retains each new upload's original file in store so it
+// can be downloaded later. Without a store originals are discarded once
+// parsed.
+func (h *UploadHandler) SetOriginalStore(store storage.BlobStore) {
+	h.originals = store
+}
+
+// acceptedFileType reports whether an upload's Content-Type or filename
+// extension is in the configured allow lists. Media type parameters (e.g.
+// charset) are ignored and both checks are case-insensitive.
+func acceptedFileType(cfg config.UploadConfig, contentType, filename string) bool {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		for _, allowed := range cfg.AllowedTypes {
+			if strings.EqualFold(mediaType, allowed) {
+				return true
+			}
+		}
+	}
+
+	ext := filepath.Ext(filename)
+	for _, allowed := range cfg.AllowedExtensions {
+		if ext != "" && strings.EqualFold(ext, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// useAsyncUpload decides whether an upload is processed in the background.
+// An explicit ?async=true|false wins; otherwise files of at least threshold
+// bytes go async. A non-positive threshold disables the size rule.
+func useAsyncUpload(asyncParam string, size, threshold int64) bool {
+	if async, err := strconv.ParseBool(asyncParam); err == nil {
+		return async
+	}
+	return threshold > 0 && size >= threshold
+}
+
+// isZipUpload reports whether an upload is a zip archive of CSVs rather
+// than a single CSV.
+func isZipUpload(contentType, filename string) bool {
+	if strings.EqualFold(filepath.Ext(filename), ".zip") {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/zip" || mediaType == "application/x-zip-compressed")
+}
+
+// duplicateUploadResponse builds the response for an upload whose content
+// matches an existing upload. latestRun is the existing upload's latest
+// successful run, or nil if it has none.
+func duplicateUploadResponse(existing *models.Upload, latestRun *models.ScoringRun) gin.H {
+	resp := gin.H{
+		"upload_id":         existing.ID,
+		"tenant_id":         existing.TenantID,
+		"filename":          existing.Filename,
+		"row_count":         existing.RowCount,
+		"schema_version":    existing.SchemaVersion,
+		"validation_status": existing.ValidationStatus,
+		"content_hash":      existing.ContentHash,
+		"created_at":        existing.CreatedAt,
+		"duplicate":         true,
+		"latest_run":        nil,
+	}
+
+	if latestRun == nil {
+		resp["message"] = "File already uploaded; returning existing upload. It has no completed scoring run yet."
+		return resp
+	}
+
+	resp["latest_run"] = gin.H{
+		"run_id":       latestRun.ID,
+		"status":       latestRun.Status,
+		"completed_at": latestRun.CompletedAt,
+	}
+	resp["message"] = "File already uploaded; returning existing upload and its latest completed run."
+	return resp
+}
+
+// checkUploadFile validates an uploaded file's type and size against
+// config, responding with the error and returning false if it is rejected.
+func (h *UploadHandler) checkUploadFile(c *gin.Context, file *multipart.FileHeader) bool {
+	// Validate file type (content-type or extension) against config
+	if !acceptedFileType(h.cfg.Upload, file.Header.Get("Content-Type"), file.Filename) {
+		response.BadRequest(c, fmt.Sprintf("unsupported file type; accepted types: %s; accepted extensions: %s",
+			strings.Join(h.cfg.Upload.AllowedTypes, ", "), strings.Join(h.cfg.Upload.AllowedExtensions, ", ")),
+			gin.H{
+				"accepted_types":      h.cfg.Upload.AllowedTypes,
+				"accepted_extensions": h.cfg.Upload.AllowedExtensions,
+			})
+		return false
+	}
+
+	// Validate file size (413 per spec)
+	if file.Size > h.cfg.Upload.MaxFileSize {
+		response.Error(c, http.StatusRequestEntityTooLarge, response.CodeFileTooLarge,
+			fmt.Sprintf("file exceeds max size of %d bytes", h.cfg.Upload.MaxFileSize), nil)
+		return false
+	}
+
+	return true
+}
+
+// saveUploadFile copies an uploaded file to path, creating its directory,
+// and returns the SHA-256 hash of its content. A partially written file is
+// removed on failure.
+func saveUploadFile(file *multipart.FileHeader, path string) (string, error) {
+	src, err := file.Open()
+	if err != nil {
+		return "", errors.New("failed to open uploaded file")
+	}
+	defer src.Close()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", errors.New("failed to create temp directory")
+	}
+	dst, err := os.Create(path)
+	if err != nil {
+		return "", errors.New("failed to create temp file")
+	}
+	defer dst.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(dst, hasher), src); err != nil {
+		os.Remove(path)
+		return "", errors.New("failed to save file")
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// scanUploadFile runs the malware scanner over a saved upload. A file that
+// is not clean is rejected with 400 and the scanner's details; a scan that
+// fails outright is reported as 503, since the file's safety is unknown.
+// Either way the file is removed and false returned.
+//
+// Recommended scanners are ClamAV (self-hosted, see ingest.ClamAVScanner)
+// or a managed service such as Google Cloud DLP behind the same interface.
+func scanUploadFile(c *gin.Context, scanner ingest.MalwareScanner, path string) bool {
+	clean, details, err := scanner.Scan(path)
+	if err != nil {
+		os.Remove(path)
+		response.Error(c, http.StatusServiceUnavailable, response.CodeScanUnavailable,
+			fmt.Sprintf("malware scan failed: %v", err), nil)
+		return false
+	}
+	if !clean {
+		os.Remove(path)
+		response.BadRequest(c, "file rejected by security scan", gin.H{"scan_details": details})
+		return false
+	}
+	return true
+}
+
+// originalKey is the blob key an upload's original file is retained under.
+func originalKey(upload *models.Upload) string {
+	return upload.TenantID.String() + "/" + upload.ID.String()
+}
+
+// retainOriginal copies the saved upload file at path into store.
+func retainOriginal(ctx context.Context, store storage.BlobStore, upload *models.Upload, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return store.Put(ctx, originalKey(upload), f)
+}
+
+// serveOriginal streams an upload's retained original file as an
+// attachment under its uploaded filename.
+func serveOriginal(c *gin.Context, store storage.BlobStore, upload *models.Upload) {
+	if store == nil {
+		response.NotFound(c, "original files are not retained on this server")
+		return
+	}
+
+	rc, err := store.Get(c.Request.Context(), originalKey(upload))
+	if errors.Is(err, storage.ErrNotFound) {
+		response.NotFound(c, "original file not retained for this upload")
+		return
+	}
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to retrieve original file: %v", err))
+		return
+	}
+	defer rc.Close()
+
+	contentType := "text/csv"
+	if isZipUpload("", upload.Filename) {
+		contentType = "application/zip"
+	}
+	headers := map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": upload.Filename}),
+	}
+	c.DataFromReader(http.StatusOK, -1, contentType, rc, headers)
+}
+
+// minimalGlobalSchema stands in for the global config when none can be loaded.
+var minimalGlobalSchema = json.RawMessage(`{"fields":{"site_id":{"type":"identifier","required":true}},"site_id_column":"site_id"}`)
+
+// resolveSchema resolves the tenant's active schema over the global one,
+// falling back to a minimal site_id-only schema if no global config exists.
+func (h *UploadHandler) resolveSchema(ctx context.Context, tenantID uuid.UUID) (*schema.ResolvedSchema, error) {
+	resolution, err := h.schemaResolver.ResolveActive(ctx, h.schemaConfigRepo, tenantID)
+	var resolveErr *schema.ResolveError
+	switch {
+	case errors.As(err, &resolveErr):
+		return nil, err
+	case err != nil:
+		// No usable global config: fall back as before, without caching
+		var tenantConfigBytes json.RawMessage
+		if tenantConfig, _ := h.schemaConfigRepo.GetTenantActive(ctx, tenantID); tenantConfig != nil {
+			tenantConfigBytes = tenantConfig.Config
+		}
+		resolvedSchema, err := h.schemaResolver.Resolve(ctx, minimalGlobalSchema, tenantConfigBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve schema: %v", err)
+		}
+		return resolvedSchema, nil
+	}
+	return resolution.Schema, nil
+}
+
 // HandleUpload handles POST /api/v1/uploads.
 func (h *UploadHandler) HandleUpload(c *gin.Context) {
 	tenantID := c.MustGet("tenant_id").(uuid.UUID)
@@ -66,7 +312,7 @@ func (h *UploadHandler) HandleUpload(c *gin.Context) {
 		}
 		if claim.AlreadyExists {
 			existing, _ := h.uploadRepo.GetByID(c.Request.Context(), tenantID, claim.ResourceID)
-			response.Conflict(c, "duplicate upload (idempotency key match)", existing)
+			respondConflict(c, "duplicate upload (idempotency key match)", existing, uploadConflictDetails(existing))
 			return
 		}
 	}
@@ -78,104 +324,37 @@ func (h *UploadHandler) HandleUpload(c *gin.Context) {
 		return
 	}
 
-	// Validate file type (content-type + extension)
-	if file.Header.Get("Content-Type") != "text/csv" && filepath.Ext(file.Filename) != ".csv" {
-		response.BadRequest(c, "file must be a CSV", nil)
-		return
-	}
-
-	// Validate file size (413 per spec)
-	if file.Size > h.cfg.Upload.MaxFileSize {
-		response.Error(c, http.StatusRequestEntityTooLarge, "FILE_TOO_LARGE",
-			fmt.Sprintf("file exceeds max size of %d bytes", h.cfg.Upload.MaxFileSize), nil)
-		return
-	}
-
-	// Open uploaded file
-	src, err := file.Open()
-	if err != nil {
-		response.InternalError(c, "failed to open uploaded file")
-		return
-	}
-	defer src.Close()
-
-	// Save to temp directory
-	if err := os.MkdirAll(h.cfg.Upload.TempDir, 0755); err != nil {
-		response.InternalError(c, "failed to create temp directory")
+	if !h.checkUploadFile(c, file) {
 		return
 	}
 
+	// Save to temp storage, hashing the content for deduplication
 	tempPath := filepath.Join(h.cfg.Upload.TempDir, uploadID.String()+".csv")
-	tempFile, err := os.Create(tempPath)
+	contentHash, err := saveUploadFile(file, tempPath)
 	if err != nil {
-		response.InternalError(c, "failed to create temp file")
+		response.InternalError(c, err.Error())
 		return
 	}
-	defer tempFile.Close()
 
-	if _, err := io.Copy(tempFile, src); err != nil {
+	// Check for duplicate content within this tenant — if the same file
+	// was already uploaded, return the existing upload and a reference to
+	// its latest successful run instead of creating a new one.
+	existing, err := h.uploadRepo.GetByContentHash(c.Request.Context(), tenantID, contentHash)
+	if err == nil && existing != nil {
 		os.Remove(tempPath)
-		response.InternalError(c, "failed to save file")
+		latestRun, err := h.runRepo.GetLatestByUpload(c.Request.Context(), tenantID, existing.ID)
+		if err != nil {
+			response.InternalError(c, fmt.Sprintf("failed to look up existing run: %v", err))
+			return
+		}
+		response.Success(c, http.StatusOK, duplicateUploadResponse(existing, latestRun))
 		return
 	}
 
-	// Compute SHA-256 content hash for deduplication
-	hashFile, err := os.Open(tempPath)
-	if err != nil {
-		os.Remove(tempPath)
-		response.InternalError(c, "failed to reopen file for hashing")
-		return
-	}
-	hasher := sha256.New()
-	if _, err := io.Copy(hasher, hashFile); err != nil {
-		hashFile.Close()
-		os.Remove(tempPath)
-		response.InternalError(c, "failed to hash file")
+	// Scan for malware after the file is saved and before it is parsed
+	if !scanUploadFile(c, h.scanner, tempPath) {
 		return
 	}
-	hashFile.Close()
-	contentHash := hex.EncodeToString(hasher.Sum(nil))
-
-	// Check for duplicate content within this tenant — if the same file
-	// was already uploaded, return the existing upload (and its results)
-	// instead of creating a new one.
-	existing, err := h.uploadRepo.GetByContentHash(c.Request.Context(), tenantID, contentHash)
-	if err == nil && existing != nil {
-		os.Remove(tempPath)
-		response.Success(c, http.StatusOK, gin.H{
-			"upload_id":         existing.ID,
-			"tenant_id":         existing.TenantID,
-			"filename":          existing.Filename,
-			"row_count":         existing.RowCount,
-			"schema_version":    existing.SchemaVersion,
-			"validation_status": existing.ValidationStatus,
-			"content_hash":      existing.ContentHash,
-			"created_at":        existing.CreatedAt,
-			"duplicate":         true,
-			"message":           "File already uploaded; returning existing upload and its results.",
-		})
-		return
-	}
-
-	// ──────────────────────────────────────────────────────────────────
-	// VIRUS / MALWARE SCAN INSERTION POINT
-	//
-	// In production, insert a call to the malware scanning service here,
-	// AFTER the file has been saved to temp storage and BEFORE CSV parsing
-	// begins. The scan blocks until completion; a failed scan rejects the
-	// upload with a 400 error:
-	//
-	//   scanResult, err := malwareScanner.Scan(tempPath)
-	//   if err != nil || !scanResult.Clean {
-	//       os.Remove(tempPath)
-	//       response.BadRequest(c, "file rejected by security scan", scanResult.Details)
-	//       return
-	//   }
-	//
-	// Recommended integration: ClamAV (self-hosted) or Google Cloud DLP
-	// (managed). The scanning interface should accept an io.Reader or file
-	// path and return (clean bool, details []string, err error).
-	// ──────────────────────────────────────────────────────────────────
 
 	// Create upload record
 	now := time.Now()
@@ -201,176 +380,334 @@ func (h *UploadHandler) HandleUpload(c *gin.Context) {
 		UpdatedAt:        now,
 	}
 
+	// Keep the original before the temp file is handed to the parser,
+	// which removes it when done
+	if h.originals != nil {
+		if err := retainOriginal(c.Request.Context(), h.originals, upload, tempPath); err != nil {
+			os.Remove(tempPath)
+			response.InternalError(c, fmt.Sprintf("failed to retain original file: %v", err))
+			return
+		}
+	}
+
 	if err := h.uploadRepo.Create(c.Request.Context(), upload); err != nil {
 		os.Remove(tempPath)
+		if h.originals != nil {
+			h.originals.Delete(c.Request.Context(), originalKey(upload))
+		}
 		response.InternalError(c, fmt.Sprintf("failed to create upload record: %v", err))
 		return
 	}
 
-	// Resolve schema
-	globalConfig, err := h.schemaConfigRepo.GetGlobalActive(c.Request.Context())
-	if err != nil || globalConfig == nil {
-		globalConfig = &models.SchemaConfig{
-			Config: json.RawMessage(`{"fields":{"site_id":{"type":"identifier","required":true}},"site_id_column":"site_id"}`),
-		}
+	resolvedSchema, err := h.resolveSchema(c.Request.Context(), tenantID)
+	if err != nil {
+		os.Remove(tempPath)
+		response.InternalError(c, err.Error())
+		return
 	}
 
-	tenantConfig, _ := h.schemaConfigRepo.GetTenantActive(c.Request.Context(), tenantID)
-	var tenantConfigBytes json.RawMessage
-	if tenantConfig != nil {
-		tenantConfigBytes = tenantConfig.Config
+	job := ingest.Job{
+		Upload: upload,
+		Path:   tempPath,
+		IsZip:  isZipUpload(file.Header.Get("Content-Type"), file.Filename),
+		Schema: resolvedSchema,
 	}
 
-	resolvedSchema, err := h.schemaResolver.Resolve(c.Request.Context(), globalConfig.Config, tenantConfigBytes)
+	// Large files are parsed in the background; the client polls
+	// GET /uploads/:upload_id until status is completed or failed.
+	if useAsyncUpload(c.Query("async"), file.Size, h.cfg.Upload.AsyncThreshold) {
+		resp := gin.H{
+			"upload_id":         upload.ID,
+			"tenant_id":         upload.TenantID,
+			"filename":          upload.Filename,
+			"status":            upload.Status,
+			"validation_status": upload.ValidationStatus,
+			"created_at":        upload.CreatedAt,
+			"status_url":        fmt.Sprintf("/api/v1/uploads/%s", upload.ID),
+		}
+		h.processor.Dispatch(job)
+		recordAudit(c, h.auditRepo, models.AuditActionUploadCreate, upload.ID)
+		response.Success(c, http.StatusAccepted, resp)
+		return
+	}
+
+	result, err := h.processor.Process(c.Request.Context(), job)
 	if err != nil {
-		os.Remove(tempPath)
-		response.InternalError(c, fmt.Sprintf("failed to resolve schema: %v", err))
+		var vErr *ingest.ValidationError
+		if errors.As(err, &vErr) {
+			// Skipped-row warnings explain an upload with no valid rows
+			var details interface{}
+			if errors.Is(err, ingest.ErrNoDataRows) && result != nil && len(result.Warnings) > 0 {
+				details = gin.H{"validation_warnings": result.Warnings}
+			}
+			response.BadRequest(c, err.Error(), details)
+			return
+		}
+		response.InternalError(c, err.Error())
 		return
 	}
 
-	// Reopen file for parsing
-	csvFile, err := os.Open(tempPath)
+	// Build response matching case study spec (includes validation_warnings)
+	uploadResponse := gin.H{
+		"upload_id":           upload.ID,
+		"tenant_id":           upload.TenantID,
+		"filename":            upload.Filename,
+		"row_count":           upload.RowCount,
+		"schema_version":      upload.SchemaVersion,
+		"validation_status":   upload.ValidationStatus,
+		"validation_warnings": result.Warnings,
+		"created_at":          upload.CreatedAt,
+	}
+	if result.Files != nil {
+		uploadResponse["files"] = result.Files
+	}
+
+	recordAudit(c, h.auditRepo, models.AuditActionUploadCreate, upload.ID)
+	response.Success(c, http.StatusCreated, uploadResponse)
+}
+
+// appendedContentHash chains an upload's content hash with the hash of a
+// file appended to it, so the result identifies the full sequence of
+// files. An upload without a hash contributes an empty prefix.
+func appendedContentHash(previous *string, appended string) string {
+	var prefix string
+	if previous != nil {
+		prefix = *previous
+	}
+	sum := sha256.Sum256([]byte(prefix + ":" + appended))
+	return hex.EncodeToString(sum[:])
+}
+
+// appendHasRunsMessage refuses an append to an upload that has been scored.
+const appendHasRunsMessage = "upload already has scoring runs; upload a new file instead so existing runs stay reproducible"
+
+// HandleAppend handles POST /api/v1/uploads/:upload_id/append. The CSV's
+// rows are validated against the current schema and added to the existing
+// upload. Uploads that already have scoring runs are refused so those runs
+// stay reproducible. The checks are repeated when the rows are stored, with
+// the upload locked, so a run created or another append made meanwhile also
+// gets a 409.
+func (h *UploadHandler) HandleAppend(c *gin.Context) {
+	tenantID := c.MustGet("tenant_id").(uuid.UUID)
+
+	uploadID, err := uuid.Parse(c.Param("upload_id"))
 	if err != nil {
-		response.InternalError(c, "failed to reopen file for parsing")
+		response.BadRequest(c, "invalid upload_id format", nil)
 		return
 	}
-	defer csvFile.Close()
 
-	// Parse and validate CSV
-	records, parseWarnings, err := ingest.Parse(csvFile, resolvedSchema)
+	upload, err := h.uploadRepo.GetByID(c.Request.Context(), tenantID, uploadID)
 	if err != nil {
-		os.Remove(tempPath)
-		upload.ValidationStatus = "invalid"
-		upload.UpdatedAt = time.Now()
-		_ = h.uploadRepo.Update(c.Request.Context(), upload)
-		response.BadRequest(c, fmt.Sprintf("CSV validation failed: %v", err), nil)
-		return
-	}
-
-	// Capture validation warnings
-	warningsJSON, _ := json.Marshal(parseWarnings)
-	upload.Warnings = warningsJSON
-
-	// Build site records
-	siteRecords := make([]models.SiteRecord, len(records))
-	for i, recordData := range records {
-		var dataMap map[string]interface{}
-		siteID := fmt.Sprintf("row_%d", i+1)
-		var siteName, location string
-		if err := json.Unmarshal(recordData, &dataMap); err == nil {
-			// Extract site_id from the schema-configured column
-			if sid, ok := dataMap[resolvedSchema.SiteIDColumn]; ok {
-				siteID = fmt.Sprintf("%v", sid)
-			}
+		response.InternalError(c, fmt.Sprintf("failed to retrieve upload: %v", err))
+		return
+	}
+	if upload == nil {
+		response.NotFound(c, "upload not found")
+		return
+	}
+	if upload.Status != "completed" {
+		response.Error(c, http.StatusConflict, response.CodeConflict,
+			fmt.Sprintf("upload is %s; rows can only be appended to a completed upload", upload.Status), nil)
+		return
+	}
 
-			// Extract site_name: try city first, fall back to site_id
-			if sn, ok := dataMap["city"]; ok {
-				siteName = fmt.Sprintf("%v", sn)
-			} else if sn, ok := dataMap["site_name"]; ok {
-				siteName = fmt.Sprintf("%v", sn)
-			} else {
-				siteName = siteID
-			}
+	runCount, err := h.runRepo.CountByUpload(c.Request.Context(), tenantID, uploadID)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to check upload runs: %v", err))
+		return
+	}
+	if runCount > 0 {
+		response.Error(c, http.StatusConflict, response.CodeConflict, appendHasRunsMessage, gin.H{"run_count": runCount})
+		return
+	}
 
-			// Extract location: try "city, state", fall back to site_id
-			if st, ok := dataMap["state"]; ok {
-				if city, ok := dataMap["city"]; ok {
-					location = fmt.Sprintf("%v, %v", city, st)
-				} else {
-					location = fmt.Sprintf("%v", st)
-				}
-			} else if loc, ok := dataMap["location"]; ok {
-				location = fmt.Sprintf("%v", loc)
-			} else {
-				location = siteID
-			}
+	file, err := c.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, "file field is required", nil)
+		return
+	}
+	if !h.checkUploadFile(c, file) {
+		return
+	}
+	if isZipUpload(file.Header.Get("Content-Type"), file.Filename) {
+		response.BadRequest(c, "only a single CSV file can be appended", nil)
+		return
+	}
 
-			// Build type-coerced data: convert string values to proper
-			// types based on schema field definitions
-			coerced := make(map[string]interface{})
-			for k, v := range dataMap {
-				strVal, isStr := v.(string)
-				if !isStr {
-					coerced[k] = v
-					continue
-				}
-				// Check if this field has a numeric type in the schema
-				if fieldDef, exists := resolvedSchema.Fields[k]; exists {
-					switch fieldDef.Type {
-					case "percentage", "index", "numeric", "population":
-						if f, err := strconv.ParseFloat(strVal, 64); err == nil {
-							coerced[k] = f
-							continue
-						}
-					case "integer":
-						if f, err := strconv.ParseFloat(strVal, 64); err == nil {
-							coerced[k] = int64(f)
-							continue
-						}
-					}
-				}
-				coerced[k] = v // keep original string for text/identifier/unknown
-			}
-			coercedJSON, _ := json.Marshal(coerced)
-
-			siteRecords[i] = models.SiteRecord{
-				ID:        uuid.New(),
-				UploadID:  uploadID,
-				TenantID:  tenantID,
-				SiteID:    siteID,
-				SiteName:  siteName,
-				Location:  location,
-				RawData:   recordData,
-				Data:      coercedJSON,
-				CreatedAt: now,
-			}
-		} else {
-			siteRecords[i] = models.SiteRecord{
-				ID:        uuid.New(),
-				UploadID:  uploadID,
-				TenantID:  tenantID,
-				SiteID:    siteID,
-				RawData:   recordData,
-				Data:      recordData,
-				CreatedAt: now,
-			}
-		}
+	tempPath := filepath.Join(h.cfg.Upload.TempDir, uuid.New().String()+".csv")
+	fileHash, err := saveUploadFile(file, tempPath)
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+	if !scanUploadFile(c, h.scanner, tempPath) {
+		return
 	}
 
-	if err := h.siteRecordRepo.BulkInsert(c.Request.Context(), siteRecords); err != nil {
+	resolvedSchema, err := h.resolveSchema(c.Request.Context(), tenantID)
+	if err != nil {
 		os.Remove(tempPath)
-		upload.ValidationStatus = "invalid"
-		upload.UpdatedAt = time.Now()
-		_ = h.uploadRepo.Update(c.Request.Context(), upload)
-		response.InternalError(c, fmt.Sprintf("failed to insert site records: %v", err))
+		response.InternalError(c, err.Error())
 		return
 	}
 
-	// Update upload as valid
-	upload.ValidationStatus = "valid"
-	upload.Status = "completed"
-	upload.RowCount = len(records)
-	upload.SchemaVersion = "v1.0"
-	upload.UpdatedAt = time.Now()
-
-	if err := h.uploadRepo.Update(c.Request.Context(), upload); err != nil {
-		response.InternalError(c, fmt.Sprintf("failed to update upload: %v", err))
+	existing, err := h.siteRecordRepo.GetByUpload(c.Request.Context(), uploadID)
+	if err != nil {
+		os.Remove(tempPath)
+		response.InternalError(c, fmt.Sprintf("failed to retrieve site records: %v", err))
 		return
 	}
 
-	os.Remove(tempPath)
+	previousCount := upload.RowCount
+	result, err := h.processor.Append(c.Request.Context(), ingest.AppendJob{
+		Job:         ingest.Job{Upload: upload, Path: tempPath, Schema: resolvedSchema},
+		Existing:    existing,
+		ContentHash: appendedContentHash(upload.ContentHash, fileHash),
+	})
+	if err != nil {
+		var vErr *ingest.ValidationError
+		if errors.As(err, &vErr) {
+			var details interface{}
+			if result != nil && len(result.Warnings) > 0 {
+				details = gin.H{"validation_warnings": result.Warnings}
+			}
+			response.BadRequest(c, err.Error(), details)
+			return
+		}
+		if errors.Is(err, repository.ErrUploadHasRuns) {
+			response.Error(c, http.StatusConflict, response.CodeConflict, appendHasRunsMessage, nil)
+			return
+		}
+		if errors.Is(err, repository.ErrUploadChanged) {
+			response.Error(c, http.StatusConflict, response.CodeConflict,
+				"upload changed while the rows were being appended; fetch it and retry", nil)
+			return
+		}
+		response.InternalError(c, err.Error())
+		return
+	}
 
-	// Build response matching case study spec (includes validation_warnings)
-	uploadResponse := gin.H{
+	recordAudit(c, h.auditRepo, models.AuditActionUploadAppend, upload.ID)
+	response.Success(c, http.StatusOK, gin.H{
 		"upload_id":           upload.ID,
 		"tenant_id":           upload.TenantID,
 		"filename":            upload.Filename,
+		"appended_filename":   file.Filename,
+		"appended_rows":       upload.RowCount - previousCount,
 		"row_count":           upload.RowCount,
 		"schema_version":      upload.SchemaVersion,
 		"validation_status":   upload.ValidationStatus,
-		"validation_warnings": parseWarnings,
-		"created_at":          upload.CreatedAt,
+		"validation_warnings": result.Warnings,
+		"content_hash":        upload.ContentHash,
+		"updated_at":          upload.UpdatedAt,
+	})
+}
+
+// HandleGetUpload handles GET /api/v1/uploads/:upload_id.
+func (h *UploadHandler) HandleGetUpload(c *gin.Context) {
+	tenantID := c.MustGet("tenant_id").(uuid.UUID)
+
+	uploadID, err := uuid.Parse(c.Param("upload_id"))
+	if err != nil {
+		response.BadRequest(c, "invalid upload_id format", nil)
+		return
 	}
 
-	response.Success(c, http.StatusCreated, uploadResponse)
+	upload, err := h.uploadRepo.GetByID(c.Request.Context(), tenantID, uploadID)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to retrieve upload: %v", err))
+		return
+	}
+	if upload == nil {
+		response.NotFound(c, "upload not found")
+		return
+	}
+
+	response.Success(c, http.StatusOK, upload)
+}
+
+// HandleGetOriginal handles GET /api/v1/uploads/:upload_id/original,
+// returning the file exactly as it was uploaded. Rows appended later are
+// not included.
+func (h *UploadHandler) HandleGetOriginal(c *gin.Context) {
+	tenantID := c.MustGet("tenant_id").(uuid.UUID)
+
+	uploadID, err := uuid.Parse(c.Param("upload_id"))
+	if err != nil {
+		response.BadRequest(c, "invalid upload_id format", nil)
+		return
+	}
+
+	upload, err := h.uploadRepo.GetByID(c.Request.Context(), tenantID, uploadID)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to retrieve upload: %v", err))
+		return
+	}
+	if upload == nil {
+		response.NotFound(c, "upload not found")
+		return
+	}
+
+	serveOriginal(c, h.originals, upload)
+}
+
+// HandleGetRecords handles GET /api/v1/uploads/:upload_id/records.
+func (h *UploadHandler) HandleGetRecords(c *gin.Context) {
+	tenantID := c.MustGet("tenant_id").(uuid.UUID)
+
+	uploadID, err := uuid.Parse(c.Param("upload_id"))
+	if err != nil {
+		response.BadRequest(c, "invalid upload_id format", nil)
+		return
+	}
+
+	page, pageSize, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	// Verify upload exists and belongs to tenant
+	upload, err := h.uploadRepo.GetByID(c.Request.Context(), tenantID, uploadID)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to retrieve upload: %v", err))
+		return
+	}
+	if upload == nil {
+		response.NotFound(c, "upload not found")
+		return
+	}
+
+	records, totalCount, err := h.siteRecordRepo.GetByUploadPaginated(c.Request.Context(), upload.TenantID, uploadID, page, pageSize)
+	if err != nil {
+		response.InternalError(c, fmt.Sprintf("failed to retrieve site records: %v", err))
+		return
+	}
+
+	totalPages := (totalCount + pageSize - 1) / pageSize
+
+	response.Success(c, http.StatusOK, gin.H{
+		"upload_id": uploadID,
+		"records":   siteRecordResponses(records),
+		"pagination": models.Pagination{
+			Page:         page,
+			PageSize:     pageSize,
+			TotalResults: totalCount,
+			TotalPages:   totalPages,
+		},
+	})
+}
+
+// siteRecordResponses builds the records-endpoint shape: identifying
+// fields plus the type-coerced data the scoring pipeline will see.
+func siteRecordResponses(records []models.SiteRecord) []gin.H {
+	out := make([]gin.H, len(records))
+	for i, rec := range records {
+		out[i] = gin.H{
+			"site_id":   rec.SiteID,
+			"site_name": rec.SiteName,
+			"location":  rec.Location,
+			"data":      rec.Data,
+		}
+	}
+	return out
 }
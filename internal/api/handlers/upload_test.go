@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/config"
+	"github.com/workforce-ai/site-selection-iq/internal/ingest"
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+	"github.com/workforce-ai/site-selection-iq/internal/storage"
+)
+
+func TestDuplicateUploadResponse_IncludesPriorRun(t *testing.T) {
+	existing := &models.Upload{ID: uuid.New(), TenantID: uuid.New(), Filename: "sites.csv", ValidationStatus: "valid"}
+	completedAt := time.Now()
+	run := &models.ScoringRun{ID: uuid.New(), UploadID: existing.ID, Status: "succeeded", CompletedAt: &completedAt}
+
+	resp := duplicateUploadResponse(existing, run)
+
+	assert.Equal(t, existing.ID, resp["upload_id"])
+	assert.Equal(t, true, resp["duplicate"])
+	assert.Equal(t, gin.H{
+		"run_id":       run.ID,
+		"status":       "succeeded",
+		"completed_at": &completedAt,
+	}, resp["latest_run"])
+	assert.Contains(t, resp["message"], "latest completed run")
+}
+
+func TestDuplicateUploadResponse_NoRunYet(t *testing.T) {
+	existing := &models.Upload{ID: uuid.New(), TenantID: uuid.New(), Filename: "sites.csv", ValidationStatus: "valid"}
+
+	resp := duplicateUploadResponse(existing, nil)
+
+	assert.Nil(t, resp["latest_run"])
+	assert.Contains(t, resp["message"], "no completed scoring run yet")
+}
+
+func TestAcceptedFileType(t *testing.T) {
+	defaults := config.UploadConfig{
+		AllowedTypes:      []string{"text/csv", "application/csv"},
+		AllowedExtensions: []string{".csv"},
+	}
+	withTSV := config.UploadConfig{
+		AllowedTypes:      []string{"text/csv", "text/tab-separated-values", "application/vnd.ms-excel"},
+		AllowedExtensions: []string{".csv", ".tsv"},
+	}
+
+	testCases := []struct {
+		name        string
+		cfg         config.UploadConfig
+		contentType string
+		filename    string
+		accepted    bool
+	}{
+		{"csv type and extension", defaults, "text/csv", "sites.csv", true},
+		{"csv type with charset", defaults, "text/csv; charset=utf-8", "sites", true},
+		{"csv extension, generic type", defaults, "application/octet-stream", "sites.CSV", true},
+		{"tsv rejected by default", defaults, "text/tab-separated-values", "sites.tsv", false},
+		{"excel type rejected by default", defaults, "application/vnd.ms-excel", "sites.xls", false},
+		{"no type, no extension", defaults, "", "sites", false},
+		{"tsv accepted when configured", withTSV, "text/tab-separated-values", "sites.tsv", true},
+		{"tsv extension accepted when configured", withTSV, "", "sites.tsv", true},
+		{"excel type accepted when configured", withTSV, "application/vnd.ms-excel", "sites.xls", true},
+		{"json still rejected", withTSV, "application/json", "sites.json", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.accepted, acceptedFileType(tc.cfg, tc.contentType, tc.filename))
+		})
+	}
+}
+
+func TestIsZipUpload(t *testing.T) {
+	assert.True(t, isZipUpload("application/zip", "regions"))
+	assert.True(t, isZipUpload("application/x-zip-compressed", "regions.bin"))
+	assert.True(t, isZipUpload("application/octet-stream", "regions.ZIP"))
+	assert.False(t, isZipUpload("text/csv", "sites.csv"))
+	assert.False(t, isZipUpload("", "sites"))
+}
+
+func TestUseAsyncUpload(t *testing.T) {
+	const mb = 1024 * 1024
+
+	assert.False(t, useAsyncUpload("", 500*mb, 0), "threshold 0 disables the size rule")
+	assert.True(t, useAsyncUpload("", 50*mb, 50*mb))
+	assert.False(t, useAsyncUpload("", 49*mb, 50*mb))
+	assert.True(t, useAsyncUpload("true", 1, 0), "query param forces async")
+	assert.False(t, useAsyncUpload("false", 500*mb, 50*mb), "query param forces sync")
+	assert.True(t, useAsyncUpload("bogus", 50*mb, 50*mb), "unparseable param falls back to threshold")
+}
+
+func TestSiteRecordResponses(t *testing.T) {
+	records := []models.SiteRecord{{
+		ID:       uuid.New(),
+		SiteID:   "S1",
+		SiteName: "Austin",
+		Location: "Austin, TX",
+		RawData:  json.RawMessage(`{"site_id": "S1", "population": "52000"}`),
+		Data:     json.RawMessage(`{"site_id": "S1", "population": 52000}`),
+	}}
+
+	body, err := json.Marshal(siteRecordResponses(records))
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `[{
+		"site_id": "S1",
+		"site_name": "Austin",
+		"location": "Austin, TX",
+		"data": {"site_id": "S1", "population": 52000}
+	}]`, string(body), "coerced data is returned, raw data is not")
+}
+
+func TestHandleGetRecords_InvalidUploadID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := &UploadHandler{}
+	r := gin.New()
+	r.GET("/uploads/:upload_id/records", func(c *gin.Context) {
+		c.Set("tenant_id", uuid.New())
+		h.HandleGetRecords(c)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/uploads/not-a-uuid/records", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid upload_id format")
+}
+
+func TestAppendedContentHash(t *testing.T) {
+	first := "a3f1"
+	chained := appendedContentHash(&first, "b2c4")
+
+	assert.Len(t, chained, 64)
+	assert.NotEqual(t, appendedContentHash(&first, "ffff"), chained, "depends on the appended file")
+	other := "0000"
+	assert.NotEqual(t, appendedContentHash(&other, "b2c4"), chained, "depends on the upload's history")
+	assert.Equal(t, chained, appendedContentHash(&first, "b2c4"))
+	assert.Len(t, appendedContentHash(nil, "b2c4"), 64)
+}
+
+// stubScanner rejects any file whose content contains "EICAR".
+type stubScanner struct {
+	err error
+}
+
+func (s stubScanner) Scan(path string) (bool, []string, error) {
+	if s.err != nil {
+		return false, nil, s.err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, nil, err
+	}
+	if strings.Contains(string(data), "EICAR") {
+		return false, []string{"Eicar-Test-Signature"}, nil
+	}
+	return true, nil, nil
+}
+
+func TestScanUploadFile(t *testing.T) {
+	serve := func(scanner ingest.MalwareScanner, body string) (*httptest.ResponseRecorder, string, bool) {
+		path := filepath.Join(t.TempDir(), "upload.csv")
+		require.NoError(t, os.WriteFile(path, []byte(body), 0o600))
+
+		var ok bool
+		r := gin.New()
+		r.POST("/uploads", func(c *gin.Context) {
+			ok = scanUploadFile(c, scanner, path)
+		})
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/uploads", nil))
+		return w, path, ok
+	}
+
+	_, path, ok := serve(stubScanner{}, "site_id\nS1\n")
+	assert.True(t, ok)
+	assert.FileExists(t, path, "clean files are kept for parsing")
+
+	w, path, ok := serve(stubScanner{}, "site_id\nEICAR\n")
+	assert.False(t, ok)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "file rejected by security scan")
+	assert.Contains(t, w.Body.String(), "Eicar-Test-Signature")
+	assert.NoFileExists(t, path)
+
+	w, path, ok = serve(stubScanner{err: errors.New("clamd unreachable")}, "site_id\nS1\n")
+	assert.False(t, ok)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code, "an unscanned file is never accepted")
+	assert.NoFileExists(t, path)
+
+	_, _, ok = serve(ingest.NoopScanner{}, "site_id\nEICAR\n")
+	assert.True(t, ok, "the default scanner accepts everything")
+}
+
+func TestOriginalRoundTrip(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := storage.NewLocalBlobStore(t.TempDir())
+	upload := &models.Upload{ID: uuid.New(), TenantID: uuid.New(), Filename: "Q3 sites.csv"}
+
+	// Bytes are kept as uploaded, including a BOM and CRLF line endings
+	original := "\ufeffsite_id,population\r\nS1,52000\r\n"
+	path := filepath.Join(t.TempDir(), "upload.csv")
+	require.NoError(t, os.WriteFile(path, []byte(original), 0o600))
+	require.NoError(t, retainOriginal(context.Background(), store, upload, path))
+	require.NoError(t, os.Remove(path), "the parser removes the temp file; the original must survive")
+
+	serve := func(store storage.BlobStore, upload *models.Upload) *httptest.ResponseRecorder {
+		r := gin.New()
+		r.GET("/uploads/:upload_id/original", func(c *gin.Context) {
+			serveOriginal(c, store, upload)
+		})
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/uploads/"+upload.ID.String()+"/original", nil))
+		return w
+	}
+
+	w := serve(store, upload)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, original, w.Body.String())
+	assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="Q3 sites.csv"`, w.Header().Get("Content-Disposition"))
+
+	// Another tenant's upload with the same ID has no original
+	w = serve(store, &models.Upload{ID: upload.ID, TenantID: uuid.New(), Filename: "Q3 sites.csv"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "original file not retained for this upload")
+
+	w = serve(nil, upload)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "original files are not retained on this server")
+}
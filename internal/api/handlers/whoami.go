@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/workforce-ai/site-selection-iq/internal/api/response"
+)
+
+// HandleWhoAmI handles GET /api/v1/whoami.
+// It echoes the tenant, user, role and scopes the caller's validated token
+// resolved to, so clients can debug auth and adapt to the user's role. It
+// reads only the request context set by AuthMiddleware.
+func HandleWhoAmI(c *gin.Context) {
+	scopes := c.GetStringSlice("scopes")
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	response.Success(c, http.StatusOK, gin.H{
+		"tenant_id": c.MustGet("tenant_id"),
+		"user_id":   c.MustGet("user_id"),
+		"role":      c.GetString("role"),
+		"scopes":    scopes,
+	})
+}
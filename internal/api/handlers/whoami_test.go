@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/api/middleware"
+	"github.com/workforce-ai/site-selection-iq/internal/config"
+	"github.com/workforce-ai/site-selection-iq/pkg/auth"
+)
+
+func TestHandleWhoAmI_EchoesTokenClaims(t *testing.T) {
+	cfg := &config.JWTConfig{Secret: "whoami-test-secret", Issuer: "ssiq-test", ExpiryHours: 1}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/whoami", middleware.AuthMiddleware(cfg), middleware.RequireRole("viewer"), HandleWhoAmI)
+
+	tests := []struct {
+		role   string
+		scopes []string
+	}{
+		{"viewer", nil},
+		{"analyst", []string{middleware.ScopeUploadsWrite, middleware.ScopeRunsWrite}},
+		{"admin", []string{middleware.ScopeRunsWrite}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			tenantID, userID := uuid.New(), uuid.New()
+			var opts []auth.TokenOption
+			if tt.scopes != nil {
+				opts = append(opts, auth.GrantScopes(tt.scopes...))
+			}
+			token, err := auth.GenerateToken(cfg.Secret, cfg.Issuer, tenantID, userID, tt.role, cfg.ExpiryHours, opts...)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+			var body struct {
+				Data struct {
+					TenantID uuid.UUID `json:"tenant_id"`
+					UserID   uuid.UUID `json:"user_id"`
+					Role     string    `json:"role"`
+					Scopes   []string  `json:"scopes"`
+				} `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tenantID, body.Data.TenantID)
+			assert.Equal(t, userID, body.Data.UserID)
+			assert.Equal(t, tt.role, body.Data.Role)
+			if tt.scopes == nil {
+				assert.NotNil(t, body.Data.Scopes)
+				assert.Empty(t, body.Data.Scopes)
+			} else {
+				assert.Equal(t, tt.scopes, body.Data.Scopes)
+			}
+		})
+	}
+}
+
+func TestHandleWhoAmI_RequiresToken(t *testing.T) {
+	cfg := &config.JWTConfig{Secret: "whoami-test-secret", Issuer: "ssiq-test", ExpiryHours: 1}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/whoami", middleware.AuthMiddleware(cfg), HandleWhoAmI)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/whoami", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -1,12 +1,12 @@
 package middleware
 
 import (
-	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/workforce-ai/site-selection-iq/pkg/auth"
+	"github.com/workforce-ai/site-selection-iq/internal/api/response"
 	"github.com/workforce-ai/site-selection-iq/internal/config"
+	"github.com/workforce-ai/site-selection-iq/pkg/auth"
 )
 
 // AuthMiddleware validates JWT tokens from the Authorization header
@@ -15,7 +15,7 @@ func AuthMiddleware(cfg *config.JWTConfig) gin.HandlerFunc {
 		// Extract Bearer token from Authorization header
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing authorization header"})
+			response.Unauthorized(c, "missing authorization header")
 			c.Abort()
 			return
 		}
@@ -23,7 +23,7 @@ func AuthMiddleware(cfg *config.JWTConfig) gin.HandlerFunc {
 		// Parse Bearer token
 		const bearerPrefix = "Bearer "
 		if !strings.HasPrefix(authHeader, bearerPrefix) {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid authorization header format"})
+			response.Unauthorized(c, "invalid authorization header format")
 			c.Abort()
 			return
 		}
@@ -31,9 +31,9 @@ func AuthMiddleware(cfg *config.JWTConfig) gin.HandlerFunc {
 		token := strings.TrimPrefix(authHeader, bearerPrefix)
 
 		// Validate token
-		claims, err := auth.ValidateToken(token, cfg.Secret)
+		claims, err := auth.ValidateToken(token, cfg.Secret, auth.WithIssuer(cfg.Issuer), auth.WithAudience(cfg.Audience))
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+			response.Unauthorized(c, "invalid token")
 			c.Abort()
 			return
 		}
@@ -42,6 +42,9 @@ func AuthMiddleware(cfg *config.JWTConfig) gin.HandlerFunc {
 		c.Set("tenant_id", claims.TenantID)
 		c.Set("user_id", claims.UserID)
 		c.Set("role", claims.Role)
+		c.Set("scopes", claims.Scopes)
+		c.Set("token_id", claims.ID)
+		c.Set("service_account", claims.ServiceAccount)
 
 		c.Next()
 	}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/workforce-ai/site-selection-iq/internal/api/response"
+)
+
+// JSONBodyLimit rejects request bodies larger than maxBytes with 413 before
+// a handler can bind them. Multipart uploads are exempt; the upload limit
+// covers them. A declared Content-Length over the limit is refused without
+// reading; otherwise at most maxBytes+1 bytes are read, so a chunked body
+// cannot grow past the limit either. maxBytes <= 0 disables the check.
+func JSONBodyLimit(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body := c.Request.Body
+		if maxBytes <= 0 || body == nil || body == http.NoBody || requestMediaType(c.Request) == "multipart/form-data" {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > maxBytes {
+			rejectOversizedBody(c, maxBytes)
+			return
+		}
+
+		buf, err := io.ReadAll(io.LimitReader(body, maxBytes+1))
+		if err != nil {
+			response.BadRequest(c, "failed to read request body", nil)
+			c.Abort()
+			return
+		}
+		if int64(len(buf)) > maxBytes {
+			rejectOversizedBody(c, maxBytes)
+			return
+		}
+
+		c.Request.Body = io.NopCloser(bytes.NewReader(buf))
+		c.Next()
+	}
+}
+
+func rejectOversizedBody(c *gin.Context, maxBytes int64) {
+	// The rest of the body is never read, so don't reuse the connection
+	c.Header("Connection", "close")
+	response.Error(c, http.StatusRequestEntityTooLarge, response.CodePayloadTooLarge,
+		fmt.Sprintf("request body exceeds max size of %d bytes", maxBytes), gin.H{"max_bytes": maxBytes})
+	c.Abort()
+}
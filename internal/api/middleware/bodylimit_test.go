@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/api/response"
+)
+
+func TestJSONBodyLimit(t *testing.T) {
+	r := setupRouter(nil)
+	r.POST("/runs", JSONBodyLimit(64), func(c *gin.Context) {
+		var req map[string]interface{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.BadRequest(c, err.Error(), nil)
+			return
+		}
+		c.JSON(http.StatusOK, req)
+	})
+
+	oversized := `{"scoring_config": {"name": "` + strings.Repeat("x", 100) + `"}}`
+
+	tests := []struct {
+		name        string
+		body        io.Reader
+		contentType string
+		chunked     bool
+		wantCode    int
+	}{
+		{"within limit", strings.NewReader(`{"name": "q3"}`), "application/json", false, http.StatusOK},
+		{"declared length over limit", strings.NewReader(oversized), "application/json", false, http.StatusRequestEntityTooLarge},
+		{"chunked body over limit", strings.NewReader(oversized), "application/json", true, http.StatusRequestEntityTooLarge},
+		{"multipart is exempt", strings.NewReader(oversized), "multipart/form-data; boundary=x", false, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/runs", tt.body)
+			req.Header.Set("Content-Type", tt.contentType)
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantCode, w.Code, w.Body.String())
+			if tt.wantCode == http.StatusRequestEntityTooLarge {
+				env := decodeEnvelope(t, w)
+				assert.Equal(t, response.CodePayloadTooLarge, env.Error.Code)
+				assert.Equal(t, "request body exceeds max size of 64 bytes", env.Error.Message)
+			}
+		})
+	}
+}
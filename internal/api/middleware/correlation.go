@@ -5,13 +5,18 @@ import (
 	"github.com/google/uuid"
 )
 
+// maxCorrelationIDLength bounds client-supplied correlation IDs; a UUID is 36.
+const maxCorrelationIDLength = 128
+
 // CorrelationMiddleware handles correlation ID tracking
 func CorrelationMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Check for existing X-Correlation-ID header
+		// Check for existing X-Correlation-ID header. It is echoed into
+		// response headers and logs, so anything that is not a short
+		// alphanumeric/hyphen token is replaced rather than trusted.
 		correlationID := c.GetHeader("X-Correlation-ID")
-		if correlationID == "" {
-			// Generate a new UUID if missing
+		if !validCorrelationID(correlationID) {
+			// Generate a new UUID if missing or unsafe
 			correlationID = uuid.New().String()
 		}
 
@@ -24,3 +29,19 @@ func CorrelationMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// validCorrelationID reports whether id is 1 to maxCorrelationIDLength ASCII
+// letters, digits and hyphens, which covers UUIDs and typical trace IDs
+// while ruling out CRLF and other header or log injection.
+func validCorrelationID(id string) bool {
+	if id == "" || len(id) > maxCorrelationIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		ch := id[i]
+		if !('a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || '0' <= ch && ch <= '9' || ch == '-') {
+			return false
+		}
+	}
+	return true
+}
@@ -12,8 +12,9 @@ func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization, X-Correlation-ID, X-Idempotency-Key")
-		c.Header("Access-Control-Expose-Headers", "X-Correlation-ID")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization, X-Correlation-ID, X-Idempotency-Key, If-None-Match, API-Version, Accept-Timezone, Accept-Timestamp-Format")
+		c.Header("Access-Control-Expose-Headers", "X-Correlation-ID, ETag, Retry-After, API-Version, Server-Timing")
+		c.Header("Timing-Allow-Origin", "*")
 		c.Header("Access-Control-Max-Age", "86400")
 
 		if c.Request.Method == http.MethodOptions {
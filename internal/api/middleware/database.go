@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/workforce-ai/site-selection-iq/internal/api/response"
+	"github.com/workforce-ai/site-selection-iq/internal/db"
+)
+
+// RequireDatabase returns middleware that rejects requests with 503 while
+// the monitor reports the database unreachable, instead of letting each one
+// wait out a connection attempt.
+func RequireDatabase(monitor *db.Monitor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !monitor.Healthy() {
+			c.Header("Retry-After", "5")
+			response.ServiceUnavailable(c, "database unavailable")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/api/response"
+	"github.com/workforce-ai/site-selection-iq/pkg/auth"
+)
+
+// decodeEnvelope asserts the body is a standard error envelope and returns it.
+func decodeEnvelope(t *testing.T, w *httptest.ResponseRecorder) response.Envelope {
+	t.Helper()
+	var env response.Envelope
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &env), "body should be an envelope: %s", w.Body.String())
+	assert.Equal(t, "error", env.Status)
+	require.NotNil(t, env.Error)
+	assert.NotEmpty(t, env.Error.Message)
+	assert.NotEmpty(t, env.Meta.Timestamp)
+	return env
+}
+
+func TestAuthErrors_UseEnvelope(t *testing.T) {
+	cfg := testJWTConfig()
+	r := setupRouter(cfg)
+	r.POST("/runs",
+		AuthMiddleware(cfg),
+		RequireRole("analyst"),
+		RequireScope(ScopeRunsWrite),
+		func(c *gin.Context) { c.JSON(200, gin.H{"ok": true}) },
+	)
+
+	token := func(role string, scopes ...string) string {
+		tok, err := auth.GenerateToken(testSecret, testIssuer, uuid.New(), uuid.New(), role, 24, auth.GrantScopes(scopes...))
+		require.NoError(t, err)
+		return "Bearer " + tok
+	}
+
+	tests := []struct {
+		name     string
+		header   string
+		wantCode int
+		wantErr  string
+	}{
+		{"missing header", "", http.StatusUnauthorized, response.CodeUnauthorized},
+		{"malformed header", "Token abc", http.StatusUnauthorized, response.CodeUnauthorized},
+		{"invalid token", "Bearer not-a-jwt", http.StatusUnauthorized, response.CodeUnauthorized},
+		{"insufficient role", token("viewer"), http.StatusForbidden, response.CodeForbidden},
+		{"missing scope", token("analyst", ScopeUploadsWrite), http.StatusForbidden, response.CodeForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/runs", nil)
+			req.Header.Set("X-Correlation-ID", "corr-123")
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantCode, w.Code)
+			env := decodeEnvelope(t, w)
+			assert.Equal(t, tt.wantErr, env.Error.Code)
+			assert.Equal(t, "corr-123", env.Meta.CorrelationID)
+		})
+	}
+}
+
+func TestAPIVersion_UnsupportedUsesEnvelope(t *testing.T) {
+	r := setupRouter(nil)
+	r.GET("/runs", APIVersion(), func(c *gin.Context) { c.JSON(200, gin.H{"ok": true}) })
+
+	req := httptest.NewRequest(http.MethodGet, "/runs", nil)
+	req.Header.Set(APIVersionHeader, "7")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusNotAcceptable, w.Code)
+	env := decodeEnvelope(t, w)
+	assert.Equal(t, response.CodeNotAcceptable, env.Error.Code)
+	assert.NotEmpty(t, env.Meta.CorrelationID)
+}
@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// redactedValue replaces sensitive header and body values in logs.
+const redactedValue = "[REDACTED]"
+
+// WithBodyLogging adds the request headers and up to maxBytes of the request
+// body to each log line, with credentials redacted. Multipart bodies (file
+// uploads) are never captured. Intended for debugging only.
+func WithBodyLogging(maxBytes int) LoggingOption {
+	return func(o *loggingOptions) {
+		o.logBodies = true
+		o.bodyMaxBytes = maxBytes
+	}
+}
+
+// sensitiveHeaders are logged as redactedValue.
+var sensitiveHeaders = map[string]bool{
+	"Authorization": true,
+	"Cookie":        true,
+	"Set-Cookie":    true,
+	"X-Api-Key":     true,
+}
+
+// sensitiveKeyPattern matches body field names whose values are redacted.
+var sensitiveKeyPattern = regexp.MustCompile(`(?i)(password|secret|token|authorization|api_?key|credential)`)
+
+// sensitiveJSONPair matches a string-valued sensitive field in JSON that
+// could not be parsed whole, e.g. a body truncated at the size cap.
+var sensitiveJSONPair = regexp.MustCompile(`(?i)("[^"]*(?:password|secret|token|authorization|api_?key|credential)[^"]*"\s*:\s*)"(?:[^"\\]|\\.)*"?`)
+
+// captureRequestBody reads up to maxBytes of the request body for logging and
+// puts it back in front of the unread remainder, so handlers still see the
+// full body. It returns the captured bytes and whether the body was longer.
+func captureRequestBody(c *gin.Context, maxBytes int) ([]byte, bool, error) {
+	body := c.Request.Body
+	if body == nil || body == http.NoBody || maxBytes <= 0 {
+		return nil, false, nil
+	}
+
+	prefix, err := io.ReadAll(io.LimitReader(body, int64(maxBytes)+1))
+	c.Request.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(prefix), body), body}
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(prefix) > maxBytes {
+		return prefix[:maxBytes], true, nil
+	}
+	return prefix, false, nil
+}
+
+// requestMediaType returns the request's Content-Type without parameters.
+func requestMediaType(r *http.Request) string {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return mediaType
+}
+
+// redactHeaders flattens request headers for logging, hiding credentials.
+func redactHeaders(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for name, values := range h {
+		if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+			out[name] = redactedValue
+			continue
+		}
+		out[name] = strings.Join(values, ", ")
+	}
+	return out
+}
+
+// redactBody returns body with sensitive fields hidden. A complete JSON
+// document or form is redacted field by field; anything else is scrubbed by
+// pattern.
+func redactBody(mediaType string, body []byte) string {
+	if mediaType == "application/x-www-form-urlencoded" {
+		if form, err := url.ParseQuery(string(body)); err == nil {
+			for k := range form {
+				if sensitiveKeyPattern.MatchString(k) {
+					form[k] = []string{redactedValue}
+				}
+			}
+			return form.Encode()
+		}
+	}
+
+	var doc any
+	if err := json.Unmarshal(body, &doc); err == nil {
+		if out, err := json.Marshal(redactJSON(doc)); err == nil {
+			return string(out)
+		}
+	}
+	return sensitiveJSONPair.ReplaceAllString(string(body), `${1}"`+redactedValue+`"`)
+}
+
+func redactJSON(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, inner := range v {
+			if sensitiveKeyPattern.MatchString(k) {
+				v[k] = redactedValue
+				continue
+			}
+			v[k] = redactJSON(inner)
+		}
+	case []any:
+		for i, inner := range v {
+			v[i] = redactJSON(inner)
+		}
+	}
+	return v
+}
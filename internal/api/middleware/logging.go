@@ -2,22 +2,65 @@ package middleware
 
 import (
 	"log/slog"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// LoggingOption adjusts what LoggingMiddleware records.
+type LoggingOption func(*loggingOptions)
+
+type loggingOptions struct {
+	logBodies     bool
+	bodyMaxBytes  int
+	sampleRate    uint64
+	slowThreshold time.Duration
+}
+
+// WithSampling logs only one in every n successful requests that are not
+// slow. Client errors, server errors and slow requests are always logged.
+// n <= 1 logs every request.
+func WithSampling(n int) LoggingOption {
+	return func(o *loggingOptions) {
+		if n > 1 {
+			o.sampleRate = uint64(n)
+		}
+	}
+}
+
+// WithSlowThreshold tags requests taking at least d with slow=true and
+// exempts them from sampling. d <= 0 disables the check.
+func WithSlowThreshold(d time.Duration) LoggingOption {
+	return func(o *loggingOptions) {
+		o.slowThreshold = d
+	}
+}
+
 // StructuredLogging provides structured JSON logging for all requests
-func StructuredLogging() gin.HandlerFunc {
+func StructuredLogging(opts ...LoggingOption) gin.HandlerFunc {
 	logger := slog.Default()
-	return LoggingMiddleware(logger, "site-selection-iq")
+	return LoggingMiddleware(logger, "site-selection-iq", opts...)
 }
 
 // LoggingMiddleware provides structured JSON logging for all requests
-func LoggingMiddleware(logger *slog.Logger, serviceName string) gin.HandlerFunc {
+func LoggingMiddleware(logger *slog.Logger, serviceName string, opts ...LoggingOption) gin.HandlerFunc {
+	var o loggingOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	var sampled atomic.Uint64
+
 	return func(c *gin.Context) {
 		startTime := time.Now()
 
+		// Capture the request body before handlers consume it
+		var bodyAttrs []slog.Attr
+		if o.logBodies {
+			bodyAttrs = requestBodyAttrs(c, o.bodyMaxBytes)
+		}
+
 		// Extract context values
 		tenantID, _ := c.Get("tenant_id")
 		correlationID, _ := c.Get("correlation_id")
@@ -50,6 +93,13 @@ func LoggingMiddleware(logger *slog.Logger, serviceName string) gin.HandlerFunc
 			level = slog.LevelInfo
 		}
 
+		// Sample fast successful requests; errors and slow requests are always logged
+		slow := o.slowThreshold > 0 && duration >= o.slowThreshold
+		sampling := o.sampleRate > 1 && statusCode < 400 && !slow
+		if sampling && (sampled.Add(1)-1)%o.sampleRate != 0 {
+			return
+		}
+
 		// Build attributes
 		attrs := []slog.Attr{
 			slog.String("service", serviceName),
@@ -71,6 +121,14 @@ func LoggingMiddleware(logger *slog.Logger, serviceName string) gin.HandlerFunc
 			attrs = append(attrs, slog.Any("user_id", userID))
 		}
 
+		if slow {
+			attrs = append(attrs, slog.Bool("slow", true))
+		}
+		if sampling {
+			attrs = append(attrs, slog.Uint64("sample_rate", o.sampleRate))
+		}
+		attrs = append(attrs, bodyAttrs...)
+
 		// Add timestamp
 		attrs = append(attrs, slog.Int64("timestamp", startTime.UnixMilli()))
 
@@ -78,3 +136,26 @@ func LoggingMiddleware(logger *slog.Logger, serviceName string) gin.HandlerFunc
 		logger.LogAttrs(c.Request.Context(), level, "request processed", attrs...)
 	}
 }
+
+// requestBodyAttrs captures redacted request headers and body for a log line.
+// Multipart bodies are left unread so file contents never reach the logs.
+func requestBodyAttrs(c *gin.Context, maxBytes int) []slog.Attr {
+	attrs := []slog.Attr{slog.Any("request_headers", redactHeaders(c.Request.Header))}
+
+	mediaType := requestMediaType(c.Request)
+	if strings.HasPrefix(mediaType, "multipart/") {
+		return append(attrs, slog.String("request_body", "[multipart omitted]"))
+	}
+
+	body, truncated, err := captureRequestBody(c, maxBytes)
+	if err != nil {
+		return append(attrs, slog.String("request_body_error", err.Error()))
+	}
+	if len(body) == 0 {
+		return attrs
+	}
+	return append(attrs,
+		slog.String("request_body", redactBody(mediaType, body)),
+		slog.Bool("request_body_truncated", truncated),
+	)
+}
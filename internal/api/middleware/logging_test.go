@@ -0,0 +1,192 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveLogged sends req through LoggingMiddleware and returns the decoded log
+// line and the body the handler read.
+func serveLogged(t *testing.T, req *http.Request, opts ...LoggingOption) (map[string]any, string) {
+	t.Helper()
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+	var handlerBody string
+	r := setupRouter(nil)
+	r.Use(LoggingMiddleware(logger, "test", opts...))
+	r.POST("/runs", func(c *gin.Context) {
+		b, err := io.ReadAll(c.Request.Body)
+		require.NoError(t, err)
+		handlerBody = string(b)
+		c.Status(http.StatusOK)
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &line), logs.String())
+	return line, handlerBody
+}
+
+func TestLoggingMiddleware_BodyLogging(t *testing.T) {
+	body := `{"upload_id":"abc","client_secret":"s3cr3t"}`
+	newReq := func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/runs", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer top-secret-token")
+		return req
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		line, handlerBody := serveLogged(t, newReq())
+		assert.NotContains(t, line, "request_body")
+		assert.NotContains(t, line, "request_headers")
+		assert.Equal(t, body, handlerBody)
+	})
+
+	t.Run("enabled logs redacted body and headers", func(t *testing.T) {
+		line, handlerBody := serveLogged(t, newReq(), WithBodyLogging(1024))
+		assert.Equal(t, body, handlerBody, "handler must still read the full body")
+
+		logged, ok := line["request_body"].(string)
+		require.True(t, ok, "request_body should be logged: %v", line)
+		assert.Contains(t, logged, `"upload_id":"abc"`)
+		assert.NotContains(t, logged, "s3cr3t")
+		assert.Contains(t, logged, redactedValue)
+		assert.Equal(t, false, line["request_body_truncated"])
+
+		headers, ok := line["request_headers"].(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, redactedValue, headers["Authorization"])
+		assert.Equal(t, "application/json", headers["Content-Type"])
+	})
+
+	t.Run("truncates at the size cap", func(t *testing.T) {
+		line, handlerBody := serveLogged(t, newReq(), WithBodyLogging(16))
+		assert.Equal(t, body, handlerBody)
+		assert.Equal(t, body[:16], line["request_body"])
+		assert.Equal(t, true, line["request_body_truncated"])
+	})
+}
+
+func TestLoggingMiddleware_BodyLoggingSkipsMultipart(t *testing.T) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	fw, err := mw.CreateFormFile("file", "sites.csv")
+	require.NoError(t, err)
+	_, err = fw.Write([]byte("site_id,population\nS1,1000\n"))
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+	sent := buf.String()
+
+	req := httptest.NewRequest(http.MethodPost, "/runs", &buf)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	line, handlerBody := serveLogged(t, req, WithBodyLogging(1024))
+	assert.Equal(t, sent, handlerBody)
+	assert.Equal(t, "[multipart omitted]", line["request_body"])
+	assert.NotContains(t, line["request_body"], "S1,1000")
+}
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		name      string
+		mediaType string
+		body      string
+		want      string
+	}{
+		{"nested json", "application/json", `{"config":{"api_key":"k"},"items":[{"token":"t","n":1}]}`,
+			`{"config":{"api_key":"[REDACTED]"},"items":[{"n":1,"token":"[REDACTED]"}]}`},
+		{"truncated json", "application/json", `{"name":"x","password":"hunt`, `{"name":"x","password":"[REDACTED]"`},
+		{"form", "application/x-www-form-urlencoded", "password=hunter2&user=bob", "password=%5BREDACTED%5D&user=bob"},
+		{"plain text", "text/plain", "hello", "hello"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, redactBody(tt.mediaType, []byte(tt.body)))
+		})
+	}
+}
+
+func TestLoggingMiddleware_SamplingAndSlowRequests(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+	r := setupRouter(nil)
+	r.Use(LoggingMiddleware(logger, "test", WithSampling(3), WithSlowThreshold(20*time.Millisecond)))
+	r.GET("/fast", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/slow", func(c *gin.Context) {
+		time.Sleep(30 * time.Millisecond)
+		c.Status(http.StatusOK)
+	})
+	r.GET("/fail", func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+
+	serve := func(path string, times int) []map[string]any {
+		logs.Reset()
+		for i := 0; i < times; i++ {
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+		}
+		var lines []map[string]any
+		dec := json.NewDecoder(&logs)
+		for dec.More() {
+			var line map[string]any
+			require.NoError(t, dec.Decode(&line))
+			lines = append(lines, line)
+		}
+		return lines
+	}
+
+	t.Run("fast requests are sampled", func(t *testing.T) {
+		lines := serve("/fast", 9)
+		require.Len(t, lines, 3)
+		for _, line := range lines {
+			assert.Equal(t, float64(3), line["sample_rate"])
+			assert.NotContains(t, line, "slow")
+		}
+	})
+
+	t.Run("slow requests are always logged", func(t *testing.T) {
+		lines := serve("/slow", 3)
+		require.Len(t, lines, 3)
+		for _, line := range lines {
+			assert.Equal(t, true, line["slow"])
+			assert.NotContains(t, line, "sample_rate")
+		}
+	})
+
+	t.Run("errors are always logged", func(t *testing.T) {
+		lines := serve("/fail", 3)
+		require.Len(t, lines, 3)
+		for _, line := range lines {
+			assert.Equal(t, "server_error", line["outcome"])
+		}
+	})
+}
+
+func TestLoggingMiddleware_NoSamplingByDefault(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+	r := setupRouter(nil)
+	r.Use(LoggingMiddleware(logger, "test", WithSampling(1)))
+	r.GET("/fast", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for i := 0; i < 5; i++ {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fast", nil))
+	}
+	assert.Equal(t, 5, strings.Count(logs.String(), "request processed"))
+}
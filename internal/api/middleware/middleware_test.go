@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -119,6 +120,26 @@ func TestAuthMiddleware_WrongSecret(t *testing.T) {
 	assert.Equal(t, 401, w.Code)
 }
 
+func TestAuthMiddleware_WrongIssuer(t *testing.T) {
+	cfg := testJWTConfig()
+	r := setupRouter(cfg)
+	r.GET("/test", AuthMiddleware(cfg), func(c *gin.Context) {
+		c.JSON(200, gin.H{"ok": true})
+	})
+
+	// Right secret, but minted by another issuer
+	token, err := auth.GenerateToken(testSecret, "someone-else", uuid.New(), uuid.New(), "admin", 24)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 401, w.Code)
+}
+
 func TestAuthMiddleware_MalformedAuthorizationHeader(t *testing.T) {
 	cfg := testJWTConfig()
 	r := setupRouter(cfg)
@@ -293,3 +314,85 @@ func TestCorrelationMiddleware_PreservesExistingID(t *testing.T) {
 	assert.Equal(t, customID, w.Header().Get("X-Correlation-ID"),
 		"should preserve the client-supplied correlation ID")
 }
+
+func TestCorrelationMiddleware_ReplacesUnsafeID(t *testing.T) {
+	r := setupRouter(testJWTConfig())
+	r.GET("/test", func(c *gin.Context) {
+		c.JSON(200, gin.H{"correlation_id": c.GetString("correlation_id")})
+	})
+
+	tests := []struct {
+		name string
+		id   string
+	}{
+		{"CRLF header splitting", "abc\r\nSet-Cookie: session=evil"},
+		{"log forging newline", "abc\n{\"level\":\"ERROR\"}"},
+		{"over-long value", strings.Repeat("a", maxCorrelationIDLength+1)},
+		{"disallowed characters", "abc def<script>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header["X-Correlation-Id"] = []string{tt.id}
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			corrID := w.Header().Get("X-Correlation-ID")
+			assert.NotEqual(t, tt.id, corrID)
+			_, err := uuid.Parse(corrID)
+			assert.NoError(t, err, "unsafe ID should be replaced with a generated UUID")
+			assert.Contains(t, w.Body.String(), corrID, "handlers should see the replacement too")
+		})
+	}
+
+	// The longest allowed ID is still accepted
+	maxID := strings.Repeat("a", maxCorrelationIDLength)
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("X-Correlation-ID", maxID)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, maxID, w.Header().Get("X-Correlation-ID"))
+}
+
+func TestRequireRole_Hierarchy(t *testing.T) {
+	cfg := testJWTConfig()
+	r := setupRouter(cfg)
+
+	ok := func(c *gin.Context) { c.JSON(200, gin.H{"ok": true}) }
+	r.GET("/viewer", AuthMiddleware(cfg), RequireRole("viewer"), ok)
+	r.GET("/analyst", AuthMiddleware(cfg), RequireRole("analyst"), ok)
+	r.GET("/auditor", AuthMiddleware(cfg), RequireRole("auditor"), ok)
+	r.GET("/exact", AuthMiddleware(cfg), RequireExactRole("viewer"), ok)
+
+	tests := []struct {
+		path     string
+		role     string
+		wantCode int
+	}{
+		{"/viewer", "admin", 200},
+		{"/viewer", "analyst", 200},
+		{"/viewer", "viewer", 200},
+		{"/analyst", "viewer", 403},
+		{"/analyst", "admin", 200},
+		{"/viewer", "auditor", 403},
+		{"/auditor", "auditor", 200},
+		{"/auditor", "admin", 403},
+		{"/exact", "viewer", 200},
+		{"/exact", "admin", 403},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role+tt.path, func(t *testing.T) {
+			token := generateTestToken(uuid.New(), uuid.New(), tt.role)
+			req := httptest.NewRequest("GET", tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code, "role=%s path=%s", tt.role, tt.path)
+		})
+	}
+}
@@ -1,40 +1,56 @@
 package middleware
 
 import (
-	"net/http"
-
 	"github.com/gin-gonic/gin"
+	"github.com/workforce-ai/site-selection-iq/internal/api/response"
 )
 
-// RequireRole returns middleware that enforces role-based access control
+// roleRank orders the built-in roles so that a higher role inherits the
+// rights of every role below it: admin > analyst > viewer.
+var roleRank = map[string]int{
+	"viewer":  1,
+	"analyst": 2,
+	"admin":   3,
+}
+
+// IsBuiltinRole reports whether role is viewer, analyst or admin.
+func IsBuiltinRole(role string) bool {
+	_, ok := roleRank[role]
+	return ok
+}
+
+// RequireRole returns middleware that enforces role-based access control.
+// Built-in roles are hierarchical, so RequireRole("viewer") also admits
+// analysts and admins. Roles outside the hierarchy only match exactly.
 func RequireRole(allowedRoles ...string) gin.HandlerFunc {
+	return requireRole(allowedRoles, true)
+}
+
+// RequireExactRole is RequireRole without inheritance: the caller's role
+// must be one of allowedRoles.
+func RequireExactRole(allowedRoles ...string) gin.HandlerFunc {
+	return requireRole(allowedRoles, false)
+}
+
+func requireRole(allowedRoles []string, inherit bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Extract role from context
 		roleInterface, exists := c.Get("role")
 		if !exists {
-			c.JSON(http.StatusForbidden, gin.H{"error": "user role not found in context"})
+			response.Forbidden(c, "user role not found in context")
 			c.Abort()
 			return
 		}
 
 		userRole, ok := roleInterface.(string)
 		if !ok {
-			c.JSON(http.StatusForbidden, gin.H{"error": "invalid role format"})
+			response.Forbidden(c, "invalid role format")
 			c.Abort()
 			return
 		}
 
-		// Check if user role is in allowed roles
-		authorized := false
-		for _, allowedRole := range allowedRoles {
-			if userRole == allowedRole {
-				authorized = true
-				break
-			}
-		}
-
-		if !authorized {
-			c.JSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+		if !roleAllowed(userRole, allowedRoles, inherit) {
+			response.Forbidden(c, "insufficient permissions")
 			c.Abort()
 			return
 		}
@@ -42,3 +58,21 @@ func RequireRole(allowedRoles ...string) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// roleAllowed reports whether userRole satisfies any of allowedRoles, either
+// by exact match or, when inherit is set, by outranking a built-in role.
+func roleAllowed(userRole string, allowedRoles []string, inherit bool) bool {
+	userRank, ranked := roleRank[userRole]
+	for _, allowedRole := range allowedRoles {
+		if userRole == allowedRole {
+			return true
+		}
+		if !inherit || !ranked {
+			continue
+		}
+		if required, ok := roleRank[allowedRole]; ok && userRank >= required {
+			return true
+		}
+	}
+	return false
+}
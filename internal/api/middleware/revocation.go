@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/workforce-ai/site-selection-iq/internal/api/response"
+)
+
+// RevocationChecker reports whether a service token may no longer be used.
+type RevocationChecker interface {
+	IsRevoked(ctx context.Context, id uuid.UUID) (bool, error)
+}
+
+// RejectRevokedServiceTokens returns middleware that rejects service
+// account tokens whose jti checker reports revoked. Other tokens are not
+// looked up. It must run after AuthMiddleware.
+func RejectRevokedServiceTokens(checker RevocationChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !c.GetBool("service_account") {
+			c.Next()
+			return
+		}
+
+		id, err := uuid.Parse(c.GetString("token_id"))
+		if err != nil {
+			response.Unauthorized(c, "invalid token")
+			c.Abort()
+			return
+		}
+
+		revoked, err := checker.IsRevoked(c.Request.Context(), id)
+		if err != nil {
+			response.InternalError(c, "failed to check token revocation")
+			c.Abort()
+			return
+		}
+		if revoked {
+			response.Unauthorized(c, "token has been revoked")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/pkg/auth"
+)
+
+// fakeRevocations is a RevocationChecker backed by a set of revoked IDs.
+type fakeRevocations struct {
+	revoked map[uuid.UUID]bool
+	err     error
+	checked int
+}
+
+func (f *fakeRevocations) IsRevoked(_ context.Context, id uuid.UUID) (bool, error) {
+	f.checked++
+	return f.revoked[id], f.err
+}
+
+func TestRejectRevokedServiceTokens(t *testing.T) {
+	cfg := testJWTConfig()
+	revokedID, liveID := uuid.New(), uuid.New()
+	checker := &fakeRevocations{revoked: map[uuid.UUID]bool{revokedID: true}}
+
+	r := setupRouter(cfg)
+	r.GET("/protected",
+		AuthMiddleware(cfg),
+		RejectRevokedServiceTokens(checker),
+		func(c *gin.Context) { c.JSON(200, gin.H{"ok": true}) },
+	)
+
+	serve := func(opts ...auth.TokenOption) int {
+		token, err := auth.GenerateToken(testSecret, testIssuer, uuid.New(), uuid.New(), "analyst", 24, opts...)
+		require.NoError(t, err)
+		req := httptest.NewRequest("GET", "/protected", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, 200, serve(), "user tokens are not looked up")
+	assert.Equal(t, 0, checker.checked)
+
+	assert.Equal(t, 200, serve(auth.AsServiceAccount(liveID)))
+	assert.Equal(t, 401, serve(auth.AsServiceAccount(revokedID)))
+	assert.Equal(t, 2, checker.checked)
+
+	checker.err = errors.New("database down")
+	assert.Equal(t, 500, serve(auth.AsServiceAccount(liveID)), "tokens are not admitted when the check fails")
+}
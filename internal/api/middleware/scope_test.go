@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/pkg/auth"
+)
+
+func TestRequireScope(t *testing.T) {
+	cfg := testJWTConfig()
+	r := setupRouter(cfg)
+	r.POST("/runs",
+		AuthMiddleware(cfg),
+		RequireRole("analyst"),
+		RequireScope(ScopeRunsWrite),
+		func(c *gin.Context) { c.JSON(200, gin.H{"ok": true}) },
+	)
+
+	tests := []struct {
+		name     string
+		role     string
+		scopes   []string
+		wantCode int
+	}{
+		{"role-only token relies on role", "analyst", nil, 200},
+		{"scoped token with required scope", "analyst", []string{ScopeRunsWrite}, 200},
+		{"scoped token missing required scope", "analyst", []string{ScopeUploadsWrite}, 403},
+		{"scope does not lift role", "viewer", []string{ScopeRunsWrite}, 403},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := auth.GenerateToken(testSecret, testIssuer, uuid.New(), uuid.New(), tt.role, 24, auth.GrantScopes(tt.scopes...))
+			require.NoError(t, err)
+			req := httptest.NewRequest("POST", "/runs", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/workforce-ai/site-selection-iq/pkg/timing"
+)
+
+// ServerTimingHeader reports where a request's time went, per the W3C
+// Server-Timing spec, so browsers and observability tools can display it.
+const ServerTimingHeader = "Server-Timing"
+
+// ServerTiming attaches a timing.Recorder to the request context, which
+// repositories (db) and the scoring engine (scoring) add to, and emits the
+// accumulated spans plus the total handler duration as a Server-Timing
+// header. The header is written just before the response headers are sent,
+// so total covers the time up to the first byte of the response.
+func ServerTiming() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, rec := timing.NewContext(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+
+		w := &serverTimingWriter{ResponseWriter: c.Writer, rec: rec, start: time.Now()}
+		c.Writer = w
+
+		c.Next()
+
+		// Responses without a body (c.Status) are only flushed by gin after
+		// the chain returns, bypassing this writer; write the header now.
+		w.WriteHeaderNow()
+	}
+}
+
+// serverTimingWriter sets the Server-Timing header the first time response
+// headers are about to be written.
+type serverTimingWriter struct {
+	gin.ResponseWriter
+	rec   *timing.Recorder
+	start time.Time
+	done  bool
+}
+
+func (w *serverTimingWriter) setHeader() {
+	if w.done || w.ResponseWriter.Written() {
+		return
+	}
+	w.done = true
+	w.Header().Set(ServerTimingHeader, w.rec.Header(time.Since(w.start)))
+}
+
+func (w *serverTimingWriter) WriteHeaderNow() {
+	w.setHeader()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *serverTimingWriter) Write(data []byte) (int, error) {
+	w.setHeader()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *serverTimingWriter) WriteString(s string) (int, error) {
+	w.setHeader()
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *serverTimingWriter) Flush() {
+	w.setHeader()
+	w.ResponseWriter.Flush()
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/pkg/timing"
+)
+
+// serverTimingMetric matches one Server-Timing metric: name, a dur in
+// milliseconds and an optional quoted description.
+var serverTimingMetric = regexp.MustCompile(`^[a-z]+;dur=\d+\.\d{2}(;desc="[^"]*")?$`)
+
+func assertServerTimingWellFormed(t *testing.T, header string) {
+	t.Helper()
+	require.NotEmpty(t, header, "Server-Timing header should be set")
+	for _, metric := range regexp.MustCompile(`,\s*`).Split(header, -1) {
+		assert.Regexp(t, serverTimingMetric, metric)
+	}
+}
+
+func TestServerTiming(t *testing.T) {
+	r := setupRouter(nil)
+	r.Use(ServerTiming())
+	r.GET("/json", func(c *gin.Context) {
+		ctx := c.Request.Context()
+		timing.Add(ctx, timing.SpanDB, 3*time.Millisecond)
+		timing.Add(ctx, timing.SpanScoring, 5*time.Millisecond)
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+	r.GET("/empty", func(c *gin.Context) { c.Status(http.StatusNotModified) })
+	r.GET("/abort", func(c *gin.Context) { c.AbortWithStatus(http.StatusForbidden) })
+
+	t.Run("reports recorded spans and total", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/json", nil))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		header := w.Result().Header.Get(ServerTimingHeader)
+		assertServerTimingWellFormed(t, header)
+		assert.Regexp(t, `^db;dur=3\.00;desc="1 call", scoring;dur=5\.00;desc="1 call", total;dur=`, header)
+	})
+
+	for _, path := range []string{"/empty", "/abort"} {
+		t.Run("bodyless response "+path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+			header := w.Result().Header.Get(ServerTimingHeader)
+			assertServerTimingWellFormed(t, header)
+			assert.Regexp(t, `^total;dur=`, header)
+		})
+	}
+}
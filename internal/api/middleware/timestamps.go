@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/workforce-ai/site-selection-iq/internal/api/response"
+)
+
+// ResponseTimestamps sets how the timestamp in response metadata is
+// rendered: def, unless the request names an IANA time zone in an
+// Accept-Timezone header or a format (rfc3339, rfc3339_millis or unix_ms) in
+// an Accept-Timestamp-Format header. Values that cannot be used are ignored
+// in favour of def, since a display preference should not fail the request.
+func ResponseTimestamps(def response.TimestampFormat) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		format := def
+
+		if name := c.GetHeader("Accept-Timestamp-Format"); name != "" {
+			if requested, err := response.ParseTimestampFormat(name, ""); err == nil {
+				format.Format = requested.Format
+			}
+		}
+		if zone := c.GetHeader("Accept-Timezone"); zone != "" {
+			if requested, err := response.ParseTimestampFormat("", zone); err == nil {
+				format.Location = requested.Location
+			}
+		}
+
+		c.Set("timestamp_format", format)
+		c.Next()
+	}
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/api/response"
+)
+
+func TestResponseTimestamps(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	def, err := response.ParseTimestampFormat(response.TimestampRFC3339, "Europe/Berlin")
+	require.NoError(t, err)
+
+	r := gin.New()
+	r.Use(ResponseTimestamps(def))
+	r.GET("/ping", func(c *gin.Context) { response.Success(c, http.StatusOK, nil) })
+
+	timestamp := func(headers map[string]string) interface{} {
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var env struct {
+			Meta struct {
+				Timestamp interface{} `json:"timestamp"`
+			} `json:"meta"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &env))
+		return env.Meta.Timestamp
+	}
+	parse := func(v interface{}) time.Time {
+		s, ok := v.(string)
+		require.True(t, ok, "timestamp should be a string, got %T", v)
+		ts, err := time.Parse(time.RFC3339, s)
+		require.NoError(t, err)
+		return ts
+	}
+	offset := func(ts time.Time) int {
+		_, off := ts.Zone()
+		return off
+	}
+
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	_, berlinOffset := time.Now().In(berlin).Zone()
+	assert.Equal(t, berlinOffset, offset(parse(timestamp(nil))), "configured zone is the default")
+
+	got := parse(timestamp(map[string]string{"Accept-Timezone": "UTC"}))
+	assert.Zero(t, offset(got), "header overrides the zone")
+
+	millis, ok := timestamp(map[string]string{"Accept-Timestamp-Format": "unix_ms"}).(float64)
+	require.True(t, ok, "unix_ms renders a JSON number")
+	assert.InDelta(t, float64(time.Now().UnixMilli()), millis, float64(time.Minute.Milliseconds()))
+
+	got = parse(timestamp(map[string]string{"Accept-Timezone": "Nowhere/Special", "Accept-Timestamp-Format": "iso"}))
+	assert.Equal(t, berlinOffset, offset(got), "unusable values fall back to the default")
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/workforce-ai/site-selection-iq/internal/api/response"
+)
+
+const (
+	// APIVersionHeader names the request header selecting a response
+	// contract version, echoed on every response with the version served.
+	APIVersionHeader = "API-Version"
+
+	// CurrentAPIVersion is served when a request does not ask for a version.
+	CurrentAPIVersion = "1"
+
+	// vendorMediaTypePrefix precedes the version in an Accept media type,
+	// e.g. application/vnd.ssiq.v1+json.
+	vendorMediaTypePrefix = "application/vnd.ssiq.v"
+)
+
+// supportedAPIVersions lists the response contract versions this server can
+// produce. A v2 shape is added here alongside v1 so both can coexist.
+var supportedAPIVersions = []string{"1"}
+
+// APIVersion negotiates the response contract version. The API-Version
+// header wins; otherwise a vendor media type in Accept
+// (application/vnd.ssiq.v1+json) selects it, and with neither the current
+// version is served. Handlers read the result from the "api_version"
+// context key. An unsupported version is rejected with 406.
+func APIVersion() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		version := strings.TrimSpace(c.GetHeader(APIVersionHeader))
+		if version == "" {
+			version = versionFromAccept(c.GetHeader("Accept"))
+		}
+		if version == "" {
+			version = CurrentAPIVersion
+		}
+
+		if !apiVersionSupported(version) {
+			response.Error(c, http.StatusNotAcceptable, response.CodeNotAcceptable,
+				"unsupported API version "+version, gin.H{"supported_versions": supportedAPIVersions})
+			c.Abort()
+			return
+		}
+
+		c.Set("api_version", version)
+		c.Header(APIVersionHeader, version)
+		c.Next()
+	}
+}
+
+// versionFromAccept returns the version named by the first vendor media
+// type in an Accept header, or "" if there is none.
+func versionFromAccept(accept string) string {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || !strings.HasPrefix(mediaType, vendorMediaTypePrefix) {
+			continue
+		}
+		version, ok := strings.CutSuffix(strings.TrimPrefix(mediaType, vendorMediaTypePrefix), "+json")
+		if ok && version != "" {
+			return version
+		}
+	}
+	return ""
+}
+
+func apiVersionSupported(version string) bool {
+	for _, v := range supportedAPIVersions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
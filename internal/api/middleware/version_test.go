@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIVersion(t *testing.T) {
+	r := setupRouter(nil)
+	r.GET("/runs", APIVersion(), func(c *gin.Context) {
+		c.JSON(200, gin.H{"api_version": c.GetString("api_version")})
+	})
+
+	tests := []struct {
+		name        string
+		header      string
+		accept      string
+		wantCode    int
+		wantVersion string
+	}{
+		{"defaults to current version", "", "", 200, CurrentAPIVersion},
+		{"header selects version", "1", "", 200, "1"},
+		{"vendor media type selects version", "", "text/html, application/vnd.ssiq.v1+json; q=0.9", 200, "1"},
+		{"plain json accept uses current version", "", "application/json", 200, CurrentAPIVersion},
+		{"header wins over accept", "1", "application/vnd.ssiq.v2+json", 200, "1"},
+		{"unsupported header version", "2", "", http.StatusNotAcceptable, ""},
+		{"unsupported media type version", "", "application/vnd.ssiq.v9+json", http.StatusNotAcceptable, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/runs", nil)
+			if tt.header != "" {
+				req.Header.Set(APIVersionHeader, tt.header)
+			}
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.Equal(t, tt.wantVersion, w.Header().Get(APIVersionHeader))
+			if tt.wantCode == http.StatusNotAcceptable {
+				assert.Contains(t, w.Body.String(), `"supported_versions":["1"]`)
+			}
+		})
+	}
+}
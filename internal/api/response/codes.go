@@ -0,0 +1,20 @@
+package response
+
+// Machine-readable error codes carried in ErrorBody.Code. Clients branch on
+// these rather than on messages, so existing values must not change.
+const (
+	CodeValidationError    = "VALIDATION_ERROR"    // 400: malformed request or rejected input
+	CodeUnauthorized       = "UNAUTHORIZED"        // 401: missing or invalid credentials
+	CodeForbidden          = "FORBIDDEN"           // 403: authenticated but not permitted
+	CodeNotFound           = "NOT_FOUND"           // 404: resource does not exist for this tenant
+	CodeNotAcceptable      = "NOT_ACCEPTABLE"      // 406: unsupported API version requested
+	CodeDuplicate          = "DUPLICATE"           // 409: idempotency key already used
+	CodeConflict           = "CONFLICT"            // 409: resource state forbids the operation
+	CodeFileTooLarge       = "FILE_TOO_LARGE"      // 413: upload exceeds the size limit
+	CodePayloadTooLarge    = "PAYLOAD_TOO_LARGE"   // 413: JSON request body exceeds the size limit
+	CodeUnsupportedMedia   = "UNSUPPORTED_MEDIA"   // 415: request body is in a format the endpoint does not accept
+	CodeUnprocessable      = "UNPROCESSABLE"       // 422: valid request the resource cannot satisfy
+	CodeInternalError      = "INTERNAL_ERROR"      // 500: unexpected server failure
+	CodeServiceUnavailable = "SERVICE_UNAVAILABLE" // 503: a dependency (e.g. the database) is down
+	CodeScanUnavailable    = "SCAN_UNAVAILABLE"    // 503: the malware scan could not be completed
+)
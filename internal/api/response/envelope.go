@@ -23,10 +23,11 @@ type ErrorBody struct {
 	Details interface{} `json:"details,omitempty"`
 }
 
-// Meta holds response metadata.
+// Meta holds response metadata. Timestamp is a string unless the request's
+// TimestampFormat is TimestampUnixMillis, when it is a number.
 type Meta struct {
-	CorrelationID string `json:"correlation_id"`
-	Timestamp     string `json:"timestamp"`
+	CorrelationID string      `json:"correlation_id"`
+	Timestamp     interface{} `json:"timestamp"`
 }
 
 func newMeta(c *gin.Context) Meta {
@@ -35,9 +36,11 @@ func newMeta(c *gin.Context) Meta {
 	if !ok {
 		corrIDStr = uuid.New().String()
 	}
+	format, _ := c.Get("timestamp_format")
+	timestampFormat, _ := format.(TimestampFormat)
 	return Meta{
 		CorrelationID: corrIDStr,
-		Timestamp:     time.Now().UTC().Format(time.RFC3339),
+		Timestamp:     timestampFormat.Render(time.Now()),
 	}
 }
 
@@ -65,22 +68,24 @@ func Error(c *gin.Context, statusCode int, code, message string, details interfa
 
 // BadRequest sends a 400 error.
 func BadRequest(c *gin.Context, message string, details interface{}) {
-	Error(c, http.StatusBadRequest, "VALIDATION_ERROR", message, details)
+	Error(c, http.StatusBadRequest, CodeValidationError, message, details)
 }
 
 // NotFound sends a 404 error.
 func NotFound(c *gin.Context, message string) {
-	Error(c, http.StatusNotFound, "NOT_FOUND", message, nil)
+	Error(c, http.StatusNotFound, CodeNotFound, message, nil)
 }
 
-// Conflict sends a 409 error.
-func Conflict(c *gin.Context, message string, data interface{}) {
+// Conflict sends a 409 error carrying the existing resource as data.
+// details describes the existing resource's state and may be nil.
+func Conflict(c *gin.Context, message string, data interface{}, details interface{}) {
 	c.JSON(http.StatusConflict, Envelope{
 		Status: "success",
 		Data:   data,
 		Error: &ErrorBody{
-			Code:    "DUPLICATE",
+			Code:    CodeDuplicate,
 			Message: message,
+			Details: details,
 		},
 		Meta: newMeta(c),
 	})
@@ -88,15 +93,20 @@ func Conflict(c *gin.Context, message string, data interface{}) {
 
 // InternalError sends a 500 error.
 func InternalError(c *gin.Context, message string) {
-	Error(c, http.StatusInternalServerError, "INTERNAL_ERROR", message, nil)
+	Error(c, http.StatusInternalServerError, CodeInternalError, message, nil)
 }
 
 // Unauthorized sends a 401 error.
 func Unauthorized(c *gin.Context, message string) {
-	Error(c, http.StatusUnauthorized, "UNAUTHORIZED", message, nil)
+	Error(c, http.StatusUnauthorized, CodeUnauthorized, message, nil)
 }
 
 // Forbidden sends a 403 error.
 func Forbidden(c *gin.Context, message string) {
-	Error(c, http.StatusForbidden, "FORBIDDEN", message, nil)
+	Error(c, http.StatusForbidden, CodeForbidden, message, nil)
+}
+
+// ServiceUnavailable sends a 503 error.
+func ServiceUnavailable(c *gin.Context, message string) {
+	Error(c, http.StatusServiceUnavailable, CodeServiceUnavailable, message, nil)
 }
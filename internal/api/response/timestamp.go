@@ -0,0 +1,60 @@
+package response
+
+import (
+	"fmt"
+	"time"
+)
+
+// Formats for the timestamp in response metadata.
+const (
+	TimestampRFC3339       = "rfc3339"        // 2024-01-15T10:20:00Z
+	TimestampRFC3339Millis = "rfc3339_millis" // 2024-01-15T10:20:00.000Z
+	TimestampUnixMillis    = "unix_ms"        // 1705314000000, a JSON number
+)
+
+// rfc3339Millis is RFC 3339 with exactly three fractional digits.
+const rfc3339Millis = "2006-01-02T15:04:05.000Z07:00"
+
+// TimestampFormat controls how the timestamp in response metadata is
+// rendered. A request's format is stored in the gin context under
+// "timestamp_format"; without one, RFC 3339 in UTC is used.
+type TimestampFormat struct {
+	Format   string         // one of the Timestamp* constants; empty means TimestampRFC3339
+	Location *time.Location // zone RFC 3339 timestamps are rendered in; nil means UTC
+}
+
+// ParseTimestampFormat returns the TimestampFormat for a format name and an
+// IANA time zone name such as "America/Chicago". An empty format or zone
+// takes the default.
+func ParseTimestampFormat(format, zone string) (TimestampFormat, error) {
+	switch format {
+	case "", TimestampRFC3339, TimestampRFC3339Millis, TimestampUnixMillis:
+	default:
+		return TimestampFormat{}, fmt.Errorf("unknown timestamp format %q", format)
+	}
+	loc := time.UTC
+	if zone != "" {
+		var err error
+		if loc, err = time.LoadLocation(zone); err != nil {
+			return TimestampFormat{}, err
+		}
+	}
+	return TimestampFormat{Format: format, Location: loc}, nil
+}
+
+// Render returns t in the format: a string for the RFC 3339 formats, or an
+// int64 of milliseconds since the Unix epoch.
+func (f TimestampFormat) Render(t time.Time) interface{} {
+	loc := f.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	switch f.Format {
+	case TimestampUnixMillis:
+		return t.UnixMilli()
+	case TimestampRFC3339Millis:
+		return t.In(loc).Format(rfc3339Millis)
+	default:
+		return t.In(loc).Format(time.RFC3339)
+	}
+}
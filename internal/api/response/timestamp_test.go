@@ -0,0 +1,34 @@
+package response
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimestampFormat_Render(t *testing.T) {
+	ts := time.Date(2024, 1, 15, 10, 20, 0, 123456789, time.UTC)
+
+	assert.Equal(t, "2024-01-15T10:20:00Z", TimestampFormat{}.Render(ts), "default is RFC 3339 in UTC")
+
+	chicago, err := ParseTimestampFormat(TimestampRFC3339Millis, "America/Chicago")
+	require.NoError(t, err)
+	assert.Equal(t, "2024-01-15T04:20:00.123-06:00", chicago.Render(ts))
+
+	unixMillis, err := ParseTimestampFormat(TimestampUnixMillis, "Asia/Tokyo")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1705314000123), unixMillis.Render(ts), "epoch millis ignore the zone")
+}
+
+func TestParseTimestampFormat_Invalid(t *testing.T) {
+	_, err := ParseTimestampFormat("iso8601", "")
+	assert.ErrorContains(t, err, `unknown timestamp format "iso8601"`)
+
+	_, err = ParseTimestampFormat("", "Mars/Olympus_Mons")
+	assert.Error(t, err)
+
+	_, err = ParseTimestampFormat("", "../../etc/passwd")
+	assert.Error(t, err)
+}
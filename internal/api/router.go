@@ -8,15 +8,22 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/workforce-ai/site-selection-iq/internal/api/handlers"
 	"github.com/workforce-ai/site-selection-iq/internal/api/middleware"
+	"github.com/workforce-ai/site-selection-iq/internal/api/response"
 	"github.com/workforce-ai/site-selection-iq/internal/config"
+	"github.com/workforce-ai/site-selection-iq/internal/db"
+	"github.com/workforce-ai/site-selection-iq/internal/ingest"
 	"github.com/workforce-ai/site-selection-iq/internal/repository"
 	"github.com/workforce-ai/site-selection-iq/internal/schema"
 	"github.com/workforce-ai/site-selection-iq/internal/scoring"
+	"github.com/workforce-ai/site-selection-iq/internal/storage"
 	"github.com/workforce-ai/site-selection-iq/pkg/auth"
 )
 
 // NewRouter creates and configures the Gin router with all routes and middleware.
-func NewRouter(pool *pgxpool.Pool, cfg *config.Config) *gin.Engine {
+// The scoring pipeline and upload processor are returned alongside so the
+// caller can manage their lifecycles. dbMonitor backs the readiness probe and
+// fails API requests fast while the database is unreachable.
+func NewRouter(pool *pgxpool.Pool, dbMonitor *db.Monitor, cfg *config.Config) (*gin.Engine, *scoring.Pipeline, *ingest.Processor) {
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.New()
 
@@ -24,7 +31,19 @@ func NewRouter(pool *pgxpool.Pool, cfg *config.Config) *gin.Engine {
 	r.Use(gin.Recovery())
 	r.Use(middleware.CORSMiddleware())
 	r.Use(middleware.CorrelationMiddleware())
-	r.Use(middleware.StructuredLogging())
+
+	// Checked at startup by ServerConfig.Validate
+	timestampFormat, _ := response.ParseTimestampFormat(cfg.Server.TimestampFormat, cfg.Server.TimeZone)
+	r.Use(middleware.ResponseTimestamps(timestampFormat))
+	logOpts := []middleware.LoggingOption{
+		middleware.WithSampling(cfg.Server.LogSampleRate),
+		middleware.WithSlowThreshold(cfg.Server.SlowRequestThreshold),
+	}
+	if cfg.Server.LogBodies {
+		logOpts = append(logOpts, middleware.WithBodyLogging(cfg.Server.LogBodyMaxBytes))
+	}
+	r.Use(middleware.StructuredLogging(logOpts...))
+	r.Use(middleware.ServerTiming())
 
 	// Health check (no auth required)
 	r.GET("/health", func(c *gin.Context) {
@@ -34,17 +53,28 @@ func NewRouter(pool *pgxpool.Pool, cfg *config.Config) *gin.Engine {
 		})
 	})
 
+	// Readiness check (no auth required): database reachability and pool stats
+	healthHandler := handlers.NewHealthHandler(dbMonitor)
+	r.GET("/ready", healthHandler.HandleReady)
+
 	// Initialize repositories
-	uploadRepo := repository.NewUploadRepository(pool)
-	siteRecordRepo := repository.NewSiteRecordRepository(pool)
-	runRepo := repository.NewRunRepository(pool)
-	recRepo := repository.NewRecommendationRepository(pool)
-	schemaConfigRepo := repository.NewSchemaConfigRepository(pool)
-	idempotencyRepo := repository.NewIdempotencyRepository(pool)
+	uploadRepo := repository.NewUploadRepository(pool, cfg.Database.QueryTimeout)
+	siteRecordRepo := repository.NewSiteRecordRepository(pool, cfg.Database.QueryTimeout)
+	runRepo := repository.NewRunRepository(pool, cfg.Database.QueryTimeout)
+	recRepo := repository.NewRecommendationRepository(pool, cfg.Database.QueryTimeout)
+	schemaConfigRepo := repository.NewSchemaConfigRepository(pool, cfg.Database.QueryTimeout)
+	idempotencyRepo := repository.NewIdempotencyRepository(pool, cfg.Database.QueryTimeout)
+	auditRepo := repository.NewAuditRepository(pool, cfg.Database.QueryTimeout)
+	presetRepo := repository.NewWeightPresetRepository(pool, cfg.Database.QueryTimeout)
+	skippedSiteRepo := repository.NewSkippedSiteRepository(pool, cfg.Database.QueryTimeout)
+	serviceTokenRepo := repository.NewServiceTokenRepository(pool, cfg.Database.QueryTimeout)
 
 	// Initialize services
 	schemaResolver := schema.NewResolver()
-	scoreFn := scoring.DefaultScoreFunc
+	if cfg.Scoring.SchemaCacheTTL > 0 {
+		schemaResolver.SetCache(schema.NewCache(cfg.Scoring.SchemaCacheTTL))
+	}
+	scoreFuncs := scoring.NewScoreFuncRegistry()
 
 	// Initialize scoring pipeline
 	pipeline := scoring.NewPipeline(
@@ -53,45 +83,221 @@ func NewRouter(pool *pgxpool.Pool, cfg *config.Config) *gin.Engine {
 		recRepo,
 		schemaConfigRepo,
 		schemaResolver,
-		scoreFn,
+		scoreFuncs,
 		cfg.Scoring.MaxRetries,
 		cfg.Scoring.RetryBaseWait,
+		cfg.Scoring.RunTimeout,
 	)
+	pipeline.SetMaxConcurrentRuns(cfg.Scoring.MaxConcurrent)
+	pipeline.SetHeartbeatInterval(cfg.Scoring.Heartbeat)
+	pipeline.SetRecommendationCap(cfg.Scoring.MaxRecommendations, cfg.Scoring.TruncateRecommendations)
+	pipeline.SetSkipTolerance(cfg.Scoring.SkipTolerance)
+	pipeline.SetSkippedSiteStore(skippedSiteRepo, cfg.Scoring.MaxSkippedSites)
+	pipeline.SetUploadStore(uploadRepo)
+	pipeline.SetDefaultLocale(cfg.Scoring.DefaultLocale)
+
+	// Initialize upload processor (async uploads run in its background workers)
+	processor := ingest.NewProcessor(uploadRepo, siteRecordRepo, cfg.Upload)
+
+	// Uploads are scanned by clamd when configured; otherwise every file is accepted
+	var scanner ingest.MalwareScanner = ingest.NoopScanner{}
+	if cfg.Upload.ClamAVAddress != "" {
+		scanner = ingest.NewClamAVScanner(cfg.Upload.ClamAVAddress, cfg.Upload.ScanTimeout)
+	}
 
 	// Initialize handlers
-	uploadHandler := handlers.NewUploadHandler(uploadRepo, siteRecordRepo, schemaConfigRepo, idempotencyRepo, schemaResolver, cfg)
-	runHandler := handlers.NewRunHandler(runRepo, uploadRepo, idempotencyRepo, pipeline, cfg)
+	uploadHandler := handlers.NewUploadHandler(uploadRepo, siteRecordRepo, schemaConfigRepo, idempotencyRepo, runRepo, schemaResolver, processor, scanner, auditRepo, cfg)
+	if cfg.Upload.RetainOriginals {
+		uploadHandler.SetOriginalStore(newBlobStore(cfg.Storage, cfg.Upload.OriginalsDir, "originals/"))
+	}
+	runHandler := handlers.NewRunHandler(runRepo, uploadRepo, idempotencyRepo, pipeline, auditRepo, recRepo, schemaConfigRepo, presetRepo, skippedSiteRepo, cfg)
 	recHandler := handlers.NewRecommendationHandler(recRepo, runRepo, schemaConfigRepo)
+	schemaHandler := handlers.NewSchemaHandler(schemaConfigRepo, schemaResolver, auditRepo)
+	auditHandler := handlers.NewAuditHandler(auditRepo)
+	presetHandler := handlers.NewWeightPresetHandler(presetRepo, schemaConfigRepo, schemaResolver, auditRepo)
+	serviceTokenHandler := handlers.NewServiceTokenHandler(serviceTokenRepo, auditRepo, cfg)
 
 	// API v1 routes (authenticated)
 	v1 := r.Group("/api/v1")
+	v1.Use(middleware.APIVersion())
+	v1.Use(middleware.RequireDatabase(dbMonitor))
 	v1.Use(middleware.AuthMiddleware(&cfg.JWT))
+	v1.Use(middleware.RejectRevokedServiceTokens(serviceTokenRepo))
+	v1.Use(middleware.JSONBodyLimit(cfg.Server.MaxJSONBodyBytes))
 	{
-		// Uploads — require admin or analyst role
+		// Token introspection — any authenticated caller
+		v1.GET("/whoami",
+			middleware.RequireRole("viewer"),
+			handlers.HandleWhoAmI,
+		)
+
+		// Uploads — analysts and above can upload, all roles can view
 		v1.POST("/uploads",
-			middleware.RequireRole("admin", "analyst"),
+			middleware.RequireRole("analyst"),
+			middleware.RequireScope(middleware.ScopeUploadsWrite),
 			uploadHandler.HandleUpload,
 		)
+		v1.POST("/uploads/:upload_id/append",
+			middleware.RequireRole("analyst"),
+			middleware.RequireScope(middleware.ScopeUploadsWrite),
+			uploadHandler.HandleAppend,
+		)
+		v1.GET("/uploads/:upload_id",
+			middleware.RequireRole("viewer"),
+			uploadHandler.HandleGetUpload,
+		)
+		v1.GET("/uploads/:upload_id/original",
+			middleware.RequireRole("viewer"),
+			uploadHandler.HandleGetOriginal,
+		)
+		v1.GET("/uploads/:upload_id/records",
+			middleware.RequireRole("viewer"),
+			uploadHandler.HandleGetRecords,
+		)
+
+		// Tenant schema config — all roles can view, only admins can change
+		v1.GET("/schema-config",
+			middleware.RequireRole("viewer"),
+			schemaHandler.HandleGetTenantSchema,
+		)
+		v1.PUT("/schema-config",
+			middleware.RequireRole("admin"),
+			middleware.RequireScope(middleware.ScopeSchemaWrite),
+			schemaHandler.HandlePutTenantSchema,
+		)
+		v1.POST("/schema-config/import",
+			middleware.RequireRole("admin"),
+			middleware.RequireScope(middleware.ScopeSchemaWrite),
+			schemaHandler.HandleImportTenantSchema,
+		)
+		v1.POST("/schema-config/preview",
+			middleware.RequireRole("admin"),
+			schemaHandler.HandlePreviewTenantSchema,
+		)
+		v1.POST("/schema-config/rescore",
+			middleware.RequireRole("admin"),
+			middleware.RequireScope(middleware.ScopeRunsWrite),
+			runHandler.HandleRescoreTenant,
+		)
 
-		// Scoring runs — require admin or analyst role
+		// Weight presets — all roles can view, analysts and above can change
+		v1.GET("/weight-presets",
+			middleware.RequireRole("viewer"),
+			presetHandler.HandleListPresets,
+		)
+		v1.POST("/weight-presets",
+			middleware.RequireRole("analyst"),
+			middleware.RequireScope(middleware.ScopeRunsWrite),
+			presetHandler.HandleCreatePreset,
+		)
+		v1.GET("/weight-presets/:name",
+			middleware.RequireRole("viewer"),
+			presetHandler.HandleGetPreset,
+		)
+		v1.PUT("/weight-presets/:name",
+			middleware.RequireRole("analyst"),
+			middleware.RequireScope(middleware.ScopeRunsWrite),
+			presetHandler.HandleUpdatePreset,
+		)
+		v1.DELETE("/weight-presets/:name",
+			middleware.RequireRole("analyst"),
+			middleware.RequireScope(middleware.ScopeRunsWrite),
+			presetHandler.HandleDeletePreset,
+		)
+
+		// Service account tokens — admins only
+		v1.GET("/service-tokens",
+			middleware.RequireRole("admin"),
+			serviceTokenHandler.HandleListServiceTokens,
+		)
+		v1.POST("/service-tokens",
+			middleware.RequireRole("admin"),
+			middleware.RequireScope(middleware.ScopeTokensWrite),
+			serviceTokenHandler.HandleMintServiceTokens,
+		)
+		v1.DELETE("/service-tokens/:token_id",
+			middleware.RequireRole("admin"),
+			middleware.RequireScope(middleware.ScopeTokensWrite),
+			serviceTokenHandler.HandleRevokeServiceToken,
+		)
+
+		// Audit log — admins only
+		v1.GET("/audit",
+			middleware.RequireRole("admin"),
+			auditHandler.HandleListAudit,
+		)
+
+		// Scoring runs — analysts and above can create
 		v1.POST("/uploads/:upload_id/runs",
-			middleware.RequireRole("admin", "analyst"),
+			middleware.RequireRole("analyst"),
+			middleware.RequireScope(middleware.ScopeRunsWrite),
 			runHandler.HandleCreateRun,
 		)
+		v1.POST("/uploads/:upload_id/sensitivity",
+			middleware.RequireRole("analyst"),
+			runHandler.HandleSensitivity,
+		)
 		v1.GET("/runs/:run_id",
-			middleware.RequireRole("admin", "analyst", "viewer"),
+			middleware.RequireRole("viewer"),
 			runHandler.HandleGetRun,
 		)
 
+		// Failed-run triage — admins only
+		v1.GET("/runs/failed",
+			middleware.RequireRole("admin"),
+			runHandler.HandleListFailedRuns,
+		)
+		v1.POST("/runs/:run_id/requeue",
+			middleware.RequireRole("admin"),
+			middleware.RequireScope(middleware.ScopeRunsWrite),
+			runHandler.HandleRequeueRun,
+		)
+		v1.GET("/runs/:run_id/skipped",
+			middleware.RequireRole("viewer"),
+			runHandler.HandleListSkippedSites,
+		)
+		v1.POST("/runs/:run_id/verify",
+			middleware.RequireRole("analyst"),
+			runHandler.HandleVerifyRun,
+		)
+		v1.POST("/runs/:run_id/rerank",
+			middleware.RequireRole("analyst"),
+			runHandler.HandleRerankRun,
+		)
+
 		// Recommendations — all authenticated roles can view
 		v1.GET("/runs/:run_id/recommendations",
-			middleware.RequireRole("admin", "analyst", "viewer"),
+			middleware.RequireRole("viewer"),
 			recHandler.HandleGetRecommendations,
 		)
+		v1.GET("/runs/:run_id/recommendations/top",
+			middleware.RequireRole("viewer"),
+			recHandler.HandleGetTop,
+		)
+		v1.GET("/runs/:run_id/recommendations/bottom",
+			middleware.RequireRole("viewer"),
+			recHandler.HandleGetBottom,
+		)
+		v1.GET("/runs/:run_id/explanations",
+			middleware.RequireRole("viewer"),
+			recHandler.HandleGetExplanations,
+		)
+		v1.GET("/runs/:run_id/histogram",
+			middleware.RequireRole("viewer"),
+			recHandler.HandleGetHistogram,
+		)
+		v1.GET("/runs/:run_id/rollup",
+			middleware.RequireRole("viewer"),
+			recHandler.HandleGetRollup,
+		)
 		v1.GET("/runs/:run_id/recommendations/:site_id/explain",
-			middleware.RequireRole("admin", "analyst", "viewer"),
+			middleware.RequireRole("viewer"),
 			recHandler.HandleGetExplanation,
 		)
+		v1.GET("/runs/:run_id/recommendations/:site_id/explain.md",
+			middleware.RequireRole("viewer"),
+			recHandler.HandleGetExplanationMarkdown,
+		)
 	}
 
 	// Token generation endpoint (dev only — generates test JWTs)
@@ -107,39 +313,51 @@ func NewRouter(pool *pgxpool.Pool, cfg *config.Config) *gin.Engine {
 		c.Redirect(http.StatusMovedPermanently, "/static/swagger.html")
 	})
 
-	return r
+	return r, pipeline, processor
+}
+
+// newBlobStore returns the configured storage backend: files under localDir,
+// or objects under prefix in the S3 bucket.
+func newBlobStore(cfg config.StorageConfig, localDir, prefix string) storage.BlobStore {
+	if cfg.Backend == "s3" {
+		s3cfg := cfg.S3
+		s3cfg.Prefix += prefix
+		return storage.NewS3BlobStore(s3cfg)
+	}
+	return storage.NewLocalBlobStore(localDir)
 }
 
 // devTokenHandler returns a handler that generates test JWTs for development.
 func devTokenHandler(cfg *config.Config) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var req struct {
-			TenantID string `json:"tenant_id"`
-			UserID   string `json:"user_id"`
-			Role     string `json:"role"`
+			TenantID string   `json:"tenant_id"`
+			UserID   string   `json:"user_id"`
+			Role     string   `json:"role"`
+			Scopes   []string `json:"scopes"`
 		}
 		if err := c.ShouldBindJSON(&req); err != nil {
-			c.JSON(400, gin.H{"error": "invalid request"})
+			response.BadRequest(c, "invalid request", nil)
 			return
 		}
 
 		tenantID, err := uuid.Parse(req.TenantID)
 		if err != nil {
-			c.JSON(400, gin.H{"error": "invalid tenant_id"})
+			response.BadRequest(c, "invalid tenant_id", nil)
 			return
 		}
 		userID, err := uuid.Parse(req.UserID)
 		if err != nil {
-			c.JSON(400, gin.H{"error": "invalid user_id"})
+			response.BadRequest(c, "invalid user_id", nil)
 			return
 		}
 		if req.Role == "" {
 			req.Role = "admin"
 		}
 
-		token, err := auth.GenerateToken(cfg.JWT.Secret, cfg.JWT.Issuer, tenantID, userID, req.Role, cfg.JWT.ExpiryHours)
+		token, err := auth.GenerateToken(cfg.JWT.Secret, cfg.JWT.Issuer, tenantID, userID, req.Role, cfg.JWT.ExpiryHours, auth.ForAudience(cfg.JWT.Audience), auth.GrantScopes(req.Scopes...))
 		if err != nil {
-			c.JSON(500, gin.H{"error": "failed to generate token"})
+			response.InternalError(c, "failed to generate token")
 			return
 		}
 
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+
+	"github.com/workforce-ai/site-selection-iq/internal/config"
+)
+
+// NewServer builds the HTTP server for handler. When cfg names a TLS
+// certificate and key they are loaded here, so a bad path or mismatched
+// pair fails at startup, and the server must then be started with
+// ListenAndServeTLS("", ""). Without them it serves plaintext HTTP.
+func NewServer(handler http.Handler, cfg config.ServerConfig) (*http.Server, error) {
+	srv := &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           handler,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.HeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+	if !cfg.TLSEnabled() {
+		return srv, nil
+	}
+
+	tlsCfg, err := tlsConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	srv.TLSConfig = tlsCfg
+	return srv, nil
+}
+
+// tlsConfig loads the certificate pair and applies the minimum version and
+// cipher suite hardening settings.
+func tlsConfig(cfg config.ServerConfig) (*tls.Config, error) {
+	if cfg.TLSCertFile == "" || cfg.TLSKeyFile == "" {
+		return nil, fmt.Errorf("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	cert, err := tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load TLS certificate: %w", err)
+	}
+
+	tlsCfg := &tls.Config{Certificates: []tls.Certificate{cert}}
+
+	switch cfg.TLSMinVersion {
+	case "", "1.2":
+		tlsCfg.MinVersion = tls.VersionTLS12
+	case "1.3":
+		tlsCfg.MinVersion = tls.VersionTLS13
+	default:
+		return nil, fmt.Errorf("TLS_MIN_VERSION must be 1.2 or 1.3, got %q", cfg.TLSMinVersion)
+	}
+
+	if len(cfg.TLSCipherSuites) > 0 {
+		// Only secure suites are accepted; TLS 1.3 suites are not configurable
+		ids := make(map[string]uint16)
+		for _, suite := range tls.CipherSuites() {
+			ids[suite.Name] = suite.ID
+		}
+		for _, name := range cfg.TLSCipherSuites {
+			id, ok := ids[name]
+			if !ok {
+				return nil, fmt.Errorf("TLS_CIPHER_SUITES: unknown or insecure cipher suite %q", name)
+			}
+			tlsCfg.CipherSuites = append(tlsCfg.CipherSuites, id)
+		}
+	}
+
+	return tlsCfg, nil
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/config"
+)
+
+// writeServerCert writes a self-signed localhost certificate and key and
+// returns their paths.
+func writeServerCert(t *testing.T) (certPath, keyPath string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certPath = filepath.Join(dir, "server.crt")
+	keyPath = filepath.Join(dir, "server.key")
+	require.NoError(t, os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	return certPath, keyPath
+}
+
+func TestNewServer_PlaintextByDefault(t *testing.T) {
+	srv, err := NewServer(http.NotFoundHandler(), config.ServerConfig{Port: "8080", ReadTimeout: time.Second})
+	require.NoError(t, err)
+	assert.Equal(t, ":8080", srv.Addr)
+	assert.Equal(t, time.Second, srv.ReadTimeout)
+	assert.Nil(t, srv.TLSConfig)
+}
+
+func TestNewServer_SetsTimeouts(t *testing.T) {
+	t.Setenv("SERVER_IDLE_TIMEOUT", "90s")
+	t.Setenv("SERVER_READ_HEADER_TIMEOUT", "")
+	t.Setenv("SERVER_READ_TIMEOUT", "")
+	t.Setenv("SERVER_WRITE_TIMEOUT", "")
+	cfg := config.Load().Server
+
+	srv, err := NewServer(http.NotFoundHandler(), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 90*time.Second, srv.IdleTimeout)
+	assert.Equal(t, 10*time.Second, srv.ReadHeaderTimeout, "default guards against slow headers")
+	assert.Equal(t, 30*time.Second, srv.ReadTimeout)
+	assert.Equal(t, 60*time.Second, srv.WriteTimeout)
+}
+
+func TestNewServer_TLSWhenCertsSet(t *testing.T) {
+	certPath, keyPath := writeServerCert(t)
+	cfg := config.ServerConfig{
+		Port:            "8443",
+		TLSCertFile:     certPath,
+		TLSKeyFile:      keyPath,
+		TLSMinVersion:   "1.2",
+		TLSCipherSuites: []string{"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384"},
+	}
+
+	srv, err := NewServer(http.NotFoundHandler(), cfg)
+	require.NoError(t, err)
+	require.NotNil(t, srv.TLSConfig)
+	assert.Len(t, srv.TLSConfig.Certificates, 1)
+	assert.Equal(t, uint16(tls.VersionTLS12), srv.TLSConfig.MinVersion)
+	assert.Equal(t, []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384}, srv.TLSConfig.CipherSuites)
+
+	cfg.TLSMinVersion = "1.3"
+	cfg.TLSCipherSuites = nil
+	srv, err = NewServer(http.NotFoundHandler(), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS13), srv.TLSConfig.MinVersion)
+	assert.Nil(t, srv.TLSConfig.CipherSuites)
+}
+
+func TestNewServer_RejectsBadTLSConfig(t *testing.T) {
+	certPath, keyPath := writeServerCert(t)
+	valid := config.ServerConfig{TLSCertFile: certPath, TLSKeyFile: keyPath}
+
+	tests := []struct {
+		name    string
+		mutate  func(c *config.ServerConfig)
+		wantErr string
+	}{
+		{"key without cert", func(c *config.ServerConfig) { c.TLSCertFile = "" }, "must be set together"},
+		{"missing file", func(c *config.ServerConfig) { c.TLSKeyFile = filepath.Join(t.TempDir(), "nope.key") }, "load TLS certificate"},
+		{"unknown min version", func(c *config.ServerConfig) { c.TLSMinVersion = "1.0" }, `must be 1.2 or 1.3, got "1.0"`},
+		{"insecure cipher", func(c *config.ServerConfig) { c.TLSCipherSuites = []string{"TLS_RSA_WITH_RC4_128_SHA"} }, "unknown or insecure cipher suite"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			tt.mutate(&cfg)
+			_, err := NewServer(http.NotFoundHandler(), cfg)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -1,98 +1,492 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/workforce-ai/site-selection-iq/internal/i18n"
 )
 
+// EnvProduction is the ENV value of production deployments, where insecure
+// settings stop the service from starting.
+const EnvProduction = "production"
+
 // Config holds all service configuration.
 type Config struct {
+	Environment string // deployment environment from ENV, e.g. development or production
+
 	Server   ServerConfig
 	Database DatabaseConfig
 	JWT      JWTConfig
 	Upload   UploadConfig
+	Storage  StorageConfig
 	Scoring  ScoringConfig
 }
 
 type ServerConfig struct {
-	Port         string
-	ReadTimeout  time.Duration
-	WriteTimeout time.Duration
+	Port            string
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	IdleTimeout     time.Duration // keep-alive connections idle this long are closed
+	HeaderTimeout   time.Duration // deadline for reading request headers (slow-loris guard)
+	ShutdownTimeout time.Duration // budget for draining requests, runs and uploads on SIGTERM
+
+	MaxJSONBodyBytes int64 // non-multipart API request bodies above this get 413; <= 0 disables
+	LogBodies        bool  // log redacted request headers and bodies (debugging only)
+	LogBodyMaxBytes  int   // request body bytes captured per log line
+
+	LogSampleRate        int           // log 1 in N fast successful requests (<= 1 logs all)
+	SlowRequestThreshold time.Duration // requests this slow are tagged slow and never sampled; 0 disables
+
+	TLSCertFile     string   // serve HTTPS when set together with TLSKeyFile
+	TLSKeyFile      string   // PEM private key for TLSCertFile
+	TLSMinVersion   string   // "1.2" or "1.3"
+	TLSCipherSuites []string // TLS 1.2 cipher suite names; empty uses Go's defaults
+
+	TimestampFormat string // response meta timestamps: rfc3339, rfc3339_millis or unix_ms
+	TimeZone        string // IANA zone response meta timestamps are rendered in
+}
+
+// Validate checks that the response timestamp format is known and the time
+// zone can be loaded.
+func (s *ServerConfig) Validate() error {
+	switch s.TimestampFormat {
+	case "rfc3339", "rfc3339_millis", "unix_ms":
+	default:
+		return fmt.Errorf("RESPONSE_TIMESTAMP_FORMAT must be rfc3339, rfc3339_millis or unix_ms, got %q", s.TimestampFormat)
+	}
+	if _, err := time.LoadLocation(s.TimeZone); err != nil {
+		return fmt.Errorf("RESPONSE_TIMEZONE: %w", err)
+	}
+	return nil
+}
+
+// TLSEnabled reports whether the server should serve HTTPS.
+func (s *ServerConfig) TLSEnabled() bool {
+	return s.TLSCertFile != "" || s.TLSKeyFile != ""
 }
 
 type DatabaseConfig struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	DBName   string
-	SSLMode  string
-	MaxConns int
+	URL            string // full connection URL; when set, replaces Host through SSLMode
+	Host           string
+	Port           string
+	User           string
+	Password       string
+	DBName         string
+	SSLMode        string
+	SSLRootCert    string // CA bundle for verify-ca / verify-full; empty uses system roots
+	SSLCert        string // client certificate, paired with SSLKey
+	SSLKey         string
+	MaxConns       int
+	QueryTimeout   time.Duration // deadline for each repository call; 0 disables
+	HealthInterval time.Duration // how often the pool is pinged and its stats logged
 }
 
 type JWTConfig struct {
 	Secret      string
 	Issuer      string
+	Audience    string // required aud claim; empty disables the check
 	ExpiryHours int
+
+	ServiceTokenMaxDays int // longest lifetime of a minted service token, and the default
+
+	SecretPolicy string // what a weak secret does in production: SecretPolicyEnforce or SecretPolicyWarn
+}
+
+// DefaultJWTSecret is the JWT_SECRET used when none is set. It is public, so
+// any deployment that keeps it accepts forged tokens.
+const DefaultJWTSecret = "dev-secret-change-in-production"
+
+// MinJWTSecretBytes is the shortest JWT secret considered strong, the size
+// of an HS256 key.
+const MinJWTSecretBytes = 32
+
+// JWT secret policies, chosen with JWT_SECRET_POLICY.
+const (
+	SecretPolicyEnforce = "enforce" // a weak secret stops a production deployment
+	SecretPolicyWarn    = "warn"    // a weak secret is only logged
+)
+
+// Validate checks that minted service tokens can live at least a day.
+func (j *JWTConfig) Validate() error {
+	if j.ServiceTokenMaxDays < 1 {
+		return fmt.Errorf("JWT_SERVICE_TOKEN_MAX_DAYS must be at least 1, got %d", j.ServiceTokenMaxDays)
+	}
+	switch j.SecretPolicy {
+	case SecretPolicyEnforce, SecretPolicyWarn:
+	default:
+		return fmt.Errorf("JWT_SECRET_POLICY must be %s or %s, got %q", SecretPolicyEnforce, SecretPolicyWarn, j.SecretPolicy)
+	}
+	return nil
+}
+
+// CheckSecret reports why the JWT secret is weak: it is empty, the public
+// DefaultJWTSecret, or shorter than MinJWTSecretBytes. It returns nil for a
+// strong secret.
+func (j *JWTConfig) CheckSecret() error {
+	switch {
+	case strings.TrimSpace(j.Secret) == "":
+		return errors.New("JWT_SECRET is empty")
+	case j.Secret == DefaultJWTSecret:
+		return errors.New("JWT_SECRET is the built-in development default")
+	case len(j.Secret) < MinJWTSecretBytes:
+		return fmt.Errorf("JWT_SECRET is %d bytes; at least %d are required", len(j.Secret), MinJWTSecretBytes)
+	}
+	return nil
 }
 
 type UploadConfig struct {
-	MaxFileSize     int64    // bytes
-	TempDir         string
-	AllowedTypes    []string
-	BatchInsertSize int
+	MaxFileSize       int64 // bytes
+	MaxUnzippedSize   int64 // bytes, total decompressed size of a zip upload
+	AsyncThreshold    int64 // bytes; larger uploads are processed in the background (0 disables)
+	TempDir           string
+	AllowedTypes      []string // accepted Content-Type media types
+	AllowedExtensions []string // accepted filename extensions, with leading dot
+	BatchInsertSize   int
+	OutlierDetection  bool          // warn on statistically extreme numeric values
+	OutlierThreshold  float64       // IQR multiplier for outlier fences
+	MaxColumns        int           // columns allowed per CSV row (0 disables)
+	MaxFieldBytes     int           // bytes allowed per CSV field (0 disables)
+	NormalizeHeaders  bool          // trim, lowercase and snake_case CSV headers before validation
+	ClamAVAddress     string        // clamd host:port for malware scanning; empty disables scanning
+	ScanTimeout       time.Duration // deadline for one malware scan
+	RetainOriginals   bool          // keep each upload's original file for download
+	OriginalsDir      string        // directory retained originals are stored under
+}
+
+// StorageConfig selects where retained upload originals live.
+type StorageConfig struct {
+	Backend string // "local" (files under UPLOAD_ORIGINALS_DIR) or "s3"
+	S3      S3Config
+}
+
+// S3Config addresses an S3-compatible bucket. Google Cloud Storage works
+// through its XML API with HMAC keys (endpoint https://storage.googleapis.com).
+type S3Config struct {
+	Endpoint        string // base URL; empty uses AWS's regional endpoint
+	Region          string
+	Bucket          string
+	Prefix          string // prepended to every object key
+	AccessKeyID     string
+	SecretAccessKey string
+	PathStyle       bool // address the bucket in the path rather than the host name
+}
+
+// BaseURL returns the endpoint requests are sent to.
+func (s *S3Config) BaseURL() string {
+	if s.Endpoint != "" {
+		return strings.TrimSuffix(s.Endpoint, "/")
+	}
+	return "https://s3." + s.Region + ".amazonaws.com"
+}
+
+// Validate checks that the backend is known and, for s3, that the bucket
+// and credentials are set. Errors never echo the secret key.
+func (s *StorageConfig) Validate() error {
+	switch s.Backend {
+	case "local":
+		return nil
+	case "s3":
+	default:
+		return fmt.Errorf("STORAGE_BACKEND must be local or s3, got %q", s.Backend)
+	}
+
+	if s.S3.Bucket == "" {
+		return fmt.Errorf("STORAGE_S3_BUCKET must be set for the s3 backend")
+	}
+	if s.S3.Region == "" {
+		return fmt.Errorf("STORAGE_S3_REGION must be set for the s3 backend")
+	}
+	if s.S3.AccessKeyID == "" || s.S3.SecretAccessKey == "" {
+		return fmt.Errorf("STORAGE_S3_ACCESS_KEY_ID and STORAGE_S3_SECRET_ACCESS_KEY must be set for the s3 backend")
+	}
+	if s.S3.Endpoint != "" {
+		u, err := url.Parse(s.S3.Endpoint)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("STORAGE_S3_ENDPOINT must be an http or https URL, got %q", s.S3.Endpoint)
+		}
+	}
+	return nil
 }
 
 type ScoringConfig struct {
-	MaxRetries    int
-	RetryBaseWait time.Duration
-	BatchSize     int
-	WorkerCount   int
+	MaxRetries     int
+	RetryBaseWait  time.Duration
+	RunTimeout     time.Duration // per-execution deadline for a run; 0 disables
+	BatchSize      int
+	WorkerCount    int
+	MaxConcurrent  int           // runs executing at once across all dispatches; 0 disables
+	QueueWorkers   int           // workers claiming queued runs from the database; 0 runs them in-process
+	QueuePoll      time.Duration // how often idle queue workers check for runs queued elsewhere
+	Heartbeat      time.Duration // how often an executing run is marked live
+	OrphanAge      time.Duration // runs idle this long with no owner are orphaned
+	OrphanSweep    time.Duration // how often running instances look for orphaned runs; 0 only at startup
+	RequeueOrphans bool          // requeue orphaned runs at startup instead of failing them
+
+	SensitivityMaxSites int // largest upload a sensitivity analysis will score
+	RerankMaxSites      int // largest upload an in-memory rerank will score
+
+	MaxRecommendations      int  // recommendations one run may persist; 0 disables
+	TruncateRecommendations bool // keep the top MaxRecommendations instead of failing the run
+
+	SkipTolerance   float64 // fraction of sites a run may skip and still succeed
+	MaxSkippedSites int     // skipped sites stored with reasons per run; 0 stores none
+
+	SchemaCacheTTL time.Duration // how long a tenant's resolved schema is cached; 0 disables
+
+	DefaultLocale string // explanation language when the schema and run set none
+}
+
+// Validate checks that the default explanation locale has a catalog and
+// that a live run's heartbeat comes well within the orphan age, so no
+// instance takes another's executing run for an orphan.
+func (s *ScoringConfig) Validate() error {
+	if !i18n.Supported(s.DefaultLocale) {
+		return fmt.Errorf("SCORING_DEFAULT_LOCALE must be one of %s, got %q", strings.Join(i18n.Locales(), ", "), s.DefaultLocale)
+	}
+	if s.OrphanAge <= 0 {
+		return fmt.Errorf("SCORING_ORPHAN_AGE must be positive, got %s", s.OrphanAge)
+	}
+	if s.Heartbeat <= 0 || s.Heartbeat*2 > s.OrphanAge {
+		return fmt.Errorf("SCORING_HEARTBEAT_INTERVAL must be positive and at most half of SCORING_ORPHAN_AGE (%s), got %s", s.OrphanAge, s.Heartbeat)
+	}
+	return nil
+}
+
+// IsProduction reports whether ENV names a production deployment.
+func (c *Config) IsProduction() bool {
+	return c.Environment == EnvProduction
+}
+
+// ValidateSecrets refuses a weak JWT secret in production, unless
+// JWT_SECRET_POLICY is warn. A weak secret it lets through is for the
+// caller to log; see JWTConfig.CheckSecret.
+func (c *Config) ValidateSecrets() error {
+	if !c.IsProduction() || c.JWT.SecretPolicy == SecretPolicyWarn {
+		return nil
+	}
+	if err := c.JWT.CheckSecret(); err != nil {
+		return fmt.Errorf("%w (set a random secret of at least %d bytes, or JWT_SECRET_POLICY=%s to start anyway)", err, MinJWTSecretBytes, SecretPolicyWarn)
+	}
+	return nil
 }
 
 // Load reads configuration from environment variables with sensible defaults.
 func Load() *Config {
 	return &Config{
+		Environment: strings.ToLower(strings.TrimSpace(getEnv("ENV", "development"))),
+
 		Server: ServerConfig{
 			Port:         getEnv("SERVER_PORT", "8080"),
 			ReadTimeout:  getDurationEnv("SERVER_READ_TIMEOUT", 30*time.Second),
 			WriteTimeout: getDurationEnv("SERVER_WRITE_TIMEOUT", 60*time.Second),
+
+			IdleTimeout:   getDurationEnv("SERVER_IDLE_TIMEOUT", 120*time.Second),
+			HeaderTimeout: getDurationEnv("SERVER_READ_HEADER_TIMEOUT", 10*time.Second),
+
+			ShutdownTimeout: getDurationEnv("SERVER_SHUTDOWN_TIMEOUT", 10*time.Second),
+
+			MaxJSONBodyBytes: int64(getIntEnv("SERVER_MAX_JSON_BODY_BYTES", 1<<20)),
+
+			LogBodies:       getBoolEnv("SERVER_LOG_BODIES", false),
+			LogBodyMaxBytes: getIntEnv("SERVER_LOG_BODY_MAX_BYTES", 4096),
+
+			LogSampleRate:        getIntEnv("SERVER_LOG_SAMPLE_RATE", 1),
+			SlowRequestThreshold: getDurationEnv("SERVER_SLOW_REQUEST_THRESHOLD", 2*time.Second),
+
+			TLSCertFile:     os.Getenv("TLS_CERT_FILE"),
+			TLSKeyFile:      os.Getenv("TLS_KEY_FILE"),
+			TLSMinVersion:   getEnv("TLS_MIN_VERSION", "1.2"),
+			TLSCipherSuites: getListEnv("TLS_CIPHER_SUITES", nil),
+
+			TimestampFormat: getEnv("RESPONSE_TIMESTAMP_FORMAT", "rfc3339"),
+			TimeZone:        getEnv("RESPONSE_TIMEZONE", "UTC"),
 		},
 		Database: DatabaseConfig{
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnv("DB_PORT", "5432"),
-			User:     getEnv("DB_USER", "ssiq"),
-			Password: getEnv("DB_PASSWORD", "ssiq_dev_password"),
-			DBName:   getEnv("DB_NAME", "ssiq"),
-			SSLMode:  getEnv("DB_SSLMODE", "disable"),
-			MaxConns: int(getIntEnv("DB_MAX_CONNS", 20)),
+			URL:            os.Getenv("DATABASE_URL"),
+			Host:           getEnv("DB_HOST", "localhost"),
+			Port:           getEnv("DB_PORT", "5432"),
+			User:           getEnv("DB_USER", "ssiq"),
+			Password:       getEnv("DB_PASSWORD", "ssiq_dev_password"),
+			DBName:         getEnv("DB_NAME", "ssiq"),
+			SSLMode:        getEnv("DB_SSLMODE", "disable"),
+			SSLRootCert:    os.Getenv("DB_SSL_ROOT_CERT"),
+			SSLCert:        os.Getenv("DB_SSL_CERT"),
+			SSLKey:         os.Getenv("DB_SSL_KEY"),
+			MaxConns:       int(getIntEnv("DB_MAX_CONNS", 20)),
+			QueryTimeout:   getDurationEnv("DB_QUERY_TIMEOUT", 30*time.Second),
+			HealthInterval: getDurationEnv("DB_HEALTH_INTERVAL", 15*time.Second),
 		},
 		JWT: JWTConfig{
-			Secret:      getEnv("JWT_SECRET", "dev-secret-change-in-production"),
+			Secret:      getEnv("JWT_SECRET", DefaultJWTSecret),
 			Issuer:      getEnv("JWT_ISSUER", "workforce-ai"),
+			Audience:    getEnv("JWT_AUDIENCE", ""),
 			ExpiryHours: getIntEnv("JWT_EXPIRY_HOURS", 24),
+
+			ServiceTokenMaxDays: getIntEnv("JWT_SERVICE_TOKEN_MAX_DAYS", 365),
+			SecretPolicy:        getEnv("JWT_SECRET_POLICY", SecretPolicyEnforce),
 		},
 		Upload: UploadConfig{
-			MaxFileSize:     int64(getIntEnv("UPLOAD_MAX_SIZE_MB", 100)) * 1024 * 1024,
-			TempDir:         getEnv("UPLOAD_TEMP_DIR", "/tmp/ssiq-uploads"),
-			AllowedTypes:    []string{"text/csv", "application/csv"},
-			BatchInsertSize: getIntEnv("UPLOAD_BATCH_INSERT_SIZE", 1000),
+			MaxFileSize:       int64(getIntEnv("UPLOAD_MAX_SIZE_MB", 100)) * 1024 * 1024,
+			MaxUnzippedSize:   int64(getIntEnv("UPLOAD_MAX_UNZIPPED_SIZE_MB", 500)) * 1024 * 1024,
+			AsyncThreshold:    int64(getIntEnv("UPLOAD_ASYNC_THRESHOLD_MB", 0)) * 1024 * 1024,
+			TempDir:           getEnv("UPLOAD_TEMP_DIR", "/tmp/ssiq-uploads"),
+			AllowedTypes:      getListEnv("UPLOAD_ALLOWED_TYPES", []string{"text/csv", "application/csv", "application/zip"}),
+			AllowedExtensions: getListEnv("UPLOAD_ALLOWED_EXTENSIONS", []string{".csv", ".zip"}),
+			BatchInsertSize:   getIntEnv("UPLOAD_BATCH_INSERT_SIZE", 1000),
+			OutlierDetection:  getBoolEnv("UPLOAD_OUTLIER_DETECTION", false),
+			OutlierThreshold:  getFloatEnv("UPLOAD_OUTLIER_THRESHOLD", 3.0),
+			MaxColumns:        getIntEnv("UPLOAD_MAX_COLUMNS", 1000),
+			MaxFieldBytes:     getIntEnv("UPLOAD_MAX_FIELD_BYTES", 64*1024),
+			NormalizeHeaders:  getBoolEnv("UPLOAD_NORMALIZE_HEADERS", false),
+			ClamAVAddress:     getEnv("UPLOAD_CLAMAV_ADDRESS", ""),
+			ScanTimeout:       getDurationEnv("UPLOAD_SCAN_TIMEOUT", 60*time.Second),
+			RetainOriginals:   getBoolEnv("UPLOAD_RETAIN_ORIGINALS", false),
+			OriginalsDir:      getEnv("UPLOAD_ORIGINALS_DIR", "/var/lib/ssiq/originals"),
+		},
+		Storage: StorageConfig{
+			Backend: getEnv("STORAGE_BACKEND", "local"),
+			S3: S3Config{
+				Endpoint:        os.Getenv("STORAGE_S3_ENDPOINT"),
+				Region:          getEnv("STORAGE_S3_REGION", "us-east-1"),
+				Bucket:          os.Getenv("STORAGE_S3_BUCKET"),
+				Prefix:          os.Getenv("STORAGE_S3_PREFIX"),
+				AccessKeyID:     os.Getenv("STORAGE_S3_ACCESS_KEY_ID"),
+				SecretAccessKey: os.Getenv("STORAGE_S3_SECRET_ACCESS_KEY"),
+				PathStyle:       getBoolEnv("STORAGE_S3_PATH_STYLE", false),
+			},
 		},
 		Scoring: ScoringConfig{
-			MaxRetries:    getIntEnv("SCORING_MAX_RETRIES", 3),
-			RetryBaseWait: getDurationEnv("SCORING_RETRY_BASE_WAIT", 2*time.Second),
-			BatchSize:     getIntEnv("SCORING_BATCH_SIZE", 1000),
-			WorkerCount:   getIntEnv("SCORING_WORKER_COUNT", 4),
+			MaxRetries:     getIntEnv("SCORING_MAX_RETRIES", 3),
+			RetryBaseWait:  getDurationEnv("SCORING_RETRY_BASE_WAIT", 2*time.Second),
+			RunTimeout:     getDurationEnv("SCORING_RUN_TIMEOUT", 0),
+			BatchSize:      getIntEnv("SCORING_BATCH_SIZE", 1000),
+			WorkerCount:    getIntEnv("SCORING_WORKER_COUNT", 4),
+			MaxConcurrent:  getIntEnv("SCORING_MAX_CONCURRENT_RUNS", 8),
+			QueueWorkers:   getIntEnv("SCORING_QUEUE_WORKERS", 8),
+			QueuePoll:      getDurationEnv("SCORING_QUEUE_POLL_INTERVAL", 2*time.Second),
+			Heartbeat:      getDurationEnv("SCORING_HEARTBEAT_INTERVAL", time.Minute),
+			OrphanAge:      getDurationEnv("SCORING_ORPHAN_AGE", 10*time.Minute),
+			OrphanSweep:    getDurationEnv("SCORING_ORPHAN_SWEEP_INTERVAL", time.Minute),
+			RequeueOrphans: getBoolEnv("SCORING_REQUEUE_ORPHANS", false),
+
+			SensitivityMaxSites: getIntEnv("SCORING_SENSITIVITY_MAX_SITES", 5000),
+			RerankMaxSites:      getIntEnv("SCORING_RERANK_MAX_SITES", 10000),
+
+			MaxRecommendations:      getIntEnv("SCORING_MAX_RECOMMENDATIONS", 1000000),
+			TruncateRecommendations: getBoolEnv("SCORING_TRUNCATE_RECOMMENDATIONS", false),
+
+			SkipTolerance:   getFloatEnv("SCORING_SKIP_TOLERANCE", 0),
+			MaxSkippedSites: getIntEnv("SCORING_MAX_SKIPPED_SITES", 1000),
+
+			SchemaCacheTTL: getDurationEnv("SCHEMA_CACHE_TTL", 5*time.Minute),
+
+			DefaultLocale: getEnv("SCORING_DEFAULT_LOCALE", i18n.DefaultLocale),
 		},
 	}
 }
 
-// DSN returns the Postgres connection string.
+// DSN returns the Postgres connection string: URL as given when set,
+// otherwise one assembled from the discrete parts. Pool settings such as
+// MaxConns are applied on top of either, and the SSL certificate paths are
+// added as sslrootcert, sslcert and sslkey, which pgx loads into its TLS config.
 func (d *DatabaseConfig) DSN() string {
-	return "postgres://" + d.User + ":" + d.Password +
-		"@" + d.Host + ":" + d.Port +
-		"/" + d.DBName + "?sslmode=" + d.SSLMode
+	dsn := d.URL
+	if dsn == "" {
+		dsn = "postgres://" + d.User + ":" + d.Password +
+			"@" + d.Host + ":" + d.Port +
+			"/" + d.DBName + "?sslmode=" + d.SSLMode
+	}
+	if d.SSLRootCert == "" && d.SSLCert == "" && d.SSLKey == "" {
+		return dsn
+	}
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return dsn // reported by Validate
+	}
+	q := u.Query()
+	for param, path := range map[string]string{
+		"sslrootcert": d.SSLRootCert,
+		"sslcert":     d.SSLCert,
+		"sslkey":      d.SSLKey,
+	} {
+		if path != "" {
+			q.Set(param, path)
+		}
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// effectiveSSLMode returns the sslmode the connection will use: the URL's
+// when URL is set, otherwise SSLMode.
+func (d *DatabaseConfig) effectiveSSLMode() string {
+	if d.URL == "" {
+		return d.SSLMode
+	}
+	u, err := url.Parse(d.URL)
+	if err != nil {
+		return ""
+	}
+	return u.Query().Get("sslmode")
+}
+
+// Validate checks that URL, when set, is a postgres:// or postgresql:// URL
+// naming a host, and that the SSL certificate files are usable when the
+// sslmode verifies the server. Errors never echo the URL, which may carry a
+// password.
+func (d *DatabaseConfig) Validate() error {
+	if err := d.validateURL(); err != nil {
+		return err
+	}
+
+	if d.SSLCert != "" && d.SSLKey == "" || d.SSLCert == "" && d.SSLKey != "" {
+		return fmt.Errorf("DB_SSL_CERT and DB_SSL_KEY must be set together")
+	}
+	if mode := d.effectiveSSLMode(); mode != "verify-ca" && mode != "verify-full" {
+		return nil
+	}
+	files := []struct{ env, path string }{
+		{"DB_SSL_ROOT_CERT", d.SSLRootCert},
+		{"DB_SSL_CERT", d.SSLCert},
+		{"DB_SSL_KEY", d.SSLKey},
+	}
+	for _, f := range files {
+		if f.path == "" {
+			continue
+		}
+		if _, err := os.Stat(f.path); err != nil {
+			return fmt.Errorf("%s: %w", f.env, err)
+		}
+	}
+	return nil
+}
+
+func (d *DatabaseConfig) validateURL() error {
+	if d.URL == "" {
+		return nil
+	}
+	u, err := url.Parse(d.URL)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("DATABASE_URL does not parse: %w", err)
+	}
+	if u.Scheme != "postgres" && u.Scheme != "postgresql" {
+		return fmt.Errorf("DATABASE_URL scheme must be postgres or postgresql, got %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("DATABASE_URL must name a host")
+	}
+	return nil
 }
 
 func getEnv(key, fallback string) string {
@@ -111,6 +505,42 @@ func getIntEnv(key string, fallback int) int {
 	return fallback
 }
 
+// getListEnv reads a comma-separated list, trimming blanks around items.
+func getListEnv(key string, fallback []string) []string {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		return fallback
+	}
+	return items
+}
+
+func getFloatEnv(key string, fallback float64) float64 {
+	if v := os.Getenv(key); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+	return fallback
+}
+
+func getBoolEnv(key string, fallback bool) bool {
+	if v := os.Getenv(key); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return fallback
+}
+
 func getDurationEnv(key string, fallback time.Duration) time.Duration {
 	if v := os.Getenv(key); v != "" {
 		if d, err := time.ParseDuration(v); err == nil {
@@ -0,0 +1,160 @@
+package db
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	// breakerThreshold is how many consecutive failed pings it takes to
+	// treat the database as unreachable.
+	breakerThreshold = 2
+
+	// pingTimeout bounds a single health check ping.
+	pingTimeout = 5 * time.Second
+
+	defaultHealthInterval = 15 * time.Second
+)
+
+// PoolStats is a snapshot of connection pool usage.
+type PoolStats struct {
+	MaxConns             int32 `json:"max_conns"`
+	TotalConns           int32 `json:"total_conns"`
+	IdleConns            int32 `json:"idle_conns"`
+	AcquiredConns        int32 `json:"acquired_conns"`
+	ConstructingConns    int32 `json:"constructing_conns"`
+	AcquireCount         int64 `json:"acquire_count"`
+	EmptyAcquireCount    int64 `json:"empty_acquire_count"`
+	CanceledAcquireCount int64 `json:"canceled_acquire_count"`
+	NewConnsCount        int64 `json:"new_conns_count"`
+	AcquireDurationMs    int64 `json:"acquire_duration_ms"`
+}
+
+// StatsOf snapshots the pool's current statistics.
+func StatsOf(pool *Pool) PoolStats {
+	s := pool.Stat()
+	return PoolStats{
+		MaxConns:             s.MaxConns(),
+		TotalConns:           s.TotalConns(),
+		IdleConns:            s.IdleConns(),
+		AcquiredConns:        s.AcquiredConns(),
+		ConstructingConns:    s.ConstructingConns(),
+		AcquireCount:         s.AcquireCount(),
+		EmptyAcquireCount:    s.EmptyAcquireCount(),
+		CanceledAcquireCount: s.CanceledAcquireCount(),
+		NewConnsCount:        s.NewConnsCount(),
+		AcquireDurationMs:    s.AcquireDuration().Milliseconds(),
+	}
+}
+
+// HealthReport describes database reachability and pool usage.
+type HealthReport struct {
+	Healthy   bool      `json:"healthy"`
+	LastError string    `json:"last_error,omitempty"`
+	Pool      PoolStats `json:"pool"`
+}
+
+// Monitor periodically pings the database and acts as a circuit breaker:
+// once breakerThreshold pings in a row fail it reports unhealthy, so
+// requests can be turned away immediately instead of each waiting on a dead
+// connection. The next successful ping closes the breaker again.
+type Monitor struct {
+	pool     *Pool
+	interval time.Duration
+
+	mu       sync.RWMutex
+	failures int
+	lastErr  error
+}
+
+// NewMonitor creates a monitor for pool. A non-positive interval is
+// replaced with defaultHealthInterval. The monitor starts out healthy.
+func NewMonitor(pool *Pool, interval time.Duration) *Monitor {
+	if interval <= 0 {
+		interval = defaultHealthInterval
+	}
+	return &Monitor{pool: pool, interval: interval}
+}
+
+// Healthy reports whether the database is currently considered reachable.
+func (m *Monitor) Healthy() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.failures < breakerThreshold
+}
+
+// Report returns the current health state with a fresh pool snapshot.
+func (m *Monitor) Report() HealthReport {
+	m.mu.RLock()
+	report := HealthReport{Healthy: m.failures < breakerThreshold}
+	if m.lastErr != nil {
+		report.LastError = m.lastErr.Error()
+	}
+	m.mu.RUnlock()
+
+	report.Pool = StatsOf(m.pool)
+	return report
+}
+
+// Check pings the database once and updates the breaker. A failed ping
+// resets the pool so connections broken by a database restart are replaced
+// rather than handed out again.
+func (m *Monitor) Check(ctx context.Context) error {
+	pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+	err := m.pool.Ping(pingCtx)
+
+	m.mu.Lock()
+	wasHealthy := m.failures < breakerThreshold
+	if err != nil {
+		m.failures++
+		m.lastErr = err
+	} else {
+		m.failures = 0
+		m.lastErr = nil
+	}
+	healthy := m.failures < breakerThreshold
+	m.mu.Unlock()
+
+	switch {
+	case wasHealthy && !healthy:
+		slog.Error("database unreachable; rejecting requests until it recovers", "error", err)
+	case !wasHealthy && healthy:
+		slog.Info("database reachable again")
+	}
+
+	if err != nil {
+		m.pool.Reset()
+	}
+	return err
+}
+
+// Run checks the database and logs pool statistics every interval until ctx
+// is done.
+func (m *Monitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.Check(ctx); err != nil && ctx.Err() == nil {
+				slog.Warn("database health check failed", "error", err)
+			}
+			stats := StatsOf(m.pool)
+			slog.Info("database pool stats",
+				"healthy", m.Healthy(),
+				"total_conns", stats.TotalConns,
+				"idle_conns", stats.IdleConns,
+				"acquired_conns", stats.AcquiredConns,
+				"max_conns", stats.MaxConns,
+				"empty_acquire_count", stats.EmptyAcquireCount,
+				"acquire_duration_ms", stats.AcquireDurationMs,
+			)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package db
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// migrationLockID is the Postgres advisory lock key held while migrating, so
+// replicas starting together apply each migration once.
+const migrationLockID int64 = 727_300_001
+
+// migration is one schema change. A migration is either a single
+// NNN_name.sql file, which cannot be rolled back, or a NNN_name.up.sql /
+// NNN_name.down.sql pair. Its version is the filename without those
+// suffixes, e.g. 002_add_stats.
+type migration struct {
+	version string
+	sql     string // up script
+	down    string // rollback script; empty if the migration has none
+}
+
+// loadMigrations reads every migrations/*.sql file in fsys, pairing up and
+// down scripts, and returns them in lexical version order so 002_x always
+// runs after 001_y.
+func loadMigrations(fsys fs.FS) ([]migration, error) {
+	names, err := fs.Glob(fsys, "migrations/*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("list migrations: %w", err)
+	}
+
+	byVersion := make(map[string]*migration, len(names))
+	for _, name := range names {
+		sqlBytes, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", name, err)
+		}
+
+		base := path.Base(name)
+		version, isDown := strings.CutSuffix(base, ".down.sql")
+		if !isDown {
+			var isUp bool
+			if version, isUp = strings.CutSuffix(base, ".up.sql"); !isUp {
+				version = strings.TrimSuffix(base, ".sql")
+			}
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &migration{version: version}
+			byVersion[version] = m
+		}
+		switch {
+		case isDown:
+			m.down = string(sqlBytes)
+		case m.sql != "":
+			return nil, fmt.Errorf("migration %s has more than one up script", version)
+		default:
+			m.sql = string(sqlBytes)
+		}
+	}
+
+	loaded := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.sql == "" {
+			return nil, fmt.Errorf("migration %s has a down script but no up script", m.version)
+		}
+		loaded = append(loaded, *m)
+	}
+	sort.Slice(loaded, func(i, j int) bool { return loaded[i].version < loaded[j].version })
+	return loaded, nil
+}
+
+// RunMigrations applies the embedded SQL migrations that have not been
+// applied yet. Applied versions are recorded in schema_migrations; each
+// migration and its record commit in one transaction, so a failed migration
+// leaves no trace and is retried on the next start.
+func RunMigrations(ctx context.Context, pool *pgxpool.Pool) error {
+	return runMigrations(ctx, pool, migrations)
+}
+
+func runMigrations(ctx context.Context, pool *pgxpool.Pool, fsys fs.FS) error {
+	pending, err := loadMigrations(fsys)
+	if err != nil {
+		return err
+	}
+
+	return withMigrationLock(ctx, pool, func(conn *pgx.Conn) error {
+		applied := 0
+		for _, m := range pending {
+			ran, err := applyMigration(ctx, conn, m)
+			if err != nil {
+				return err
+			}
+			if ran {
+				applied++
+				slog.Info("applied database migration", "version", m.version)
+			}
+		}
+
+		slog.Info("database migrations up to date", "applied", applied, "total", len(pending))
+		return nil
+	})
+}
+
+// RollbackMigrations reverts the last n applied migrations, newest first,
+// by running their down scripts, and returns the versions rolled back. It
+// refuses to start if any of them has no down script or is no longer
+// embedded, so a rollback never stops partway for that reason.
+func RollbackMigrations(ctx context.Context, pool *pgxpool.Pool, n int) ([]string, error) {
+	return rollbackMigrations(ctx, pool, migrations, n)
+}
+
+func rollbackMigrations(ctx context.Context, pool *pgxpool.Pool, fsys fs.FS, n int) ([]string, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("rollback count must be at least 1, got %d", n)
+	}
+
+	loaded, err := loadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[string]migration, len(loaded))
+	for _, m := range loaded {
+		byVersion[m.version] = m
+	}
+
+	var rolledBack []string
+	err = withMigrationLock(ctx, pool, func(conn *pgx.Conn) error {
+		rows, err := conn.Query(ctx,
+			`SELECT version FROM schema_migrations ORDER BY version DESC LIMIT $1`, n)
+		if err != nil {
+			return fmt.Errorf("list applied migrations: %w", err)
+		}
+		versions, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			return fmt.Errorf("list applied migrations: %w", err)
+		}
+
+		targets := make([]migration, 0, len(versions))
+		for _, version := range versions {
+			m, ok := byVersion[version]
+			if !ok {
+				return fmt.Errorf("applied migration %s is not embedded in this build", version)
+			}
+			if m.down == "" {
+				return fmt.Errorf("migration %s has no down script", version)
+			}
+			targets = append(targets, m)
+		}
+
+		for _, m := range targets {
+			if err := revertMigration(ctx, conn, m); err != nil {
+				return err
+			}
+			rolledBack = append(rolledBack, m.version)
+			slog.Info("rolled back database migration", "version", m.version)
+		}
+		return nil
+	})
+	return rolledBack, err
+}
+
+// withMigrationLock runs fn on a single connection holding the migration
+// advisory lock, after making sure schema_migrations exists.
+func withMigrationLock(ctx context.Context, pool *pgxpool.Pool, fn func(conn *pgx.Conn) error) error {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire migration connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer func() {
+		// The lock is session-scoped; release it even if ctx was cancelled
+		if _, err := conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockID); err != nil {
+			slog.Warn("failed to release migration lock", "error", err)
+		}
+	}()
+
+	if _, err := conn.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    TEXT PRIMARY KEY,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)`); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	return fn(conn.Conn())
+}
+
+// applyMigration runs m in a transaction unless schema_migrations already
+// records it, and reports whether it ran.
+func applyMigration(ctx context.Context, conn *pgx.Conn, m migration) (bool, error) {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin migration %s: %w", m.version, err)
+	}
+	defer tx.Rollback(ctx)
+
+	var version string
+	err = tx.QueryRow(ctx, `SELECT version FROM schema_migrations WHERE version = $1`, m.version).Scan(&version)
+	if err == nil {
+		return false, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return false, fmt.Errorf("check migration %s: %w", m.version, err)
+	}
+
+	if _, err := tx.Exec(ctx, m.sql); err != nil {
+		return false, fmt.Errorf("execute migration %s: %w", m.version, err)
+	}
+	if _, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, m.version); err != nil {
+		return false, fmt.Errorf("record migration %s: %w", m.version, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit migration %s: %w", m.version, err)
+	}
+	return true, nil
+}
+
+// revertMigration runs m's down script and removes its schema_migrations
+// record in one transaction.
+func revertMigration(ctx context.Context, conn *pgx.Conn, m migration) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin rollback %s: %w", m.version, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, m.down); err != nil {
+		return fmt.Errorf("execute rollback %s: %w", m.version, err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, m.version); err != nil {
+		return fmt.Errorf("unrecord migration %s: %w", m.version, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit rollback %s: %w", m.version, err)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package db
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMigrations_LexicalOrder(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/010_late.sql":           {Data: []byte("SELECT 10;")},
+		"migrations/002_add_column.sql":     {Data: []byte("SELECT 2;")},
+		"migrations/001_initial_schema.sql": {Data: []byte("SELECT 1;")},
+		"migrations/README.md":              {Data: []byte("not a migration")},
+	}
+
+	loaded, err := loadMigrations(fsys)
+	require.NoError(t, err)
+
+	versions := make([]string, 0, len(loaded))
+	for _, m := range loaded {
+		versions = append(versions, m.version)
+	}
+	assert.Equal(t, []string{"001_initial_schema", "002_add_column", "010_late"}, versions)
+	assert.Equal(t, "SELECT 2;", loaded[1].sql)
+}
+
+func TestLoadMigrations_Embedded(t *testing.T) {
+	loaded, err := loadMigrations(migrations)
+	require.NoError(t, err)
+	require.NotEmpty(t, loaded)
+	assert.Equal(t, "001_initial_schema", loaded[0].version)
+}
+
+func TestLoadMigrations_PairsUpAndDown(t *testing.T) {
+	loaded, err := loadMigrations(fstest.MapFS{
+		"migrations/001_initial_schema.sql": {Data: []byte("CREATE TABLE a ();")},
+		"migrations/002_add_stats.up.sql":   {Data: []byte("ALTER TABLE a ADD COLUMN stats JSONB;")},
+		"migrations/002_add_stats.down.sql": {Data: []byte("ALTER TABLE a DROP COLUMN stats;")},
+	})
+	require.NoError(t, err)
+	require.Len(t, loaded, 2)
+	assert.Equal(t, migration{version: "001_initial_schema", sql: "CREATE TABLE a ();"}, loaded[0])
+	assert.Equal(t, migration{
+		version: "002_add_stats",
+		sql:     "ALTER TABLE a ADD COLUMN stats JSONB;",
+		down:    "ALTER TABLE a DROP COLUMN stats;",
+	}, loaded[1])
+
+	_, err = loadMigrations(fstest.MapFS{
+		"migrations/002_add_stats.down.sql": {Data: []byte("SELECT 1;")},
+	})
+	assert.EqualError(t, err, "migration 002_add_stats has a down script but no up script")
+
+	_, err = loadMigrations(fstest.MapFS{
+		"migrations/002_add_stats.sql":    {Data: []byte("SELECT 1;")},
+		"migrations/002_add_stats.up.sql": {Data: []byte("SELECT 2;")},
+	})
+	assert.EqualError(t, err, "migration 002_add_stats has more than one up script")
+}
+
+// testMigrationPool connects to TEST_DATABASE_URL, skipping the test if it
+// is not set.
+func testMigrationPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set; skipping migration integration test")
+	}
+
+	pool, err := pgxpool.New(context.Background(), dsn)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+// TestRunMigrations_Idempotent runs against a real Postgres instance and is
+// skipped unless TEST_DATABASE_URL is set.
+func TestRunMigrations_Idempotent(t *testing.T) {
+	pool := testMigrationPool(t)
+	ctx := context.Background()
+
+	require.NoError(t, RunMigrations(ctx, pool))
+
+	countRows := func(query string) int {
+		var n int
+		require.NoError(t, pool.QueryRow(ctx, query).Scan(&n))
+		return n
+	}
+	globalConfigs := countRows(`SELECT COUNT(*) FROM schema_configs WHERE tenant_id IS NULL`)
+
+	// A second run applies nothing and re-seeds nothing
+	require.NoError(t, RunMigrations(ctx, pool))
+
+	loaded, err := loadMigrations(migrations)
+	require.NoError(t, err)
+	assert.Equal(t, len(loaded), countRows(`SELECT COUNT(*) FROM schema_migrations`))
+	assert.Equal(t, globalConfigs, countRows(`SELECT COUNT(*) FROM schema_configs WHERE tenant_id IS NULL`))
+}
+
+// TestRollbackMigrations_RevertsSchemaChange runs against a real Postgres
+// instance and is skipped unless TEST_DATABASE_URL is set.
+func TestRollbackMigrations_RevertsSchemaChange(t *testing.T) {
+	pool := testMigrationPool(t)
+	ctx := context.Background()
+
+	fsys := fstest.MapFS{
+		"migrations/999_rollback_probe.up.sql":   {Data: []byte("CREATE TABLE rollback_probe (id INT PRIMARY KEY);")},
+		"migrations/999_rollback_probe.down.sql": {Data: []byte("DROP TABLE rollback_probe;")},
+	}
+	embedded, err := fs.Glob(migrations, "migrations/*.sql")
+	require.NoError(t, err)
+	for _, name := range embedded {
+		data, err := fs.ReadFile(migrations, name)
+		require.NoError(t, err)
+		fsys[name] = &fstest.MapFile{Data: data}
+	}
+
+	tableExists := func() bool {
+		var exists bool
+		require.NoError(t, pool.QueryRow(ctx, `SELECT to_regclass('public.rollback_probe') IS NOT NULL`).Scan(&exists))
+		return exists
+	}
+
+	require.NoError(t, runMigrations(ctx, pool, fsys))
+	require.True(t, tableExists())
+
+	rolledBack, err := rollbackMigrations(ctx, pool, fsys, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"999_rollback_probe"}, rolledBack)
+	assert.False(t, tableExists())
+
+	var recorded bool
+	require.NoError(t, pool.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = '999_rollback_probe')`).Scan(&recorded))
+	assert.False(t, recorded)
+}
@@ -110,6 +110,9 @@ CREATE INDEX IF NOT EXISTS idx_scoring_runs_tenant ON scoring_runs (tenant_id, c
 CREATE INDEX IF NOT EXISTS idx_scoring_runs_upload ON scoring_runs (upload_id);
 CREATE UNIQUE INDEX IF NOT EXISTS idx_scoring_runs_idempotency ON scoring_runs (tenant_id, idempotency_key) WHERE idempotency_key IS NOT NULL;
 
+-- Final score distribution, written when a run succeeds
+ALTER TABLE scoring_runs ADD COLUMN IF NOT EXISTS stats JSONB;
+
 -- ============================================================
 -- Schema Config Snapshots (immutable, captured at run time)
 -- ============================================================
@@ -140,6 +143,7 @@ CREATE TABLE IF NOT EXISTS recommendations (
 );
 
 CREATE INDEX IF NOT EXISTS idx_recommendations_run_score ON recommendations (run_id, final_score DESC);
+CREATE INDEX IF NOT EXISTS idx_recommendations_run_keyset ON recommendations (run_id, final_score DESC, site_id);
 CREATE INDEX IF NOT EXISTS idx_recommendations_run_site ON recommendations (run_id, site_id);
 CREATE INDEX IF NOT EXISTS idx_recommendations_tenant ON recommendations (tenant_id);
 
@@ -156,6 +160,35 @@ CREATE TABLE IF NOT EXISTS idempotency_keys (
     PRIMARY KEY (tenant_id, key, resource_type)
 );
 
+-- ============================================================
+-- Audit Log (who performed which mutating action)
+-- ============================================================
+CREATE TABLE IF NOT EXISTS audit_log (
+    id             UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id      UUID NOT NULL REFERENCES tenants(id),
+    user_id        UUID NOT NULL,
+    action         TEXT NOT NULL,
+    resource_id    UUID,
+    correlation_id TEXT NOT NULL DEFAULT '',
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_tenant ON audit_log (tenant_id, created_at DESC);
+
+-- ============================================================
+-- Weight Presets (named per-tenant weight overrides for runs)
+-- ============================================================
+CREATE TABLE IF NOT EXISTS weight_presets (
+    id          UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id   UUID NOT NULL REFERENCES tenants(id),
+    name        TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    weights     JSONB NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (tenant_id, name)
+);
+
 -- ============================================================
 -- Seed: Global schema configuration
 -- ============================================================
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_scoring_runs_result_key;
+ALTER TABLE scoring_runs DROP COLUMN IF EXISTS result_key;
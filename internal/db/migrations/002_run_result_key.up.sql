@@ -0,0 +1,7 @@
+-- Identifies a run's results by upload content and effective schema, so an
+-- identical run can reuse them (see Pipeline.ResultKey)
+ALTER TABLE scoring_runs ADD COLUMN IF NOT EXISTS result_key TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_scoring_runs_result_key
+    ON scoring_runs (tenant_id, upload_id, result_key)
+    WHERE result_key IS NOT NULL AND status = 'succeeded';
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_scoring_runs_queued;
@@ -0,0 +1,4 @@
+-- Workers claim the oldest queued run (see RunRepository.ClaimNext)
+CREATE INDEX IF NOT EXISTS idx_scoring_runs_queued
+    ON scoring_runs (created_at)
+    WHERE status = 'queued';
@@ -0,0 +1,7 @@
+UPDATE scoring_runs SET status = 'succeeded' WHERE status = 'completed_with_errors';
+ALTER TABLE scoring_runs DROP CONSTRAINT IF EXISTS scoring_runs_status_check;
+ALTER TABLE scoring_runs ADD CONSTRAINT scoring_runs_status_check
+    CHECK (status IN ('queued', 'running', 'succeeded', 'failed', 'completed'));
+
+ALTER TABLE scoring_runs DROP COLUMN IF EXISTS skipped_sites;
+ALTER TABLE scoring_runs DROP COLUMN IF EXISTS skipped_count;
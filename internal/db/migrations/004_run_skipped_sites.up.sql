@@ -0,0 +1,9 @@
+-- Sites a run could not parse or score: how many, and a sample of why
+ALTER TABLE scoring_runs ADD COLUMN IF NOT EXISTS skipped_count INTEGER;
+ALTER TABLE scoring_runs ADD COLUMN IF NOT EXISTS skipped_sites JSONB;
+
+-- A run that skipped more sites than SCORING_SKIP_TOLERANCE allows ends as
+-- completed_with_errors; its results are stored like a succeeded run's
+ALTER TABLE scoring_runs DROP CONSTRAINT IF EXISTS scoring_runs_status_check;
+ALTER TABLE scoring_runs ADD CONSTRAINT scoring_runs_status_check
+    CHECK (status IN ('queued', 'running', 'succeeded', 'completed_with_errors', 'failed', 'completed'));
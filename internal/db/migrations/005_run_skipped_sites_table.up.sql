@@ -0,0 +1,13 @@
+-- One row per site a run skipped, up to SCORING_MAX_SKIPPED_SITES per run.
+-- Keyed by the site's position in the upload, so a retried run rewrites the
+-- same rows instead of duplicating them.
+CREATE TABLE IF NOT EXISTS run_skipped_sites (
+    run_id      UUID NOT NULL REFERENCES scoring_runs(id) ON DELETE CASCADE,
+    tenant_id   UUID NOT NULL REFERENCES tenants(id),
+    position    INTEGER NOT NULL,
+    site_id     TEXT NOT NULL,
+    site_name   TEXT NOT NULL DEFAULT '',
+    reason      TEXT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (run_id, position)
+);
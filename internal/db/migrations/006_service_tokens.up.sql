@@ -0,0 +1,16 @@
+-- Long-lived tokens an admin minted for a tenant's integrations. id is the
+-- token's jti claim; the signed token itself is never stored. A token whose
+-- row is revoked is rejected even before it expires.
+CREATE TABLE IF NOT EXISTS service_tokens (
+    id          UUID PRIMARY KEY,
+    tenant_id   UUID NOT NULL REFERENCES tenants(id),
+    name        TEXT NOT NULL,
+    role        TEXT NOT NULL,
+    scopes      TEXT[] NOT NULL DEFAULT '{}',
+    created_by  UUID NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at  TIMESTAMPTZ NOT NULL,
+    revoked_at  TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_service_tokens_tenant ON service_tokens (tenant_id, created_at DESC);
@@ -0,0 +1 @@
+ALTER TABLE scoring_runs DROP COLUMN IF EXISTS correlation_id;
@@ -0,0 +1,3 @@
+-- The request a run was created (or last requeued) by, so a queue worker on
+-- any instance logs the run under the same correlation ID
+ALTER TABLE scoring_runs ADD COLUMN IF NOT EXISTS correlation_id TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_scoring_runs_result_key;
+
+CREATE INDEX IF NOT EXISTS idx_scoring_runs_result_key
+    ON scoring_runs (tenant_id, upload_id, result_key)
+    WHERE result_key IS NOT NULL AND status = 'succeeded';
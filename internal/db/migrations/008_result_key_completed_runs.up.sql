@@ -0,0 +1,6 @@
+-- Runs completed_with_errors are reused by result key too
+DROP INDEX IF EXISTS idx_scoring_runs_result_key;
+
+CREATE INDEX IF NOT EXISTS idx_scoring_runs_result_key
+    ON scoring_runs (tenant_id, upload_id, result_key)
+    WHERE result_key IS NOT NULL AND status IN ('succeeded', 'completed_with_errors');
@@ -2,7 +2,6 @@ package db
 
 import (
 	"context"
-	"embed"
 	"fmt"
 	"log/slog"
 	"time"
@@ -11,24 +10,16 @@ import (
 	"github.com/workforce-ai/site-selection-iq/internal/config"
 )
 
-//go:embed migrations/*.sql
-var migrations embed.FS
-
 // Pool is a type alias for pgxpool.Pool for use in other packages.
 type Pool = pgxpool.Pool
 
 // Connect creates a connection pool to Postgres.
 func Connect(ctx context.Context, cfg config.DatabaseConfig) (*pgxpool.Pool, error) {
-	poolCfg, err := pgxpool.ParseConfig(cfg.DSN())
+	poolCfg, err := poolConfig(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("parse db config: %w", err)
+		return nil, err
 	}
 
-	poolCfg.MaxConns = int32(cfg.MaxConns)
-	poolCfg.MinConns = 2
-	poolCfg.MaxConnLifetime = 30 * time.Minute
-	poolCfg.MaxConnIdleTime = 5 * time.Minute
-
 	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
 	if err != nil {
 		return nil, fmt.Errorf("create pool: %w", err)
@@ -40,27 +31,52 @@ func Connect(ctx context.Context, cfg config.DatabaseConfig) (*pgxpool.Pool, err
 	}
 
 	slog.Info("connected to database",
-		"host", cfg.Host,
-		"port", cfg.Port,
-		"database", cfg.DBName,
+		"host", poolCfg.ConnConfig.Host,
+		"port", poolCfg.ConnConfig.Port,
+		"database", poolCfg.ConnConfig.Database,
 		"max_conns", cfg.MaxConns,
 	)
 
 	return pool, nil
 }
 
-// RunMigrations executes embedded SQL migrations.
-func RunMigrations(ctx context.Context, pool *pgxpool.Pool) error {
-	sqlBytes, err := migrations.ReadFile("migrations/001_initial_schema.sql")
+// poolConfig parses the connection settings, including any TLS certificates,
+// and applies the pool tuning.
+func poolConfig(cfg config.DatabaseConfig) (*pgxpool.Config, error) {
+	poolCfg, err := pgxpool.ParseConfig(cfg.DSN())
 	if err != nil {
-		return fmt.Errorf("read migration: %w", err)
+		return nil, fmt.Errorf("parse db config: %w", err)
 	}
 
-	_, err = pool.Exec(ctx, string(sqlBytes))
-	if err != nil {
-		return fmt.Errorf("execute migration: %w", err)
-	}
+	poolCfg.MaxConns = int32(cfg.MaxConns)
+	poolCfg.MinConns = 2
+	poolCfg.MaxConnLifetime = 30 * time.Minute
+	poolCfg.MaxConnIdleTime = 5 * time.Minute
+	return poolCfg, nil
+}
 
-	slog.Info("database migrations applied successfully")
-	return nil
+// ConnectWithRetry calls Connect up to attempts times, waiting wait between
+// tries, so the service can start (or reconnect) while Postgres is still
+// coming up. It gives up early if ctx is done.
+func ConnectWithRetry(ctx context.Context, cfg config.DatabaseConfig, attempts int, wait time.Duration) (*pgxpool.Pool, error) {
+	var lastErr error
+	for i := 0; i < attempts; i++ {
+		pool, err := Connect(ctx, cfg)
+		if err == nil {
+			return pool, nil
+		}
+		lastErr = err
+		slog.Warn("database not ready, retrying...",
+			"attempt", i+1,
+			"max_retries", attempts,
+			"error", err,
+		)
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+	return nil, fmt.Errorf("connect after %d attempts: %w", attempts, lastErr)
 }
@@ -0,0 +1,85 @@
+package db
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/config"
+)
+
+// writeTestCert writes a self-signed certificate and its key as PEM files in
+// dir and returns their paths.
+func writeTestCert(t *testing.T, dir, name string) (certPath, keyPath string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPath = filepath.Join(dir, name+".crt")
+	keyPath = filepath.Join(dir, name+".key")
+	require.NoError(t, os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	return certPath, keyPath
+}
+
+func TestPoolConfig_LoadsTLSCertificates(t *testing.T) {
+	dir := t.TempDir()
+	caPath, _ := writeTestCert(t, dir, "ca")
+	certPath, keyPath := writeTestCert(t, dir, "client")
+
+	cfg := config.DatabaseConfig{
+		Host:        "db.example.com",
+		Port:        "5432",
+		User:        "ssiq",
+		Password:    "secret",
+		DBName:      "ssiq",
+		SSLMode:     "verify-full",
+		SSLRootCert: caPath,
+		SSLCert:     certPath,
+		SSLKey:      keyPath,
+		MaxConns:    5,
+	}
+	require.NoError(t, cfg.Validate())
+
+	poolCfg, err := poolConfig(cfg)
+	require.NoError(t, err)
+
+	tlsCfg := poolCfg.ConnConfig.TLSConfig
+	require.NotNil(t, tlsCfg, "verify-full should produce a TLS config")
+	assert.NotNil(t, tlsCfg.RootCAs, "root CA should be loaded")
+	assert.Len(t, tlsCfg.Certificates, 1, "client certificate should be loaded")
+	assert.Equal(t, "db.example.com", tlsCfg.ServerName)
+	assert.Equal(t, int32(5), poolCfg.MaxConns)
+
+	// Without certificates the connection is plaintext as before
+	cfg.SSLMode = "disable"
+	cfg.SSLRootCert, cfg.SSLCert, cfg.SSLKey = "", "", ""
+	poolCfg, err = poolConfig(cfg)
+	require.NoError(t, err)
+	assert.Nil(t, poolCfg.ConnConfig.TLSConfig)
+}
@@ -0,0 +1,245 @@
+// Package i18n holds the message catalogs scoring explanations are
+// rendered with, one per supported locale.
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale is the locale used when none is configured or requested.
+const DefaultLocale = "en"
+
+// Catalog holds the text templates for one locale. Templates are fmt
+// formats; those with several arguments use explicit indexes (%[2]s) so a
+// translation can reorder them.
+type Catalog struct {
+	Locale string
+
+	// Quality labels for a factor's normalized value, best first
+	QualityExcellent string
+	QualityGood      string
+	QualityFair      string
+	QualityPoor      string
+
+	// Direction phrases closing a factor's reason
+	HigherIsBetter string
+	LowerIsBetter  string
+	TargetIsBest   string
+
+	// Reason is a factor's reason: field, value, quality, direction phrase.
+	// ReasonTarget adds the target: field, value, target, quality, phrase.
+	Reason       string
+	ReasonTarget string
+	// RankReason is a rank_sum factor's reason: ordinal rank, total,
+	// field, direction phrase.
+	RankReason string
+
+	// Notes appended to a reason when a value was capped at a bound
+	ClampAbove string
+	ClampBelow string
+
+	// Summary sentences. SummaryNoFactors stands alone; the others take
+	// the formatted score or a list of fields.
+	SummaryNoFactors  string
+	SummaryScoreOnly  string
+	SummaryScore      string
+	SummaryPrimary    string
+	SummaryTop        string
+	SummaryHeldBack   string
+	SummaryCoverage   string
+	SummaryFreshness  string
+	WeakLow           string
+	WeakHigh          string
+	WeakOffTarget     string
+	ScoreOutOfTen     string
+	ScoreOutOfOne     string
+	ListPairSeparator string // between the two items of a two-item list
+	ListLastSeparator string // before the last item of a longer list
+
+	// Ordinal formats a rank, e.g. 3 as "3rd"
+	Ordinal func(n int) string
+}
+
+// List joins items as prose in the catalog's language, e.g. "a, b, and c".
+func (c *Catalog) List(items []string) string {
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	case 2:
+		return items[0] + c.ListPairSeparator + items[1]
+	default:
+		return strings.Join(items[:len(items)-1], ", ") + c.ListLastSeparator + items[len(items)-1]
+	}
+}
+
+var english = &Catalog{
+	Locale: "en",
+
+	QualityExcellent: "excellent",
+	QualityGood:      "good",
+	QualityFair:      "fair",
+	QualityPoor:      "poor",
+
+	HigherIsBetter: "higher is better",
+	LowerIsBetter:  "lower is better",
+	TargetIsBest:   "closest to target is best",
+
+	Reason:       "%[1]s value is %.2[2]f, which is %[3]s for this metric (%[4]s)",
+	ReasonTarget: "%[1]s value is %.2[2]f against a target of %.2[3]f, which is %[4]s for this metric (%[5]s)",
+	RankReason:   "%[1]s of %[2]d on %[3]s (%[4]s)",
+
+	ClampAbove: "; value exceeds configured max of %g and was capped",
+	ClampBelow: "; value is below configured min of %g and was capped",
+
+	SummaryNoFactors:  "No scoring factors contributed to this site's score.",
+	SummaryScoreOnly:  "Final score is %s based on weighted factor analysis.",
+	SummaryScore:      "Final score is %s.",
+	SummaryPrimary:    " The primary contributing factor is %s.",
+	SummaryTop:        " Top contributing factors are %s.",
+	SummaryHeldBack:   " Held back by %s.",
+	SummaryCoverage:   " Score based on only %.0f%% of weighted factors.",
+	SummaryFreshness:  " Score reduced from %[1]s to %[2]s because the data is %.0[3]f days old (collected %[4]s; half-life %[5]g days).",
+	WeakLow:           "low %s",
+	WeakHigh:          "high %s",
+	WeakOffTarget:     "off-target %s",
+	ScoreOutOfTen:     "%.1f out of 10",
+	ScoreOutOfOne:     "%.2f out of 1",
+	ListPairSeparator: " and ",
+	ListLastSeparator: ", and ",
+
+	Ordinal: englishOrdinal,
+}
+
+var spanish = &Catalog{
+	Locale: "es",
+
+	QualityExcellent: "excelente",
+	QualityGood:      "bueno",
+	QualityFair:      "regular",
+	QualityPoor:      "deficiente",
+
+	HigherIsBetter: "cuanto más alto, mejor",
+	LowerIsBetter:  "cuanto más bajo, mejor",
+	TargetIsBest:   "cuanto más cerca del objetivo, mejor",
+
+	Reason:       "El valor de %[1]s es %.2[2]f, lo que es %[3]s para esta métrica (%[4]s)",
+	ReasonTarget: "El valor de %[1]s es %.2[2]f frente a un objetivo de %.2[3]f, lo que es %[4]s para esta métrica (%[5]s)",
+	RankReason:   "%[1]s de %[2]d en %[3]s (%[4]s)",
+
+	ClampAbove: "; el valor supera el máximo configurado de %g y se ha limitado",
+	ClampBelow: "; el valor está por debajo del mínimo configurado de %g y se ha limitado",
+
+	SummaryNoFactors:  "Ningún factor de puntuación contribuyó a la puntuación de este sitio.",
+	SummaryScoreOnly:  "La puntuación final es %s según el análisis ponderado de factores.",
+	SummaryScore:      "La puntuación final es %s.",
+	SummaryPrimary:    " El factor que más contribuye es %s.",
+	SummaryTop:        " Los factores que más contribuyen son %s.",
+	SummaryHeldBack:   " Penalizada por %s.",
+	SummaryCoverage:   " Puntuación basada solo en el %.0f%% de los factores ponderados.",
+	SummaryFreshness:  " Puntuación reducida de %[1]s a %[2]s porque los datos tienen %.0[3]f días (recogidos el %[4]s; vida media de %[5]g días).",
+	WeakLow:           "valor bajo de %s",
+	WeakHigh:          "valor alto de %s",
+	WeakOffTarget:     "%s fuera del objetivo",
+	ScoreOutOfTen:     "%.1f de 10",
+	ScoreOutOfOne:     "%.2f de 1",
+	ListPairSeparator: " y ",
+	ListLastSeparator: " y ",
+
+	Ordinal: func(n int) string { return fmt.Sprintf("%d.º", n) },
+}
+
+// catalogs are the supported locales.
+var catalogs = map[string]*Catalog{
+	english.Locale: english,
+	spanish.Locale: spanish,
+}
+
+// Lookup returns the catalog for locale, or the DefaultLocale catalog when
+// locale is empty or unsupported.
+func Lookup(locale string) *Catalog {
+	if c, ok := catalogs[strings.ToLower(locale)]; ok {
+		return c
+	}
+	return catalogs[DefaultLocale]
+}
+
+// Supported reports whether locale has a catalog.
+func Supported(locale string) bool {
+	_, ok := catalogs[strings.ToLower(locale)]
+	return ok
+}
+
+// Locales returns the supported locales, sorted.
+func Locales() []string {
+	locales := make([]string, 0, len(catalogs))
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Negotiate picks the supported locale best matching an Accept-Language
+// header such as "es-MX,es;q=0.9,en;q=0.5". Region subtags fall back to
+// their language. It returns "" when nothing in the header is supported.
+func Negotiate(acceptLanguage string) string {
+	type candidate struct {
+		locale string
+		q      float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+		language, _, _ := strings.Cut(tag, "-")
+		if Supported(tag) {
+			candidates = append(candidates, candidate{tag, q})
+		} else if Supported(language) {
+			candidates = append(candidates, candidate{language, q})
+		}
+	}
+
+	// Stable, so equal weights keep the header's order
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	if len(candidates) == 0 {
+		return ""
+	}
+	return candidates[0].locale
+}
+
+// englishOrdinal formats n as 1st, 2nd, 3rd, 4th, ..., 11th, 12th, 13th, 21st, ...
+func englishOrdinal(n int) string {
+	suffix := "th"
+	switch n % 100 {
+	case 11, 12, 13:
+	default:
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
@@ -0,0 +1,71 @@
+package i18n
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{"es", "es"},
+		{"es-MX", "es"},
+		{"ES-mx,en;q=0.5", "es"},
+		{"fr-FR,fr;q=0.9,es;q=0.8,en;q=0.7", "es"},
+		{"en;q=0.4,es;q=0.6", "es"},
+		{"es;q=0.5,en;q=0.5", "es"},
+		{"es;q=0,en", "en"},
+		{"*", ""},
+		{"fr, de", ""},
+		{"es;q=high", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, Negotiate(tt.header), "header %q", tt.header)
+	}
+}
+
+func TestLookup_FallsBackToDefault(t *testing.T) {
+	assert.Equal(t, "es", Lookup("ES").Locale)
+	assert.Equal(t, DefaultLocale, Lookup("").Locale)
+	assert.Equal(t, DefaultLocale, Lookup("fr").Locale)
+	assert.False(t, Supported("fr"))
+	assert.Equal(t, []string{"en", "es"}, Locales())
+}
+
+func TestCatalogs_Complete(t *testing.T) {
+	// Test that no catalog leaves a message empty
+	for _, locale := range Locales() {
+		c := reflect.ValueOf(*Lookup(locale))
+		for i := 0; i < c.NumField(); i++ {
+			field := c.Field(i)
+			name := c.Type().Field(i).Name
+			switch field.Kind() {
+			case reflect.String:
+				assert.NotEmpty(t, field.String(), "%s: %s", locale, name)
+			case reflect.Func:
+				assert.False(t, field.IsNil(), "%s: %s", locale, name)
+			}
+		}
+	}
+}
+
+func TestCatalog_List(t *testing.T) {
+	items := []string{"income", "growth", "rent"}
+
+	assert.Equal(t, "income, growth, and rent", Lookup("en").List(items))
+	assert.Equal(t, "income, growth y rent", Lookup("es").List(items))
+	assert.Equal(t, "income y growth", Lookup("es").List(items[:2]))
+	assert.Equal(t, "income", Lookup("es").List(items[:1]))
+	assert.Empty(t, Lookup("en").List(nil))
+}
+
+func TestOrdinal(t *testing.T) {
+	assert.Equal(t, "3.º", Lookup("es").Ordinal(3))
+	assert.Equal(t, "112th", Lookup("en").Ordinal(112))
+}
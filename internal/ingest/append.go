@@ -0,0 +1,120 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+)
+
+// AppendJob is a saved CSV whose rows are added to an existing, completed
+// upload rather than creating a new one.
+type AppendJob struct {
+	Job
+	Existing    []models.SiteRecord // records already stored under the upload
+	ContentHash string              // the upload's content hash once the rows are appended
+}
+
+// Append parses the job's file against the upload's schema and inserts its
+// rows under the existing upload, adding to its row count and replacing its
+// content hash. The file must have the same columns as the upload's
+// existing records and must not repeat any of their site IDs. The rows and
+// the upload are stored in one transaction, which fails with the store's
+// error if the upload changed since it was read or has been scored; unlike
+// Process, a failed append leaves the stored upload untouched. The temp
+// file is removed either way.
+func (p *Processor) Append(ctx context.Context, job AppendJob) (*Result, error) {
+	defer os.Remove(job.Path)
+	upload := job.Upload
+	previousHash := upload.ContentHash
+
+	records, result, err := p.parse(job.Job)
+	if err != nil {
+		return result, err
+	}
+	if err := checkAppendCompatible(records, job.Existing, job.Schema.SiteIDColumn); err != nil {
+		return result, &ValidationError{Err: fmt.Errorf("CSV validation failed: %v", err)}
+	}
+
+	now := time.Now()
+	siteRecords, coercionWarnings := BuildSiteRecords(records, job.Schema, upload.ID, upload.TenantID, now)
+	result.Warnings = append(result.Warnings, coercionWarnings...)
+
+	// The upload keeps its earlier warnings alongside the appended file's
+	var warnings []string
+	_ = json.Unmarshal(upload.Warnings, &warnings)
+	upload.Warnings, _ = json.Marshal(append(warnings, result.Warnings...))
+	upload.RowCount += len(records)
+	if info, err := os.Stat(job.Path); err == nil {
+		upload.FileSize += info.Size()
+	}
+	upload.ContentHash = &job.ContentHash
+	upload.UpdatedAt = now
+	if err := p.uploadRepo.AppendRecords(ctx, upload, previousHash, siteRecords); err != nil {
+		return result, fmt.Errorf("failed to append site records: %w", err)
+	}
+	return result, nil
+}
+
+// checkAppendCompatible reports an error if the appended records' columns
+// differ from the existing records' or if they reuse an existing site ID.
+func checkAppendCompatible(records []json.RawMessage, existing []models.SiteRecord, siteIDColumn string) error {
+	if len(existing) == 0 {
+		return nil
+	}
+
+	// Every parsed record carries the file's full header, so one suffices
+	want := recordColumns(existing[0].RawData)
+	if got := recordColumns(records[0]); !sameColumns(got, want) {
+		return fmt.Errorf("headers do not match the upload's columns: got %s, want %s",
+			strings.Join(got, ", "), strings.Join(want, ", "))
+	}
+
+	seen := make(map[string]bool, len(existing))
+	for _, rec := range existing {
+		seen[rec.SiteID] = true
+	}
+	for _, raw := range records {
+		var row map[string]interface{}
+		if err := json.Unmarshal(raw, &row); err != nil {
+			continue
+		}
+		siteID := fmt.Sprintf("%v", row[siteIDColumn])
+		if seen[siteID] {
+			return fmt.Errorf("site %s already exists in this upload", siteID)
+		}
+		seen[siteID] = true
+	}
+	return nil
+}
+
+// recordColumns returns the sorted column names of a parsed CSV record.
+func recordColumns(raw json.RawMessage) []string {
+	var row map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &row); err != nil {
+		return nil
+	}
+	columns := make([]string, 0, len(row))
+	for column := range row {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+func sameColumns(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
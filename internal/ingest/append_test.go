@@ -0,0 +1,131 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/config"
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+)
+
+// completedUpload processes body as a fresh upload and returns it with the
+// site records it stored.
+func completedUpload(t *testing.T, p *Processor, sites *fakeSiteRecordStore, body string) (*models.Upload, []models.SiteRecord) {
+	t.Helper()
+	upload := pendingUpload()
+	_, err := p.Process(context.Background(), Job{Upload: upload, Path: writeTempCSV(t, body), Schema: outlierSchema(t)})
+	require.NoError(t, err)
+	return upload, append([]models.SiteRecord(nil), sites.inserted...)
+}
+
+func TestProcessor_AppendAddsRows(t *testing.T) {
+	uploads := &fakeUploadStore{}
+	sites := &fakeSiteRecordStore{}
+	p := NewProcessor(uploads, sites, config.UploadConfig{})
+	upload, existing := completedUpload(t, p, sites, "site_id,unemployment_rate,population\nS1,4.1,52000\nS2,5.3,48000\n")
+	originalHash := "original-hash"
+	upload.ContentHash = &originalHash
+
+	path := writeTempCSV(t, "population,site_id,unemployment_rate\n61000,S3,3.9\n55000,S4,6.0\n50000,S5,4.8\n")
+	_, err := p.Append(context.Background(), AppendJob{
+		Job:         Job{Upload: upload, Path: path, Schema: outlierSchema(t)},
+		Existing:    existing,
+		ContentHash: "appended-hash",
+	})
+
+	require.NoError(t, err)
+	final := uploads.last()
+	assert.Equal(t, 5, final.RowCount, "appended rows add to the existing total")
+	assert.Equal(t, "appended-hash", *final.ContentHash)
+	assert.Equal(t, "completed", final.Status)
+	require.NotNil(t, uploads.previousHash)
+	assert.Equal(t, "original-hash", *uploads.previousHash, "the store checks the hash the upload was read with")
+	assert.Len(t, uploads.appended, 3, "appended rows are stored with the upload")
+	for _, rec := range uploads.appended {
+		assert.Equal(t, upload.ID, rec.UploadID)
+	}
+	assert.NoFileExists(t, path, "temp file is removed after processing")
+}
+
+func TestProcessor_AppendRejectsDifferentColumns(t *testing.T) {
+	uploads := &fakeUploadStore{}
+	sites := &fakeSiteRecordStore{}
+	p := NewProcessor(uploads, sites, config.UploadConfig{})
+	upload, existing := completedUpload(t, p, sites, "site_id,unemployment_rate,population\nS1,4.1,52000\n")
+	updates := len(uploads.updates)
+
+	path := writeTempCSV(t, "site_id,unemployment_rate,population,notes\nS2,5.3,48000,new\n")
+	_, err := p.Append(context.Background(), AppendJob{
+		Job:      Job{Upload: upload, Path: path, Schema: outlierSchema(t)},
+		Existing: existing,
+	})
+
+	var vErr *ValidationError
+	require.ErrorAs(t, err, &vErr)
+	assert.Contains(t, err.Error(), "headers do not match the upload's columns")
+	assert.Len(t, uploads.updates, updates, "a failed append leaves the upload untouched")
+	assert.Equal(t, 1, upload.RowCount)
+	assert.Len(t, sites.inserted, 1)
+}
+
+func TestProcessor_AppendRejectsExistingSiteIDs(t *testing.T) {
+	uploads := &fakeUploadStore{}
+	sites := &fakeSiteRecordStore{}
+	p := NewProcessor(uploads, sites, config.UploadConfig{})
+	upload, existing := completedUpload(t, p, sites, "site_id,unemployment_rate,population\nS1,4.1,52000\n")
+
+	path := writeTempCSV(t, "site_id,unemployment_rate,population\nS2,5.3,48000\nS1,3.9,61000\n")
+	_, err := p.Append(context.Background(), AppendJob{
+		Job:      Job{Upload: upload, Path: path, Schema: outlierSchema(t)},
+		Existing: existing,
+	})
+
+	var vErr *ValidationError
+	require.ErrorAs(t, err, &vErr)
+	assert.Contains(t, err.Error(), "site S1 already exists in this upload")
+	assert.Len(t, sites.inserted, 1)
+}
+
+func TestProcessor_AppendReturnsStoreConflict(t *testing.T) {
+	uploads := &fakeUploadStore{}
+	sites := &fakeSiteRecordStore{}
+	p := NewProcessor(uploads, sites, config.UploadConfig{})
+	upload, existing := completedUpload(t, p, sites, "site_id,unemployment_rate,population\nS1,4.1,52000\n")
+
+	// The upload was scored between the caller's checks and the append
+	errScored := errors.New("upload already has scoring runs")
+	uploads.appendErr = errScored
+	path := writeTempCSV(t, "site_id,unemployment_rate,population\nS2,5.3,48000\n")
+	_, err := p.Append(context.Background(), AppendJob{
+		Job:      Job{Upload: upload, Path: path, Schema: outlierSchema(t)},
+		Existing: existing,
+	})
+
+	assert.ErrorIs(t, err, errScored)
+	assert.Empty(t, uploads.appended)
+	assert.NoFileExists(t, path)
+}
+
+func TestProcessor_AppendKeepsEarlierWarnings(t *testing.T) {
+	uploads := &fakeUploadStore{}
+	sites := &fakeSiteRecordStore{}
+	p := NewProcessor(uploads, sites, config.UploadConfig{})
+	upload, existing := completedUpload(t, p, sites, "site_id,unemployment_rate,population\nS1,4.1,52000\nS0,,1\n")
+
+	path := writeTempCSV(t, "site_id,unemployment_rate,population\nS2,5.3,48000\nS9,,1\n")
+	_, err := p.Append(context.Background(), AppendJob{
+		Job:      Job{Upload: upload, Path: path, Schema: outlierSchema(t)},
+		Existing: existing,
+	})
+
+	require.NoError(t, err)
+	var warnings []string
+	require.NoError(t, json.Unmarshal(uploads.last().Warnings, &warnings))
+	assert.Len(t, warnings, 2, "one skipped row from each file")
+	assert.Equal(t, 2, uploads.last().RowCount)
+}
@@ -5,13 +5,83 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
 
+	"github.com/workforce-ai/site-selection-iq/internal/config"
 	"github.com/workforce-ai/site-selection-iq/internal/schema"
 )
 
+// ParseOptions controls how a CSV file is read. The limits bound its shape
+// so a malformed or hostile upload cannot expand into an unbounded number of
+// columns or oversized values; a zero limit is not enforced.
+type ParseOptions struct {
+	MaxColumns    int // columns per row, header included
+	MaxFieldBytes int // bytes per field
+
+	// NormalizeHeaders rewrites headers like "Site ID " to "site_id" before
+	// they are matched against the schema.
+	NormalizeHeaders bool
+}
+
+// OptionsFromConfig returns the CSV parse options configured for uploads.
+func OptionsFromConfig(cfg config.UploadConfig) ParseOptions {
+	return ParseOptions{
+		MaxColumns:       cfg.MaxColumns,
+		MaxFieldBytes:    cfg.MaxFieldBytes,
+		NormalizeHeaders: cfg.NormalizeHeaders,
+	}
+}
+
+// check returns an error if row breaks a limit. lineNum is the 1-based CSV line.
+func (o ParseOptions) check(row []string, lineNum int) error {
+	if o.MaxColumns > 0 && len(row) > o.MaxColumns {
+		return fmt.Errorf("line %d: %d columns exceeds the limit of %d", lineNum, len(row), o.MaxColumns)
+	}
+	if o.MaxFieldBytes > 0 {
+		for i, field := range row {
+			if len(field) > o.MaxFieldBytes {
+				return fmt.Errorf("line %d, column %d: field is %d bytes, exceeding the limit of %d",
+					lineNum, i+1, len(field), o.MaxFieldBytes)
+			}
+		}
+	}
+	return nil
+}
+
+// headers applies header normalization when enabled, returning the headers
+// to match against the schema and a warning for each one that changed.
+func (o ParseOptions) headers(raw []string) ([]string, []string, error) {
+	if !o.NormalizeHeaders {
+		return raw, nil, nil
+	}
+	return NormalizeHeaders(raw)
+}
+
+// NormalizeHeaders trims each header, lowercases it and joins its words with
+// underscores, so "Site ID" becomes "site_id". It returns a warning recording
+// each header that changed, and an error if two headers normalize to the
+// same name.
+func NormalizeHeaders(headers []string) (normalized []string, warnings []string, err error) {
+	normalized = make([]string, len(headers))
+	source := make(map[string]string, len(headers))
+	for i, header := range headers {
+		name := strings.Join(strings.Fields(strings.ToLower(header)), "_")
+		if prev, ok := source[name]; ok {
+			return nil, nil, fmt.Errorf("headers %q and %q both normalize to %q", prev, header, name)
+		}
+		source[name] = header
+		normalized[i] = name
+		if name != header {
+			warnings = append(warnings, fmt.Sprintf("header %q normalized to %q", header, name))
+		}
+	}
+	return normalized, warnings, nil
+}
+
 // Parse reads and validates a CSV file, returning site records, validation warnings, and any fatal errors.
 // Warnings are non-fatal (e.g., unexpected columns, skipped rows). Errors are fatal (e.g., missing required columns).
-func Parse(reader io.Reader, schemaConfig *schema.ResolvedSchema) (
+// A row with more columns or longer fields than opts allows rejects the whole file.
+func Parse(reader io.Reader, schemaConfig *schema.ResolvedSchema, opts ParseOptions) (
 	records []json.RawMessage,
 	warnings []string,
 	err error,
@@ -21,7 +91,7 @@ func Parse(reader io.Reader, schemaConfig *schema.ResolvedSchema) (
 
 	// Create CSV reader
 	csvReader := csv.NewReader(reader)
-	csvReader.FieldsPerRecord = -1 // Allow variable number of fields
+	csvReader.FieldsPerRecord = -1 // Allow variable number of fields; bounded by opts
 
 	// Read header row
 	headers, err := csvReader.Read()
@@ -31,6 +101,19 @@ func Parse(reader io.Reader, schemaConfig *schema.ResolvedSchema) (
 		}
 		return records, warnings, fmt.Errorf("failed to read CSV headers: %v", err)
 	}
+	if err := opts.check(headers, 1); err != nil {
+		return records, warnings, err
+	}
+	headers, headerNotes, err := opts.headers(headers)
+	if err != nil {
+		return records, warnings, err
+	}
+	warnings = append(warnings, headerNotes...)
+
+	// Data rows share one slice; each row's values are copied into rowMap
+	// before the next read. Enabled after the header read so headers keeps
+	// its own backing array.
+	csvReader.ReuseRecord = true
 
 	// Validate headers
 	headerWarnings, headerErrors := schema.ValidateHeaders(headers, schemaConfig)
@@ -52,6 +135,9 @@ func Parse(reader io.Reader, schemaConfig *schema.ResolvedSchema) (
 			}
 			return records, warnings, fmt.Errorf("line %d: failed to read CSV row: %v", lineNum, err)
 		}
+		if err := opts.check(csvRow, lineNum); err != nil {
+			return records, warnings, err
+		}
 
 		// Convert CSV row to map
 		rowMap := make(map[string]string)
@@ -0,0 +1,98 @@
+package ingest
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_WithinLimits(t *testing.T) {
+	csv := "site_id,unemployment_rate,population\nS1,4.1,52000\nS2,5.3,48000\n"
+
+	records, _, err := Parse(strings.NewReader(csv), outlierSchema(t), ParseOptions{MaxColumns: 3, MaxFieldBytes: len("unemployment_rate")})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	// Rows are read into a reused slice; each record must keep its own values
+	var first, second map[string]interface{}
+	require.NoError(t, json.Unmarshal(records[0], &first))
+	require.NoError(t, json.Unmarshal(records[1], &second))
+	assert.Equal(t, "S1", first["site_id"])
+	assert.Equal(t, "52000", first["population"])
+	assert.Equal(t, "S2", second["site_id"])
+}
+
+func TestParse_RejectsRowOverColumnLimit(t *testing.T) {
+	csv := "site_id,unemployment_rate,population\nS1,4.1,52000\nS2,5.3,48000" + strings.Repeat(",x", 10) + "\n"
+
+	_, _, err := Parse(strings.NewReader(csv), outlierSchema(t), ParseOptions{MaxColumns: 5})
+	require.Error(t, err)
+	assert.Equal(t, "line 3: 13 columns exceeds the limit of 5", err.Error())
+
+	// The header row counts too
+	_, _, err = Parse(strings.NewReader(csv), outlierSchema(t), ParseOptions{MaxColumns: 2})
+	assert.ErrorContains(t, err, "line 1: 3 columns exceeds the limit of 2")
+}
+
+func TestParse_RejectsOverLongField(t *testing.T) {
+	csv := "site_id,unemployment_rate,population\nS1,4.1,52000\nS2," + strings.Repeat("9", 100) + ",48000\n"
+
+	_, _, err := Parse(strings.NewReader(csv), outlierSchema(t), ParseOptions{MaxFieldBytes: 64})
+	require.Error(t, err)
+	assert.Equal(t, "line 3, column 2: field is 100 bytes, exceeding the limit of 64", err.Error())
+}
+
+func TestParseZip_AppliesLimitsToMembers(t *testing.T) {
+	archive := buildZip(t,
+		zipMember{"a.csv", "site_id,unemployment_rate,population\nS1,4.1,52000\n"},
+		zipMember{"b.csv", "site_id,unemployment_rate,population\nS2,4.1," + strings.Repeat("1", 40) + "\n"},
+	)
+
+	_, _, _, err := ParseZip(archive, archive.Size(), outlierSchema(t), 1<<20, ParseOptions{MaxFieldBytes: 32})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "b.csv: line 2, column 3")
+}
+
+func TestParse_NormalizesHeaders(t *testing.T) {
+	csv := "Site ID, Unemployment Rate ,population\nS1,4.1,52000\n"
+
+	records, warnings, err := Parse(strings.NewReader(csv), outlierSchema(t), ParseOptions{NormalizeHeaders: true})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, []string{
+		`header "Site ID" normalized to "site_id"`,
+		`header " Unemployment Rate " normalized to "unemployment_rate"`,
+	}, warnings)
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(records[0], &record))
+	assert.Equal(t, "S1", record["site_id"])
+	assert.Equal(t, "4.1", record["unemployment_rate"])
+
+	// Without normalization the headers do not match the schema
+	_, _, err = Parse(strings.NewReader(csv), outlierSchema(t), ParseOptions{})
+	assert.ErrorContains(t, err, "header validation failed")
+}
+
+func TestParse_RejectsNormalizedHeaderCollision(t *testing.T) {
+	csv := "site_id,Site ID,unemployment_rate,population\nS1,S1,4.1,52000\n"
+
+	_, _, err := Parse(strings.NewReader(csv), outlierSchema(t), ParseOptions{NormalizeHeaders: true})
+	require.Error(t, err)
+	assert.Equal(t, `headers "site_id" and "Site ID" both normalize to "site_id"`, err.Error())
+}
+
+func TestParseZip_NormalizesHeadersBeforeComparingMembers(t *testing.T) {
+	archive := buildZip(t,
+		zipMember{"a.csv", "site_id,unemployment_rate,population\nS1,4.1,52000\n"},
+		zipMember{"b.csv", "Site ID,Unemployment Rate,Population\nS2,5.3,48000\n"},
+	)
+
+	records, warnings, _, err := ParseZip(archive, archive.Size(), outlierSchema(t), 1<<20, ParseOptions{NormalizeHeaders: true})
+	require.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Contains(t, warnings, `b.csv: header "Site ID" normalized to "site_id"`)
+}
@@ -0,0 +1,112 @@
+package ingest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
+)
+
+// minOutlierSample is the fewest values a field needs before outliers are
+// checked; quartiles of smaller samples are too noisy to be useful.
+const minOutlierSample = 4
+
+// DetectOutliers makes a second pass over parsed records and returns a
+// warning for each numeric value outside Tukey's fences: below
+// Q1 - threshold*IQR or above Q3 + threshold*IQR. A threshold of 1.5 flags
+// mild outliers, 3.0 only extreme ones. Warnings never block an upload.
+func DetectOutliers(records []json.RawMessage, schemaConfig *schema.ResolvedSchema, threshold float64) []string {
+	warnings := make([]string, 0)
+	if threshold <= 0 || len(records) < minOutlierSample {
+		return warnings
+	}
+
+	rows := make([]map[string]interface{}, 0, len(records))
+	for _, record := range records {
+		var row map[string]interface{}
+		if err := json.Unmarshal(record, &row); err == nil {
+			rows = append(rows, row)
+		}
+	}
+
+	fieldNames := make([]string, 0, len(schemaConfig.Fields))
+	for name, fieldDef := range schemaConfig.Fields {
+		if fieldDef.Type.IsNumeric() {
+			fieldNames = append(fieldNames, name)
+		}
+	}
+	sort.Strings(fieldNames)
+
+	for _, field := range fieldNames {
+		type observation struct {
+			siteID string
+			value  float64
+		}
+		var observations []observation
+		for _, row := range rows {
+			value, ok := numericValue(row[field], schemaConfig.NumberFormat)
+			if !ok {
+				continue
+			}
+			observations = append(observations, observation{
+				siteID: fmt.Sprintf("%v", row[schemaConfig.SiteIDColumn]),
+				value:  value,
+			})
+		}
+		if len(observations) < minOutlierSample {
+			continue
+		}
+
+		values := make([]float64, len(observations))
+		for i, o := range observations {
+			values[i] = o.value
+		}
+		sort.Float64s(values)
+
+		q1, q3 := quantile(values, 0.25), quantile(values, 0.75)
+		iqr := q3 - q1
+		lower, upper := q1-threshold*iqr, q3+threshold*iqr
+
+		for _, o := range observations {
+			if o.value < lower || o.value > upper {
+				warnings = append(warnings, fmt.Sprintf(
+					"site %s: %s value %g looks like an outlier (expected %g to %g)",
+					o.siteID, field, o.value, lower, upper))
+			}
+		}
+	}
+
+	return warnings
+}
+
+// numericValue extracts a finite number from a parsed CSV cell.
+func numericValue(v interface{}, format schema.NumberFormat) (float64, bool) {
+	var f float64
+	switch val := v.(type) {
+	case float64:
+		f = val
+	case string:
+		parsed, err := format.ParseFloat(val)
+		if err != nil {
+			return 0, false
+		}
+		f = parsed
+	default:
+		return 0, false
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, false
+	}
+	return f, true
+}
+
+// quantile returns the q-th quantile of sorted values using linear
+// interpolation between closest ranks.
+func quantile(sorted []float64, q float64) float64 {
+	pos := q * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(pos-float64(lo))
+}
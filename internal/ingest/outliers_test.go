@@ -0,0 +1,79 @@
+package ingest
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
+)
+
+func outlierSchema(t *testing.T) *schema.ResolvedSchema {
+	t.Helper()
+	resolved, err := schema.Resolve(json.RawMessage(`{
+		"site_id_column": "site_id",
+		"fields": {
+			"site_id": {"type": "identifier", "required": true},
+			"unemployment_rate": {"type": "percentage", "required": true, "weight": 1.0, "direction": "minimize"},
+			"population": {"type": "population", "required": true, "weight": 1.0, "direction": "maximize"}
+		}
+	}`), nil)
+	require.NoError(t, err)
+	return resolved
+}
+
+func TestDetectOutliers_FlagsObviousOutlier(t *testing.T) {
+	resolved := outlierSchema(t)
+	csv := `site_id,unemployment_rate,population
+S1,4.1,52000
+S2,5.3,48000
+S3,3.9,61000
+S4,6.0,55000
+S5,4.8,50000
+S6,95,53000
+`
+
+	records, warnings, err := Parse(strings.NewReader(csv), resolved, ParseOptions{})
+	require.NoError(t, err)
+	require.Len(t, records, 6, "outliers never block ingest")
+	assert.Empty(t, warnings)
+
+	outliers := DetectOutliers(records, resolved, 3.0)
+
+	require.Len(t, outliers, 1)
+	assert.Contains(t, outliers[0], "site S6: unemployment_rate value 95 looks like an outlier")
+}
+
+func TestDetectOutliers_ThresholdControlsSensitivity(t *testing.T) {
+	resolved := outlierSchema(t)
+	var records []json.RawMessage
+	for _, row := range []string{
+		`{"site_id": "S1", "population": "100"}`,
+		`{"site_id": "S2", "population": "110"}`,
+		`{"site_id": "S3", "population": "120"}`,
+		`{"site_id": "S4", "population": "130"}`,
+		`{"site_id": "S5", "population": "175"}`,
+	} {
+		records = append(records, json.RawMessage(row))
+	}
+
+	// Q1 = 110, Q3 = 130, IQR = 20: 175 is past the 1.5x fence (160)
+	// but inside the 3x fence (190)
+	assert.Len(t, DetectOutliers(records, resolved, 1.5), 1)
+	assert.Empty(t, DetectOutliers(records, resolved, 3.0))
+	assert.Empty(t, DetectOutliers(records, resolved, 0), "non-positive threshold disables the check")
+}
+
+func TestDetectOutliers_SmallSamplesSkipped(t *testing.T) {
+	resolved := outlierSchema(t)
+	records := []json.RawMessage{
+		json.RawMessage(`{"site_id": "S1", "population": "100"}`),
+		json.RawMessage(`{"site_id": "S2", "population": "110"}`),
+		json.RawMessage(`{"site_id": "S3", "population": "900000"}`),
+	}
+
+	assert.Empty(t, DetectOutliers(records, resolved, 1.5))
+}
@@ -0,0 +1,250 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/workforce-ai/site-selection-iq/internal/config"
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
+)
+
+// interruptedReason is recorded on uploads still processing when shutdown times out.
+const interruptedReason = "upload processing interrupted by server shutdown"
+
+// UploadStore is the subset of upload persistence the processor depends on.
+// AppendRecords stores an append's records and updated upload atomically,
+// failing if the upload changed from previousHash or was scored meanwhile.
+type UploadStore interface {
+	Update(ctx context.Context, upload *models.Upload) error
+	AppendRecords(ctx context.Context, upload *models.Upload, previousHash *string, records []models.SiteRecord) error
+}
+
+// SiteRecordStore is the subset of site record persistence the processor depends on.
+type SiteRecordStore interface {
+	BulkInsert(ctx context.Context, records []models.SiteRecord) error
+}
+
+// Job is a saved upload file waiting to be parsed and stored.
+type Job struct {
+	Upload *models.Upload
+	Path   string // temp file; removed once processing finishes
+	IsZip  bool
+	Schema *schema.ResolvedSchema
+}
+
+// Result summarises a processed upload.
+type Result struct {
+	Warnings []string
+	Files    []FileRowCount // per-member row counts; nil unless IsZip
+}
+
+// ErrNoDataRows is reported for an upload with no valid data rows, such as
+// a header-only CSV or one whose every row was skipped.
+var ErrNoDataRows = errors.New("CSV contains no valid data rows")
+
+// ValidationError reports an upload rejected by CSV or schema validation,
+// as opposed to a storage failure.
+type ValidationError struct {
+	Err error
+}
+
+func (e *ValidationError) Error() string { return e.Err.Error() }
+
+func (e *ValidationError) Unwrap() error { return e.Err }
+
+// Processor parses, validates and stores uploaded files, either inline or
+// in tracked background goroutines.
+type Processor struct {
+	uploadRepo     UploadStore
+	siteRecordRepo SiteRecordStore
+	cfg            config.UploadConfig
+
+	// In-flight tracking for jobs launched via Dispatch
+	baseCtx    context.Context
+	cancelBase context.CancelFunc
+	wg         sync.WaitGroup
+	mu         sync.Mutex
+	inFlight   map[uuid.UUID]models.Upload // snapshot taken at dispatch
+}
+
+// NewProcessor creates a new upload processor.
+func NewProcessor(uploadRepo UploadStore, siteRecordRepo SiteRecordStore, cfg config.UploadConfig) *Processor {
+	baseCtx, cancelBase := context.WithCancel(context.Background())
+	return &Processor{
+		uploadRepo:     uploadRepo,
+		siteRecordRepo: siteRecordRepo,
+		cfg:            cfg,
+		baseCtx:        baseCtx,
+		cancelBase:     cancelBase,
+		inFlight:       make(map[uuid.UUID]models.Upload),
+	}
+}
+
+// Process parses the job's file, inserts its site records and marks the
+// upload completed. On failure the upload is marked failed (and invalid for
+// validation errors) with the error recorded. The temp file is removed
+// either way.
+func (p *Processor) Process(ctx context.Context, job Job) (*Result, error) {
+	defer os.Remove(job.Path)
+	upload := job.Upload
+
+	result, err := p.process(ctx, job)
+	if err != nil {
+		upload.Status = "failed"
+		var vErr *ValidationError
+		if errors.As(err, &vErr) {
+			upload.ValidationStatus = "invalid"
+		}
+		if result != nil {
+			upload.Warnings, _ = json.Marshal(result.Warnings)
+		}
+		upload.Errors, _ = json.Marshal([]string{err.Error()})
+		upload.UpdatedAt = time.Now()
+		_ = p.uploadRepo.Update(ctx, upload)
+		return result, err
+	}
+
+	upload.Warnings, _ = json.Marshal(result.Warnings)
+	upload.ValidationStatus = "valid"
+	upload.Status = "completed"
+	upload.SchemaVersion = "v1.0"
+	upload.UpdatedAt = time.Now()
+	if err := p.uploadRepo.Update(ctx, upload); err != nil {
+		return result, fmt.Errorf("failed to update upload: %v", err)
+	}
+	return result, nil
+}
+
+func (p *Processor) process(ctx context.Context, job Job) (*Result, error) {
+	records, result, err := p.parse(job)
+	if err != nil {
+		return result, err
+	}
+
+	siteRecords, coercionWarnings := BuildSiteRecords(records, job.Schema, job.Upload.ID, job.Upload.TenantID, job.Upload.CreatedAt)
+	result.Warnings = append(result.Warnings, coercionWarnings...)
+	if err := p.siteRecordRepo.BulkInsert(ctx, siteRecords); err != nil {
+		return result, fmt.Errorf("failed to insert site records: %v", err)
+	}
+	job.Upload.RowCount = len(records)
+
+	return result, nil
+}
+
+// parse reads and validates the job's file, rejecting one with no valid
+// data rows, and flags outliers when enabled.
+func (p *Processor) parse(job Job) ([]json.RawMessage, *Result, error) {
+	file, err := os.Open(job.Path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to reopen file for parsing: %v", err)
+	}
+	defer file.Close()
+
+	// Parse and validate CSV. A zip archive is unpacked in memory and its
+	// CSV members merged into this one upload.
+	var records []json.RawMessage
+	result := &Result{}
+	if job.IsZip {
+		records, result.Warnings, result.Files, err = ParseZip(file, job.Upload.FileSize, job.Schema, p.cfg.MaxUnzippedSize, OptionsFromConfig(p.cfg))
+	} else {
+		records, result.Warnings, err = Parse(file, job.Schema, OptionsFromConfig(p.cfg))
+	}
+	if err != nil {
+		return nil, result, &ValidationError{Err: fmt.Errorf("CSV validation failed: %v", err)}
+	}
+	if len(records) == 0 {
+		// Nothing to score; skipped-row warnings explain why
+		return nil, result, &ValidationError{Err: fmt.Errorf("CSV validation failed: %w", ErrNoDataRows)}
+	}
+
+	// Flag statistically extreme values; these are warnings only
+	if p.cfg.OutlierDetection {
+		result.Warnings = append(result.Warnings, DetectOutliers(records, job.Schema, p.cfg.OutlierThreshold)...)
+	}
+	return records, result, nil
+}
+
+// Dispatch runs Process for the job in a tracked background goroutine.
+// Jobs dispatched this way are awaited by Shutdown. The caller must not
+// touch job.Upload afterwards.
+func (p *Processor) Dispatch(job Job) {
+	p.mu.Lock()
+	p.inFlight[job.Upload.ID] = *job.Upload
+	p.mu.Unlock()
+
+	uploadID := job.Upload.ID
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer func() {
+			p.mu.Lock()
+			delete(p.inFlight, uploadID)
+			p.mu.Unlock()
+		}()
+
+		logger := slog.With(slog.String("upload_id", job.Upload.ID.String()),
+			slog.String("tenant_id", job.Upload.TenantID.String()))
+		if _, err := p.Process(p.baseCtx, job); err != nil {
+			logger.Warn("async upload processing failed", slog.String("error", err.Error()))
+			return
+		}
+		logger.Info("async upload processing completed", slog.Int("row_count", job.Upload.RowCount))
+	}()
+}
+
+// InFlight returns the number of dispatched uploads that have not yet finished.
+func (p *Processor) InFlight() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.inFlight)
+}
+
+// Shutdown waits for dispatched uploads to finish. If ctx expires first,
+// the remaining jobs are cancelled and their uploads marked failed.
+func (p *Processor) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	p.mu.Lock()
+	interrupted := make([]models.Upload, 0, len(p.inFlight))
+	for _, upload := range p.inFlight {
+		interrupted = append(interrupted, upload)
+	}
+	p.mu.Unlock()
+
+	p.cancelBase()
+
+	// The shutdown context has expired, so use a short fresh one to record
+	// the interruption.
+	markCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for i := range interrupted {
+		upload := &interrupted[i]
+		upload.Status = "failed"
+		upload.Errors, _ = json.Marshal([]string{interruptedReason})
+		upload.UpdatedAt = time.Now()
+		if err := p.uploadRepo.Update(markCtx, upload); err != nil {
+			slog.Error("failed to mark interrupted upload",
+				slog.String("upload_id", upload.ID.String()), slog.String("error", err.Error()))
+		}
+	}
+
+	return ctx.Err()
+}
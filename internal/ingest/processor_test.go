@@ -0,0 +1,207 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/config"
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+)
+
+type fakeUploadStore struct {
+	mu      sync.Mutex
+	updates []models.Upload
+
+	appended     []models.SiteRecord // records stored by AppendRecords
+	previousHash *string             // hash the last AppendRecords expected
+	appendErr    error
+}
+
+func (f *fakeUploadStore) Update(_ context.Context, upload *models.Upload) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.updates = append(f.updates, *upload)
+	return nil
+}
+
+func (f *fakeUploadStore) AppendRecords(_ context.Context, upload *models.Upload, previousHash *string, records []models.SiteRecord) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.previousHash = previousHash
+	if f.appendErr != nil {
+		return f.appendErr
+	}
+	f.appended = append(f.appended, records...)
+	f.updates = append(f.updates, *upload)
+	return nil
+}
+
+func (f *fakeUploadStore) last() models.Upload {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.updates[len(f.updates)-1]
+}
+
+type fakeSiteRecordStore struct {
+	mu       sync.Mutex
+	inserted []models.SiteRecord
+	err      error
+	block    chan struct{}
+}
+
+func (f *fakeSiteRecordStore) BulkInsert(ctx context.Context, records []models.SiteRecord) error {
+	if f.block != nil {
+		select {
+		case <-f.block:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	f.inserted = append(f.inserted, records...)
+	return nil
+}
+
+func pendingUpload() *models.Upload {
+	now := time.Now()
+	return &models.Upload{
+		ID:               uuid.New(),
+		TenantID:         uuid.New(),
+		Filename:         "sites.csv",
+		Status:           "pending",
+		ValidationStatus: "pending",
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+}
+
+func writeTempCSV(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "upload.csv")
+	require.NoError(t, os.WriteFile(path, []byte(body), 0o600))
+	return path
+}
+
+func TestProcessor_DispatchPendingToCompleted(t *testing.T) {
+	uploads := &fakeUploadStore{}
+	sites := &fakeSiteRecordStore{block: make(chan struct{})}
+	p := NewProcessor(uploads, sites, config.UploadConfig{})
+
+	upload := pendingUpload()
+	path := writeTempCSV(t, "site_id,unemployment_rate,population\nS1,4.1,52000\nS2,5.3,48000\n")
+	p.Dispatch(Job{Upload: upload, Path: path, Schema: outlierSchema(t)})
+
+	// Still pending while the worker is blocked on insert
+	assert.Equal(t, 1, p.InFlight())
+	assert.Empty(t, uploads.updates)
+
+	close(sites.block)
+	require.NoError(t, p.Shutdown(context.Background()))
+
+	assert.Equal(t, 0, p.InFlight())
+	final := uploads.last()
+	assert.Equal(t, "completed", final.Status)
+	assert.Equal(t, "valid", final.ValidationStatus)
+	assert.Equal(t, 2, final.RowCount)
+	assert.Len(t, sites.inserted, 2)
+	assert.NoFileExists(t, path, "temp file is removed after processing")
+}
+
+func TestProcessor_ValidationFailureMarksUploadFailed(t *testing.T) {
+	uploads := &fakeUploadStore{}
+	p := NewProcessor(uploads, &fakeSiteRecordStore{}, config.UploadConfig{})
+
+	upload := pendingUpload()
+	path := writeTempCSV(t, "")
+	_, err := p.Process(context.Background(), Job{Upload: upload, Path: path, Schema: outlierSchema(t)})
+
+	var vErr *ValidationError
+	require.ErrorAs(t, err, &vErr)
+	final := uploads.last()
+	assert.Equal(t, "failed", final.Status)
+	assert.Equal(t, "invalid", final.ValidationStatus)
+	assert.Contains(t, string(final.Errors), "CSV file is empty")
+}
+
+func TestProcessor_HeaderOnlyFileIsInvalid(t *testing.T) {
+	uploads := &fakeUploadStore{}
+	sites := &fakeSiteRecordStore{}
+	p := NewProcessor(uploads, sites, config.UploadConfig{})
+
+	path := writeTempCSV(t, "site_id,unemployment_rate,population\n")
+	_, err := p.Process(context.Background(), Job{Upload: pendingUpload(), Path: path, Schema: outlierSchema(t)})
+
+	var vErr *ValidationError
+	require.ErrorAs(t, err, &vErr)
+	assert.ErrorIs(t, err, ErrNoDataRows)
+	final := uploads.last()
+	assert.Equal(t, "failed", final.Status)
+	assert.Equal(t, "invalid", final.ValidationStatus)
+	assert.Contains(t, string(final.Errors), "no valid data rows")
+	assert.Empty(t, sites.inserted)
+}
+
+func TestProcessor_AllRowsSkippedIsInvalid(t *testing.T) {
+	uploads := &fakeUploadStore{}
+	p := NewProcessor(uploads, &fakeSiteRecordStore{}, config.UploadConfig{})
+
+	// The only row is missing a required value
+	path := writeTempCSV(t, "site_id,unemployment_rate,population\nS1,,52000\n")
+	result, err := p.Process(context.Background(), Job{Upload: pendingUpload(), Path: path, Schema: outlierSchema(t)})
+
+	assert.ErrorIs(t, err, ErrNoDataRows)
+	require.NotNil(t, result)
+	assert.NotEmpty(t, result.Warnings, "skipped-row warnings are kept")
+	assert.Equal(t, "invalid", uploads.last().ValidationStatus)
+}
+
+func TestProcessor_InsertFailureIsNotValidationError(t *testing.T) {
+	uploads := &fakeUploadStore{}
+	p := NewProcessor(uploads, &fakeSiteRecordStore{err: errors.New("db down")}, config.UploadConfig{})
+
+	path := writeTempCSV(t, "site_id,unemployment_rate,population\nS1,4.1,52000\n")
+	_, err := p.Process(context.Background(), Job{Upload: pendingUpload(), Path: path, Schema: outlierSchema(t)})
+
+	require.Error(t, err)
+	var vErr *ValidationError
+	assert.False(t, errors.As(err, &vErr))
+	assert.Equal(t, "failed", uploads.last().Status)
+	assert.Equal(t, "pending", uploads.last().ValidationStatus)
+}
+
+func TestProcessor_ShutdownMarksInterruptedUploadsFailed(t *testing.T) {
+	uploads := &fakeUploadStore{}
+	sites := &fakeSiteRecordStore{block: make(chan struct{})}
+	p := NewProcessor(uploads, sites, config.UploadConfig{})
+
+	upload := pendingUpload()
+	path := writeTempCSV(t, "site_id,unemployment_rate,population\nS1,4.1,52000\n")
+	p.Dispatch(Job{Upload: upload, Path: path, Schema: outlierSchema(t)})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, p.Shutdown(ctx), context.DeadlineExceeded)
+
+	var marked bool
+	uploads.mu.Lock()
+	for _, u := range uploads.updates {
+		if u.ID == upload.ID && u.Status == "failed" && string(u.Errors) == `["`+interruptedReason+`"]` {
+			marked = true
+		}
+	}
+	uploads.mu.Unlock()
+	assert.True(t, marked)
+}
@@ -0,0 +1,114 @@
+package ingest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
+)
+
+// BuildSiteRecords turns parsed CSV records into site records for an
+// upload, extracting site_id, site_name and location and coercing values
+// to the types declared in the schema. Numeric values are read using the
+// schema's NumberFormat; any that still cannot be parsed are kept as
+// strings and reported in the returned warnings. Blank cells and null
+// sentinels in schema fields are stored as empty strings.
+func BuildSiteRecords(records []json.RawMessage, resolvedSchema *schema.ResolvedSchema, uploadID, tenantID uuid.UUID, now time.Time) ([]models.SiteRecord, []string) {
+	siteRecords := make([]models.SiteRecord, len(records))
+	warnings := make([]string, 0)
+	for i, recordData := range records {
+		var dataMap map[string]interface{}
+		siteID := fmt.Sprintf("row_%d", i+1)
+		var siteName, location string
+		if err := json.Unmarshal(recordData, &dataMap); err == nil {
+			// Extract site_id from the schema-configured column
+			if sid, ok := dataMap[resolvedSchema.SiteIDColumn]; ok {
+				siteID = fmt.Sprintf("%v", sid)
+			}
+
+			// Extract site_name: try city first, fall back to site_id
+			if sn, ok := dataMap["city"]; ok {
+				siteName = fmt.Sprintf("%v", sn)
+			} else if sn, ok := dataMap["site_name"]; ok {
+				siteName = fmt.Sprintf("%v", sn)
+			} else {
+				siteName = siteID
+			}
+
+			// Extract location: try "city, state", fall back to site_id
+			if st, ok := dataMap["state"]; ok {
+				if city, ok := dataMap["city"]; ok {
+					location = fmt.Sprintf("%v, %v", city, st)
+				} else {
+					location = fmt.Sprintf("%v", st)
+				}
+			} else if loc, ok := dataMap["location"]; ok {
+				location = fmt.Sprintf("%v", loc)
+			} else {
+				location = siteID
+			}
+
+			// Build type-coerced data: convert string values to proper
+			// types based on schema field definitions
+			coerced := make(map[string]interface{})
+			for k, v := range dataMap {
+				strVal, isStr := v.(string)
+				if !isStr {
+					coerced[k] = v
+					continue
+				}
+				fieldDef, exists := resolvedSchema.Fields[k]
+				// Null sentinels ("NA", "-") in schema fields are stored as
+				// empty, like blank cells, so they are treated as missing
+				if exists && resolvedSchema.IsNull(strVal) {
+					coerced[k] = ""
+					continue
+				}
+				// Check if this field has a numeric type in the schema.
+				// NaN/Inf parse without error but are not valid JSON, so
+				// they stay strings and are rejected at scoring time.
+				if exists && fieldDef.Type.IsNumeric() && strVal != "" {
+					f, err := resolvedSchema.NumberFormat.ParseFloat(strVal)
+					if err == nil && !math.IsNaN(f) && !math.IsInf(f, 0) {
+						if fieldDef.Type == schema.TypeInteger {
+							coerced[k] = int64(f)
+						} else {
+							coerced[k] = f
+						}
+						continue
+					}
+					warnings = append(warnings, fmt.Sprintf("site %s: %s value %q could not be read as a number and will not be scored", siteID, k, strVal))
+				}
+				coerced[k] = v // keep original string for text/identifier/unknown
+			}
+			coercedJSON, _ := json.Marshal(coerced)
+
+			siteRecords[i] = models.SiteRecord{
+				ID:        uuid.New(),
+				UploadID:  uploadID,
+				TenantID:  tenantID,
+				SiteID:    siteID,
+				SiteName:  siteName,
+				Location:  location,
+				RawData:   recordData,
+				Data:      coercedJSON,
+				CreatedAt: now,
+			}
+		} else {
+			siteRecords[i] = models.SiteRecord{
+				ID:        uuid.New(),
+				UploadID:  uploadID,
+				TenantID:  tenantID,
+				SiteID:    siteID,
+				RawData:   recordData,
+				Data:      recordData,
+				CreatedAt: now,
+			}
+		}
+	}
+	return siteRecords, warnings
+}
@@ -0,0 +1,86 @@
+package ingest
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
+)
+
+func coercionSchema() *schema.ResolvedSchema {
+	return &schema.ResolvedSchema{
+		SiteIDColumn: "site_id",
+		Fields: map[string]schema.FieldDef{
+			"site_id":    {Type: schema.TypeIdentifier, Required: true},
+			"population": {Type: schema.TypePopulation},
+			"income":     {Type: schema.TypeNumeric},
+			"rate":       {Type: schema.TypePercentage},
+			"stores":     {Type: schema.TypeInteger},
+			"notes":      {Type: schema.TypeText},
+		},
+	}
+}
+
+func TestBuildSiteRecords_CoercesFormattedNumbers(t *testing.T) {
+	records := []json.RawMessage{json.RawMessage(
+		`{"site_id": "S1", "population": "1,234", "income": "$5,000", "rate": "5%", "stores": "1,200", "notes": "$5 lunch"}`)}
+
+	siteRecords, warnings := BuildSiteRecords(records, coercionSchema(), uuid.New(), uuid.New(), time.Now())
+
+	assert.Empty(t, warnings)
+	require.Len(t, siteRecords, 1)
+	assert.JSONEq(t, `{
+		"site_id": "S1",
+		"population": 1234,
+		"income": 5000,
+		"rate": 5,
+		"stores": 1200,
+		"notes": "$5 lunch"
+	}`, string(siteRecords[0].Data), "text fields are left untouched")
+	assert.Contains(t, string(siteRecords[0].RawData), `"1,234"`, "raw data keeps the original value")
+}
+
+func TestBuildSiteRecords_WarnsOnUncoercibleNumbers(t *testing.T) {
+	records := []json.RawMessage{json.RawMessage(`{"site_id": "S1", "population": "about 5k", "income": ""}`)}
+
+	siteRecords, warnings := BuildSiteRecords(records, coercionSchema(), uuid.New(), uuid.New(), time.Now())
+
+	require.Len(t, warnings, 1, "empty values are missing, not malformed")
+	assert.Equal(t, `site S1: population value "about 5k" could not be read as a number and will not be scored`, warnings[0])
+	assert.JSONEq(t, `{"site_id": "S1", "population": "about 5k", "income": ""}`, string(siteRecords[0].Data))
+}
+
+func TestBuildSiteRecords_ConfiguredNumberFormat(t *testing.T) {
+	resolved := coercionSchema()
+	resolved.NumberFormat = schema.NumberFormat{DecimalSeparator: ",", StripSymbols: []string{"€"}}
+	records := []json.RawMessage{json.RawMessage(`{"site_id": "S1", "income": "€1.234,50", "rate": "5%"}`)}
+
+	siteRecords, warnings := BuildSiteRecords(records, resolved, uuid.New(), uuid.New(), time.Now())
+
+	require.Len(t, warnings, 1, "% is not stripped once strip_symbols is configured")
+	assert.JSONEq(t, `{"site_id": "S1", "income": 1234.5, "rate": "5%"}`, string(siteRecords[0].Data))
+}
+
+func TestBuildSiteRecords_NullSentinelsAreMissing(t *testing.T) {
+	records := []json.RawMessage{json.RawMessage(
+		`{"site_id": "S1", "population": "NA", "income": " n/a ", "rate": "-", "stores": "NULL", "notes": "null", "extra": "NA"}`)}
+
+	siteRecords, warnings := BuildSiteRecords(records, coercionSchema(), uuid.New(), uuid.New(), time.Now())
+
+	assert.Empty(t, warnings, "null sentinels are missing, not malformed")
+	assert.JSONEq(t, `{
+		"site_id": "S1",
+		"population": "",
+		"income": "",
+		"rate": "",
+		"stores": "",
+		"notes": "",
+		"extra": "NA"
+	}`, string(siteRecords[0].Data), "columns outside the schema are left untouched")
+	assert.Contains(t, string(siteRecords[0].RawData), `"NA"`, "raw data keeps the original value")
+}
@@ -0,0 +1,118 @@
+package ingest
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// MalwareScanner checks a saved upload before it is parsed. A scan blocks
+// until it completes; clean is false if the file must be rejected, with
+// details describing what was found. err reports a scan that could not be
+// carried out, not an infected file.
+type MalwareScanner interface {
+	Scan(path string) (clean bool, details []string, err error)
+}
+
+// NoopScanner accepts every file. It is used when no scanner is configured.
+type NoopScanner struct{}
+
+// Scan implements MalwareScanner.
+func (NoopScanner) Scan(string) (bool, []string, error) {
+	return true, nil, nil
+}
+
+// clamdChunkSize is the largest chunk streamed to clamd in one INSTREAM frame.
+const clamdChunkSize = 64 * 1024
+
+// ClamAVScanner scans files by streaming them to a clamd daemon over TCP
+// using its INSTREAM command. The file never needs to be visible to clamd's
+// filesystem.
+type ClamAVScanner struct {
+	Address string        // clamd host:port
+	Timeout time.Duration // dial and scan deadline; zero means no deadline
+}
+
+// NewClamAVScanner creates a scanner talking to the clamd daemon at address.
+func NewClamAVScanner(address string, timeout time.Duration) *ClamAVScanner {
+	return &ClamAVScanner{Address: address, Timeout: timeout}
+}
+
+// Scan implements MalwareScanner.
+func (s *ClamAVScanner) Scan(path string) (bool, []string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to open file for scanning: %v", err)
+	}
+	defer file.Close()
+
+	conn, err := net.DialTimeout("tcp", s.Address, s.Timeout)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to connect to clamd: %v", err)
+	}
+	defer conn.Close()
+	if s.Timeout > 0 {
+		_ = conn.SetDeadline(time.Now().Add(s.Timeout))
+	}
+
+	if err := clamdInstream(conn, file); err != nil {
+		return false, nil, fmt.Errorf("failed to stream file to clamd: %v", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && err != io.EOF {
+		return false, nil, fmt.Errorf("failed to read clamd reply: %v", err)
+	}
+	return parseClamdReply(reply)
+}
+
+// clamdInstream sends the INSTREAM command followed by the file as
+// length-prefixed chunks and a zero-length terminator.
+func clamdInstream(w io.Writer, r io.Reader) error {
+	if _, err := w.Write([]byte("zINSTREAM\x00")); err != nil {
+		return err
+	}
+	buf := make([]byte, clamdChunkSize)
+	var size [4]byte
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size[:], uint32(n))
+			if _, werr := w.Write(size[:]); werr != nil {
+				return werr
+			}
+			if _, werr := w.Write(buf[:n]); werr != nil {
+				return werr
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	binary.BigEndian.PutUint32(size[:], 0)
+	_, err := w.Write(size[:])
+	return err
+}
+
+// parseClamdReply interprets a clamd scan reply: "stream: OK" is clean,
+// "stream: <signature> FOUND" is infected and anything else is an error.
+func parseClamdReply(reply string) (bool, []string, error) {
+	reply = strings.TrimSpace(strings.TrimRight(reply, "\x00"))
+	result := strings.TrimSpace(strings.TrimPrefix(reply, "stream:"))
+	switch {
+	case result == "OK":
+		return true, nil, nil
+	case strings.HasSuffix(result, " FOUND"):
+		return false, []string{strings.TrimSuffix(result, " FOUND")}, nil
+	default:
+		return false, nil, fmt.Errorf("unexpected clamd reply %q", reply)
+	}
+}
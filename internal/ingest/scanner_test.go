@@ -0,0 +1,91 @@
+package ingest
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClamd accepts one INSTREAM session, decodes the streamed file and
+// replies with reply(file).
+func fakeClamd(t *testing.T, reply func(file []byte) string) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		cmd := make([]byte, len("zINSTREAM\x00"))
+		if _, err := io.ReadFull(conn, cmd); err != nil {
+			return
+		}
+		var file bytes.Buffer
+		for {
+			var size uint32
+			if err := binary.Read(conn, binary.BigEndian, &size); err != nil {
+				return
+			}
+			if size == 0 {
+				break
+			}
+			if _, err := io.CopyN(&file, conn, int64(size)); err != nil {
+				return
+			}
+		}
+		_, _ = conn.Write([]byte(reply(file.Bytes()) + "\x00"))
+	}()
+	return ln.Addr().String()
+}
+
+func TestClamAVScanner_StreamsFileAndReadsVerdict(t *testing.T) {
+	received := make(chan []byte, 1)
+	addr := fakeClamd(t, func(file []byte) string {
+		received <- file
+		if bytes.Contains(file, []byte("EICAR")) {
+			return "stream: Eicar-Test-Signature FOUND"
+		}
+		return "stream: OK"
+	})
+
+	body := "site_id,population\n" + string(bytes.Repeat([]byte("S1,100\n"), 20000)) + "EICAR\n"
+	clean, details, err := NewClamAVScanner(addr, 5*time.Second).Scan(writeTempCSV(t, body))
+
+	require.NoError(t, err)
+	assert.False(t, clean)
+	assert.Equal(t, []string{"Eicar-Test-Signature"}, details)
+	assert.Equal(t, body, string(<-received), "file is streamed in full across chunks")
+}
+
+func TestClamAVScanner_UnreachableDaemonIsError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	ln.Close()
+
+	clean, _, err := NewClamAVScanner(addr, time.Second).Scan(writeTempCSV(t, "site_id\nS1\n"))
+
+	require.Error(t, err)
+	assert.False(t, clean)
+}
+
+func TestParseClamdReply(t *testing.T) {
+	clean, details, err := parseClamdReply("stream: OK\x00")
+	require.NoError(t, err)
+	assert.True(t, clean)
+	assert.Empty(t, details)
+
+	_, _, err = parseClamdReply("INSTREAM size limit exceeded. ERROR")
+	assert.Error(t, err)
+}
@@ -0,0 +1,158 @@
+package ingest
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
+)
+
+// FileRowCount reports how many records one member of a zip upload
+// contributed.
+type FileRowCount struct {
+	Filename string `json:"filename"`
+	RowCount int    `json:"row_count"`
+}
+
+// ParseZip reads a zip archive of CSV files, validates each member against
+// the schema and merges their records. Every member must share the first
+// member's header set (column order may differ). maxUncompressed caps the
+// total decompressed size across all members, guarding against zip bombs,
+// and opts applies to each member as in Parse. Member warnings are
+// prefixed with the member name.
+func ParseZip(r io.ReaderAt, size int64, schemaConfig *schema.ResolvedSchema, maxUncompressed int64, opts ParseOptions) (
+	records []json.RawMessage,
+	warnings []string,
+	files []FileRowCount,
+	err error,
+) {
+	records = make([]json.RawMessage, 0)
+	warnings = make([]string, 0)
+	files = make([]FileRowCount, 0)
+
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return records, warnings, files, fmt.Errorf("invalid zip archive: %v", err)
+	}
+
+	members := make([]*zip.File, 0, len(archive.File))
+	for _, f := range archive.File {
+		if !safeMemberName(f.Name) {
+			return records, warnings, files, fmt.Errorf("zip member %q has an unsafe path", f.Name)
+		}
+		if f.FileInfo().IsDir() || ignoredMember(f.Name) {
+			continue
+		}
+		if !strings.EqualFold(path.Ext(f.Name), ".csv") {
+			return records, warnings, files, fmt.Errorf("zip member %q is not a CSV file", f.Name)
+		}
+		members = append(members, f)
+	}
+	if len(members) == 0 {
+		return records, warnings, files, fmt.Errorf("zip archive contains no CSV files")
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].Name < members[j].Name })
+
+	var firstHeaders []string
+	var firstName string
+	remaining := maxUncompressed
+
+	for _, member := range members {
+		data, err := readMember(member, remaining)
+		if err != nil {
+			return records, warnings, files, err
+		}
+		remaining -= int64(len(data))
+
+		headers, err := csv.NewReader(bytes.NewReader(data)).Read()
+		if err != nil {
+			return records, warnings, files, fmt.Errorf("%s: failed to read CSV headers: %v", member.Name, err)
+		}
+		// Members are compared as the schema will see them; Parse reports
+		// any normalization collision
+		if normalized, _, err := opts.headers(headers); err == nil {
+			headers = normalized
+		}
+		if firstHeaders == nil {
+			firstHeaders, firstName = headers, member.Name
+		} else if !sameHeaders(firstHeaders, headers) {
+			return records, warnings, files, fmt.Errorf("%s: headers do not match %s", member.Name, firstName)
+		}
+
+		memberRecords, memberWarnings, err := Parse(bytes.NewReader(data), schemaConfig, opts)
+		for _, w := range memberWarnings {
+			warnings = append(warnings, fmt.Sprintf("%s: %s", member.Name, w))
+		}
+		if err != nil {
+			return records, warnings, files, fmt.Errorf("%s: %v", member.Name, err)
+		}
+
+		records = append(records, memberRecords...)
+		files = append(files, FileRowCount{Filename: member.Name, RowCount: len(memberRecords)})
+	}
+
+	return records, warnings, files, nil
+}
+
+// readMember decompresses a zip member, failing once more than limit bytes
+// have been read. The declared size in the zip header is not trusted.
+func readMember(member *zip.File, limit int64) ([]byte, error) {
+	rc, err := member.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to open zip member: %v", member.Name, err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to decompress zip member: %v", member.Name, err)
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("zip archive exceeds max uncompressed size")
+	}
+	return data, nil
+}
+
+// safeMemberName rejects absolute paths and any ".." component (zip-slip).
+func safeMemberName(name string) bool {
+	name = strings.ReplaceAll(name, `\`, "/")
+	if name == "" || strings.HasPrefix(name, "/") || (len(name) > 1 && name[1] == ':') {
+		return false
+	}
+	for _, part := range strings.Split(name, "/") {
+		if part == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// ignoredMember reports archive metadata added by desktop zip tools.
+func ignoredMember(name string) bool {
+	return strings.HasPrefix(name, "__MACOSX/") || strings.HasPrefix(path.Base(name), ".")
+}
+
+// sameHeaders reports whether two header rows contain the same columns,
+// ignoring order.
+func sameHeaders(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	x := append([]string(nil), a...)
+	y := append([]string(nil), b...)
+	sort.Strings(x)
+	sort.Strings(y)
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,99 @@
+package ingest
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type zipMember struct {
+	name string
+	body string
+}
+
+func buildZip(t *testing.T, members ...zipMember) *bytes.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, m := range members {
+		f, err := w.Create(m.name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(m.body))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestParseZip_MergesMembers(t *testing.T) {
+	resolved := outlierSchema(t)
+	archive := buildZip(t,
+		zipMember{"west.csv", "site_id,unemployment_rate,population\nW1,4.1,52000\nW2,5.3,48000\nW3,3.9,61000\n"},
+		zipMember{"east/east.csv", "population,site_id,unemployment_rate\n55000,E1,6.0\n50000,E2,4.8\n"},
+		zipMember{"__MACOSX/._west.csv", "junk"},
+	)
+
+	records, warnings, files, err := ParseZip(archive, archive.Size(), resolved, 1<<20, ParseOptions{})
+
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+	assert.Len(t, records, 5)
+	assert.Equal(t, []FileRowCount{
+		{Filename: "east/east.csv", RowCount: 2},
+		{Filename: "west.csv", RowCount: 3},
+	}, files)
+}
+
+func TestParseZip_RejectsIncompatibleHeaders(t *testing.T) {
+	resolved := outlierSchema(t)
+	archive := buildZip(t,
+		zipMember{"a.csv", "site_id,unemployment_rate,population\nA1,4.1,52000\n"},
+		zipMember{"b.csv", "site_id,unemployment_rate,population,notes\nB1,5.3,48000,x\n"},
+	)
+
+	_, _, _, err := ParseZip(archive, archive.Size(), resolved, 1<<20, ParseOptions{})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "b.csv: headers do not match a.csv")
+}
+
+func TestParseZip_RejectsUnsafePaths(t *testing.T) {
+	resolved := outlierSchema(t)
+	for _, name := range []string{"../escape.csv", "data/../../escape.csv", "/etc/sites.csv", `..\escape.csv`} {
+		archive := buildZip(t, zipMember{name, "site_id,unemployment_rate,population\nA1,4.1,52000\n"})
+
+		_, _, _, err := ParseZip(archive, archive.Size(), resolved, 1<<20, ParseOptions{})
+
+		require.Error(t, err, name)
+		assert.Contains(t, err.Error(), "unsafe path")
+	}
+}
+
+func TestParseZip_RejectsDecompressionBomb(t *testing.T) {
+	resolved := outlierSchema(t)
+	body := "site_id,unemployment_rate,population\n" + strings.Repeat("A1,4.1,52000\n", 10000)
+	archive := buildZip(t, zipMember{"big.csv", body})
+	require.Less(t, archive.Size(), int64(len(body))/10, "payload should compress well")
+
+	_, _, _, err := ParseZip(archive, archive.Size(), resolved, int64(len(body))-1, ParseOptions{})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeds max uncompressed size")
+}
+
+func TestParseZip_RejectsNonCSVMembers(t *testing.T) {
+	resolved := outlierSchema(t)
+	archive := buildZip(t,
+		zipMember{"a.csv", "site_id,unemployment_rate,population\nA1,4.1,52000\n"},
+		zipMember{"notes.txt", "hello"},
+	)
+
+	_, _, _, err := ParseZip(archive, archive.Size(), resolved, 1<<20, ParseOptions{})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not a CSV file")
+}
@@ -82,6 +82,43 @@ type ScoringRun struct {
 	CompletedAt            *time.Time      `json:"completed_at,omitempty"`
 	CreatedAt              time.Time       `json:"created_at"`
 	UpdatedAt              time.Time       `json:"updated_at"`
+	Stats                  *RunStats       `json:"stats,omitempty"`
+	SkippedCount           *int            `json:"skipped_count,omitempty"`
+	SkippedSites           []SkippedSite   `json:"skipped_sites,omitempty"`
+	ResultKey              *string         `json:"-"` // identifies the run's results for reuse; see Pipeline.ResultKey
+	CorrelationID          string          `json:"-"` // originating request, carried into the pipeline's logs
+}
+
+// SkippedSite records a site a run could not parse or score, and why.
+type SkippedSite struct {
+	SiteID string `json:"site_id"`
+	Reason string `json:"reason"`
+}
+
+// MaxSkippedSites caps how many skipped sites a run keeps in SkippedSites;
+// SkippedCount counts them all.
+const MaxSkippedSites = 20
+
+// RunSkippedSite is a site a scoring run left out, with the reason, kept so
+// analysts can find and fix the offending rows.
+// DB columns: run_id, tenant_id, position, site_id, site_name, reason, created_at
+type RunSkippedSite struct {
+	RunID     uuid.UUID `json:"run_id"`
+	TenantID  uuid.UUID `json:"tenant_id"`
+	Position  int       `json:"position"` // 1-based, among the upload's site records
+	SiteID    string    `json:"site_id"`
+	SiteName  string    `json:"site_name"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RunStats summarizes the final_score distribution of a completed run.
+type RunStats struct {
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	StdDev float64 `json:"stddev"`
 }
 
 // Recommendation holds a scored site result with explanation.
@@ -103,6 +140,19 @@ type Recommendation struct {
 	CreatedAt       time.Time       `json:"created_at"`
 }
 
+// RecommendationResponse is the API contract for one ranked site in the
+// recommendation list, top/bottom and explanations endpoints.
+type RecommendationResponse struct {
+	Rank        int         `json:"rank"`
+	SiteID      string      `json:"site_id"`
+	SiteName    string      `json:"site_name"`
+	FinalScore  float64     `json:"final_score"`
+	RawScore    float64     `json:"raw_score"`
+	ScoreLow    *float64    `json:"score_low,omitempty"`  // bounds of the score interval when
+	ScoreHigh   *float64    `json:"score_high,omitempty"` // the schema has uncertainty columns
+	Explanation Explanation `json:"explanation"`
+}
+
 // SchemaConfig holds schema configuration (global or tenant-specific).
 // DB columns: id, tenant_id, version, config, schema_definition, description,
 //
@@ -139,14 +189,129 @@ type ExplanationFactor struct {
 	Contribution float64 `json:"contribution"`
 	Direction    string  `json:"direction"`
 	Reason       string  `json:"reason"`
+
+	// Clamped is set when the value fell outside the field's bounds and its
+	// normalized value was capped to 0 or 1; UnclampedNormalized holds the
+	// normalized value before capping.
+	Clamped             bool     `json:"clamped,omitempty"`
+	UnclampedNormalized *float64 `json:"unclamped_normalized,omitempty"`
+}
+
+// CategoryScore is one field category's part of a two-level score.
+type CategoryScore struct {
+	Name         string  `json:"name"`
+	Weight       float64 `json:"weight"`
+	Score        float64 `json:"score"`        // category subscore on the run's score scale
+	Contribution float64 `json:"contribution"` // category weight * subscore (0-1)
+}
+
+// FreshnessPenalty records how a site's score was discounted for stale data.
+type FreshnessPenalty struct {
+	AsOf    string  `json:"as_of"`    // the site's data collection date
+	AgeDays float64 `json:"age_days"` // days from AsOf to the run's reference date
+	Factor  float64 `json:"factor"`   // multiplier applied to the score (0-1)
 }
 
 // Explanation contains the full structured explanation for a recommendation.
 type Explanation struct {
-	Factors []ExplanationFactor `json:"factors"`
-	Summary string              `json:"summary"`
+	Factors    []ExplanationFactor `json:"factors"`
+	Categories []CategoryScore     `json:"categories,omitempty"` // set when the schema weights categories
+	Summary    string              `json:"summary"`
+	Coverage   float64             `json:"coverage"`            // share of schema weight actually scored (0-1)
+	TieBreak   string              `json:"tie_break,omitempty"` // how the rank was decided among equal scores
+
+	// CategoryScores maps each scored category to its subscore on the run's
+	// score scale, and CategorySummary lists them ("Economic: 82, Talent:
+	// 45"). Both are set only when the schema weights categories.
+	CategoryScores  map[string]float64 `json:"category_scores,omitempty"`
+	CategorySummary string             `json:"category_summary,omitempty"`
+
+	// Freshness is set when the site's score was discounted because its
+	// data is old; factor contributions are before the discount.
+	Freshness *FreshnessPenalty `json:"freshness,omitempty"`
+}
+
+// HistogramBucket counts the recommendations whose final_score falls in
+// [Lower, Upper). The last bucket of a histogram also includes Upper.
+type HistogramBucket struct {
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+	Count int     `json:"count"`
+}
+
+// RollupGroup summarises the final scores of a run's recommendations that
+// share one value of a site data field. Value is nil for sites without it.
+type RollupGroup struct {
+	Value    *string `json:"value"`
+	Count    int     `json:"count"`
+	AvgScore float64 `json:"avg_score"`
+	MinScore float64 `json:"min_score"`
+	MaxScore float64 `json:"max_score"`
+}
+
+// Audit actions recorded for mutating API operations.
+const (
+	AuditActionUploadCreate = "upload.create"
+	AuditActionUploadAppend = "upload.append"
+	AuditActionRunCreate    = "run.create"
+	AuditActionRunRequeue   = "run.requeue"
+	AuditActionSchemaUpdate = "schema_config.update"
+	AuditActionPresetCreate = "weight_preset.create"
+	AuditActionPresetUpdate = "weight_preset.update"
+	AuditActionPresetDelete = "weight_preset.delete"
+
+	AuditActionServiceTokenCreate = "service_token.create"
+	AuditActionServiceTokenRevoke = "service_token.revoke"
+)
+
+// AuditEntry records one mutating action taken by a user.
+// DB columns: id, tenant_id, user_id, action, resource_id, correlation_id, created_at
+type AuditEntry struct {
+	ID            uuid.UUID  `json:"id"`
+	TenantID      uuid.UUID  `json:"tenant_id"`
+	UserID        uuid.UUID  `json:"user_id"`
+	Action        string     `json:"action"`
+	ResourceID    *uuid.UUID `json:"resource_id,omitempty"`
+	CorrelationID string     `json:"correlation_id"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// WeightPreset is a named set of field weights a tenant can apply to runs.
+// DB columns: id, tenant_id, name, description, weights, created_at, updated_at
+type WeightPreset struct {
+	ID          uuid.UUID          `json:"id"`
+	TenantID    uuid.UUID          `json:"tenant_id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Weights     map[string]float64 `json:"weights"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
 }
 
+// ServiceToken records a long-lived token an admin minted for a tenant's
+// integrations. ID is the token's jti claim and also its user_id, so audit
+// entries name the token that acted. The token itself is returned once,
+// when minted, and never stored.
+// DB columns: id, tenant_id, name, role, scopes, created_by, created_at, expires_at, revoked_at
+type ServiceToken struct {
+	ID        uuid.UUID  `json:"id"`
+	TenantID  uuid.UUID  `json:"tenant_id"`
+	Name      string     `json:"name"`
+	Role      string     `json:"role"`
+	Scopes    []string   `json:"scopes"`
+	CreatedBy uuid.UUID  `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Page size bounds shared by every page-number paginated endpoint and the
+// repositories behind them.
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
 // Pagination holds pagination metadata.
 type Pagination struct {
 	Page         int `json:"page"`
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+)
+
+// AuditRepository handles data access for the audit log of mutating actions.
+type AuditRepository struct {
+	pool         *pgxpool.Pool
+	queryTimeout time.Duration
+}
+
+// NewAuditRepository creates a new audit repository.
+func NewAuditRepository(pool *pgxpool.Pool, queryTimeout time.Duration) *AuditRepository {
+	return &AuditRepository{pool: pool, queryTimeout: queryTimeout}
+}
+
+// Record inserts an audit entry. ID and CreatedAt are filled in when unset.
+func (r *AuditRepository) Record(ctx context.Context, entry *models.AuditEntry) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO audit_log (id, tenant_id, user_id, action, resource_id, correlation_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		entry.ID,
+		entry.TenantID,
+		entry.UserID,
+		entry.Action,
+		entry.ResourceID,
+		entry.CorrelationID,
+		entry.CreatedAt,
+	)
+	return err
+}
+
+// ListByTenant retrieves one page of a tenant's audit history, newest
+// first, along with the tenant's total entry count.
+func (r *AuditRepository) ListByTenant(
+	ctx context.Context,
+	tenantID uuid.UUID,
+	page int,
+	pageSize int,
+) ([]models.AuditEntry, int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = models.DefaultPageSize
+	}
+
+	offset := (page - 1) * pageSize
+
+	var totalCount int
+	err := r.pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM audit_log WHERE tenant_id = $1`,
+		tenantID,
+	).Scan(&totalCount)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT id, tenant_id, user_id, action, resource_id, correlation_id, created_at
+		FROM audit_log
+		WHERE tenant_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.pool.Query(ctx, query, tenantID, pageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	entries := make([]models.AuditEntry, 0, pageSize)
+	for rows.Next() {
+		entry := models.AuditEntry{}
+		err := rows.Scan(
+			&entry.ID,
+			&entry.TenantID,
+			&entry.UserID,
+			&entry.Action,
+			&entry.ResourceID,
+			&entry.CorrelationID,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, totalCount, rows.Err()
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+)
+
+func TestAuditRepository_RecordAndList(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	repo := NewAuditRepository(pool, testQueryTimeout)
+
+	tenantID := createTestTenant(t, pool)
+	otherTenant := createTestTenant(t, pool)
+	userID := uuid.New()
+
+	base := time.Now().Add(-time.Hour)
+	actions := []string{models.AuditActionUploadCreate, models.AuditActionRunCreate, models.AuditActionSchemaUpdate}
+	for i, action := range actions {
+		resourceID := uuid.New()
+		require.NoError(t, repo.Record(ctx, &models.AuditEntry{
+			TenantID:      tenantID,
+			UserID:        userID,
+			Action:        action,
+			ResourceID:    &resourceID,
+			CorrelationID: "corr-" + action,
+			CreatedAt:     base.Add(time.Duration(i) * time.Minute),
+		}))
+	}
+	require.NoError(t, repo.Record(ctx, &models.AuditEntry{
+		TenantID: otherTenant,
+		UserID:   uuid.New(),
+		Action:   models.AuditActionUploadCreate,
+	}))
+
+	page1, total, err := repo.ListByTenant(ctx, tenantID, 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 3, total, "other tenant's entries are not counted")
+	require.Len(t, page1, 2)
+	assert.Equal(t, models.AuditActionSchemaUpdate, page1[0].Action, "newest first")
+	assert.Equal(t, models.AuditActionRunCreate, page1[1].Action)
+	assert.Equal(t, userID, page1[0].UserID)
+	assert.Equal(t, "corr-"+models.AuditActionSchemaUpdate, page1[0].CorrelationID)
+	assert.NotNil(t, page1[0].ResourceID)
+
+	page2, _, err := repo.ListByTenant(ctx, tenantID, 2, 2)
+	require.NoError(t, err)
+	require.Len(t, page2, 1)
+	assert.Equal(t, models.AuditActionUploadCreate, page2[0].Action)
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -19,12 +20,13 @@ type IdempotencyResult struct {
 
 // IdempotencyRepository handles atomic idempotency key operations.
 type IdempotencyRepository struct {
-	pool *pgxpool.Pool
+	pool         *pgxpool.Pool
+	queryTimeout time.Duration
 }
 
 // NewIdempotencyRepository creates a new idempotency repository.
-func NewIdempotencyRepository(pool *pgxpool.Pool) *IdempotencyRepository {
-	return &IdempotencyRepository{pool: pool}
+func NewIdempotencyRepository(pool *pgxpool.Pool, queryTimeout time.Duration) *IdempotencyRepository {
+	return &IdempotencyRepository{pool: pool, queryTimeout: queryTimeout}
 }
 
 // Claim atomically attempts to claim an idempotency key for a resource.
@@ -39,6 +41,9 @@ func (r *IdempotencyRepository) Claim(
 	resourceType string,
 	resourceID uuid.UUID,
 ) (*IdempotencyResult, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	if key == "" {
 		return nil, errors.New("idempotency key cannot be empty")
 	}
@@ -78,6 +83,9 @@ func (r *IdempotencyRepository) Claim(
 
 // CleanExpired removes expired idempotency keys. Call from a background job.
 func (r *IdempotencyRepository) CleanExpired(ctx context.Context) (int64, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	tag, err := r.pool.Exec(ctx, `DELETE FROM idempotency_keys WHERE expires_at < NOW()`)
 	if err != nil {
 		return 0, err
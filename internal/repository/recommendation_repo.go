@@ -2,8 +2,13 @@ package repository
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -11,112 +16,146 @@ import (
 	"github.com/workforce-ai/site-selection-iq/internal/models"
 )
 
+// recommendationColumns is the column list shared by every query that returns
+// a full recommendation. It must stay in sync with scanRecommendation.
+const recommendationColumns = `id, run_id, tenant_id, site_id, site_name, ranking,
+		final_score, component_scores, metadata, created_at`
+
+// scanRecommendation scans a row selected with recommendationColumns into rec.
+func scanRecommendation(row pgx.Row, rec *models.Recommendation) error {
+	return row.Scan(
+		&rec.ID,
+		&rec.RunID,
+		&rec.TenantID,
+		&rec.SiteID,
+		&rec.SiteName,
+		&rec.Ranking,
+		&rec.FinalScore,
+		&rec.ComponentScores,
+		&rec.Metadata,
+		&rec.CreatedAt,
+	)
+}
+
 // RecommendationRepository handles data access for recommendation records
 type RecommendationRepository struct {
-	pool *pgxpool.Pool
+	pool         *pgxpool.Pool
+	queryTimeout time.Duration
 }
 
 // NewRecommendationRepository creates a new recommendation repository
-func NewRecommendationRepository(pool *pgxpool.Pool) *RecommendationRepository {
-	return &RecommendationRepository{pool: pool}
+func NewRecommendationRepository(pool *pgxpool.Pool, queryTimeout time.Duration) *RecommendationRepository {
+	return &RecommendationRepository{pool: pool, queryTimeout: queryTimeout}
 }
 
-// BulkInsert performs a batch insert of recommendations using parameterized queries
-func (r *RecommendationRepository) BulkInsert(ctx context.Context, recs []models.Recommendation) error {
-	if len(recs) == 0 {
-		return nil
+// ReplaceByRun stores recs as the run's recommendations in one transaction,
+// deleting any the run already has, e.g. from an earlier attempt that failed
+// after inserting them. Every rec must belong to runID.
+func (r *RecommendationRepository) ReplaceByRun(ctx context.Context, runID uuid.UUID, recs []models.Recommendation) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	for _, rec := range recs {
+		if rec.RunID != runID {
+			return fmt.Errorf("recommendation for site %q belongs to run %s, not %s", rec.SiteID, rec.RunID, runID)
+		}
 	}
 
-	batch := &pgx.Batch{}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
 
-	query := `
-		INSERT INTO recommendations (
-			id, run_id, tenant_id, site_id, site_name, ranking,
-			final_score, component_scores, metadata, created_at
-		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10
-		)
-	`
+	if _, err := tx.Exec(ctx, `DELETE FROM recommendations WHERE run_id = $1`, runID); err != nil {
+		return err
+	}
 
-	for _, rec := range recs {
-		batch.Queue(
-			query,
-			rec.ID,
-			rec.RunID,
-			rec.TenantID,
-			rec.SiteID,
-			rec.SiteName,
-			rec.Ranking,
-			rec.FinalScore,
-			rec.ComponentScores,
-			rec.Metadata,
-			rec.CreatedAt,
-		)
-	}
-
-	results := r.pool.SendBatch(ctx, batch)
-	defer results.Close()
-
-	for i := 0; i < len(recs); i++ {
-		_, err := results.Exec()
-		if err != nil {
+	if len(recs) > 0 {
+		batch := &pgx.Batch{}
+
+		query := `
+			INSERT INTO recommendations (
+				id, run_id, tenant_id, site_id, site_name, ranking,
+				final_score, component_scores, metadata, created_at
+			) VALUES (
+				$1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+			)
+		`
+
+		for _, rec := range recs {
+			batch.Queue(
+				query,
+				rec.ID,
+				rec.RunID,
+				rec.TenantID,
+				rec.SiteID,
+				rec.SiteName,
+				rec.Ranking,
+				rec.FinalScore,
+				rec.ComponentScores,
+				rec.Metadata,
+				rec.CreatedAt,
+			)
+		}
+
+		results := tx.SendBatch(ctx, batch)
+		for i := 0; i < len(recs); i++ {
+			if _, err := results.Exec(); err != nil {
+				results.Close()
+				return err
+			}
+		}
+		if err := results.Close(); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	return tx.Commit(ctx)
 }
 
 // GetByRun retrieves recommendations for a given run with pagination,
-// optionally filtered by minimum score, ordered by final_score DESC
+// optionally filtered by minimum score and by the sites' uploaded data,
+// ordered by final_score DESC
 func (r *RecommendationRepository) GetByRun(
 	ctx context.Context,
 	runID uuid.UUID,
 	page int,
 	pageSize int,
 	minScore *float64,
+	filters []SiteDataFilter,
 ) ([]models.Recommendation, int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	if page < 1 {
 		page = 1
 	}
 	if pageSize < 1 {
-		pageSize = 10
+		pageSize = models.DefaultPageSize
 	}
 
 	offset := (page - 1) * pageSize
 
 	// Get total count
+	where, args := recommendationFilterClause(runID, minScore, filters)
+
 	countQuery := `
 		SELECT COUNT(*)
 		FROM recommendations
-		WHERE run_id = $1
-	`
-	countArgs := []interface{}{runID}
-
-	if minScore != nil {
-		countQuery += ` AND final_score >= $2`
-		countArgs = append(countArgs, *minScore)
-	}
+		WHERE ` + where
 
 	var totalCount int
-	err := r.pool.QueryRow(ctx, countQuery, countArgs...).Scan(&totalCount)
+	err := r.pool.QueryRow(ctx, countQuery, args...).Scan(&totalCount)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	// Get paginated results
 	query := `
-		SELECT id, run_id, tenant_id, site_id, site_name, ranking,
-		       final_score, component_scores, metadata, created_at
+		SELECT ` + recommendationColumns + `
 		FROM recommendations
-		WHERE run_id = $1
-	`
-	args := []interface{}{runID}
-
-	if minScore != nil {
-		query += ` AND final_score >= $2`
-		args = append(args, *minScore)
-	}
+		WHERE ` + where
 
 	limitParamNum := len(args) + 1
 	offsetParamNum := len(args) + 2
@@ -133,18 +172,7 @@ func (r *RecommendationRepository) GetByRun(
 	var recommendations []models.Recommendation
 	for rows.Next() {
 		rec := models.Recommendation{}
-		err := rows.Scan(
-			&rec.ID,
-			&rec.RunID,
-			&rec.TenantID,
-			&rec.SiteID,
-			&rec.SiteName,
-			&rec.Ranking,
-			&rec.FinalScore,
-			&rec.ComponentScores,
-			&rec.Metadata,
-			&rec.CreatedAt,
-		)
+		err := scanRecommendation(rows, &rec)
 		if err != nil {
 			return nil, 0, err
 		}
@@ -158,28 +186,276 @@ func (r *RecommendationRepository) GetByRun(
 	return recommendations, totalCount, nil
 }
 
+// ListByRun retrieves every recommendation for a run, ordered by ranking
+func (r *RecommendationRepository) ListByRun(ctx context.Context, runID uuid.UUID) ([]models.Recommendation, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		SELECT ` + recommendationColumns + `
+		FROM recommendations
+		WHERE run_id = $1
+		ORDER BY ranking ASC, site_id ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query, runID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recommendations []models.Recommendation
+	for rows.Next() {
+		rec := models.Recommendation{}
+		if err := scanRecommendation(rows, &rec); err != nil {
+			return nil, err
+		}
+		recommendations = append(recommendations, rec)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return recommendations, nil
+}
+
+// RecommendationCursor marks the last recommendation of a page for keyset
+// pagination. Clients see it only as an opaque token.
+type RecommendationCursor struct {
+	Score  float64 `json:"s"`
+	SiteID string  `json:"id"`
+}
+
+// Encode returns the cursor as an opaque URL-safe token.
+func (c RecommendationCursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeRecommendationCursor parses a token produced by Encode.
+func DecodeRecommendationCursor(token string) (*RecommendationCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+	var c RecommendationCursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.SiteID == "" {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+	return &c, nil
+}
+
+// SiteDataFilter restricts recommendations to sites whose uploaded data
+// field compares to Value under Op. Build filters with ParseSiteDataFilters
+// so that Field and Op are known to be safe.
+type SiteDataFilter struct {
+	Field string
+	Op    string
+	Value string
+}
+
+// Comparison operators accepted in a filter expression. OpEq and OpNe
+// compare the field as text; the rest compare it as a number.
+const (
+	OpEq  = "="
+	OpNe  = "!="
+	OpGt  = ">"
+	OpGte = ">="
+	OpLt  = "<"
+	OpLte = "<="
+)
+
+// MaxSiteDataFilters caps the number of conditions in one filter expression.
+const MaxSiteDataFilters = 10
+
+// siteDataFieldPattern limits filter and grouping field names to plain
+// data keys.
+var siteDataFieldPattern = regexp.MustCompile(`^[A-Za-z0-9_]{1,64}$`)
+
+// ValidSiteDataField reports whether name may be used as a site data field
+// in a filter or rollup.
+func ValidSiteDataField(name string) bool {
+	return siteDataFieldPattern.MatchString(name)
+}
+
+// siteDataNumberPattern accepts plain decimal numbers that PostgreSQL can
+// cast to numeric, unlike strconv which also allows hex, NaN and Inf.
+var siteDataNumberPattern = regexp.MustCompile(`^[-+]?(\d+\.?\d*|\.\d+)([eE][-+]?\d+)?$`)
+
+// siteDataOperators lists value prefixes in match order, longest first so
+// that ">=" is not read as ">" followed by "=...".
+var siteDataOperators = []string{OpGte, OpLte, OpNe, OpGt, OpLt}
+
+// ParseSiteDataFilters parses a comma-separated filter expression such as
+// "state:TX,population:>100000". Each condition is field:value for equality,
+// or field:<op>value with op one of != > >= < <=. Ordering operators
+// require a numeric value. An empty expression yields no filters.
+func ParseSiteDataFilters(expr string) ([]SiteDataFilter, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(expr, ",")
+	if len(parts) > MaxSiteDataFilters {
+		return nil, fmt.Errorf("at most %d filter conditions are allowed", MaxSiteDataFilters)
+	}
+
+	filters := make([]SiteDataFilter, 0, len(parts))
+	for _, part := range parts {
+		field, rest, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if !ok {
+			return nil, fmt.Errorf("filter condition %q must be field:value", part)
+		}
+		if !ValidSiteDataField(field) {
+			return nil, fmt.Errorf("invalid filter field %q", field)
+		}
+
+		op := OpEq
+		for _, candidate := range siteDataOperators {
+			if strings.HasPrefix(rest, candidate) {
+				op = candidate
+				rest = rest[len(candidate):]
+				break
+			}
+		}
+		if rest == "" {
+			return nil, fmt.Errorf("filter on %q is missing a value", field)
+		}
+		if op != OpEq && op != OpNe {
+			if !siteDataNumberPattern.MatchString(rest) {
+				return nil, fmt.Errorf("filter on %q needs a numeric value for %s", field, op)
+			}
+		}
+
+		filters = append(filters, SiteDataFilter{Field: field, Op: op, Value: rest})
+	}
+	return filters, nil
+}
+
+// recommendationFilterClause builds the WHERE clause shared by the
+// recommendation list queries, starting at $1 with the run ID. Data filters
+// join each recommendation to its site record through the run's upload;
+// field names and values are always bound as parameters.
+func recommendationFilterClause(runID uuid.UUID, minScore *float64, filters []SiteDataFilter) (string, []interface{}) {
+	where := `run_id = $1`
+	args := []interface{}{runID}
+
+	if minScore != nil {
+		args = append(args, *minScore)
+		where += fmt.Sprintf(` AND final_score >= $%d`, len(args))
+	}
+
+	if len(filters) == 0 {
+		return where, args
+	}
+
+	conditions := make([]string, 0, len(filters))
+	for _, f := range filters {
+		args = append(args, f.Field, f.Value)
+		field, value := len(args)-1, len(args)
+		switch f.Op {
+		case OpEq:
+			conditions = append(conditions, fmt.Sprintf(`sr.data->>$%d::text = $%d::text`, field, value))
+		case OpNe:
+			conditions = append(conditions, fmt.Sprintf(`sr.data->>$%d::text IS DISTINCT FROM $%d::text`, field, value))
+		case OpGt, OpGte, OpLt, OpLte:
+			// Only JSON numbers are compared, so a text value in one row
+			// excludes that row instead of failing the cast
+			conditions = append(conditions, fmt.Sprintf(
+				`CASE WHEN jsonb_typeof(sr.data->$%d::text) = 'number' THEN (sr.data->>$%d::text)::numeric %s $%d::text::numeric ELSE false END`,
+				field, field, f.Op, value))
+		}
+	}
+
+	where += `
+		AND EXISTS (
+			SELECT 1
+			FROM scoring_runs fr
+			JOIN site_records sr ON sr.upload_id = fr.upload_id
+			WHERE fr.id = recommendations.run_id
+			  AND sr.tenant_id = recommendations.tenant_id
+			  AND sr.site_id = recommendations.site_id
+			  AND ` + strings.Join(conditions, `
+			  AND `) + `
+		)`
+	return where, args
+}
+
+// GetByRunCursor retrieves up to limit recommendations for a run using
+// keyset pagination, ordered by final_score DESC then site_id ASC. A nil
+// after starts from the top; otherwise rows strictly after that position
+// are returned, so pages stay stable however deep the client pages. The
+// returned cursor is nil once the last page has been read.
+func (r *RecommendationRepository) GetByRunCursor(
+	ctx context.Context,
+	runID uuid.UUID,
+	after *RecommendationCursor,
+	limit int,
+	minScore *float64,
+	filters []SiteDataFilter,
+) ([]models.Recommendation, *RecommendationCursor, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	if limit < 1 {
+		limit = 10
+	}
+
+	where, args := recommendationFilterClause(runID, minScore, filters)
+	query := `
+		SELECT ` + recommendationColumns + `
+		FROM recommendations
+		WHERE ` + where
+	if after != nil {
+		args = append(args, after.Score, after.SiteID)
+		query += fmt.Sprintf(` AND (final_score < $%d OR (final_score = $%d AND site_id > $%d))`,
+			len(args)-1, len(args)-1, len(args))
+	}
+
+	// Fetch one extra row to learn whether another page follows
+	args = append(args, limit+1)
+	query += fmt.Sprintf(` ORDER BY final_score DESC, site_id ASC LIMIT $%d`, len(args))
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	recommendations := make([]models.Recommendation, 0, limit)
+	for rows.Next() {
+		rec := models.Recommendation{}
+		if err := scanRecommendation(rows, &rec); err != nil {
+			return nil, nil, err
+		}
+		recommendations = append(recommendations, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if len(recommendations) <= limit {
+		return recommendations, nil, nil
+	}
+	recommendations = recommendations[:limit]
+	last := recommendations[limit-1]
+	return recommendations, &RecommendationCursor{Score: last.FinalScore, SiteID: last.SiteID}, nil
+}
+
 // GetBySiteID retrieves a recommendation for a specific site within a run
 func (r *RecommendationRepository) GetBySiteID(ctx context.Context, runID uuid.UUID, siteID string) (*models.Recommendation, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	query := `
-		SELECT id, run_id, tenant_id, site_id, site_name, ranking,
-		       final_score, component_scores, metadata, created_at
+		SELECT ` + recommendationColumns + `
 		FROM recommendations
 		WHERE run_id = $1 AND site_id = $2
 	`
 
 	rec := &models.Recommendation{}
-	err := r.pool.QueryRow(ctx, query, runID, siteID).Scan(
-		&rec.ID,
-		&rec.RunID,
-		&rec.TenantID,
-		&rec.SiteID,
-		&rec.SiteName,
-		&rec.Ranking,
-		&rec.FinalScore,
-		&rec.ComponentScores,
-		&rec.Metadata,
-		&rec.CreatedAt,
-	)
+	err := scanRecommendation(r.pool.QueryRow(ctx, query, runID, siteID), rec)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -190,3 +466,164 @@ func (r *RecommendationRepository) GetBySiteID(ctx context.Context, runID uuid.U
 
 	return rec, nil
 }
+
+// TopN returns the n highest-scoring recommendations for a run, or the n
+// lowest when ascending is true. Ties are broken by ranking so the result is
+// stable and top/bottom mirror each other.
+func (r *RecommendationRepository) TopN(ctx context.Context, runID uuid.UUID, n int, ascending bool) ([]models.Recommendation, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	order := `final_score DESC, ranking ASC`
+	if ascending {
+		order = `final_score ASC, ranking DESC`
+	}
+
+	query := `
+		SELECT ` + recommendationColumns + `
+		FROM recommendations
+		WHERE run_id = $1
+		ORDER BY ` + order + `
+		LIMIT $2
+	`
+
+	rows, err := r.pool.Query(ctx, query, runID, n)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recommendations []models.Recommendation
+	for rows.Next() {
+		rec := models.Recommendation{}
+		if err := scanRecommendation(rows, &rec); err != nil {
+			return nil, err
+		}
+		recommendations = append(recommendations, rec)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return recommendations, nil
+}
+
+// Histogram counts a run's recommendations per equal-width final_score bucket
+// across [0, maxScore], where maxScore is the top of the run's score scale.
+// All buckets are returned, including empty ones. Scores at the upper bound
+// fall in the last bucket.
+func (r *RecommendationRepository) Histogram(ctx context.Context, runID uuid.UUID, buckets int, maxScore float64) ([]models.HistogramBucket, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	if buckets < 1 {
+		return nil, errors.New("buckets must be positive")
+	}
+	if maxScore <= 0 {
+		return nil, errors.New("maxScore must be positive")
+	}
+	const minScore = 0.0
+
+	// width_bucket returns 0 below the range and buckets+1 at or above the
+	// upper bound; clamp both into the outer buckets
+	query := `
+		SELECT LEAST(GREATEST(width_bucket(final_score, $2::numeric, $3::numeric, $4::int), 1), $4::int) AS bucket,
+		       COUNT(*)
+		FROM recommendations
+		WHERE run_id = $1
+		GROUP BY bucket
+	`
+
+	rows, err := r.pool.Query(ctx, query, runID, minScore, maxScore, buckets)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	width := (maxScore - minScore) / float64(buckets)
+	histogram := make([]models.HistogramBucket, buckets)
+	for i := range histogram {
+		histogram[i].Lower = minScore + float64(i)*width
+		histogram[i].Upper = minScore + float64(i+1)*width
+	}
+
+	for rows.Next() {
+		var bucket, count int
+		if err := rows.Scan(&bucket, &count); err != nil {
+			return nil, err
+		}
+		histogram[bucket-1].Count = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return histogram, nil
+}
+
+// Rollup aggregates a run's recommendations by the value of field in each
+// site's uploaded data, returning the count and the average, minimum and
+// maximum final_score per group. Groups are ordered by average score
+// descending, then by value, with sites lacking the field grouped last.
+func (r *RecommendationRepository) Rollup(
+	ctx context.Context,
+	runID uuid.UUID,
+	field string,
+	minScore *float64,
+) ([]models.RollupGroup, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	if !ValidSiteDataField(field) {
+		return nil, fmt.Errorf("invalid rollup field %q", field)
+	}
+
+	where := `rec.run_id = $1`
+	args := []interface{}{runID, field}
+
+	if minScore != nil {
+		args = append(args, *minScore)
+		where += fmt.Sprintf(` AND rec.final_score >= $%d`, len(args))
+	}
+
+	query := `
+		SELECT value, site_count, avg_score, min_score, max_score
+		FROM (
+			SELECT sr.data->>$2::text AS value,
+			       COUNT(*) AS site_count,
+			       AVG(rec.final_score)::float8 AS avg_score,
+			       MIN(rec.final_score)::float8 AS min_score,
+			       MAX(rec.final_score)::float8 AS max_score
+			FROM recommendations rec
+			JOIN scoring_runs fr ON fr.id = rec.run_id
+			JOIN site_records sr ON sr.upload_id = fr.upload_id
+			                    AND sr.tenant_id = rec.tenant_id
+			                    AND sr.site_id = rec.site_id
+			WHERE ` + where + `
+			GROUP BY 1
+		) g
+		ORDER BY value IS NULL, avg_score DESC, value ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := []models.RollupGroup{}
+	for rows.Next() {
+		var g models.RollupGroup
+		if err := rows.Scan(&g.Value, &g.Count, &g.AvgScore, &g.MinScore, &g.MaxScore); err != nil {
+			return nil, err
+		}
+		groups = append(groups, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,419 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+)
+
+// insertTestRecommendations stores one recommendation per score for the run.
+// Site IDs are SITE-<index> and rankings follow score order descending.
+func insertTestRecommendations(t *testing.T, repo *RecommendationRepository, run *models.ScoringRun, scores ...float64) {
+	t.Helper()
+	recs := make([]models.Recommendation, len(scores))
+	for i, score := range scores {
+		recs[i] = models.Recommendation{
+			ID:              uuid.New(),
+			RunID:           run.ID,
+			TenantID:        run.TenantID,
+			SiteID:          fmt.Sprintf("SITE-%d", i),
+			FinalScore:      score,
+			ComponentScores: json.RawMessage("{}"),
+			Metadata:        json.RawMessage("{}"),
+			CreatedAt:       time.Now(),
+		}
+	}
+	for i := range recs {
+		recs[i].Ranking = 1
+		for _, other := range recs {
+			if other.FinalScore > recs[i].FinalScore {
+				recs[i].Ranking++
+			}
+		}
+	}
+	require.NoError(t, repo.ReplaceByRun(context.Background(), run.ID, recs))
+}
+
+func TestRecommendationRepository_TopN(t *testing.T) {
+	pool := testPool(t)
+	repo := NewRecommendationRepository(pool, testQueryTimeout)
+	ctx := context.Background()
+
+	tenantID := createTestTenant(t, pool)
+	upload := createTestUpload(t, pool, tenantID)
+	run := createTestRun(t, pool, upload, "succeeded", uuid.New(), time.Now())
+	insertTestRecommendations(t, repo, run, 40, 90, 10, 70, 55)
+
+	siteScores := func(recs []models.Recommendation) []float64 {
+		scores := make([]float64, len(recs))
+		for i, rec := range recs {
+			scores[i] = rec.FinalScore
+		}
+		return scores
+	}
+
+	top, err := repo.TopN(ctx, run.ID, 3, false)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{90, 70, 55}, siteScores(top))
+
+	bottom, err := repo.TopN(ctx, run.ID, 2, true)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{10, 40}, siteScores(bottom))
+
+	// n larger than the run returns every recommendation
+	all, err := repo.TopN(ctx, run.ID, 100, false)
+	require.NoError(t, err)
+	assert.Len(t, all, 5)
+}
+
+func TestRecommendationRepository_ReplaceByRun(t *testing.T) {
+	pool := testPool(t)
+	repo := NewRecommendationRepository(pool, testQueryTimeout)
+	ctx := context.Background()
+
+	tenantID := createTestTenant(t, pool)
+	upload := createTestUpload(t, pool, tenantID)
+	run := createTestRun(t, pool, upload, "running", uuid.New(), time.Now())
+	other := createTestRun(t, pool, upload, "succeeded", uuid.New(), time.Now())
+	insertTestRecommendations(t, repo, other, 50)
+
+	// A second attempt of the run replaces the first one's results
+	insertTestRecommendations(t, repo, run, 40, 90, 10)
+	insertTestRecommendations(t, repo, run, 70, 20)
+	all, err := repo.TopN(ctx, run.ID, 100, false)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, 70.0, all[0].FinalScore)
+
+	// Replacing with nothing clears the run
+	require.NoError(t, repo.ReplaceByRun(ctx, run.ID, nil))
+	all, err = repo.TopN(ctx, run.ID, 100, false)
+	require.NoError(t, err)
+	assert.Empty(t, all)
+
+	// Other runs are untouched, and a recommendation of another run is refused
+	all, err = repo.TopN(ctx, other.ID, 100, false)
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+	assert.Error(t, repo.ReplaceByRun(ctx, run.ID, []models.Recommendation{{RunID: other.ID, SiteID: "X"}}))
+}
+
+func TestRecommendationRepository_Histogram(t *testing.T) {
+	pool := testPool(t)
+	repo := NewRecommendationRepository(pool, testQueryTimeout)
+	ctx := context.Background()
+
+	tenantID := createTestTenant(t, pool)
+	upload := createTestUpload(t, pool, tenantID)
+	run := createTestRun(t, pool, upload, "succeeded", uuid.New(), time.Now())
+
+	insertTestRecommendations(t, repo, run, 0, 5, 12.5, 19.99, 20, 55, 99.9, 100)
+
+	histogram, err := repo.Histogram(ctx, run.ID, 5, 100)
+	require.NoError(t, err)
+	require.Len(t, histogram, 5)
+
+	counts := make([]int, len(histogram))
+	for i, bucket := range histogram {
+		counts[i] = bucket.Count
+	}
+	// [0,20) [20,40) [40,60) [60,80) [80,100]
+	assert.Equal(t, []int{4, 1, 1, 0, 2}, counts)
+
+	assert.Equal(t, 0.0, histogram[0].Lower)
+	assert.Equal(t, 20.0, histogram[0].Upper)
+	assert.Equal(t, 80.0, histogram[4].Lower)
+	assert.Equal(t, 100.0, histogram[4].Upper)
+}
+
+func TestRecommendationRepository_HistogramEmptyRun(t *testing.T) {
+	pool := testPool(t)
+	repo := NewRecommendationRepository(pool, testQueryTimeout)
+
+	histogram, err := repo.Histogram(context.Background(), uuid.New(), 10, 100)
+	require.NoError(t, err)
+	require.Len(t, histogram, 10)
+	for _, bucket := range histogram {
+		assert.Zero(t, bucket.Count)
+	}
+}
+
+func TestRecommendationRepository_HistogramUnitScale(t *testing.T) {
+	pool := testPool(t)
+	repo := NewRecommendationRepository(pool, testQueryTimeout)
+	ctx := context.Background()
+
+	tenantID := createTestTenant(t, pool)
+	upload := createTestUpload(t, pool, tenantID)
+	run := createTestRun(t, pool, upload, "succeeded", uuid.New(), time.Now())
+	insertTestRecommendations(t, repo, run, 0.1, 0.3, 0.6, 1.0)
+
+	histogram, err := repo.Histogram(ctx, run.ID, 2, 1)
+	require.NoError(t, err)
+	require.Len(t, histogram, 2)
+	assert.Equal(t, 2, histogram[0].Count)
+	assert.Equal(t, 2, histogram[1].Count)
+	assert.Equal(t, 0.5, histogram[0].Upper)
+}
+
+func TestRecommendationRepository_GetByRunCursor(t *testing.T) {
+	pool := testPool(t)
+	repo := NewRecommendationRepository(pool, testQueryTimeout)
+	ctx := context.Background()
+
+	tenantID := createTestTenant(t, pool)
+	upload := createTestUpload(t, pool, tenantID)
+	run := createTestRun(t, pool, upload, "succeeded", uuid.New(), time.Now())
+	// Ties at 70 and 55 exercise the site_id tie-break across page boundaries
+	insertTestRecommendations(t, repo, run, 40, 90, 70, 70, 55, 55, 55, 10)
+
+	var seen []string
+	var after *RecommendationCursor
+	pages := 0
+	for {
+		page, next, err := repo.GetByRunCursor(ctx, run.ID, after, 3, nil, nil)
+		require.NoError(t, err)
+		pages++
+		for _, rec := range page {
+			seen = append(seen, rec.SiteID)
+		}
+		if next == nil {
+			break
+		}
+		// Round-trip through the opaque token as a client would
+		after, err = DecodeRecommendationCursor(next.Encode())
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 3, pages)
+	assert.Equal(t, []string{
+		"SITE-1",
+		"SITE-2", "SITE-3",
+		"SITE-4", "SITE-5", "SITE-6",
+		"SITE-0",
+		"SITE-7",
+	}, seen, "every site exactly once: 90, the 70s, the 55s, 40, 10")
+
+	// min_score applies alongside the cursor
+	minScore := 55.0
+	filtered, next, err := repo.GetByRunCursor(ctx, run.ID, &RecommendationCursor{Score: 70, SiteID: "SITE-3"}, 10, &minScore, nil)
+	require.NoError(t, err)
+	assert.Nil(t, next)
+	assert.Len(t, filtered, 3)
+}
+
+func TestRecommendationCursor_RoundTrip(t *testing.T) {
+	token := RecommendationCursor{Score: 72.5, SiteID: "ATL-01"}.Encode()
+
+	decoded, err := DecodeRecommendationCursor(token)
+	require.NoError(t, err)
+	assert.Equal(t, RecommendationCursor{Score: 72.5, SiteID: "ATL-01"}, *decoded)
+
+	for _, bad := range []string{"not base64!", "e30", "bm9wZQ"} {
+		_, err := DecodeRecommendationCursor(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+// insertTestSiteData stores a site record for each SITE-<index> recommendation
+// of the upload, with the given data documents.
+func insertTestSiteData(t *testing.T, pool *pgxpool.Pool, upload *models.Upload, data ...string) {
+	t.Helper()
+	records := make([]models.SiteRecord, len(data))
+	for i, doc := range data {
+		records[i] = models.SiteRecord{
+			ID:        uuid.New(),
+			UploadID:  upload.ID,
+			TenantID:  upload.TenantID,
+			SiteID:    fmt.Sprintf("SITE-%d", i),
+			RawData:   json.RawMessage(doc),
+			Data:      json.RawMessage(doc),
+			CreatedAt: time.Now(),
+		}
+	}
+	require.NoError(t, NewSiteRecordRepository(pool, testQueryTimeout).BulkInsert(context.Background(), records))
+}
+
+func siteIDs(recs []models.Recommendation) []string {
+	ids := make([]string, len(recs))
+	for i, rec := range recs {
+		ids[i] = rec.SiteID
+	}
+	return ids
+}
+
+func TestRecommendationRepository_GetByRunDataFilterEquality(t *testing.T) {
+	pool := testPool(t)
+	repo := NewRecommendationRepository(pool, testQueryTimeout)
+	ctx := context.Background()
+
+	tenantID := createTestTenant(t, pool)
+	upload := createTestUpload(t, pool, tenantID)
+	run := createTestRun(t, pool, upload, "succeeded", uuid.New(), time.Now())
+	insertTestRecommendations(t, repo, run, 80, 60, 90, 70)
+	insertTestSiteData(t, pool, upload,
+		`{"state": "TX", "population": 250000}`,
+		`{"state": "CA", "population": 90000}`,
+		`{"state": "TX", "population": 50000}`,
+		`{"state": "NY", "population": 400000}`,
+	)
+
+	filters, err := ParseSiteDataFilters("state:TX")
+	require.NoError(t, err)
+	recs, total, err := repo.GetByRun(ctx, run.ID, 1, 10, nil, filters)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, []string{"SITE-2", "SITE-0"}, siteIDs(recs))
+
+	filters, err = ParseSiteDataFilters("state:!=TX")
+	require.NoError(t, err)
+	recs, total, err = repo.GetByRun(ctx, run.ID, 1, 10, nil, filters)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, []string{"SITE-3", "SITE-1"}, siteIDs(recs))
+
+	// A field no site has matches nothing rather than erroring
+	filters, err = ParseSiteDataFilters("county:Travis")
+	require.NoError(t, err)
+	recs, total, err = repo.GetByRun(ctx, run.ID, 1, 10, nil, filters)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, recs)
+}
+
+func TestRecommendationRepository_GetByRunDataFilterNumeric(t *testing.T) {
+	pool := testPool(t)
+	repo := NewRecommendationRepository(pool, testQueryTimeout)
+	ctx := context.Background()
+
+	tenantID := createTestTenant(t, pool)
+	upload := createTestUpload(t, pool, tenantID)
+	run := createTestRun(t, pool, upload, "succeeded", uuid.New(), time.Now())
+	insertTestRecommendations(t, repo, run, 80, 60, 90, 70, 50)
+	insertTestSiteData(t, pool, upload,
+		`{"state": "TX", "population": 250000}`,
+		`{"state": "CA", "population": 90000}`,
+		`{"state": "TX", "population": 50000}`,
+		`{"state": "NY", "population": 100000}`,
+		`{"state": "TX", "population": "unknown"}`,
+	)
+
+	filters, err := ParseSiteDataFilters("population:>100000")
+	require.NoError(t, err)
+	recs, total, err := repo.GetByRun(ctx, run.ID, 1, 10, nil, filters)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, []string{"SITE-0"}, siteIDs(recs))
+
+	filters, err = ParseSiteDataFilters("population:>=100000")
+	require.NoError(t, err)
+	recs, _, err = repo.GetByRun(ctx, run.ID, 1, 10, nil, filters)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"SITE-0", "SITE-3"}, siteIDs(recs))
+
+	// Conditions combine with AND and with min_score
+	minScore := 75.0
+	filters, err = ParseSiteDataFilters("state:TX,population:<300000")
+	require.NoError(t, err)
+	recs, total, err = repo.GetByRun(ctx, run.ID, 1, 10, &minScore, filters)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, []string{"SITE-2", "SITE-0"}, siteIDs(recs))
+
+	// The cursor path applies the same filters; without min_score the
+	// non-numeric population is excluded instead of failing the cast
+	recs, next, err := repo.GetByRunCursor(ctx, run.ID, nil, 10, nil, filters)
+	require.NoError(t, err)
+	assert.Nil(t, next)
+	assert.Equal(t, []string{"SITE-2", "SITE-0"}, siteIDs(recs))
+}
+
+func TestParseSiteDataFilters(t *testing.T) {
+	filters, err := ParseSiteDataFilters("state:TX, population:>100000,median_age:<=42.5,region:!=south")
+	require.NoError(t, err)
+	assert.Equal(t, []SiteDataFilter{
+		{Field: "state", Op: OpEq, Value: "TX"},
+		{Field: "population", Op: OpGt, Value: "100000"},
+		{Field: "median_age", Op: OpLte, Value: "42.5"},
+		{Field: "region", Op: OpNe, Value: "south"},
+	}, filters)
+
+	filters, err = ParseSiteDataFilters("")
+	require.NoError(t, err)
+	assert.Nil(t, filters)
+
+	for _, bad := range []string{
+		"state",            // no value separator
+		"state:",           // empty value
+		"st ate:TX",        // field outside the whitelist
+		"data->>'x':1",     // injection attempt in the field
+		"population:>lots", // ordering needs a number
+		"population:>0x10", // hex is not a SQL numeric
+		"population:>NaN",  // nor is NaN
+		"a:1,b:2,c:3,d:4,e:5,f:6,g:7,h:8,i:9,j:10,k:11", // too many conditions
+	} {
+		_, err := ParseSiteDataFilters(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestRecommendationRepository_Rollup(t *testing.T) {
+	pool := testPool(t)
+	repo := NewRecommendationRepository(pool, testQueryTimeout)
+	ctx := context.Background()
+
+	tenantID := createTestTenant(t, pool)
+	upload := createTestUpload(t, pool, tenantID)
+	run := createTestRun(t, pool, upload, "succeeded", uuid.New(), time.Now())
+	insertTestRecommendations(t, repo, run, 80, 60, 90, 70, 50, 65)
+	insertTestSiteData(t, pool, upload,
+		`{"state": "TX"}`,
+		`{"state": "CA"}`,
+		`{"state": "TX"}`,
+		`{"state": "CA"}`,
+		`{"state": "TX"}`,
+		`{"population": 1000}`,
+	)
+
+	groups, err := repo.Rollup(ctx, run.ID, "state", nil)
+	require.NoError(t, err)
+	require.Len(t, groups, 3)
+
+	// TX averages 73.3 and CA 65; the site without a state comes last
+	require.NotNil(t, groups[0].Value)
+	assert.Equal(t, "TX", *groups[0].Value)
+	assert.Equal(t, 3, groups[0].Count)
+	assert.InDelta(t, 220.0/3, groups[0].AvgScore, 1e-9)
+	assert.Equal(t, 50.0, groups[0].MinScore)
+	assert.Equal(t, 90.0, groups[0].MaxScore)
+
+	require.NotNil(t, groups[1].Value)
+	assert.Equal(t, "CA", *groups[1].Value)
+	assert.Equal(t, 2, groups[1].Count)
+	assert.InDelta(t, 65.0, groups[1].AvgScore, 1e-9)
+
+	assert.Nil(t, groups[2].Value)
+	assert.Equal(t, 1, groups[2].Count)
+
+	// min_score narrows each group before aggregating
+	minScore := 70.0
+	groups, err = repo.Rollup(ctx, run.ID, "state", &minScore)
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	assert.Equal(t, "TX", *groups[0].Value)
+	assert.Equal(t, 2, groups[0].Count)
+	assert.Equal(t, "CA", *groups[1].Value)
+	assert.Equal(t, 1, groups[1].Count)
+
+	_, err = repo.Rollup(ctx, run.ID, "state'--", nil)
+	assert.Error(t, err)
+}
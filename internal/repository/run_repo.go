@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -10,18 +12,60 @@ import (
 	"github.com/workforce-ai/site-selection-iq/internal/models"
 )
 
+// runColumns is the column list shared by every query that returns a full
+// scoring run. It must stay in sync with scanRun.
+const runColumns = `id, upload_id, tenant_id, status, model_version, scoring_config,
+		schema_config_snapshot_id, instance_id, transaction_id, row_count,
+		scored_count, attempt, last_error, idempotency_key, duration_ms,
+		started_at, completed_at, created_at, updated_at, stats, result_key,
+		skipped_count, skipped_sites, correlation_id`
+
+// scanRun scans a row selected with runColumns into run.
+func scanRun(row pgx.Row, run *models.ScoringRun) error {
+	return row.Scan(
+		&run.ID,
+		&run.UploadID,
+		&run.TenantID,
+		&run.Status,
+		&run.ModelVersion,
+		&run.ScoringConfig,
+		&run.SchemaConfigSnapshotID,
+		&run.InstanceID,
+		&run.TransactionID,
+		&run.RowCount,
+		&run.ScoredCount,
+		&run.Attempt,
+		&run.LastError,
+		&run.IdempotencyKey,
+		&run.DurationMs,
+		&run.StartedAt,
+		&run.CompletedAt,
+		&run.CreatedAt,
+		&run.UpdatedAt,
+		&run.Stats,
+		&run.ResultKey,
+		&run.SkippedCount,
+		&run.SkippedSites,
+		&run.CorrelationID,
+	)
+}
+
 // RunRepository handles data access for scoring run records
 type RunRepository struct {
-	pool *pgxpool.Pool
+	pool         *pgxpool.Pool
+	queryTimeout time.Duration
 }
 
 // NewRunRepository creates a new run repository
-func NewRunRepository(pool *pgxpool.Pool) *RunRepository {
-	return &RunRepository{pool: pool}
+func NewRunRepository(pool *pgxpool.Pool, queryTimeout time.Duration) *RunRepository {
+	return &RunRepository{pool: pool, queryTimeout: queryTimeout}
 }
 
 // Create inserts a new scoring run record
 func (r *RunRepository) Create(ctx context.Context, run *models.ScoringRun) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	if run == nil {
 		return errors.New("scoring run cannot be nil")
 	}
@@ -31,17 +75,15 @@ func (r *RunRepository) Create(ctx context.Context, run *models.ScoringRun) erro
 			id, upload_id, tenant_id, status, model_version, scoring_config,
 			schema_config_snapshot_id, instance_id, transaction_id, row_count,
 			scored_count, attempt, last_error, idempotency_key, duration_ms,
-			started_at, completed_at, created_at, updated_at
+			started_at, completed_at, created_at, updated_at, result_key,
+			correlation_id
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20,
+			$21
 		)
-		RETURNING id, upload_id, tenant_id, status, model_version, scoring_config,
-		          schema_config_snapshot_id, instance_id, transaction_id, row_count,
-		          scored_count, attempt, last_error, idempotency_key, duration_ms,
-		          started_at, completed_at, created_at, updated_at
-	`
+		RETURNING ` + runColumns
 
-	err := r.pool.QueryRow(
+	err := scanRun(r.pool.QueryRow(
 		ctx,
 		query,
 		run.ID,
@@ -63,27 +105,9 @@ func (r *RunRepository) Create(ctx context.Context, run *models.ScoringRun) erro
 		run.CompletedAt,
 		run.CreatedAt,
 		run.UpdatedAt,
-	).Scan(
-		&run.ID,
-		&run.UploadID,
-		&run.TenantID,
-		&run.Status,
-		&run.ModelVersion,
-		&run.ScoringConfig,
-		&run.SchemaConfigSnapshotID,
-		&run.InstanceID,
-		&run.TransactionID,
-		&run.RowCount,
-		&run.ScoredCount,
-		&run.Attempt,
-		&run.LastError,
-		&run.IdempotencyKey,
-		&run.DurationMs,
-		&run.StartedAt,
-		&run.CompletedAt,
-		&run.CreatedAt,
-		&run.UpdatedAt,
-	)
+		run.ResultKey,
+		run.CorrelationID,
+	), run)
 
 	if err != nil {
 		return err
@@ -94,37 +118,17 @@ func (r *RunRepository) Create(ctx context.Context, run *models.ScoringRun) erro
 
 // GetByID retrieves a scoring run by ID, scoped to the tenant
 func (r *RunRepository) GetByID(ctx context.Context, tenantID, runID uuid.UUID) (*models.ScoringRun, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	query := `
-		SELECT id, upload_id, tenant_id, status, model_version, scoring_config,
-		       schema_config_snapshot_id, instance_id, transaction_id, row_count,
-		       scored_count, attempt, last_error, idempotency_key, duration_ms,
-		       started_at, completed_at, created_at, updated_at
+		SELECT ` + runColumns + `
 		FROM scoring_runs
 		WHERE id = $1 AND tenant_id = $2
 	`
 
 	run := &models.ScoringRun{}
-	err := r.pool.QueryRow(ctx, query, runID, tenantID).Scan(
-		&run.ID,
-		&run.UploadID,
-		&run.TenantID,
-		&run.Status,
-		&run.ModelVersion,
-		&run.ScoringConfig,
-		&run.SchemaConfigSnapshotID,
-		&run.InstanceID,
-		&run.TransactionID,
-		&run.RowCount,
-		&run.ScoredCount,
-		&run.Attempt,
-		&run.LastError,
-		&run.IdempotencyKey,
-		&run.DurationMs,
-		&run.StartedAt,
-		&run.CompletedAt,
-		&run.CreatedAt,
-		&run.UpdatedAt,
-	)
+	err := scanRun(r.pool.QueryRow(ctx, query, runID, tenantID), run)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -138,37 +142,76 @@ func (r *RunRepository) GetByID(ctx context.Context, tenantID, runID uuid.UUID)
 
 // GetByIdempotencyKey retrieves a scoring run by idempotency key, scoped to the tenant
 func (r *RunRepository) GetByIdempotencyKey(ctx context.Context, tenantID uuid.UUID, key string) (*models.ScoringRun, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	query := `
-		SELECT id, upload_id, tenant_id, status, model_version, scoring_config,
-		       schema_config_snapshot_id, instance_id, transaction_id, row_count,
-		       scored_count, attempt, last_error, idempotency_key, duration_ms,
-		       started_at, completed_at, created_at, updated_at
+		SELECT ` + runColumns + `
 		FROM scoring_runs
 		WHERE tenant_id = $1 AND idempotency_key = $2
 	`
 
 	run := &models.ScoringRun{}
-	err := r.pool.QueryRow(ctx, query, tenantID, key).Scan(
-		&run.ID,
-		&run.UploadID,
-		&run.TenantID,
-		&run.Status,
-		&run.ModelVersion,
-		&run.ScoringConfig,
-		&run.SchemaConfigSnapshotID,
-		&run.InstanceID,
-		&run.TransactionID,
-		&run.RowCount,
-		&run.ScoredCount,
-		&run.Attempt,
-		&run.LastError,
-		&run.IdempotencyKey,
-		&run.DurationMs,
-		&run.StartedAt,
-		&run.CompletedAt,
-		&run.CreatedAt,
-		&run.UpdatedAt,
-	)
+	err := scanRun(r.pool.QueryRow(ctx, query, tenantID, key), run)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return run, nil
+}
+
+// GetLatestByUpload retrieves the most recently completed run with results
+// for an upload (succeeded or completed_with_errors), scoped to the tenant.
+// It returns nil if the upload has no such run.
+func (r *RunRepository) GetLatestByUpload(ctx context.Context, tenantID, uploadID uuid.UUID) (*models.ScoringRun, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		SELECT ` + runColumns + `
+		FROM scoring_runs
+		WHERE tenant_id = $1 AND upload_id = $2 AND status IN ('succeeded', 'completed_with_errors')
+		ORDER BY COALESCE(completed_at, updated_at) DESC, created_at DESC
+		LIMIT 1
+	`
+
+	run := &models.ScoringRun{}
+	err := scanRun(r.pool.QueryRow(ctx, query, tenantID, uploadID), run)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return run, nil
+}
+
+// GetCompletedByResultKey retrieves the most recently completed run with
+// results (succeeded or completed_with_errors) of an upload with the given
+// result key, scoped to the tenant. A completed_with_errors run qualifies:
+// the same content and schema skip the same sites, so scoring again would
+// reproduce it. It returns nil if there is none.
+func (r *RunRepository) GetCompletedByResultKey(ctx context.Context, tenantID, uploadID uuid.UUID, resultKey string) (*models.ScoringRun, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		SELECT ` + runColumns + `
+		FROM scoring_runs
+		WHERE tenant_id = $1 AND upload_id = $2 AND result_key = $3
+		  AND status IN ('succeeded', 'completed_with_errors')
+		ORDER BY COALESCE(completed_at, updated_at) DESC, created_at DESC
+		LIMIT 1
+	`
+
+	run := &models.ScoringRun{}
+	err := scanRun(r.pool.QueryRow(ctx, query, tenantID, uploadID, resultKey), run)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -180,6 +223,25 @@ func (r *RunRepository) GetByIdempotencyKey(ctx context.Context, tenantID uuid.U
 	return run, nil
 }
 
+// CountByUpload returns the number of scoring runs, in any status, created
+// against an upload, scoped to the tenant.
+func (r *RunRepository) CountByUpload(ctx context.Context, tenantID, uploadID uuid.UUID) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		SELECT COUNT(*)
+		FROM scoring_runs
+		WHERE tenant_id = $1 AND upload_id = $2
+	`
+
+	var count int
+	if err := r.pool.QueryRow(ctx, query, tenantID, uploadID).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
 // UpdateStatus updates the status and related fields for a scoring run
 func (r *RunRepository) UpdateStatus(
 	ctx context.Context,
@@ -189,13 +251,16 @@ func (r *RunRepository) UpdateStatus(
 	lastError *string,
 	durationMs *int,
 ) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	query := `
 		UPDATE scoring_runs
 		SET status = $1,
 		    scored_count = COALESCE($2, scored_count),
 		    last_error = COALESCE($3, last_error),
 		    duration_ms = COALESCE($4, duration_ms),
-		    completed_at = CASE WHEN $1 IN ('succeeded', 'failed') THEN NOW() ELSE completed_at END,
+		    completed_at = CASE WHEN $1 IN ('succeeded', 'completed_with_errors', 'failed') THEN NOW() ELSE completed_at END,
 		    updated_at = NOW()
 		WHERE id = $5
 		RETURNING id
@@ -224,6 +289,9 @@ func (r *RunRepository) UpdateStatus(
 
 // Update updates a scoring run record
 func (r *RunRepository) Update(ctx context.Context, run *models.ScoringRun) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	if run == nil {
 		return errors.New("scoring run cannot be nil")
 	}
@@ -234,15 +302,12 @@ func (r *RunRepository) Update(ctx context.Context, run *models.ScoringRun) erro
 		    scoring_config = $6, schema_config_snapshot_id = $7, instance_id = $8,
 		    transaction_id = $9, row_count = $10, scored_count = $11, attempt = $12,
 		    last_error = $13, idempotency_key = $14, duration_ms = $15,
-		    started_at = $16, completed_at = $17, updated_at = $18
+		    started_at = $16, completed_at = $17, updated_at = $18, stats = $19,
+		    skipped_count = $20, skipped_sites = $21
 		WHERE id = $1
-		RETURNING id, upload_id, tenant_id, status, model_version, scoring_config,
-		          schema_config_snapshot_id, instance_id, transaction_id, row_count,
-		          scored_count, attempt, last_error, idempotency_key, duration_ms,
-		          started_at, completed_at, created_at, updated_at
-	`
+		RETURNING ` + runColumns
 
-	err := r.pool.QueryRow(
+	err := scanRun(r.pool.QueryRow(
 		ctx,
 		query,
 		run.ID,
@@ -263,27 +328,91 @@ func (r *RunRepository) Update(ctx context.Context, run *models.ScoringRun) erro
 		run.StartedAt,
 		run.CompletedAt,
 		run.UpdatedAt,
-	).Scan(
-		&run.ID,
-		&run.UploadID,
-		&run.TenantID,
-		&run.Status,
-		&run.ModelVersion,
-		&run.ScoringConfig,
-		&run.SchemaConfigSnapshotID,
-		&run.InstanceID,
-		&run.TransactionID,
-		&run.RowCount,
-		&run.ScoredCount,
-		&run.Attempt,
-		&run.LastError,
-		&run.IdempotencyKey,
-		&run.DurationMs,
-		&run.StartedAt,
-		&run.CompletedAt,
-		&run.CreatedAt,
-		&run.UpdatedAt,
-	)
+		run.Stats,
+		run.SkippedCount,
+		run.SkippedSites,
+	), run)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errors.New("scoring run not found")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// UpdateStats records the final score distribution for a scoring run
+func (r *RunRepository) UpdateStats(ctx context.Context, runID uuid.UUID, stats *models.RunStats) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE scoring_runs
+		SET stats = $1,
+		    updated_at = NOW()
+		WHERE id = $2
+		RETURNING id
+	`
+
+	var id uuid.UUID
+	err := r.pool.QueryRow(ctx, query, stats, runID).Scan(&id)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errors.New("scoring run not found")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// UpdateResultKey sets the result key a scoring run's results are reused
+// under; nil clears it, so the results are never reused.
+func (r *RunRepository) UpdateResultKey(ctx context.Context, runID uuid.UUID, resultKey *string) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE scoring_runs
+		SET result_key = $1,
+		    updated_at = NOW()
+		WHERE id = $2
+		RETURNING id
+	`
+
+	var id uuid.UUID
+	err := r.pool.QueryRow(ctx, query, resultKey, runID).Scan(&id)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errors.New("scoring run not found")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// UpdateSkipped records how many sites a scoring run skipped and a sample
+// of them with their reasons
+func (r *RunRepository) UpdateSkipped(ctx context.Context, runID uuid.UUID, skippedCount int, sample []models.SkippedSite) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE scoring_runs
+		SET skipped_count = $1,
+		    skipped_sites = $2,
+		    updated_at = NOW()
+		WHERE id = $3
+		RETURNING id
+	`
+
+	var id uuid.UUID
+	err := r.pool.QueryRow(ctx, query, skippedCount, sample, runID).Scan(&id)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -297,6 +426,9 @@ func (r *RunRepository) Update(ctx context.Context, run *models.ScoringRun) erro
 
 // IncrementAttempt increments the attempt counter for a scoring run
 func (r *RunRepository) IncrementAttempt(ctx context.Context, runID uuid.UUID) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	query := `
 		UPDATE scoring_runs
 		SET attempt = attempt + 1,
@@ -317,3 +449,287 @@ func (r *RunRepository) IncrementAttempt(ctx context.Context, runID uuid.UUID) e
 
 	return nil
 }
+
+// ListStale returns runs in a non-terminal state ("queued" or "running") that
+// have not been updated within olderThan and are not owned by excludeInstance.
+// These are runs orphaned by a process that died before finishing them.
+func (r *RunRepository) ListStale(ctx context.Context, olderThan time.Duration, excludeInstance uuid.UUID) ([]models.ScoringRun, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		SELECT ` + runColumns + `
+		FROM scoring_runs
+		WHERE status IN ('queued', 'running')
+		  AND updated_at < NOW() - make_interval(secs => $1)
+		  AND instance_id <> $2
+		ORDER BY created_at ASC
+	`
+
+	// The cutoff is taken from the database clock, the same one that
+	// stamps updated_at, so clock skew between instances cannot make a
+	// live run look stale
+	rows, err := r.pool.Query(ctx, query, olderThan.Seconds(), excludeInstance)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []models.ScoringRun
+	for rows.Next() {
+		run := models.ScoringRun{}
+		err := scanRun(rows, &run)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
+
+// ClaimOrphan transfers ownership of a non-terminal run from one instance to
+// another and resets it to "queued". It returns false if the run was already
+// claimed by someone else or has since reached a terminal state.
+func (r *RunRepository) ClaimOrphan(ctx context.Context, runID, fromInstance, toInstance uuid.UUID) (bool, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE scoring_runs
+		SET status = 'queued',
+		    instance_id = $3,
+		    updated_at = NOW()
+		WHERE id = $1
+		  AND instance_id = $2
+		  AND status IN ('queued', 'running')
+	`
+
+	tag, err := r.pool.Exec(ctx, query, runID, fromInstance, toInstance)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// FailOrphan marks a non-terminal run still owned by fromInstance "failed"
+// with reason as its last error. It returns false if the run was claimed by
+// someone else or has since reached a terminal state, which is then left as
+// it is.
+func (r *RunRepository) FailOrphan(ctx context.Context, runID, fromInstance uuid.UUID, reason string) (bool, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE scoring_runs
+		SET status = 'failed',
+		    last_error = $3,
+		    completed_at = NOW(),
+		    updated_at = NOW()
+		WHERE id = $1
+		  AND instance_id = $2
+		  AND status IN ('queued', 'running')
+	`
+
+	tag, err := r.pool.Exec(ctx, query, runID, fromInstance, reason)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// Heartbeat refreshes updated_at on a non-terminal run owned by instanceID,
+// so a run still executing is never mistaken for an orphan by ListStale.
+func (r *RunRepository) Heartbeat(ctx context.Context, runID, instanceID uuid.UUID) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE scoring_runs
+		SET updated_at = NOW()
+		WHERE id = $1
+		  AND instance_id = $2
+		  AND status IN ('queued', 'running')
+	`
+
+	_, err := r.pool.Exec(ctx, query, runID, instanceID)
+	return err
+}
+
+// ClaimNext takes the oldest queued run off the queue for instanceID,
+// marking it "running" and returning it, or returns nil if no run is
+// queued. Rows locked by a concurrent claim are skipped rather than waited
+// on, so any number of workers on any number of instances can claim at once
+// and each run is handed to exactly one of them.
+func (r *RunRepository) ClaimNext(ctx context.Context, instanceID uuid.UUID) (*models.ScoringRun, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE scoring_runs
+		SET status = 'running',
+		    instance_id = $1,
+		    started_at = COALESCE(started_at, NOW()),
+		    updated_at = NOW()
+		WHERE id = (
+			SELECT id
+			FROM scoring_runs
+			WHERE status = 'queued'
+			ORDER BY created_at ASC
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + runColumns
+
+	run := &models.ScoringRun{}
+	err := scanRun(r.pool.QueryRow(ctx, query, instanceID), run)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return run, nil
+}
+
+// FailedRunFilter narrows ListFailed. Zero values match every failed run.
+type FailedRunFilter struct {
+	ErrorContains string    // case-insensitive substring of last_error
+	Since         time.Time // failed at or after
+	Until         time.Time // failed before
+}
+
+// ListFailed retrieves one page of a tenant's failed runs matching filter,
+// most recently failed first, along with the total number matching.
+func (r *RunRepository) ListFailed(
+	ctx context.Context,
+	tenantID uuid.UUID,
+	filter FailedRunFilter,
+	page int,
+	pageSize int,
+) ([]models.ScoringRun, int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = models.DefaultPageSize
+	}
+	offset := (page - 1) * pageSize
+
+	// Empty filter values are passed as NULL and match everything
+	where := `
+		WHERE tenant_id = $1
+		  AND status = 'failed'
+		  AND ($2::text IS NULL OR last_error ILIKE '%' || $2 || '%' ESCAPE '\')
+		  AND ($3::timestamptz IS NULL OR completed_at >= $3)
+		  AND ($4::timestamptz IS NULL OR completed_at < $4)
+	`
+	var pattern *string
+	if filter.ErrorContains != "" {
+		escaped := likeEscaper.Replace(filter.ErrorContains)
+		pattern = &escaped
+	}
+	var since, until *time.Time
+	if !filter.Since.IsZero() {
+		since = &filter.Since
+	}
+	if !filter.Until.IsZero() {
+		until = &filter.Until
+	}
+
+	var totalCount int
+	err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM scoring_runs`+where,
+		tenantID, pattern, since, until,
+	).Scan(&totalCount)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + runColumns + ` FROM scoring_runs` + where + `
+		ORDER BY completed_at DESC NULLS LAST, id DESC
+		LIMIT $5 OFFSET $6
+	`
+
+	rows, err := r.pool.Query(ctx, query, tenantID, pattern, since, until, pageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	runs := make([]models.ScoringRun, 0, pageSize)
+	for rows.Next() {
+		run := models.ScoringRun{}
+		if err := scanRun(rows, &run); err != nil {
+			return nil, 0, err
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, totalCount, rows.Err()
+}
+
+// likeEscaper escapes LIKE wildcards so a pattern matches literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Requeue resets a failed run to "queued" under instanceID, clearing its
+// attempt counter, error, stats and completion details, and deleting any
+// recommendations and skipped sites it stored, so it is retried from
+// scratch. The run's correlation ID becomes that of the requeue request,
+// whose logs it then shares. It returns false if the run does not exist for the tenant or is
+// not failed, e.g. because a concurrent requeue got there first.
+func (r *RunRepository) Requeue(ctx context.Context, tenantID, runID, instanceID uuid.UUID, correlationID string) (bool, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE scoring_runs
+		SET status = 'queued',
+		    attempt = 0,
+		    last_error = NULL,
+		    duration_ms = NULL,
+		    scored_count = NULL,
+		    stats = NULL,
+		    skipped_count = NULL,
+		    skipped_sites = NULL,
+		    completed_at = NULL,
+		    instance_id = $3,
+		    correlation_id = $4,
+		    updated_at = NOW()
+		WHERE id = $1
+		  AND tenant_id = $2
+		  AND status = 'failed'
+	`
+
+	tag, err := tx.Exec(ctx, query, runID, tenantID, instanceID, correlationID)
+	if err != nil {
+		return false, err
+	}
+	if tag.RowsAffected() != 1 {
+		return false, nil
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM recommendations WHERE run_id = $1`, runID); err != nil {
+		return false, err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM run_skipped_sites WHERE run_id = $1`, runID); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit(ctx)
+}
@@ -0,0 +1,474 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+)
+
+func TestRunRepository_ListStale(t *testing.T) {
+	pool := testPool(t)
+	repo := NewRunRepository(pool, testQueryTimeout)
+	ctx := context.Background()
+
+	tenantID := createTestTenant(t, pool)
+	upload := createTestUpload(t, pool, tenantID)
+
+	deadInstance := uuid.New()
+	liveInstance := uuid.New()
+	old := time.Now().Add(-time.Hour)
+
+	orphanRunning := createTestRun(t, pool, upload, "running", deadInstance, old)
+	orphanQueued := createTestRun(t, pool, upload, "queued", deadInstance, old)
+	createTestRun(t, pool, upload, "succeeded", deadInstance, old)      // terminal
+	createTestRun(t, pool, upload, "running", liveInstance, old)        // owned by us
+	createTestRun(t, pool, upload, "running", deadInstance, time.Now()) // too recent
+
+	stale, err := repo.ListStale(ctx, 10*time.Minute, liveInstance)
+	require.NoError(t, err)
+
+	var ids []uuid.UUID
+	for _, run := range stale {
+		if run.TenantID == tenantID {
+			ids = append(ids, run.ID)
+		}
+	}
+	assert.ElementsMatch(t, []uuid.UUID{orphanRunning.ID, orphanQueued.ID}, ids)
+}
+
+func TestRunRepository_ClaimOrphan(t *testing.T) {
+	pool := testPool(t)
+	repo := NewRunRepository(pool, testQueryTimeout)
+	ctx := context.Background()
+
+	tenantID := createTestTenant(t, pool)
+	upload := createTestUpload(t, pool, tenantID)
+
+	deadInstance := uuid.New()
+	run := createTestRun(t, pool, upload, "running", deadInstance, time.Now().Add(-time.Hour))
+
+	first, second := uuid.New(), uuid.New()
+
+	claimed, err := repo.ClaimOrphan(ctx, run.ID, deadInstance, first)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+
+	// A second instance racing for the same run loses
+	claimed, err = repo.ClaimOrphan(ctx, run.ID, deadInstance, second)
+	require.NoError(t, err)
+	assert.False(t, claimed)
+
+	got, err := repo.GetByID(ctx, tenantID, run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "queued", got.Status)
+	assert.Equal(t, first, got.InstanceID)
+}
+
+func TestRunRepository_FailOrphan(t *testing.T) {
+	pool := testPool(t)
+	repo := NewRunRepository(pool, testQueryTimeout)
+	ctx := context.Background()
+
+	tenantID := createTestTenant(t, pool)
+	upload := createTestUpload(t, pool, tenantID)
+
+	deadInstance := uuid.New()
+	run := createTestRun(t, pool, upload, "running", deadInstance, time.Now().Add(-time.Hour))
+	finished := createTestRun(t, pool, upload, "succeeded", deadInstance, time.Now().Add(-time.Hour))
+	claimed := createTestRun(t, pool, upload, "running", deadInstance, time.Now().Add(-time.Hour))
+	ok, err := repo.ClaimOrphan(ctx, claimed.ID, deadInstance, uuid.New())
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	failed, err := repo.FailOrphan(ctx, run.ID, deadInstance, "orphaned")
+	require.NoError(t, err)
+	assert.True(t, failed)
+	got, err := repo.GetByID(ctx, tenantID, run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "failed", got.Status)
+	require.NotNil(t, got.LastError)
+	assert.Equal(t, "orphaned", *got.LastError)
+	assert.NotNil(t, got.CompletedAt)
+
+	// A run that finished, or was claimed by a live instance, is left alone
+	for _, id := range []uuid.UUID{finished.ID, claimed.ID} {
+		failed, err = repo.FailOrphan(ctx, id, deadInstance, "orphaned")
+		require.NoError(t, err)
+		assert.False(t, failed)
+	}
+	got, err = repo.GetByID(ctx, tenantID, finished.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "succeeded", got.Status)
+}
+
+func TestRunRepository_Heartbeat(t *testing.T) {
+	pool := testPool(t)
+	repo := NewRunRepository(pool, testQueryTimeout)
+	ctx := context.Background()
+
+	tenantID := createTestTenant(t, pool)
+	upload := createTestUpload(t, pool, tenantID)
+
+	instance := uuid.New()
+	run := createTestRun(t, pool, upload, "running", instance, time.Now().Add(-time.Hour))
+
+	require.NoError(t, repo.Heartbeat(ctx, run.ID, instance))
+
+	stale, err := repo.ListStale(ctx, 10*time.Minute, uuid.New())
+	require.NoError(t, err)
+	for _, s := range stale {
+		assert.NotEqual(t, run.ID, s.ID, "a run with a fresh heartbeat is not stale")
+	}
+}
+
+func TestRunRepository_ClaimNextKeepsCorrelationID(t *testing.T) {
+	pool := testPool(t)
+	repo := NewRunRepository(pool, testQueryTimeout)
+	ctx := context.Background()
+
+	tenantID := createTestTenant(t, pool)
+	upload := createTestUpload(t, pool, tenantID)
+	run := &models.ScoringRun{
+		ID:            uuid.New(),
+		UploadID:      upload.ID,
+		TenantID:      tenantID,
+		Status:        "queued",
+		ModelVersion:  "site-selection-iq-v1.0",
+		InstanceID:    uuid.New(),
+		TransactionID: uuid.New(),
+		CorrelationID: "create-req",
+		CreatedAt:     time.Now().Add(-24 * time.Hour), // ahead of other tests' queued runs
+		UpdatedAt:     time.Now(),
+	}
+	require.NoError(t, repo.Create(ctx, run))
+
+	// A worker on another instance claims the run from the database alone
+	claimed, err := repo.ClaimNext(ctx, uuid.New())
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+	assert.Equal(t, run.ID, claimed.ID)
+	assert.Equal(t, "create-req", claimed.CorrelationID)
+}
+
+func TestRunRepository_ClaimNextConcurrent(t *testing.T) {
+	pool := testPool(t)
+	repo := NewRunRepository(pool, testQueryTimeout)
+	ctx := context.Background()
+
+	tenantID := createTestTenant(t, pool)
+	upload := createTestUpload(t, pool, tenantID)
+	creator := uuid.New()
+
+	queued := make(map[uuid.UUID]bool)
+	for i := 0; i < 20; i++ {
+		run := createTestRun(t, pool, upload, "queued", creator, time.Now())
+		queued[run.ID] = true
+	}
+	createTestRun(t, pool, upload, "running", creator, time.Now()) // not queued
+
+	// Eight workers race to drain the queue; every run goes to exactly one
+	var (
+		mu      sync.Mutex
+		claims  = make(map[uuid.UUID]int)
+		owners  = make(map[uuid.UUID]uuid.UUID)
+		wg      sync.WaitGroup
+		claimed []error
+	)
+	for w := 0; w < 8; w++ {
+		worker := uuid.New()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				run, err := repo.ClaimNext(ctx, worker)
+				if err != nil || run == nil {
+					mu.Lock()
+					claimed = append(claimed, err)
+					mu.Unlock()
+					return
+				}
+				mu.Lock()
+				claims[run.ID]++
+				owners[run.ID] = run.InstanceID
+				mu.Unlock()
+				assert.Equal(t, "running", run.Status)
+				assert.NotNil(t, run.StartedAt)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range claimed {
+		require.NoError(t, err)
+	}
+	for id := range queued {
+		assert.Equal(t, 1, claims[id], "run %s claimed %d times", id, claims[id])
+		assert.NotEqual(t, creator, owners[id], "the claiming worker owns the run")
+	}
+
+	got, err := repo.ClaimNext(ctx, uuid.New())
+	require.NoError(t, err)
+	assert.Nil(t, got, "an empty queue yields no run")
+}
+
+func TestRunRepository_GetLatestByUpload(t *testing.T) {
+	pool := testPool(t)
+	repo := NewRunRepository(pool, testQueryTimeout)
+	ctx := context.Background()
+
+	tenantID := createTestTenant(t, pool)
+	upload := createTestUpload(t, pool, tenantID)
+	instanceID := uuid.New()
+
+	latest, err := repo.GetLatestByUpload(ctx, tenantID, upload.ID)
+	require.NoError(t, err)
+	assert.Nil(t, latest, "no runs yet")
+
+	createTestRun(t, pool, upload, "succeeded", instanceID, time.Now().Add(-2*time.Hour))
+	newest := createTestRun(t, pool, upload, "succeeded", instanceID, time.Now().Add(-time.Hour))
+	createTestRun(t, pool, upload, "failed", instanceID, time.Now()) // not completed successfully
+
+	latest, err = repo.GetLatestByUpload(ctx, tenantID, upload.ID)
+	require.NoError(t, err)
+	require.NotNil(t, latest)
+	assert.Equal(t, newest.ID, latest.ID)
+
+	// Scoped to the tenant
+	latest, err = repo.GetLatestByUpload(ctx, uuid.New(), upload.ID)
+	require.NoError(t, err)
+	assert.Nil(t, latest)
+}
+
+func TestRunRepository_GetCompletedByResultKey(t *testing.T) {
+	pool := testPool(t)
+	repo := NewRunRepository(pool, testQueryTimeout)
+	ctx := context.Background()
+
+	tenantID := createTestTenant(t, pool)
+	upload := createTestUpload(t, pool, tenantID)
+	other := createTestUpload(t, pool, tenantID)
+
+	withKey := func(upload *models.Upload, status, key string) *models.ScoringRun {
+		run := &models.ScoringRun{
+			ID:            uuid.New(),
+			UploadID:      upload.ID,
+			TenantID:      upload.TenantID,
+			Status:        status,
+			ModelVersion:  "site-selection-iq-v1.0",
+			InstanceID:    uuid.New(),
+			TransactionID: uuid.New(),
+			CreatedAt:     time.Now(),
+			UpdatedAt:     time.Now(),
+			ResultKey:     &key,
+		}
+		require.NoError(t, repo.Create(ctx, run))
+		return run
+	}
+
+	prior := withKey(upload, "succeeded", "key-a")
+	withKey(upload, "failed", "key-b")
+	withKey(other, "succeeded", "key-c")
+	partial := withKey(upload, "completed_with_errors", "key-d")
+
+	got, err := repo.GetCompletedByResultKey(ctx, tenantID, upload.ID, "key-a")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, prior.ID, got.ID)
+	require.NotNil(t, got.ResultKey)
+	assert.Equal(t, "key-a", *got.ResultKey)
+
+	// Skipped sites are skipped again on an identical run, so its results stand
+	got, err = repo.GetCompletedByResultKey(ctx, tenantID, upload.ID, "key-d")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, partial.ID, got.ID)
+
+	for name, lookup := range map[string]struct {
+		tenantID, uploadID uuid.UUID
+		key                string
+	}{
+		"failed run":     {tenantID, upload.ID, "key-b"},
+		"other upload":   {tenantID, upload.ID, "key-c"},
+		"unknown key":    {tenantID, upload.ID, "key-z"},
+		"another tenant": {uuid.New(), upload.ID, "key-a"},
+	} {
+		got, err := repo.GetCompletedByResultKey(ctx, lookup.tenantID, lookup.uploadID, lookup.key)
+		require.NoError(t, err, name)
+		assert.Nil(t, got, name)
+	}
+}
+
+func TestRunRepository_UpdateResultKey(t *testing.T) {
+	pool := testPool(t)
+	repo := NewRunRepository(pool, testQueryTimeout)
+	ctx := context.Background()
+
+	tenantID := createTestTenant(t, pool)
+	upload := createTestUpload(t, pool, tenantID)
+	run := createTestRun(t, pool, upload, "succeeded", uuid.New(), time.Now())
+	key := "key-" + run.ID.String()
+
+	require.NoError(t, repo.UpdateResultKey(ctx, run.ID, &key))
+	got, err := repo.GetCompletedByResultKey(ctx, tenantID, upload.ID, key)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, run.ID, got.ID)
+
+	// Clearing the key stops the run's results from being reused
+	require.NoError(t, repo.UpdateResultKey(ctx, run.ID, nil))
+	got, err = repo.GetCompletedByResultKey(ctx, tenantID, upload.ID, key)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	assert.Error(t, repo.UpdateResultKey(ctx, uuid.New(), &key))
+}
+
+func TestRunRepository_CountByUpload(t *testing.T) {
+	pool := testPool(t)
+	repo := NewRunRepository(pool, testQueryTimeout)
+	ctx := context.Background()
+
+	tenantID := createTestTenant(t, pool)
+	upload := createTestUpload(t, pool, tenantID)
+
+	count, err := repo.CountByUpload(ctx, tenantID, upload.ID)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	createTestRun(t, pool, upload, "succeeded", uuid.New(), time.Now())
+	createTestRun(t, pool, upload, "failed", uuid.New(), time.Now())
+
+	count, err = repo.CountByUpload(ctx, tenantID, upload.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count, "runs in any status count")
+
+	count, err = repo.CountByUpload(ctx, uuid.New(), upload.ID)
+	require.NoError(t, err)
+	assert.Zero(t, count, "scoped to the tenant")
+}
+
+// createFailedRun stores a run that exhausted its retries at failedAt.
+func createFailedRun(t *testing.T, repo *RunRepository, upload *models.Upload, lastError string, failedAt time.Time) *models.ScoringRun {
+	t.Helper()
+	run := createTestRun(t, repo.pool, upload, "failed", uuid.New(), failedAt)
+	durationMs := 1200
+	run.Attempt = 3
+	run.LastError = &lastError
+	run.DurationMs = &durationMs
+	run.StartedAt = &failedAt
+	run.CompletedAt = &failedAt
+	require.NoError(t, repo.Update(context.Background(), run))
+	return run
+}
+
+func TestRunRepository_ListFailed(t *testing.T) {
+	pool := testPool(t)
+	repo := NewRunRepository(pool, testQueryTimeout)
+	ctx := context.Background()
+
+	tenantID := createTestTenant(t, pool)
+	upload := createTestUpload(t, pool, tenantID)
+
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	timeout := createFailedRun(t, repo, upload, "scoring timed out after 5m", day.Add(2*time.Hour))
+	parse := createFailedRun(t, repo, upload, "parse error: column 100%_share", day.Add(26*time.Hour))
+	older := createFailedRun(t, repo, upload, "connection reset", day.Add(-time.Hour))
+	createTestRun(t, pool, upload, "succeeded", uuid.New(), day)
+
+	otherUpload := createTestUpload(t, pool, createTestTenant(t, pool))
+	createFailedRun(t, repo, otherUpload, "scoring timed out after 5m", day)
+
+	ids := func(runs []models.ScoringRun) []uuid.UUID {
+		out := make([]uuid.UUID, len(runs))
+		for i, run := range runs {
+			out[i] = run.ID
+		}
+		return out
+	}
+
+	runs, total, err := repo.ListFailed(ctx, tenantID, FailedRunFilter{}, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, []uuid.UUID{parse.ID, timeout.ID, older.ID}, ids(runs), "newest failure first, tenant-scoped")
+
+	runs, total, err = repo.ListFailed(ctx, tenantID, FailedRunFilter{}, 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, []uuid.UUID{older.ID}, ids(runs))
+
+	runs, _, err = repo.ListFailed(ctx, tenantID, FailedRunFilter{ErrorContains: "TIMED OUT"}, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{timeout.ID}, ids(runs), "error match is case-insensitive")
+
+	runs, _, err = repo.ListFailed(ctx, tenantID, FailedRunFilter{ErrorContains: "%_"}, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{parse.ID}, ids(runs), "wildcards match literally")
+
+	runs, _, err = repo.ListFailed(ctx, tenantID, FailedRunFilter{Since: day, Until: day.Add(24 * time.Hour)}, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{timeout.ID}, ids(runs))
+}
+
+func TestRunRepository_Requeue(t *testing.T) {
+	pool := testPool(t)
+	repo := NewRunRepository(pool, testQueryTimeout)
+	ctx := context.Background()
+
+	tenantID := createTestTenant(t, pool)
+	upload := createTestUpload(t, pool, tenantID)
+
+	run := createFailedRun(t, repo, upload, "scoring timed out after 5m", time.Now().Add(-time.Hour))
+	instance := uuid.New()
+
+	// Results stored by the failed attempt are dropped with it
+	recRepo := NewRecommendationRepository(pool, testQueryTimeout)
+	insertTestRecommendations(t, recRepo, run, 80, 60)
+	skippedRepo := NewSkippedSiteRepository(pool, testQueryTimeout)
+	require.NoError(t, skippedRepo.BulkInsert(ctx, []models.RunSkippedSite{
+		{RunID: run.ID, TenantID: tenantID, Position: 3, SiteID: "S3", Reason: "bad value", CreatedAt: time.Now()},
+	}))
+	require.NoError(t, repo.UpdateStats(ctx, run.ID, &models.RunStats{Min: 60, Max: 80, Mean: 70, Median: 70}))
+
+	requeued, err := repo.Requeue(ctx, uuid.New(), run.ID, instance, "requeue-req")
+	require.NoError(t, err)
+	assert.False(t, requeued, "scoped to the tenant")
+
+	requeued, err = repo.Requeue(ctx, tenantID, run.ID, instance, "requeue-req")
+	require.NoError(t, err)
+	assert.True(t, requeued)
+
+	got, err := repo.GetByID(ctx, tenantID, run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "queued", got.Status)
+	assert.Equal(t, instance, got.InstanceID)
+	assert.Zero(t, got.Attempt)
+	assert.Nil(t, got.LastError)
+	assert.Nil(t, got.CompletedAt)
+	assert.Nil(t, got.DurationMs)
+	assert.Nil(t, got.Stats)
+	assert.Equal(t, "requeue-req", got.CorrelationID, "the run is traced to the requeue request")
+
+	recs, err := recRepo.TopN(ctx, run.ID, 10, false)
+	require.NoError(t, err)
+	assert.Empty(t, recs)
+	_, total, err := skippedRepo.ListByRun(ctx, run.ID, 1, 10)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+
+	// Only failed runs can be requeued, so a second request is a no-op
+	requeued, err = repo.Requeue(ctx, tenantID, run.ID, uuid.New(), "")
+	require.NoError(t, err)
+	assert.False(t, requeued)
+
+	succeeded := createTestRun(t, pool, upload, "succeeded", uuid.New(), time.Now())
+	requeued, err = repo.Requeue(ctx, tenantID, succeeded.ID, instance, "")
+	require.NoError(t, err)
+	assert.False(t, requeued)
+}
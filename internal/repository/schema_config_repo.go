@@ -3,25 +3,31 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/workforce-ai/site-selection-iq/internal/models"
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
 )
 
 // SchemaConfigRepository handles data access for schema configuration records
 type SchemaConfigRepository struct {
-	pool *pgxpool.Pool
+	pool         *pgxpool.Pool
+	queryTimeout time.Duration
 }
 
 // NewSchemaConfigRepository creates a new schema config repository
-func NewSchemaConfigRepository(pool *pgxpool.Pool) *SchemaConfigRepository {
-	return &SchemaConfigRepository{pool: pool}
+func NewSchemaConfigRepository(pool *pgxpool.Pool, queryTimeout time.Duration) *SchemaConfigRepository {
+	return &SchemaConfigRepository{pool: pool, queryTimeout: queryTimeout}
 }
 
 // GetGlobalActive retrieves the currently active global schema configuration
 func (r *SchemaConfigRepository) GetGlobalActive(ctx context.Context) (*models.SchemaConfig, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	query := `
 		SELECT id, tenant_id, version, config, schema_definition, description,
 		       is_active, created_at, updated_at
@@ -56,6 +62,9 @@ func (r *SchemaConfigRepository) GetGlobalActive(ctx context.Context) (*models.S
 
 // GetTenantActive retrieves the currently active schema configuration for a specific tenant
 func (r *SchemaConfigRepository) GetTenantActive(ctx context.Context, tenantID uuid.UUID) (*models.SchemaConfig, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	query := `
 		SELECT id, tenant_id, version, config, schema_definition, description,
 		       is_active, created_at, updated_at
@@ -88,8 +97,112 @@ func (r *SchemaConfigRepository) GetTenantActive(ctx context.Context, tenantID u
 	return config, nil
 }
 
+// GetActiveVersions reports the ID and last update of the active global
+// schema configuration and of the tenant's active override, in one query
+// that loads neither config. All fields are zero when no global
+// configuration is active.
+func (r *SchemaConfigRepository) GetActiveVersions(ctx context.Context, tenantID uuid.UUID) (schema.ConfigVersions, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		SELECT g.id, g.updated_at, t.id, t.updated_at
+		FROM (
+			SELECT id, updated_at FROM schema_configs
+			WHERE tenant_id IS NULL AND is_active = true
+			ORDER BY version DESC
+			LIMIT 1
+		) g
+		LEFT JOIN (
+			SELECT id, updated_at FROM schema_configs
+			WHERE tenant_id = $1 AND is_active = true
+			ORDER BY version DESC
+			LIMIT 1
+		) t ON true
+	`
+
+	var versions schema.ConfigVersions
+	var overrideID *uuid.UUID
+	var overrideUpdatedAt *time.Time
+	err := r.pool.QueryRow(ctx, query, tenantID).Scan(
+		&versions.GlobalID,
+		&versions.GlobalUpdatedAt,
+		&overrideID,
+		&overrideUpdatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return schema.ConfigVersions{}, nil
+		}
+		return schema.ConfigVersions{}, err
+	}
+	if overrideID != nil {
+		versions.TenantID = *overrideID
+		versions.TenantUpdatedAt = *overrideUpdatedAt
+	}
+
+	return versions, nil
+}
+
+// UpsertTenant stores config as the tenant's new active schema override.
+// The previous active override is deactivated and kept for history; versions
+// are numbered v1, v2, ... per tenant.
+func (r *SchemaConfigRepository) UpsertTenant(ctx context.Context, tenantID uuid.UUID, config []byte, description string) (*models.SchemaConfig, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		UPDATE schema_configs
+		SET is_active = false, updated_at = NOW()
+		WHERE tenant_id = $1 AND is_active = true
+	`, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO schema_configs (tenant_id, version, config, description, is_active)
+		SELECT $1, 'v' || (COUNT(*) + 1), $2, $3, true
+		FROM schema_configs
+		WHERE tenant_id = $1
+		RETURNING id, tenant_id, version, config, schema_definition, description,
+		          is_active, created_at, updated_at
+	`
+
+	saved := &models.SchemaConfig{}
+	err = tx.QueryRow(ctx, query, tenantID, config, description).Scan(
+		&saved.ID,
+		&saved.TenantID,
+		&saved.Version,
+		&saved.Config,
+		&saved.SchemaDefinition,
+		&saved.Description,
+		&saved.IsActive,
+		&saved.CreatedAt,
+		&saved.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
 // CreateSnapshot creates a new schema configuration snapshot
 func (r *SchemaConfigRepository) CreateSnapshot(ctx context.Context, snapshot *models.SchemaConfigSnapshot) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	if snapshot == nil {
 		return errors.New("schema config snapshot cannot be nil")
 	}
@@ -134,6 +247,9 @@ func (r *SchemaConfigRepository) CreateSnapshot(ctx context.Context, snapshot *m
 
 // GetSnapshot retrieves a specific schema configuration snapshot by ID
 func (r *SchemaConfigRepository) GetSnapshot(ctx context.Context, snapshotID uuid.UUID) (*models.SchemaConfigSnapshot, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	query := `
 		SELECT id, run_id, schema_config_id, upload_id, config, snapshot_data,
 		       created_at
@@ -161,3 +277,39 @@ func (r *SchemaConfigRepository) GetSnapshot(ctx context.Context, snapshotID uui
 
 	return snapshot, nil
 }
+
+// GetSnapshotByRun retrieves the most recent schema configuration snapshot
+// taken for a run (a retried run takes one snapshot per attempt)
+func (r *SchemaConfigRepository) GetSnapshotByRun(ctx context.Context, runID uuid.UUID) (*models.SchemaConfigSnapshot, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		SELECT id, run_id, schema_config_id, upload_id, config, snapshot_data,
+		       created_at
+		FROM schema_config_snapshots
+		WHERE run_id = $1
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	snapshot := &models.SchemaConfigSnapshot{}
+	err := r.pool.QueryRow(ctx, query, runID).Scan(
+		&snapshot.ID,
+		&snapshot.RunID,
+		&snapshot.SchemaConfigID,
+		&snapshot.UploadID,
+		&snapshot.Config,
+		&snapshot.SnapshotData,
+		&snapshot.CreatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return snapshot, nil
+}
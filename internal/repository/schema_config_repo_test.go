@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaConfigRepository_UpsertTenant(t *testing.T) {
+	pool := testPool(t)
+	repo := NewSchemaConfigRepository(pool, testQueryTimeout)
+	tenantID := createTestTenant(t, pool)
+	ctx := context.Background()
+
+	first, err := repo.UpsertTenant(ctx, tenantID, []byte(`{"weights": {"unemployment_rate": 0.5}}`), "first")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", first.Version)
+	assert.True(t, first.IsActive)
+
+	second, err := repo.UpsertTenant(ctx, tenantID, []byte(`{"weights": {"unemployment_rate": 0.7}}`), "second")
+	require.NoError(t, err)
+	assert.Equal(t, "v2", second.Version)
+
+	// Only the newest override is active
+	active, err := repo.GetTenantActive(ctx, tenantID)
+	require.NoError(t, err)
+	require.NotNil(t, active)
+	assert.Equal(t, second.ID, active.ID)
+	assert.JSONEq(t, `{"weights": {"unemployment_rate": 0.7}}`, string(active.Config))
+
+	var activeCount int
+	require.NoError(t, pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM schema_configs WHERE tenant_id = $1 AND is_active`, tenantID).Scan(&activeCount))
+	assert.Equal(t, 1, activeCount)
+}
+
+func TestSchemaConfigRepository_GetActiveVersions(t *testing.T) {
+	pool := testPool(t)
+	repo := NewSchemaConfigRepository(pool, testQueryTimeout)
+	tenantID := createTestTenant(t, pool)
+	ctx := context.Background()
+
+	global, err := repo.GetGlobalActive(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, global, "migrations seed a global config")
+
+	versions, err := repo.GetActiveVersions(ctx, tenantID)
+	require.NoError(t, err)
+	assert.Equal(t, global.ID, versions.GlobalID)
+	assert.True(t, global.UpdatedAt.Equal(versions.GlobalUpdatedAt))
+	assert.Equal(t, uuid.Nil, versions.TenantID, "no override yet")
+
+	override, err := repo.UpsertTenant(ctx, tenantID, []byte(`{"weights": {"unemployment_rate": 0.5}}`), "first")
+	require.NoError(t, err)
+	versions, err = repo.GetActiveVersions(ctx, tenantID)
+	require.NoError(t, err)
+	assert.Equal(t, global.ID, versions.GlobalID)
+	assert.Equal(t, override.ID, versions.TenantID)
+	assert.True(t, override.UpdatedAt.Equal(versions.TenantUpdatedAt))
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+)
+
+// serviceTokenColumns is the column list shared by every query that returns
+// a full service token. It must stay in sync with scanServiceToken.
+const serviceTokenColumns = `id, tenant_id, name, role, scopes, created_by, created_at, expires_at, revoked_at`
+
+// scanServiceToken scans a row selected with serviceTokenColumns into token.
+func scanServiceToken(row pgx.Row, token *models.ServiceToken) error {
+	return row.Scan(
+		&token.ID,
+		&token.TenantID,
+		&token.Name,
+		&token.Role,
+		&token.Scopes,
+		&token.CreatedBy,
+		&token.CreatedAt,
+		&token.ExpiresAt,
+		&token.RevokedAt,
+	)
+}
+
+// ServiceTokenRepository handles data access for minted service tokens
+type ServiceTokenRepository struct {
+	pool         *pgxpool.Pool
+	queryTimeout time.Duration
+}
+
+// NewServiceTokenRepository creates a new service token repository
+func NewServiceTokenRepository(pool *pgxpool.Pool, queryTimeout time.Duration) *ServiceTokenRepository {
+	return &ServiceTokenRepository{pool: pool, queryTimeout: queryTimeout}
+}
+
+// CreateBatch records tokens in one transaction, so either all of them are
+// stored or none is. CreatedAt is filled in when unset.
+func (r *ServiceTokenRepository) CreateBatch(ctx context.Context, tokens []*models.ServiceToken) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO service_tokens (id, tenant_id, name, role, scopes, created_by, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	for _, token := range tokens {
+		if token == nil {
+			return errors.New("service token cannot be nil")
+		}
+		if token.CreatedAt.IsZero() {
+			token.CreatedAt = time.Now()
+		}
+		if token.Scopes == nil {
+			token.Scopes = []string{}
+		}
+
+		_, err := tx.Exec(ctx, query,
+			token.ID,
+			token.TenantID,
+			token.Name,
+			token.Role,
+			token.Scopes,
+			token.CreatedBy,
+			token.CreatedAt,
+			token.ExpiresAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ListByTenant retrieves all of a tenant's service tokens, revoked and
+// expired ones included, newest first
+func (r *ServiceTokenRepository) ListByTenant(ctx context.Context, tenantID uuid.UUID) ([]models.ServiceToken, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `SELECT ` + serviceTokenColumns + ` FROM service_tokens WHERE tenant_id = $1 ORDER BY created_at DESC, id ASC`
+
+	rows, err := r.pool.Query(ctx, query, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := []models.ServiceToken{}
+	for rows.Next() {
+		token := models.ServiceToken{}
+		if err := scanServiceToken(rows, &token); err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// Revoke marks the tenant's service token revoked and returns it, or nil if
+// the tenant has no such token. Revoking a revoked token keeps its original
+// revocation time.
+func (r *ServiceTokenRepository) Revoke(ctx context.Context, tenantID, id uuid.UUID) (*models.ServiceToken, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE service_tokens
+		SET revoked_at = COALESCE(revoked_at, NOW())
+		WHERE tenant_id = $1 AND id = $2
+		RETURNING ` + serviceTokenColumns
+
+	token := &models.ServiceToken{}
+	err := scanServiceToken(r.pool.QueryRow(ctx, query, tenantID, id), token)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+// IsRevoked reports whether the service token with the given jti may no
+// longer be used: it was revoked, or it was never recorded.
+func (r *ServiceTokenRepository) IsRevoked(ctx context.Context, id uuid.UUID) (bool, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var revoked bool
+	err := r.pool.QueryRow(ctx,
+		`SELECT revoked_at IS NOT NULL FROM service_tokens WHERE id = $1`,
+		id,
+	).Scan(&revoked)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return true, nil
+		}
+		return false, err
+	}
+	return revoked, nil
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+)
+
+func TestServiceTokenRepository_CreateAndList(t *testing.T) {
+	pool := testPool(t)
+	repo := NewServiceTokenRepository(pool, testQueryTimeout)
+	tenantID := createTestTenant(t, pool)
+	otherTenantID := createTestTenant(t, pool)
+	ctx := context.Background()
+
+	adminID := uuid.New()
+	now := time.Now().Truncate(time.Second)
+	older := &models.ServiceToken{
+		ID:        uuid.New(),
+		TenantID:  tenantID,
+		Name:      "nightly export",
+		Role:      "viewer",
+		CreatedBy: adminID,
+		CreatedAt: now.Add(-time.Hour),
+		ExpiresAt: now.Add(90 * 24 * time.Hour),
+	}
+	newer := &models.ServiceToken{
+		ID:        uuid.New(),
+		TenantID:  tenantID,
+		Name:      "ingest bot",
+		Role:      "analyst",
+		Scopes:    []string{"uploads:write", "runs:write"},
+		CreatedBy: adminID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(365 * 24 * time.Hour),
+	}
+	require.NoError(t, repo.CreateBatch(ctx, []*models.ServiceToken{older, newer}))
+	require.NoError(t, repo.CreateBatch(ctx, []*models.ServiceToken{{
+		ID:        uuid.New(),
+		TenantID:  otherTenantID,
+		Name:      "other tenant",
+		Role:      "viewer",
+		CreatedBy: uuid.New(),
+		ExpiresAt: now.Add(time.Hour),
+	}}))
+
+	tokens, err := repo.ListByTenant(ctx, tenantID)
+	require.NoError(t, err)
+	require.Len(t, tokens, 2, "other tenants' tokens are not listed")
+	assert.Equal(t, newer.ID, tokens[0].ID, "newest first")
+	assert.Equal(t, "ingest bot", tokens[0].Name)
+	assert.Equal(t, []string{"uploads:write", "runs:write"}, tokens[0].Scopes)
+	assert.Equal(t, adminID, tokens[0].CreatedBy)
+	assert.True(t, newer.ExpiresAt.Equal(tokens[0].ExpiresAt))
+	assert.Nil(t, tokens[0].RevokedAt)
+	assert.Equal(t, older.ID, tokens[1].ID)
+	assert.Equal(t, []string{}, tokens[1].Scopes)
+
+	// A batch with a duplicate ID stores nothing
+	duplicate := &models.ServiceToken{ID: uuid.New(), TenantID: tenantID, Name: "dup", Role: "viewer", CreatedBy: adminID, ExpiresAt: now}
+	require.Error(t, repo.CreateBatch(ctx, []*models.ServiceToken{duplicate, duplicate}))
+	tokens, err = repo.ListByTenant(ctx, tenantID)
+	require.NoError(t, err)
+	assert.Len(t, tokens, 2)
+}
+
+func TestServiceTokenRepository_Revoke(t *testing.T) {
+	pool := testPool(t)
+	repo := NewServiceTokenRepository(pool, testQueryTimeout)
+	tenantID := createTestTenant(t, pool)
+	ctx := context.Background()
+
+	token := &models.ServiceToken{
+		ID:        uuid.New(),
+		TenantID:  tenantID,
+		Name:      "ingest bot",
+		Role:      "analyst",
+		CreatedBy: uuid.New(),
+		ExpiresAt: time.Now().Add(24 * time.Hour),
+	}
+	require.NoError(t, repo.CreateBatch(ctx, []*models.ServiceToken{token}))
+
+	revoked, err := repo.IsRevoked(ctx, token.ID)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	// Another tenant cannot revoke it
+	got, err := repo.Revoke(ctx, createTestTenant(t, pool), token.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	got, err = repo.Revoke(ctx, tenantID, token.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	require.NotNil(t, got.RevokedAt)
+	firstRevokedAt := *got.RevokedAt
+
+	revoked, err = repo.IsRevoked(ctx, token.ID)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	got, err = repo.Revoke(ctx, tenantID, token.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.True(t, firstRevokedAt.Equal(*got.RevokedAt), "revoking again keeps the first revocation time")
+
+	revoked, err = repo.IsRevoked(ctx, uuid.New())
+	require.NoError(t, err)
+	assert.True(t, revoked, "unrecorded tokens are treated as revoked")
+}
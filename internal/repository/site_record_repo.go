@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -11,20 +12,39 @@ import (
 
 // SiteRecordRepository handles data access for site records parsed from CSV uploads
 type SiteRecordRepository struct {
-	pool *pgxpool.Pool
+	pool         *pgxpool.Pool
+	queryTimeout time.Duration
 }
 
 // NewSiteRecordRepository creates a new site record repository
-func NewSiteRecordRepository(pool *pgxpool.Pool) *SiteRecordRepository {
-	return &SiteRecordRepository{pool: pool}
+func NewSiteRecordRepository(pool *pgxpool.Pool, queryTimeout time.Duration) *SiteRecordRepository {
+	return &SiteRecordRepository{pool: pool, queryTimeout: queryTimeout}
 }
 
 // BulkInsert performs a batch insert of site records using parameterized queries
 func (r *SiteRecordRepository) BulkInsert(ctx context.Context, records []models.SiteRecord) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	if len(records) == 0 {
 		return nil
 	}
 
+	results := r.pool.SendBatch(ctx, siteRecordBatch(records))
+	defer results.Close()
+
+	for i := 0; i < len(records); i++ {
+		_, err := results.Exec()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// siteRecordBatch queues one insert per record.
+func siteRecordBatch(records []models.SiteRecord) *pgx.Batch {
 	batch := &pgx.Batch{}
 
 	query := `
@@ -53,21 +73,14 @@ func (r *SiteRecordRepository) BulkInsert(ctx context.Context, records []models.
 		)
 	}
 
-	results := r.pool.SendBatch(ctx, batch)
-	defer results.Close()
-
-	for i := 0; i < len(records); i++ {
-		_, err := results.Exec()
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return batch
 }
 
 // GetByUpload retrieves all site records for a given upload
 func (r *SiteRecordRepository) GetByUpload(ctx context.Context, uploadID uuid.UUID) ([]models.SiteRecord, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	query := `
 		SELECT id, upload_id, tenant_id, site_id, site_name, location,
 		       latitude, longitude, raw_data, data, created_at
@@ -111,8 +124,86 @@ func (r *SiteRecordRepository) GetByUpload(ctx context.Context, uploadID uuid.UU
 	return records, nil
 }
 
+// GetByUploadPaginated retrieves one page of an upload's site records,
+// scoped to the tenant, along with the upload's total record count.
+// Records are ordered by site_id so pages are stable.
+func (r *SiteRecordRepository) GetByUploadPaginated(
+	ctx context.Context,
+	tenantID uuid.UUID,
+	uploadID uuid.UUID,
+	page int,
+	pageSize int,
+) ([]models.SiteRecord, int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = models.DefaultPageSize
+	}
+
+	offset := (page - 1) * pageSize
+
+	var totalCount int
+	err := r.pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM site_records WHERE upload_id = $1 AND tenant_id = $2`,
+		uploadID, tenantID,
+	).Scan(&totalCount)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT id, upload_id, tenant_id, site_id, site_name, location,
+		       latitude, longitude, raw_data, data, created_at
+		FROM site_records
+		WHERE upload_id = $1 AND tenant_id = $2
+		ORDER BY site_id ASC, id ASC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.pool.Query(ctx, query, uploadID, tenantID, pageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	records := make([]models.SiteRecord, 0, pageSize)
+	for rows.Next() {
+		record := models.SiteRecord{}
+		err := rows.Scan(
+			&record.ID,
+			&record.UploadID,
+			&record.TenantID,
+			&record.SiteID,
+			&record.SiteName,
+			&record.Location,
+			&record.Latitude,
+			&record.Longitude,
+			&record.RawData,
+			&record.Data,
+			&record.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		records = append(records, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return records, totalCount, nil
+}
+
 // CountByUpload returns the total number of site records for a given upload
 func (r *SiteRecordRepository) CountByUpload(ctx context.Context, uploadID uuid.UUID) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	query := `
 		SELECT COUNT(*)
 		FROM site_records
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+)
+
+func insertTestSiteRecords(t *testing.T, repo *SiteRecordRepository, upload *models.Upload, count int) {
+	t.Helper()
+	now := time.Now()
+	records := make([]models.SiteRecord, count)
+	for i := range records {
+		siteID := fmt.Sprintf("S%02d", i+1)
+		records[i] = models.SiteRecord{
+			ID:        uuid.New(),
+			UploadID:  upload.ID,
+			TenantID:  upload.TenantID,
+			SiteID:    siteID,
+			SiteName:  "Site " + siteID,
+			Location:  "Austin, TX",
+			RawData:   json.RawMessage(fmt.Sprintf(`{"site_id": %q, "population": "%d"}`, siteID, 1000*(i+1))),
+			Data:      json.RawMessage(fmt.Sprintf(`{"site_id": %q, "population": %d}`, siteID, 1000*(i+1))),
+			CreatedAt: now,
+		}
+	}
+	require.NoError(t, repo.BulkInsert(context.Background(), records))
+}
+
+func TestSiteRecordRepository_GetByUploadPaginated(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	repo := NewSiteRecordRepository(pool, testQueryTimeout)
+
+	tenantID := createTestTenant(t, pool)
+	upload := createTestUpload(t, pool, tenantID)
+	insertTestSiteRecords(t, repo, upload, 5)
+
+	page1, total, err := repo.GetByUploadPaginated(ctx, tenantID, upload.ID, 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 5, total)
+	require.Len(t, page1, 2)
+	assert.Equal(t, "S01", page1[0].SiteID)
+	assert.Equal(t, "S02", page1[1].SiteID)
+	assert.JSONEq(t, `{"site_id": "S01", "population": 1000}`, string(page1[0].Data))
+
+	page3, _, err := repo.GetByUploadPaginated(ctx, tenantID, upload.ID, 3, 2)
+	require.NoError(t, err)
+	require.Len(t, page3, 1)
+	assert.Equal(t, "S05", page3[0].SiteID)
+
+	beyond, total, err := repo.GetByUploadPaginated(ctx, tenantID, upload.ID, 4, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 5, total)
+	assert.Empty(t, beyond)
+}
+
+func TestSiteRecordRepository_GetByUploadPaginatedTenantScoped(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	repo := NewSiteRecordRepository(pool, testQueryTimeout)
+
+	tenantID := createTestTenant(t, pool)
+	upload := createTestUpload(t, pool, tenantID)
+	insertTestSiteRecords(t, repo, upload, 3)
+
+	otherTenant := createTestTenant(t, pool)
+	records, total, err := repo.GetByUploadPaginated(ctx, otherTenant, upload.ID, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+	assert.Empty(t, records)
+}
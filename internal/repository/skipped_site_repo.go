@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+)
+
+// SkippedSiteRepository handles data access for the sites scoring runs skipped
+type SkippedSiteRepository struct {
+	pool         *pgxpool.Pool
+	queryTimeout time.Duration
+}
+
+// NewSkippedSiteRepository creates a new skipped site repository
+func NewSkippedSiteRepository(pool *pgxpool.Pool, queryTimeout time.Duration) *SkippedSiteRepository {
+	return &SkippedSiteRepository{pool: pool, queryTimeout: queryTimeout}
+}
+
+// BulkInsert stores skipped sites in a single batch. A site already stored
+// for its run and position, e.g. by an earlier attempt of the run, has its
+// reason updated instead.
+func (r *SkippedSiteRepository) BulkInsert(ctx context.Context, sites []models.RunSkippedSite) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	if len(sites) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+
+	query := `
+		INSERT INTO run_skipped_sites (
+			run_id, tenant_id, position, site_id, site_name, reason, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7
+		)
+		ON CONFLICT (run_id, position) DO UPDATE
+		SET site_id = EXCLUDED.site_id,
+		    site_name = EXCLUDED.site_name,
+		    reason = EXCLUDED.reason
+	`
+
+	for _, site := range sites {
+		batch.Queue(
+			query,
+			site.RunID,
+			site.TenantID,
+			site.Position,
+			site.SiteID,
+			site.SiteName,
+			site.Reason,
+			site.CreatedAt,
+		)
+	}
+
+	results := r.pool.SendBatch(ctx, batch)
+	defer results.Close()
+
+	for i := 0; i < len(sites); i++ {
+		if _, err := results.Exec(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ListByRun retrieves one page of a run's skipped sites in upload order,
+// along with the total number stored for the run.
+func (r *SkippedSiteRepository) ListByRun(
+	ctx context.Context,
+	runID uuid.UUID,
+	page int,
+	pageSize int,
+) ([]models.RunSkippedSite, int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = models.DefaultPageSize
+	}
+	offset := (page - 1) * pageSize
+
+	var totalCount int
+	err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM run_skipped_sites WHERE run_id = $1`, runID).Scan(&totalCount)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT run_id, tenant_id, position, site_id, site_name, reason, created_at
+		FROM run_skipped_sites
+		WHERE run_id = $1
+		ORDER BY position
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.pool.Query(ctx, query, runID, pageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	sites := make([]models.RunSkippedSite, 0, pageSize)
+	for rows.Next() {
+		var site models.RunSkippedSite
+		err := rows.Scan(
+			&site.RunID,
+			&site.TenantID,
+			&site.Position,
+			&site.SiteID,
+			&site.SiteName,
+			&site.Reason,
+			&site.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		sites = append(sites, site)
+	}
+
+	return sites, totalCount, rows.Err()
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+)
+
+func TestSkippedSiteRepository_BulkInsertAndList(t *testing.T) {
+	pool := testPool(t)
+	repo := NewSkippedSiteRepository(pool, testQueryTimeout)
+	ctx := context.Background()
+
+	tenantID := createTestTenant(t, pool)
+	upload := createTestUpload(t, pool, tenantID)
+	run := createTestRun(t, pool, upload, "completed_with_errors", uuid.New(), time.Now())
+
+	skipped := func(position int, reason string) models.RunSkippedSite {
+		return models.RunSkippedSite{
+			RunID:     run.ID,
+			TenantID:  tenantID,
+			Position:  position,
+			SiteID:    fmt.Sprintf("SITE-%d", position),
+			Reason:    reason,
+			CreatedAt: time.Now(),
+		}
+	}
+	require.NoError(t, repo.BulkInsert(ctx, []models.RunSkippedSite{
+		skipped(7, "invalid site data: unexpected end of JSON input"),
+		skipped(2, "missing required field population"),
+		skipped(4, "population must not be negative"),
+	}))
+
+	// A retried run rewrites its rows rather than duplicating them
+	require.NoError(t, repo.BulkInsert(ctx, []models.RunSkippedSite{skipped(2, "population is not a number")}))
+
+	sites, total, err := repo.ListByRun(ctx, run.ID, 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	require.Len(t, sites, 2)
+	assert.Equal(t, 2, sites[0].Position, "upload order")
+	assert.Equal(t, "SITE-2", sites[0].SiteID)
+	assert.Equal(t, "population is not a number", sites[0].Reason)
+	assert.Equal(t, 4, sites[1].Position)
+
+	sites, _, err = repo.ListByRun(ctx, run.ID, 2, 2)
+	require.NoError(t, err)
+	require.Len(t, sites, 1)
+	assert.Equal(t, "invalid site data: unexpected end of JSON input", sites[0].Reason)
+
+	sites, total, err = repo.ListByRun(ctx, uuid.New(), 1, 10)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, sites)
+}
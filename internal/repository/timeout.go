@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/workforce-ai/site-selection-iq/pkg/timing"
+)
+
+// withQueryTimeout bounds ctx to timeout so a slow or locked query cannot
+// hold a pooled connection indefinitely. A caller deadline that is already
+// sooner is kept as is. A non-positive timeout leaves ctx unbounded.
+//
+// Every repository call goes through here, so the returned cancel func also
+// records the call's duration as database time on any timing.Recorder in ctx.
+func withQueryTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	stop := timing.Track(ctx, timing.SpanDB)
+	if timeout <= 0 {
+		return ctx, stop
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
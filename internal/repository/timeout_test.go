@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/pkg/timing"
+)
+
+func TestWithQueryTimeout_AppliesTimeout(t *testing.T) {
+	start := time.Now()
+	ctx, cancel := withQueryTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	deadline, ok := ctx.Deadline()
+	require.True(t, ok, "timeout should set a deadline")
+	assert.WithinDuration(t, start.Add(50*time.Millisecond), deadline, 20*time.Millisecond)
+
+	<-ctx.Done()
+	assert.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+}
+
+func TestWithQueryTimeout_KeepsSoonerCallerDeadline(t *testing.T) {
+	parent, parentCancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer parentCancel()
+	parentDeadline, _ := parent.Deadline()
+
+	ctx, cancel := withQueryTimeout(parent, time.Minute)
+	defer cancel()
+
+	deadline, ok := ctx.Deadline()
+	require.True(t, ok)
+	assert.Equal(t, parentDeadline, deadline, "a nearer caller deadline must not be extended")
+}
+
+func TestWithQueryTimeout_ShortensLaterCallerDeadline(t *testing.T) {
+	parent, parentCancel := context.WithTimeout(context.Background(), time.Hour)
+	defer parentCancel()
+
+	ctx, cancel := withQueryTimeout(parent, 10*time.Millisecond)
+	defer cancel()
+
+	deadline, ok := ctx.Deadline()
+	require.True(t, ok)
+	assert.True(t, deadline.Before(time.Now().Add(time.Second)), "query timeout should cap the caller deadline")
+}
+
+func TestWithQueryTimeout_ZeroDisables(t *testing.T) {
+	ctx, cancel := withQueryTimeout(context.Background(), 0)
+	defer cancel()
+
+	_, ok := ctx.Deadline()
+	assert.False(t, ok, "zero timeout should leave the context unbounded")
+}
+
+func TestWithQueryTimeout_RecordsDatabaseTime(t *testing.T) {
+	ctx, rec := timing.NewContext(context.Background())
+
+	for _, timeout := range []time.Duration{time.Minute, 0} {
+		_, cancel := withQueryTimeout(ctx, timeout)
+		time.Sleep(2 * time.Millisecond)
+		cancel()
+	}
+
+	assert.GreaterOrEqual(t, rec.Duration(timing.SpanDB), 4*time.Millisecond)
+	assert.Contains(t, rec.Header(0), `db;dur=`)
+	assert.Contains(t, rec.Header(0), `desc="2 calls"`)
+}
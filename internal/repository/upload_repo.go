@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -10,14 +11,22 @@ import (
 	"github.com/workforce-ai/site-selection-iq/internal/models"
 )
 
+// Errors returned by AppendRecords when the upload no longer accepts the
+// append it was validated for
+var (
+	ErrUploadChanged = errors.New("upload changed while rows were being appended")
+	ErrUploadHasRuns = errors.New("upload already has scoring runs")
+)
+
 // UploadRepository handles data access for upload records
 type UploadRepository struct {
-	pool *pgxpool.Pool
+	pool         *pgxpool.Pool
+	queryTimeout time.Duration
 }
 
 // NewUploadRepository creates a new upload repository
-func NewUploadRepository(pool *pgxpool.Pool) *UploadRepository {
-	return &UploadRepository{pool: pool}
+func NewUploadRepository(pool *pgxpool.Pool, queryTimeout time.Duration) *UploadRepository {
+	return &UploadRepository{pool: pool, queryTimeout: queryTimeout}
 }
 
 // uploadColumns is the canonical column list for uploads, used across all queries.
@@ -47,6 +56,9 @@ func scanUpload(row pgx.Row, upload *models.Upload) error {
 
 // Create inserts a new upload record
 func (r *UploadRepository) Create(ctx context.Context, upload *models.Upload) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	if upload == nil {
 		return errors.New("upload cannot be nil")
 	}
@@ -72,6 +84,9 @@ func (r *UploadRepository) Create(ctx context.Context, upload *models.Upload) er
 
 // GetByID retrieves an upload by ID, scoped to the tenant
 func (r *UploadRepository) GetByID(ctx context.Context, tenantID, uploadID uuid.UUID) (*models.Upload, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	query := `SELECT ` + uploadColumns + ` FROM uploads WHERE id = $1 AND tenant_id = $2`
 	upload := &models.Upload{}
 	err := scanUpload(r.pool.QueryRow(ctx, query, uploadID, tenantID), upload)
@@ -84,8 +99,37 @@ func (r *UploadRepository) GetByID(ctx context.Context, tenantID, uploadID uuid.
 	return upload, nil
 }
 
+// ListByTenant retrieves every upload for a tenant, oldest first
+func (r *UploadRepository) ListByTenant(ctx context.Context, tenantID uuid.UUID) ([]models.Upload, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `SELECT ` + uploadColumns + ` FROM uploads WHERE tenant_id = $1 ORDER BY created_at ASC, id ASC`
+	rows, err := r.pool.Query(ctx, query, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var uploads []models.Upload
+	for rows.Next() {
+		upload := models.Upload{}
+		if err := scanUpload(rows, &upload); err != nil {
+			return nil, err
+		}
+		uploads = append(uploads, upload)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return uploads, nil
+}
+
 // GetByIdempotencyKey retrieves an upload by idempotency key, scoped to the tenant
 func (r *UploadRepository) GetByIdempotencyKey(ctx context.Context, tenantID uuid.UUID, key string) (*models.Upload, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	query := `SELECT ` + uploadColumns + ` FROM uploads WHERE tenant_id = $1 AND idempotency_key = $2`
 	upload := &models.Upload{}
 	err := scanUpload(r.pool.QueryRow(ctx, query, tenantID, key), upload)
@@ -101,6 +145,9 @@ func (r *UploadRepository) GetByIdempotencyKey(ctx context.Context, tenantID uui
 // GetByContentHash retrieves an upload by SHA-256 content hash, scoped to the tenant.
 // Returns nil, nil if no match found.
 func (r *UploadRepository) GetByContentHash(ctx context.Context, tenantID uuid.UUID, hash string) (*models.Upload, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	query := `SELECT ` + uploadColumns + ` FROM uploads WHERE tenant_id = $1 AND content_hash = $2`
 	upload := &models.Upload{}
 	err := scanUpload(r.pool.QueryRow(ctx, query, tenantID, hash), upload)
@@ -115,10 +162,96 @@ func (r *UploadRepository) GetByContentHash(ctx context.Context, tenantID uuid.U
 
 // Update updates an upload record
 func (r *UploadRepository) Update(ctx context.Context, upload *models.Upload) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	if upload == nil {
+		return errors.New("upload cannot be nil")
+	}
+
+	return updateUpload(ctx, r.pool, upload)
+}
+
+// AppendRecords inserts records under upload and saves upload's new row
+// count, size, warnings and content hash in one transaction. The upload row
+// is locked first, and the append is refused with ErrUploadChanged if the
+// stored upload is no longer completed or its content hash is no longer
+// previousHash, i.e. another append landed after the caller read it, or
+// with ErrUploadHasRuns if it has been scored since. The lock also holds
+// off new runs of the upload until the append commits, since inserting a
+// run locks the upload row for its foreign key.
+func (r *UploadRepository) AppendRecords(ctx context.Context, upload *models.Upload, previousHash *string, records []models.SiteRecord) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	if upload == nil {
 		return errors.New("upload cannot be nil")
 	}
 
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var status string
+	var contentHash *string
+	err = tx.QueryRow(ctx, `
+		SELECT status, content_hash
+		FROM uploads
+		WHERE id = $1 AND tenant_id = $2
+		FOR UPDATE
+	`, upload.ID, upload.TenantID).Scan(&status, &contentHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errors.New("upload not found")
+		}
+		return err
+	}
+	if status != "completed" || !equalHash(contentHash, previousHash) {
+		return ErrUploadChanged
+	}
+
+	var runCount int
+	err = tx.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM scoring_runs
+		WHERE tenant_id = $1 AND upload_id = $2
+	`, upload.TenantID, upload.ID).Scan(&runCount)
+	if err != nil {
+		return err
+	}
+	if runCount > 0 {
+		return ErrUploadHasRuns
+	}
+
+	if len(records) > 0 {
+		results := tx.SendBatch(ctx, siteRecordBatch(records))
+		for i := 0; i < len(records); i++ {
+			if _, err := results.Exec(); err != nil {
+				results.Close()
+				return err
+			}
+		}
+		if err := results.Close(); err != nil {
+			return err
+		}
+	}
+
+	if err := updateUpload(ctx, tx, upload); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// rowQuerier runs single-row queries; *pgxpool.Pool and pgx.Tx satisfy it.
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// updateUpload saves upload's mutable fields through q and scans the
+// stored row back into it.
+func updateUpload(ctx context.Context, q rowQuerier, upload *models.Upload) error {
 	query := `
 		UPDATE uploads
 		SET filename = $3, file_size = $4,
@@ -128,7 +261,7 @@ func (r *UploadRepository) Update(ctx context.Context, upload *models.Upload) er
 		WHERE id = $1 AND tenant_id = $2
 		RETURNING ` + uploadColumns
 
-	err := scanUpload(r.pool.QueryRow(
+	err := scanUpload(q.QueryRow(
 		ctx, query,
 		upload.ID, upload.TenantID, upload.Filename, upload.FileSize,
 		upload.Status, upload.ValidationStatus, upload.RowCount, upload.SchemaVersion,
@@ -144,3 +277,11 @@ func (r *UploadRepository) Update(ctx context.Context, upload *models.Upload) er
 	}
 	return nil
 }
+
+// equalHash reports whether two optional content hashes are equal.
+func equalHash(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
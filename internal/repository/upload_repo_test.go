@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+)
+
+func TestUploadRepository_AppendRecords(t *testing.T) {
+	pool := testPool(t)
+	repo := NewUploadRepository(pool, testQueryTimeout)
+	sites := NewSiteRecordRepository(pool, testQueryTimeout)
+	ctx := context.Background()
+
+	tenantID := createTestTenant(t, pool)
+	upload := createTestUpload(t, pool, tenantID)
+
+	record := func(siteID string) models.SiteRecord {
+		return models.SiteRecord{
+			ID:        uuid.New(),
+			UploadID:  upload.ID,
+			TenantID:  tenantID,
+			SiteID:    siteID,
+			SiteName:  siteID,
+			RawData:   json.RawMessage(`{}`),
+			Data:      json.RawMessage(`{}`),
+			CreatedAt: time.Now(),
+		}
+	}
+	appendAs := func(previous *string, hash string, siteID string) error {
+		next := *upload
+		next.ContentHash = &hash
+		next.RowCount++
+		return repo.AppendRecords(ctx, &next, previous, []models.SiteRecord{record(siteID)})
+	}
+
+	require.NoError(t, appendAs(nil, "hash-1", "S1"))
+	stored, err := repo.GetByID(ctx, tenantID, upload.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored.RowCount)
+	require.NotNil(t, stored.ContentHash)
+	assert.Equal(t, "hash-1", *stored.ContentHash)
+
+	// An append validated against the upload before the first one landed
+	assert.ErrorIs(t, appendAs(nil, "hash-2", "S2"), ErrUploadChanged)
+
+	previous := "hash-1"
+	createTestRun(t, pool, stored, "queued", uuid.New(), time.Now())
+	assert.ErrorIs(t, appendAs(&previous, "hash-2", "S2"), ErrUploadHasRuns)
+
+	count, err := sites.CountByUpload(ctx, upload.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count, "refused appends store no records")
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+)
+
+// weightPresetColumns is the column list shared by every query that returns
+// a full preset. It must stay in sync with scanWeightPreset.
+const weightPresetColumns = `id, tenant_id, name, description, weights, created_at, updated_at`
+
+// scanWeightPreset scans a row selected with weightPresetColumns into preset.
+func scanWeightPreset(row pgx.Row, preset *models.WeightPreset) error {
+	var weights []byte
+	err := row.Scan(
+		&preset.ID,
+		&preset.TenantID,
+		&preset.Name,
+		&preset.Description,
+		&weights,
+		&preset.CreatedAt,
+		&preset.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(weights, &preset.Weights)
+}
+
+// WeightPresetRepository handles data access for tenant weight presets
+type WeightPresetRepository struct {
+	pool         *pgxpool.Pool
+	queryTimeout time.Duration
+}
+
+// NewWeightPresetRepository creates a new weight preset repository
+func NewWeightPresetRepository(pool *pgxpool.Pool, queryTimeout time.Duration) *WeightPresetRepository {
+	return &WeightPresetRepository{pool: pool, queryTimeout: queryTimeout}
+}
+
+// Create inserts a preset. ID and timestamps are filled in when unset. It
+// returns false, without error, when the tenant already has a preset of
+// that name.
+func (r *WeightPresetRepository) Create(ctx context.Context, preset *models.WeightPreset) (bool, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	if preset == nil {
+		return false, errors.New("preset cannot be nil")
+	}
+	if preset.ID == uuid.Nil {
+		preset.ID = uuid.New()
+	}
+	if preset.CreatedAt.IsZero() {
+		preset.CreatedAt = time.Now()
+	}
+	if preset.UpdatedAt.IsZero() {
+		preset.UpdatedAt = preset.CreatedAt
+	}
+
+	weights, err := json.Marshal(preset.Weights)
+	if err != nil {
+		return false, err
+	}
+
+	query := `
+		INSERT INTO weight_presets (id, tenant_id, name, description, weights, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (tenant_id, name) DO NOTHING
+	`
+
+	tag, err := r.pool.Exec(ctx, query,
+		preset.ID,
+		preset.TenantID,
+		preset.Name,
+		preset.Description,
+		weights,
+		preset.CreatedAt,
+		preset.UpdatedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// GetByName retrieves a tenant's preset by name, or nil if there is none
+func (r *WeightPresetRepository) GetByName(ctx context.Context, tenantID uuid.UUID, name string) (*models.WeightPreset, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `SELECT ` + weightPresetColumns + ` FROM weight_presets WHERE tenant_id = $1 AND name = $2`
+
+	preset := &models.WeightPreset{}
+	err := scanWeightPreset(r.pool.QueryRow(ctx, query, tenantID, name), preset)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return preset, nil
+}
+
+// ListByTenant retrieves all of a tenant's presets, ordered by name
+func (r *WeightPresetRepository) ListByTenant(ctx context.Context, tenantID uuid.UUID) ([]models.WeightPreset, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `SELECT ` + weightPresetColumns + ` FROM weight_presets WHERE tenant_id = $1 ORDER BY name ASC`
+
+	rows, err := r.pool.Query(ctx, query, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	presets := []models.WeightPreset{}
+	for rows.Next() {
+		preset := models.WeightPreset{}
+		if err := scanWeightPreset(rows, &preset); err != nil {
+			return nil, err
+		}
+		presets = append(presets, preset)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return presets, nil
+}
+
+// Update replaces the description and weights of the tenant's preset named
+// preset.Name and refreshes preset from the stored row. It returns false
+// when no such preset exists.
+func (r *WeightPresetRepository) Update(ctx context.Context, preset *models.WeightPreset) (bool, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	weights, err := json.Marshal(preset.Weights)
+	if err != nil {
+		return false, err
+	}
+
+	query := `
+		UPDATE weight_presets
+		SET description = $3, weights = $4, updated_at = NOW()
+		WHERE tenant_id = $1 AND name = $2
+		RETURNING ` + weightPresetColumns
+
+	err = scanWeightPreset(r.pool.QueryRow(ctx, query, preset.TenantID, preset.Name, preset.Description, weights), preset)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Delete removes the tenant's preset by name, returning its ID, or nil if
+// there was no such preset
+func (r *WeightPresetRepository) Delete(ctx context.Context, tenantID uuid.UUID, name string) (*uuid.UUID, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var id uuid.UUID
+	err := r.pool.QueryRow(ctx,
+		`DELETE FROM weight_presets WHERE tenant_id = $1 AND name = $2 RETURNING id`,
+		tenantID, name,
+	).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &id, nil
+}
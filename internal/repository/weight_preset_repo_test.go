@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+)
+
+func TestWeightPresetRepository_CRUD(t *testing.T) {
+	pool := testPool(t)
+	repo := NewWeightPresetRepository(pool, testQueryTimeout)
+	tenantID := createTestTenant(t, pool)
+	ctx := context.Background()
+
+	preset := &models.WeightPreset{
+		TenantID:    tenantID,
+		Name:        "cost-focused",
+		Description: "Favor low labor cost",
+		Weights:     map[string]float64{"avg_hourly_wage": 3, "unemployment_rate": 0.5},
+	}
+	created, err := repo.Create(ctx, preset)
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	// A second preset with the same name is not created
+	created, err = repo.Create(ctx, &models.WeightPreset{TenantID: tenantID, Name: "cost-focused", Weights: map[string]float64{}})
+	require.NoError(t, err)
+	assert.False(t, created)
+
+	got, err := repo.GetByName(ctx, tenantID, "cost-focused")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, preset.ID, got.ID)
+	assert.Equal(t, preset.Weights, got.Weights)
+
+	updated := &models.WeightPreset{TenantID: tenantID, Name: "cost-focused", Weights: map[string]float64{"avg_hourly_wage": 5}}
+	found, err := repo.Update(ctx, updated)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, preset.ID, updated.ID)
+	assert.Equal(t, map[string]float64{"avg_hourly_wage": 5}, updated.Weights)
+
+	presets, err := repo.ListByTenant(ctx, tenantID)
+	require.NoError(t, err)
+	require.Len(t, presets, 1)
+
+	deleted, err := repo.Delete(ctx, tenantID, "cost-focused")
+	require.NoError(t, err)
+	require.NotNil(t, deleted)
+	assert.Equal(t, preset.ID, *deleted)
+
+	got, err = repo.GetByName(ctx, tenantID, "cost-focused")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
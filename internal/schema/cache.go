@@ -0,0 +1,217 @@
+package schema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+	"github.com/workforce-ai/site-selection-iq/pkg/clock"
+)
+
+// ErrNoGlobalConfig is returned by ResolveActive when no global schema
+// config is active.
+var ErrNoGlobalConfig = errors.New("no active global schema configuration found")
+
+// ResolveError reports that the stored configs were loaded but do not
+// resolve, as opposed to a failure loading them; retrying will not help.
+type ResolveError struct {
+	Err error
+}
+
+func (e *ResolveError) Error() string { return "failed to resolve schema: " + e.Err.Error() }
+func (e *ResolveError) Unwrap() error { return e.Err }
+
+// ConfigStore loads the active global and tenant schema configs.
+// GetActiveVersions reports which configs are active without loading them,
+// so a cached resolution can be checked cheaply.
+type ConfigStore interface {
+	GetGlobalActive(ctx context.Context) (*models.SchemaConfig, error)
+	GetTenantActive(ctx context.Context, tenantID uuid.UUID) (*models.SchemaConfig, error)
+	GetActiveVersions(ctx context.Context, tenantID uuid.UUID) (ConfigVersions, error)
+}
+
+// ConfigVersions identifies the active global config and a tenant's
+// override by ID and last update. The tenant fields are zero when the
+// tenant has no override, and all fields are zero when no global config is
+// active.
+type ConfigVersions struct {
+	GlobalID        uuid.UUID
+	GlobalUpdatedAt time.Time
+	TenantID        uuid.UUID
+	TenantUpdatedAt time.Time
+}
+
+// versionsOf returns the versions of the configs res was resolved from.
+func versionsOf(res *Resolution) ConfigVersions {
+	v := ConfigVersions{GlobalID: res.Global.ID, GlobalUpdatedAt: res.Global.UpdatedAt}
+	if res.Tenant != nil {
+		v.TenantID, v.TenantUpdatedAt = res.Tenant.ID, res.Tenant.UpdatedAt
+	}
+	return v
+}
+
+// matches reports whether v and other identify the same configs.
+func (v ConfigVersions) matches(other ConfigVersions) bool {
+	return v.GlobalID == other.GlobalID && v.GlobalUpdatedAt.Equal(other.GlobalUpdatedAt) &&
+		v.TenantID == other.TenantID && v.TenantUpdatedAt.Equal(other.TenantUpdatedAt)
+}
+
+// Resolution is a tenant's resolved schema with the configs it came from.
+type Resolution struct {
+	Global *models.SchemaConfig
+	Tenant *models.SchemaConfig // nil when the tenant has no override
+	Schema *ResolvedSchema
+}
+
+// ResolveActive loads the active global config and tenantID's override from
+// store and resolves them. When the resolver has a cache, a cached result is
+// served instead if it was resolved from the configs store reports active.
+// The returned schema is the caller's own to modify.
+func (r *Resolver) ResolveActive(ctx context.Context, store ConfigStore, tenantID uuid.UUID) (*Resolution, error) {
+	if r.cache != nil {
+		versions, err := store.GetActiveVersions(ctx, tenantID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve schema config versions: %w", err)
+		}
+		if res, ok := r.cache.get(tenantID, versions); ok {
+			return res, nil
+		}
+	}
+	gen := r.cache.generation()
+
+	global, err := store.GetGlobalActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve global schema config: %w", err)
+	}
+	if global == nil {
+		return nil, ErrNoGlobalConfig
+	}
+	tenant, err := store.GetTenantActive(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve tenant schema config: %w", err)
+	}
+
+	var tenantConfig []byte
+	if tenant != nil {
+		tenantConfig = tenant.Config
+	}
+	resolved, err := Resolve(global.Config, tenantConfig)
+	if err != nil {
+		return nil, &ResolveError{Err: err}
+	}
+
+	res := &Resolution{Global: global, Tenant: tenant, Schema: resolved}
+	r.cache.put(tenantID, res, gen)
+	return res, nil
+}
+
+// Invalidate drops tenantID's cached schema, e.g. after its override changes.
+func (r *Resolver) Invalidate(tenantID uuid.UUID) {
+	r.cache.invalidate(tenantID)
+}
+
+// Cache holds resolved schemas per tenant, each recording the global and
+// tenant config versions it was resolved from. An entry is only served
+// while those versions are still the active ones, so a config changed by
+// another replica or outside the API is picked up on the next resolve.
+// Entries are also dropped when the tenant's override is saved through this
+// process, and expire after the TTL. It is safe for concurrent use; a nil
+// *Cache caches nothing.
+type Cache struct {
+	ttl   time.Duration
+	clock clock.Clock
+
+	mu      sync.Mutex
+	entries map[uuid.UUID]cacheEntry
+	gen     uint64 // bumped on every invalidation
+}
+
+type cacheEntry struct {
+	res      Resolution
+	versions ConfigVersions
+	expires  time.Time
+}
+
+// NewCache creates a cache whose entries live for ttl.
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{ttl: ttl, clock: clock.Real{}, entries: make(map[uuid.UUID]cacheEntry)}
+}
+
+// SetClock replaces the clock used for expiry (for tests).
+func (c *Cache) SetClock(clk clock.Clock) {
+	c.clock = clk
+}
+
+// Len returns the number of cached tenants, including expired entries not
+// yet evicted.
+func (c *Cache) Len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// get returns a copy of tenantID's unexpired entry, if it was resolved from
+// the configs identified by versions. An outdated entry is evicted.
+func (c *Cache) get(tenantID uuid.UUID, versions ConfigVersions) (*Resolution, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[tenantID]
+	if !ok {
+		return nil, false
+	}
+	if !c.clock.Now().Before(entry.expires) || !entry.versions.matches(versions) {
+		delete(c.entries, tenantID)
+		return nil, false
+	}
+	res := entry.res
+	res.Schema = entry.res.Schema.Clone()
+	return &res, true
+}
+
+// generation returns the invalidation counter to pass to put.
+func (c *Cache) generation() uint64 {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.gen
+}
+
+// put stores a copy of res unless an invalidation happened since gen was
+// read, in which case res may predate the change and is not cached.
+func (c *Cache) put(tenantID uuid.UUID, res *Resolution, gen uint64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.gen != gen {
+		return
+	}
+	stored := *res
+	stored.Schema = res.Schema.Clone()
+	c.entries[tenantID] = cacheEntry{res: stored, versions: versionsOf(res), expires: c.clock.Now().Add(c.ttl)}
+}
+
+func (c *Cache) invalidate(tenantID uuid.UUID) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, tenantID)
+	c.gen++
+}
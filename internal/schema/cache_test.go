@@ -0,0 +1,253 @@
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+	"github.com/workforce-ai/site-selection-iq/pkg/clock"
+)
+
+const cacheTestGlobal = `{
+	"site_id_column": "site_id",
+	"fields": {
+		"population": {"type": "population", "required": true, "weight": 1, "direction": "maximize"}
+	}
+}`
+
+// fakeConfigStore serves fixed configs and counts global config loads and
+// version lookups.
+type fakeConfigStore struct {
+	global      *models.SchemaConfig
+	tenants     map[uuid.UUID]*models.SchemaConfig
+	err         error
+	versionsErr error
+	loads       int
+	lookups     int
+
+	// onLoad, if set, runs during each load, before the configs are returned.
+	onLoad func()
+}
+
+func newFakeConfigStore() *fakeConfigStore {
+	return &fakeConfigStore{
+		global:  &models.SchemaConfig{ID: uuid.New(), Config: json.RawMessage(cacheTestGlobal)},
+		tenants: make(map[uuid.UUID]*models.SchemaConfig),
+	}
+}
+
+func (s *fakeConfigStore) GetGlobalActive(ctx context.Context) (*models.SchemaConfig, error) {
+	s.loads++
+	if s.onLoad != nil {
+		s.onLoad()
+	}
+	return s.global, s.err
+}
+
+func (s *fakeConfigStore) GetTenantActive(ctx context.Context, tenantID uuid.UUID) (*models.SchemaConfig, error) {
+	return s.tenants[tenantID], nil
+}
+
+func (s *fakeConfigStore) GetActiveVersions(ctx context.Context, tenantID uuid.UUID) (ConfigVersions, error) {
+	s.lookups++
+	var v ConfigVersions
+	if s.global != nil {
+		v.GlobalID, v.GlobalUpdatedAt = s.global.ID, s.global.UpdatedAt
+	}
+	if tenant := s.tenants[tenantID]; tenant != nil {
+		v.TenantID, v.TenantUpdatedAt = tenant.ID, tenant.UpdatedAt
+	}
+	return v, s.versionsErr
+}
+
+func (s *fakeConfigStore) setTenantWeight(tenantID uuid.UUID, weight float64) {
+	cfg, _ := json.Marshal(map[string]any{"weights": map[string]float64{"population": weight}})
+	s.tenants[tenantID] = &models.SchemaConfig{ID: uuid.New(), TenantID: &tenantID, Config: cfg}
+}
+
+func newCachingResolver(ttl time.Duration) (*Resolver, *Cache, *clock.Fake) {
+	clk := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	cache := NewCache(ttl)
+	cache.SetClock(clk)
+	r := NewResolver()
+	r.SetCache(cache)
+	return r, cache, clk
+}
+
+func TestResolveActive_CachesPerTenant(t *testing.T) {
+	r, cache, _ := newCachingResolver(time.Minute)
+	store := newFakeConfigStore()
+	tenantA, tenantB := uuid.New(), uuid.New()
+	store.setTenantWeight(tenantA, 3)
+
+	first, err := r.ResolveActive(context.Background(), store, tenantA)
+	require.NoError(t, err)
+	second, err := r.ResolveActive(context.Background(), store, tenantA)
+	require.NoError(t, err)
+	assert.Equal(t, 1, store.loads, "second resolve should be served from the cache")
+	assert.Equal(t, 3.0, second.Schema.Weights["population"])
+	assert.Equal(t, first.Tenant.ID, second.Tenant.ID)
+	assert.Equal(t, first.Global.ID, second.Global.ID)
+
+	other, err := r.ResolveActive(context.Background(), store, tenantB)
+	require.NoError(t, err)
+	assert.Equal(t, 2, store.loads, "tenants are cached separately")
+	assert.Nil(t, other.Tenant)
+	assert.Equal(t, 1.0, other.Schema.Weights["population"])
+	assert.Equal(t, 2, cache.Len())
+}
+
+func TestResolveActive_InvalidateRefetches(t *testing.T) {
+	r, _, _ := newCachingResolver(time.Minute)
+	store := newFakeConfigStore()
+	tenantID := uuid.New()
+	store.setTenantWeight(tenantID, 2)
+
+	_, err := r.ResolveActive(context.Background(), store, tenantID)
+	require.NoError(t, err)
+
+	// Saving an override invalidates the tenant's entry
+	store.setTenantWeight(tenantID, 5)
+	r.Invalidate(tenantID)
+
+	res, err := r.ResolveActive(context.Background(), store, tenantID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, store.loads)
+	assert.Equal(t, 5.0, res.Schema.Weights["population"])
+}
+
+func TestResolveActive_ChangedVersionsRefetch(t *testing.T) {
+	r, _, _ := newCachingResolver(time.Minute)
+	store := newFakeConfigStore()
+	tenantID := uuid.New()
+	store.setTenantWeight(tenantID, 2)
+
+	_, err := r.ResolveActive(context.Background(), store, tenantID)
+	require.NoError(t, err)
+
+	// Another replica saves an override; this one is never told
+	store.setTenantWeight(tenantID, 5)
+	res, err := r.ResolveActive(context.Background(), store, tenantID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, store.loads)
+	assert.Equal(t, 5.0, res.Schema.Weights["population"])
+
+	// The global config is edited in place
+	global := *store.global
+	global.UpdatedAt = global.UpdatedAt.Add(time.Second)
+	global.Config = json.RawMessage(strings.Replace(cacheTestGlobal, `"weight": 1`, `"weight": 4`, 1))
+	store.global = &global
+	store.tenants[tenantID] = nil
+	res, err = r.ResolveActive(context.Background(), store, tenantID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, store.loads)
+	assert.Equal(t, 4.0, res.Schema.Weights["population"])
+
+	_, err = r.ResolveActive(context.Background(), store, tenantID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, store.loads, "unchanged versions are served from the cache")
+	assert.Equal(t, 4, store.lookups)
+}
+
+func TestResolveActive_EntriesExpire(t *testing.T) {
+	r, _, clk := newCachingResolver(time.Minute)
+	store := newFakeConfigStore()
+	tenantID := uuid.New()
+
+	_, err := r.ResolveActive(context.Background(), store, tenantID)
+	require.NoError(t, err)
+	clk.Advance(59 * time.Second)
+	_, err = r.ResolveActive(context.Background(), store, tenantID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, store.loads)
+
+	clk.Advance(time.Second)
+	_, err = r.ResolveActive(context.Background(), store, tenantID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, store.loads, "expired entry should be reloaded")
+}
+
+func TestResolveActive_ReturnsIndependentCopies(t *testing.T) {
+	r, _, _ := newCachingResolver(time.Minute)
+	store := newFakeConfigStore()
+	tenantID := uuid.New()
+
+	res, err := r.ResolveActive(context.Background(), store, tenantID)
+	require.NoError(t, err)
+	res.Schema.Weights["population"] = 42
+	res.Schema.SiteIDColumn = "changed"
+
+	again, err := r.ResolveActive(context.Background(), store, tenantID)
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, again.Schema.Weights["population"])
+	assert.Equal(t, "site_id", again.Schema.SiteIDColumn)
+}
+
+func TestResolveActive_InvalidateDuringLoadSkipsCaching(t *testing.T) {
+	r, cache, _ := newCachingResolver(time.Minute)
+	store := newFakeConfigStore()
+	tenantID := uuid.New()
+
+	// An override saved while the old configs are being read must not let
+	// the stale resolution into the cache
+	store.onLoad = func() { r.Invalidate(tenantID) }
+	_, err := r.ResolveActive(context.Background(), store, tenantID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, cache.Len())
+
+	store.onLoad = nil
+	_, err = r.ResolveActive(context.Background(), store, tenantID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, cache.Len())
+}
+
+func TestResolveActive_Errors(t *testing.T) {
+	r, cache, _ := newCachingResolver(time.Minute)
+	tenantID := uuid.New()
+
+	store := newFakeConfigStore()
+	store.global = nil
+	_, err := r.ResolveActive(context.Background(), store, tenantID)
+	assert.ErrorIs(t, err, ErrNoGlobalConfig)
+
+	store = newFakeConfigStore()
+	store.err = errors.New("connection refused")
+	_, err = r.ResolveActive(context.Background(), store, tenantID)
+	assert.ErrorContains(t, err, "failed to retrieve global schema config: connection refused")
+
+	store = newFakeConfigStore()
+	store.tenants[tenantID] = &models.SchemaConfig{Config: json.RawMessage(`{"weights": {"missing": 1}}`)}
+	_, err = r.ResolveActive(context.Background(), store, tenantID)
+	var resolveErr *ResolveError
+	assert.ErrorAs(t, err, &resolveErr)
+
+	store = newFakeConfigStore()
+	store.versionsErr = errors.New("connection refused")
+	_, err = r.ResolveActive(context.Background(), store, tenantID)
+	assert.ErrorContains(t, err, "failed to retrieve schema config versions: connection refused")
+	assert.Equal(t, 0, store.loads)
+
+	assert.Equal(t, 0, cache.Len(), "failures are not cached")
+}
+
+func TestResolveActive_WithoutCache(t *testing.T) {
+	r := NewResolver()
+	store := newFakeConfigStore()
+	tenantID := uuid.New()
+
+	for i := 0; i < 2; i++ {
+		_, err := r.ResolveActive(context.Background(), store, tenantID)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, store.loads)
+	assert.Equal(t, 0, store.lookups, "versions are only looked up to check the cache")
+	r.Invalidate(tenantID) // no-op without a cache
+}
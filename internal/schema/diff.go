@@ -0,0 +1,115 @@
+package schema
+
+import "sort"
+
+// SchemaDiff is the field-level difference between two resolved schemas,
+// e.g. a tenant's current effective schema and one with a proposed override.
+type SchemaDiff struct {
+	AddedFields   []string      `json:"added_fields"`
+	RemovedFields []string      `json:"removed_fields"`
+	ChangedFields []FieldChange `json:"changed_fields"`
+}
+
+// FieldChange is one attribute of a field that differs between two schemas.
+// Attribute is one of type, required, weight, direction, min, max,
+// expression or category; a nil From or To is an unset bound.
+type FieldChange struct {
+	Field     string      `json:"field"`
+	Attribute string      `json:"attribute"`
+	From      interface{} `json:"from"`
+	To        interface{} `json:"to"`
+}
+
+// HasChanges reports whether the diff records any difference.
+func (d SchemaDiff) HasChanges() bool {
+	return len(d.AddedFields) > 0 || len(d.RemovedFields) > 0 || len(d.ChangedFields) > 0
+}
+
+// Diff compares the fields of current and proposed. Weights are the
+// effective ones, after tenant weight overrides. Fields and their changes
+// are listed in name order.
+func Diff(current, proposed *ResolvedSchema) SchemaDiff {
+	diff := SchemaDiff{
+		AddedFields:   []string{},
+		RemovedFields: []string{},
+		ChangedFields: []FieldChange{},
+	}
+
+	for _, name := range sortedFieldNames(proposed.Fields) {
+		if _, ok := current.Fields[name]; !ok {
+			diff.AddedFields = append(diff.AddedFields, name)
+		}
+	}
+	for _, name := range sortedFieldNames(current.Fields) {
+		before := current.Fields[name]
+		after, ok := proposed.Fields[name]
+		if !ok {
+			diff.RemovedFields = append(diff.RemovedFields, name)
+			continue
+		}
+
+		changed := func(attribute string, from, to interface{}) {
+			diff.ChangedFields = append(diff.ChangedFields, FieldChange{
+				Field: name, Attribute: attribute, From: from, To: to,
+			})
+		}
+		if before.Type != after.Type {
+			changed("type", before.Type, after.Type)
+		}
+		if before.Required != after.Required {
+			changed("required", before.Required, after.Required)
+		}
+		if from, to := effectiveWeight(current, name), effectiveWeight(proposed, name); from != to {
+			changed("weight", from, to)
+		}
+		if before.Direction != after.Direction {
+			changed("direction", before.Direction, after.Direction)
+		}
+		if !sameBound(before.Target, after.Target) {
+			changed("target", before.Target, after.Target)
+		}
+		if before.Transform != after.Transform {
+			changed("transform", before.Transform, after.Transform)
+		}
+		if before.UncertaintyColumn != after.UncertaintyColumn {
+			changed("uncertainty_column", before.UncertaintyColumn, after.UncertaintyColumn)
+		}
+		if !sameBound(before.Min, after.Min) {
+			changed("min", before.Min, after.Min)
+		}
+		if !sameBound(before.Max, after.Max) {
+			changed("max", before.Max, after.Max)
+		}
+		if before.Expression != after.Expression {
+			changed("expression", before.Expression, after.Expression)
+		}
+		if before.Category != after.Category {
+			changed("category", before.Category, after.Category)
+		}
+	}
+	return diff
+}
+
+// effectiveWeight returns the weight name is scored with in s.
+func effectiveWeight(s *ResolvedSchema, name string) float64 {
+	if w, ok := s.Weights[name]; ok {
+		return w
+	}
+	return s.Fields[name].Weight
+}
+
+func sameBound(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func sortedFieldNames(fields map[string]FieldDef) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
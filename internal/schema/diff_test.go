@@ -0,0 +1,82 @@
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const diffGlobalConfig = `{
+	"site_id_column": "site_id",
+	"fields": {
+		"site_id": {"type": "identifier", "required": true},
+		"population": {"type": "population", "weight": 1.5, "direction": "maximize"},
+		"unemployment": {"type": "percentage", "min": 0, "max": 100, "weight": 2.0, "direction": "minimize"}
+	}
+}`
+
+func TestDiff_WeightChangeAndNewField(t *testing.T) {
+	current, err := Resolve(json.RawMessage(diffGlobalConfig), json.RawMessage(`{"weights": {"population": 2}}`))
+	require.NoError(t, err)
+
+	proposed, err := Resolve(json.RawMessage(diffGlobalConfig), json.RawMessage(`{
+		"fields": {
+			"growth_rate": {"type": "percentage", "weight": 3.0, "direction": "maximize"}
+		},
+		"weights": {"population": 4}
+	}`))
+	require.NoError(t, err)
+
+	diff := Diff(current, proposed)
+
+	assert.True(t, diff.HasChanges())
+	assert.Equal(t, []string{"growth_rate"}, diff.AddedFields)
+	assert.Empty(t, diff.RemovedFields)
+	assert.Equal(t, []FieldChange{
+		{Field: "population", Attribute: "weight", From: 2.0, To: 4.0},
+	}, diff.ChangedFields)
+}
+
+func TestDiff_RemovedFieldAndAttributeChanges(t *testing.T) {
+	current, err := Resolve(json.RawMessage(diffGlobalConfig), json.RawMessage(`{
+		"fields": {
+			"growth_rate": {"type": "percentage", "weight": 1, "direction": "maximize"}
+		}
+	}`))
+	require.NoError(t, err)
+
+	proposed, err := Resolve(json.RawMessage(diffGlobalConfig), json.RawMessage(`{
+		"fields": {
+			"unemployment": {"type": "percentage", "max": 50, "weight": 2.0, "direction": "maximize"}
+		}
+	}`))
+	require.NoError(t, err)
+
+	diff := Diff(current, proposed)
+
+	assert.Empty(t, diff.AddedFields)
+	assert.Equal(t, []string{"growth_rate"}, diff.RemovedFields)
+
+	byAttribute := make(map[string]FieldChange)
+	for _, change := range diff.ChangedFields {
+		assert.Equal(t, "unemployment", change.Field)
+		byAttribute[change.Attribute] = change
+	}
+	assert.Equal(t, DirectionMinimize, byAttribute["direction"].From)
+	assert.Equal(t, DirectionMaximize, byAttribute["direction"].To)
+	require.Contains(t, byAttribute, "max")
+	assert.Equal(t, 50.0, *byAttribute["max"].To.(*float64))
+}
+
+func TestDiff_IdenticalSchemas(t *testing.T) {
+	current, err := Resolve(json.RawMessage(diffGlobalConfig), nil)
+	require.NoError(t, err)
+	proposed, err := Resolve(json.RawMessage(diffGlobalConfig), json.RawMessage(`{}`))
+	require.NoError(t, err)
+
+	diff := Diff(current, proposed)
+	assert.False(t, diff.HasChanges())
+	assert.NotNil(t, diff.ChangedFields, "empty lists serialize as [] rather than null")
+}
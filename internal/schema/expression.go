@@ -0,0 +1,278 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"unicode"
+)
+
+// ErrDivisionByZero is returned when evaluating an expression divides by zero.
+var ErrDivisionByZero = errors.New("division by zero")
+
+// Expression is a parsed arithmetic expression over field names, used by
+// computed fields. It supports numbers, field references, unary minus,
+// +, -, *, / and parentheses. Nothing else can be expressed, so evaluating
+// one can never run arbitrary code.
+type Expression struct {
+	source string
+	root   exprNode
+	fields []string
+}
+
+// exprNode is a node in a parsed expression tree.
+type exprNode interface {
+	eval(lookup func(string) (float64, bool)) (float64, error)
+}
+
+type numberNode float64
+
+type fieldNode string
+
+type negateNode struct {
+	operand exprNode
+}
+
+type binaryNode struct {
+	op          byte
+	left, right exprNode
+}
+
+func (n numberNode) eval(func(string) (float64, bool)) (float64, error) {
+	return float64(n), nil
+}
+
+func (n fieldNode) eval(lookup func(string) (float64, bool)) (float64, error) {
+	v, ok := lookup(string(n))
+	if !ok {
+		return 0, fmt.Errorf("no numeric value for field '%s'", string(n))
+	}
+	return v, nil
+}
+
+func (n negateNode) eval(lookup func(string) (float64, bool)) (float64, error) {
+	v, err := n.operand.eval(lookup)
+	return -v, err
+}
+
+func (n binaryNode) eval(lookup func(string) (float64, bool)) (float64, error) {
+	l, err := n.left.eval(lookup)
+	if err != nil {
+		return 0, err
+	}
+	r, err := n.right.eval(lookup)
+	if err != nil {
+		return 0, err
+	}
+	switch n.op {
+	case '+':
+		return l + r, nil
+	case '-':
+		return l - r, nil
+	case '*':
+		return l * r, nil
+	default:
+		if r == 0 {
+			return 0, ErrDivisionByZero
+		}
+		return l / r, nil
+	}
+}
+
+// ParseExpression parses an arithmetic expression such as
+// "employment / population".
+func ParseExpression(source string) (*Expression, error) {
+	p := &exprParser{src: source}
+	p.next()
+	root, err := p.parseSum()
+	if err == nil {
+		err = p.err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", source, err)
+	}
+	if p.tok.kind != tokEOF {
+		return nil, fmt.Errorf("invalid expression %q: unexpected %s at position %d", source, p.tok, p.tok.pos)
+	}
+	return &Expression{source: source, root: root, fields: p.fields}, nil
+}
+
+// Fields returns the field names referenced by the expression, in order of
+// first appearance.
+func (e *Expression) Fields() []string {
+	return e.fields
+}
+
+// String returns the expression source.
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Eval evaluates the expression, resolving field names through lookup.
+// It fails if a referenced field has no value or on division by zero.
+func (e *Expression) Eval(lookup func(field string) (float64, bool)) (float64, error) {
+	return e.root.eval(lookup)
+}
+
+// Tokenizer and recursive-descent parser:
+//
+//	sum     = product { ("+" | "-") product }
+//	product = unary { ("*" | "/") unary }
+//	unary   = "-" unary | primary
+//	primary = number | field | "(" sum ")"
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("'%s'", t.text)
+}
+
+type exprParser struct {
+	src    string
+	pos    int
+	tok    token
+	err    error
+	fields []string
+}
+
+// next advances to the following token, recording any lexing error.
+func (p *exprParser) next() {
+	for p.pos < len(p.src) && unicode.IsSpace(rune(p.src[p.pos])) {
+		p.pos++
+	}
+	start := p.pos
+	if p.pos >= len(p.src) {
+		p.tok = token{kind: tokEOF, pos: start}
+		return
+	}
+
+	c := p.src[p.pos]
+	switch {
+	case c == '+' || c == '-' || c == '*' || c == '/' || c == '(' || c == ')':
+		p.pos++
+		p.tok = token{kind: tokOp, text: string(c), pos: start}
+	case c >= '0' && c <= '9' || c == '.':
+		for p.pos < len(p.src) && (p.src[p.pos] >= '0' && p.src[p.pos] <= '9' || p.src[p.pos] == '.') {
+			p.pos++
+		}
+		p.tok = token{kind: tokNumber, text: p.src[start:p.pos], pos: start}
+	case c == '_' || unicode.IsLetter(rune(c)):
+		for p.pos < len(p.src) && (p.src[p.pos] == '_' || unicode.IsLetter(rune(p.src[p.pos])) || unicode.IsDigit(rune(p.src[p.pos]))) {
+			p.pos++
+		}
+		p.tok = token{kind: tokIdent, text: p.src[start:p.pos], pos: start}
+	default:
+		p.pos++
+		p.tok = token{kind: tokOp, text: string(c), pos: start}
+		if p.err == nil {
+			p.err = fmt.Errorf("unexpected character '%c' at position %d", c, start)
+		}
+	}
+}
+
+func (p *exprParser) parseSum() (exprNode, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.kind == tokOp && (p.tok.text == "+" || p.tok.text == "-") {
+		op := p.tok.text[0]
+		p.next()
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseProduct() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.kind == tokOp && (p.tok.text == "*" || p.tok.text == "/") {
+		op := p.tok.text[0]
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if p.tok.kind == tokOp && p.tok.text == "-" {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return negateNode{operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	tok := p.tok
+	switch {
+	case tok.kind == tokNumber:
+		v, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s at position %d", tok, tok.pos)
+		}
+		p.next()
+		return numberNode(v), nil
+	case tok.kind == tokIdent:
+		p.addField(tok.text)
+		p.next()
+		return fieldNode(tok.text), nil
+	case tok.kind == tokOp && tok.text == "(":
+		p.next()
+		inner, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if p.err != nil {
+			return nil, p.err
+		}
+		if p.tok.kind != tokOp || p.tok.text != ")" {
+			return nil, fmt.Errorf("expected ')' at position %d, found %s", p.tok.pos, p.tok)
+		}
+		p.next()
+		return inner, nil
+	default:
+		return nil, fmt.Errorf("unexpected %s at position %d", tok, tok.pos)
+	}
+}
+
+func (p *exprParser) addField(name string) {
+	for _, f := range p.fields {
+		if f == name {
+			return
+		}
+	}
+	p.fields = append(p.fields, name)
+}
@@ -0,0 +1,115 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lookupFrom(values map[string]float64) func(string) (float64, bool) {
+	return func(name string) (float64, bool) {
+		v, ok := values[name]
+		return v, ok
+	}
+}
+
+func TestParseExpression_Evaluates(t *testing.T) {
+	values := map[string]float64{"employment": 4500, "population": 9000, "a": 2, "b": 3}
+
+	testCases := []struct {
+		expr     string
+		expected float64
+	}{
+		{"employment / population", 0.5},
+		{"a + b * 4", 14},
+		{"(a + b) * 4", 20},
+		{"a - b - 1", -2},
+		{"-a * b", -6},
+		{"-(a - b)", 1},
+		{"100 * employment / population", 50},
+		{"1.5 * a", 3},
+	}
+
+	for _, tc := range testCases {
+		expr, err := ParseExpression(tc.expr)
+		require.NoError(t, err, tc.expr)
+		got, err := expr.Eval(lookupFrom(values))
+		require.NoError(t, err, tc.expr)
+		assert.InDelta(t, tc.expected, got, 1e-12, tc.expr)
+	}
+}
+
+func TestParseExpression_Fields(t *testing.T) {
+	expr, err := ParseExpression("(jobs + jobs_2023) / population")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"jobs", "jobs_2023", "population"}, expr.Fields())
+}
+
+func TestParseExpression_RejectsInvalid(t *testing.T) {
+	for _, src := range []string{
+		"",
+		"a +",
+		"(a + b",
+		"a b",
+		"a ^ b",
+		"os.Exit(1)",
+		"a; b",
+		"1..2",
+	} {
+		_, err := ParseExpression(src)
+		assert.Error(t, err, "expected %q to be rejected", src)
+	}
+}
+
+func TestExpression_DivisionByZero(t *testing.T) {
+	expr, err := ParseExpression("employment / population")
+	require.NoError(t, err)
+
+	_, err = expr.Eval(lookupFrom(map[string]float64{"employment": 10, "population": 0}))
+	assert.ErrorIs(t, err, ErrDivisionByZero)
+}
+
+func TestExpression_MissingField(t *testing.T) {
+	expr, err := ParseExpression("employment / population")
+	require.NoError(t, err)
+
+	_, err = expr.Eval(lookupFrom(map[string]float64{"employment": 10}))
+	assert.ErrorContains(t, err, "population")
+}
+
+func TestResolve_ComputedFieldValidation(t *testing.T) {
+	base := `{
+		"site_id_column": "site_id",
+		"fields": {
+			"site_id": {"type": "identifier", "required": true},
+			"employment": {"type": "integer", "required": true},
+			"population": {"type": "population", "required": true},
+			"jobs_per_capita": {"type": "computed", "expression": %s, "weight": 1.0, "direction": "maximize"}
+		}
+	}`
+	withExpr := func(expr string) json.RawMessage {
+		b, _ := json.Marshal(expr)
+		return json.RawMessage(fmt.Sprintf(base, string(b)))
+	}
+
+	resolved, err := Resolve(withExpr("employment / population"), nil)
+	require.NoError(t, err)
+	expr, err := resolved.ComputedExpression("jobs_per_capita")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"employment", "population"}, expr.Fields())
+
+	_, err = Resolve(withExpr("employment / households"), nil)
+	assert.ErrorContains(t, err, "unknown field: households")
+
+	_, err = Resolve(withExpr("employment / site_id"), nil)
+	assert.ErrorContains(t, err, "non-numeric field: site_id")
+
+	_, err = Resolve(withExpr("employment /"), nil)
+	assert.ErrorContains(t, err, "invalid expression")
+
+	_, err = Resolve(withExpr(""), nil)
+	assert.ErrorContains(t, err, "must specify an expression")
+}
@@ -0,0 +1,76 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// dateLayout is the layout of as-of dates and the freshness reference date.
+const dateLayout = "2006-01-02"
+
+// Freshness discounts sites whose data is old. Each site's score is
+// multiplied by 0.5^(age/half-life), where age is the time from the date in
+// its AsOfColumn to ReferenceDate. Sites with no readable date, or a date
+// after the reference, are not discounted.
+type Freshness struct {
+	// AsOfColumn names the CSV column holding each site's collection date,
+	// as YYYY-MM-DD or an RFC 3339 timestamp.
+	AsOfColumn string `json:"as_of_column"`
+
+	// HalfLifeDays is the data age at which a site's score is halved.
+	HalfLifeDays float64 `json:"half_life_days"`
+
+	// ReferenceDate (YYYY-MM-DD) is the date ages are measured from. Runs
+	// fill it in with the date they are scored on, so reranking a run's
+	// snapshot later gives the same result.
+	ReferenceDate string `json:"reference_date,omitempty"`
+}
+
+// ParseAsOf reads a collection date cell. It reports false for empty or
+// unreadable values.
+func ParseAsOf(v interface{}) (time.Time, bool) {
+	s, ok := v.(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(dateLayout, s); err == nil {
+		return t, true
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// Reference returns the date ages are measured from: ReferenceDate when set,
+// otherwise now.
+func (f *Freshness) Reference(now time.Time) time.Time {
+	if t, err := time.Parse(dateLayout, f.ReferenceDate); err == nil {
+		return t
+	}
+	return now
+}
+
+// Pin sets ReferenceDate to now's date unless it is already set.
+func (f *Freshness) Pin(now time.Time) {
+	if f.ReferenceDate == "" {
+		f.ReferenceDate = now.UTC().Format(dateLayout)
+	}
+}
+
+func (f *Freshness) validate() error {
+	if f.AsOfColumn == "" {
+		return fmt.Errorf("freshness.as_of_column must be set")
+	}
+	if !(f.HalfLifeDays > 0) {
+		return fmt.Errorf("freshness.half_life_days must be positive, got %g", f.HalfLifeDays)
+	}
+	if f.ReferenceDate != "" {
+		if _, err := time.Parse(dateLayout, f.ReferenceDate); err != nil {
+			return fmt.Errorf("freshness.reference_date must be a YYYY-MM-DD date, got %q", f.ReferenceDate)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package schema
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ImportField is one row of a flat schema definition, the form admins
+// import instead of writing a tenant override by hand. Blank optional
+// columns leave the corresponding FieldDef attribute unset.
+type ImportField struct {
+	Field     string   `json:"field"`
+	Type      string   `json:"type"`
+	Weight    float64  `json:"weight"`
+	Direction string   `json:"direction,omitempty"`
+	Min       *float64 `json:"min,omitempty"`
+	Max       *float64 `json:"max,omitempty"`
+	Required  bool     `json:"required"`
+}
+
+// ImportColumns are the CSV columns ImportCSV understands, in their usual
+// order. Only field and type must be present in the header.
+var ImportColumns = []string{"field", "type", "weight", "direction", "min", "max", "required"}
+
+// importableTypes are the field types a flat definition may declare.
+// Computed fields need an expression, which the flat form cannot carry.
+var importableTypes = map[FieldType]bool{
+	TypePercentage: true,
+	TypeIndex:      true,
+	TypeInteger:    true,
+	TypeNumeric:    true,
+	TypePopulation: true,
+	TypeText:       true,
+	TypeIdentifier: true,
+}
+
+// ImportCSV reads a flat schema definition from CSV with a header row
+// naming columns from ImportColumns in any order, and builds the tenant
+// override it describes. Errors are *ConfigError values.
+func ImportCSV(r io.Reader) (json.RawMessage, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, &ConfigError{Message: "CSV is empty"}
+	}
+	if err != nil {
+		return nil, &ConfigError{Message: fmt.Sprintf("invalid CSV: %v", err)}
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !isImportColumn(name) {
+			return nil, &ConfigError{Message: fmt.Sprintf("unknown column %q; expected %s", name, strings.Join(ImportColumns, ", "))}
+		}
+		if _, dup := columns[name]; dup {
+			return nil, &ConfigError{Message: fmt.Sprintf("column %q appears more than once", name)}
+		}
+		columns[name] = i
+	}
+	for _, name := range []string{"field", "type"} {
+		if _, ok := columns[name]; !ok {
+			return nil, &ConfigError{Message: fmt.Sprintf("missing required column %q", name)}
+		}
+	}
+
+	var fields []ImportField
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, &ConfigError{Message: fmt.Sprintf("invalid CSV: %v", err)}
+		}
+		line, _ := reader.FieldPos(0)
+
+		cell := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+
+		field := ImportField{
+			Field:     cell("field"),
+			Type:      cell("type"),
+			Direction: cell("direction"),
+		}
+		if v := cell("weight"); v != "" {
+			if field.Weight, err = strconv.ParseFloat(v, 64); err != nil {
+				return nil, &ConfigError{Message: fmt.Sprintf("line %d: weight %q is not a number", line, v)}
+			}
+		}
+		for _, bound := range []struct {
+			name string
+			dst  **float64
+		}{{"min", &field.Min}, {"max", &field.Max}} {
+			v := cell(bound.name)
+			if v == "" {
+				continue
+			}
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, &ConfigError{Message: fmt.Sprintf("line %d: %s %q is not a number", line, bound.name, v)}
+			}
+			*bound.dst = &f
+		}
+		if v := cell("required"); v != "" {
+			if field.Required, err = parseImportBool(v); err != nil {
+				return nil, &ConfigError{Message: fmt.Sprintf("line %d: required %q must be true or false", line, v)}
+			}
+		}
+		fields = append(fields, field)
+	}
+
+	return BuildOverride(fields)
+}
+
+// ImportJSON reads a flat schema definition given as a JSON array of
+// ImportField objects and builds the tenant override it describes. Unknown
+// keys are rejected. Errors are *ConfigError values.
+func ImportJSON(raw json.RawMessage) (json.RawMessage, error) {
+	if isEmptyJSON(raw) {
+		return nil, &ConfigError{Message: "definition body is required"}
+	}
+
+	var fields []ImportField
+	if err := decodeStrict(raw, &fields); err != nil {
+		return nil, err
+	}
+	return BuildOverride(fields)
+}
+
+// BuildOverride validates a flat schema definition and converts it into a
+// tenant override body defining each field. Whether the override resolves
+// against the global config is checked separately by Resolve.
+func BuildOverride(fields []ImportField) (json.RawMessage, error) {
+	if len(fields) == 0 {
+		return nil, &ConfigError{Message: "definition must list at least one field"}
+	}
+
+	defs := make(map[string]FieldDef, len(fields))
+	for i, f := range fields {
+		name := strings.TrimSpace(f.Field)
+		if name == "" {
+			return nil, &ConfigError{Field: fmt.Sprintf("fields.%d", i), Message: "field name is required"}
+		}
+		if _, dup := defs[name]; dup {
+			return nil, &ConfigError{Field: "fields." + name, Message: "field is defined more than once"}
+		}
+
+		fieldType := FieldType(strings.ToLower(strings.TrimSpace(f.Type)))
+		if !importableTypes[fieldType] {
+			return nil, &ConfigError{Field: "fields." + name + ".type", Message: fmt.Sprintf("unknown type %q", f.Type)}
+		}
+
+		direction := Direction(strings.ToLower(strings.TrimSpace(f.Direction)))
+		switch direction {
+		case "":
+			if fieldType.IsNumeric() {
+				direction = DirectionMaximize
+			}
+		case DirectionMaximize, DirectionMinimize:
+		default:
+			return nil, &ConfigError{Field: "fields." + name + ".direction", Message: fmt.Sprintf("must be %s or %s, got %q", DirectionMaximize, DirectionMinimize, f.Direction)}
+		}
+
+		if f.Weight < 0 {
+			return nil, &ConfigError{Field: "fields." + name + ".weight", Message: fmt.Sprintf("must not be negative, got %g", f.Weight)}
+		}
+		if f.Min != nil && f.Max != nil && *f.Min > *f.Max {
+			return nil, &ConfigError{Field: "fields." + name + ".min", Message: fmt.Sprintf("min %g is above max %g", *f.Min, *f.Max)}
+		}
+
+		defs[name] = FieldDef{
+			Type:      fieldType,
+			Required:  f.Required,
+			Min:       f.Min,
+			Max:       f.Max,
+			Weight:    f.Weight,
+			Direction: direction,
+		}
+	}
+
+	return json.Marshal(TenantSchemaOverride{Fields: defs})
+}
+
+// isImportColumn reports whether name is one of ImportColumns.
+func isImportColumn(name string) bool {
+	for _, column := range ImportColumns {
+		if name == column {
+			return true
+		}
+	}
+	return false
+}
+
+// isBlankRecord reports whether every cell of a CSV record is empty.
+func isBlankRecord(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// parseImportBool reads a required cell, accepting yes/no and y/n as well
+// as the forms strconv.ParseBool understands.
+func parseImportBool(v string) (bool, error) {
+	switch strings.ToLower(v) {
+	case "yes", "y":
+		return true, nil
+	case "no", "n":
+		return false, nil
+	}
+	return strconv.ParseBool(v)
+}
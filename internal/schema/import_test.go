@@ -0,0 +1,137 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const importGlobalConfig = `{
+	"site_id_column": "site_id",
+	"fields": {
+		"population": {"type": "population", "required": true, "weight": 1.5, "direction": "maximize"}
+	}
+}`
+
+func TestImportCSV(t *testing.T) {
+	csv := "Field,Type,Weight,Direction,Min,Max,Required\n" +
+		"median_income,numeric,2,maximize,20000,200000,yes\n" +
+		"unemployment, percentage, 1.5, minimize, 0, 100, false\n" +
+		"\n" +
+		"state,text,,,,,no\n"
+
+	override, err := ImportCSV(strings.NewReader(csv))
+	require.NoError(t, err)
+	require.NoError(t, ValidateTenantOverride(override), "import must produce a valid override")
+
+	resolved, err := Resolve(json.RawMessage(importGlobalConfig), override)
+	require.NoError(t, err)
+	assert.Len(t, resolved.Fields, 4, "imported fields are added to the global ones")
+
+	income := resolved.Fields["median_income"]
+	assert.Equal(t, TypeNumeric, income.Type)
+	assert.Equal(t, DirectionMaximize, income.Direction)
+	assert.True(t, income.Required)
+	require.NotNil(t, income.Min)
+	require.NotNil(t, income.Max)
+	assert.Equal(t, 20000.0, *income.Min)
+	assert.Equal(t, 200000.0, *income.Max)
+	assert.Equal(t, 2.0, resolved.Weights["median_income"])
+
+	unemployment := resolved.Fields["unemployment"]
+	assert.Equal(t, TypePercentage, unemployment.Type)
+	assert.Equal(t, DirectionMinimize, unemployment.Direction)
+	assert.False(t, unemployment.Required)
+	assert.Equal(t, 1.5, resolved.Weights["unemployment"])
+
+	state := resolved.Fields["state"]
+	assert.Equal(t, TypeText, state.Type)
+	assert.Empty(t, state.Direction)
+	assert.Nil(t, state.Min)
+	assert.Zero(t, resolved.Weights["state"])
+}
+
+func TestImportCSV_ColumnOrderAndDefaults(t *testing.T) {
+	override, err := ImportCSV(strings.NewReader("type,field\ninteger,store_count\n"))
+	require.NoError(t, err)
+
+	resolved, err := Resolve(json.RawMessage(importGlobalConfig), override)
+	require.NoError(t, err)
+	assert.Equal(t, TypeInteger, resolved.Fields["store_count"].Type)
+	assert.Equal(t, DirectionMaximize, resolved.Fields["store_count"].Direction, "numeric fields default to maximize")
+}
+
+func TestImportJSON(t *testing.T) {
+	definition := json.RawMessage(`[
+		{"field": "median_income", "type": "numeric", "weight": 2, "direction": "maximize", "min": 20000, "max": 200000, "required": true},
+		{"field": "crime_index", "type": "index", "weight": 0.5, "direction": "minimize"},
+		{"field": "population", "type": "population", "weight": 3}
+	]`)
+
+	override, err := ImportJSON(definition)
+	require.NoError(t, err)
+	require.NoError(t, ValidateTenantOverride(override))
+
+	resolved, err := Resolve(json.RawMessage(importGlobalConfig), override)
+	require.NoError(t, err)
+	assert.Len(t, resolved.Fields, 3)
+	assert.Equal(t, 2.0, resolved.Weights["median_income"])
+	assert.Equal(t, DirectionMinimize, resolved.Fields["crime_index"].Direction)
+	assert.Equal(t, 3.0, resolved.Weights["population"], "imported fields replace global ones of the same name")
+	assert.False(t, resolved.Fields["population"].Required)
+}
+
+func TestImportCSV_Rejections(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		message string
+		field   string
+	}{
+		{"empty", "", "CSV is empty", ""},
+		{"missing type column", "field,weight\nincome,1\n", `missing required column "type"`, ""},
+		{"unknown column", "field,type,colour\nincome,numeric,red\n", `unknown column "colour"`, ""},
+		{"duplicate column", "field,type,type\nincome,numeric,numeric\n", `column "type" appears more than once`, ""},
+		{"bad weight", "field,type,weight\nincome,numeric,1\nage,numeric,heavy\n", `line 3: weight "heavy" is not a number`, ""},
+		{"bad bound", "field,type,min\nincome,numeric,low\n", `line 2: min "low" is not a number`, ""},
+		{"bad required", "field,type,required\nincome,numeric,maybe\n", `line 2: required "maybe"`, ""},
+		{"no rows", "field,type\n", "at least one field", ""},
+		{"unknown type", "field,type\nincome,money\n", `unknown type "money"`, "fields.income.type"},
+		{"computed type", "field,type\nratio,computed\n", `unknown type "computed"`, "fields.ratio.type"},
+		{"bad direction", "field,type,direction\nincome,numeric,up\n", `got "up"`, "fields.income.direction"},
+		{"negative weight", "field,type,weight\nincome,numeric,-1\n", "must not be negative", "fields.income.weight"},
+		{"inverted bounds", "field,type,min,max\nincome,numeric,10,5\n", "min 10 is above max 5", "fields.income.min"},
+		{"duplicate field", "field,type\nincome,numeric\nincome,integer\n", "defined more than once", "fields.income"},
+		{"blank field name", "field,type\n,numeric\n", "field name is required", "fields.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ImportCSV(strings.NewReader(tt.csv))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.message)
+
+			var cfgErr *ConfigError
+			require.True(t, errors.As(err, &cfgErr), "errors are ConfigErrors")
+			assert.Equal(t, tt.field, cfgErr.Field)
+		})
+	}
+}
+
+func TestImportJSON_Rejections(t *testing.T) {
+	_, err := ImportJSON(json.RawMessage(`[{"field": "income", "type": "numeric", "wieght": 1}]`))
+	require.Error(t, err, "unknown keys are rejected")
+
+	_, err = ImportJSON(json.RawMessage(`{"fields": []}`))
+	require.Error(t, err, "the definition is an array")
+
+	_, err = ImportJSON(json.RawMessage(`[]`))
+	require.Error(t, err)
+
+	_, err = ImportJSON(nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,45 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// defaultNullValues are the cell values read as missing when the schema does
+// not set null_values.
+var defaultNullValues = []string{"NA", "N/A", "null", "-"}
+
+// nullValues returns the configured null sentinels or the defaults.
+func (s *ResolvedSchema) nullValues() []string {
+	if s.NullValues == nil {
+		return defaultNullValues
+	}
+	return s.NullValues
+}
+
+// IsNull reports whether a CSV cell holds one of the schema's null
+// sentinels ("NA", "N/A", "null", "-" by default), ignoring case and
+// surrounding whitespace. Such cells are read as missing, like blank ones.
+func (s *ResolvedSchema) IsNull(value string) bool {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return false
+	}
+	for _, sentinel := range s.nullValues() {
+		if strings.EqualFold(value, sentinel) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateNullValues rejects blank sentinels, which would be ambiguous with
+// the always-null empty cell.
+func (s *ResolvedSchema) validateNullValues() error {
+	for _, sentinel := range s.NullValues {
+		if strings.TrimSpace(sentinel) == "" {
+			return fmt.Errorf("null_values cannot contain a blank value")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package schema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// defaultStripSymbols are removed from numeric cells when number_format does
+// not set strip_symbols.
+var defaultStripSymbols = []string{"$", "€", "£", "%"}
+
+// NumberFormat controls how numeric CSV cells are read. It lets uploads
+// use thousands separators ("1,234"), currency symbols ("$5,000") and
+// percent signs ("5%", read as 5). The zero value reads US-style numbers.
+type NumberFormat struct {
+	// ThousandsSeparator is removed from numeric cells; defaults to ","
+	// (or "." when DecimalSeparator is ",").
+	ThousandsSeparator string `json:"thousands_separator,omitempty"`
+
+	// DecimalSeparator marks the fractional part; defaults to ".".
+	DecimalSeparator string `json:"decimal_separator,omitempty"`
+
+	// StripSymbols are removed from numeric cells before parsing; unset
+	// means $, €, £ and %. An empty list strips nothing.
+	StripSymbols []string `json:"strip_symbols,omitempty"`
+}
+
+func (f NumberFormat) decimal() string {
+	if f.DecimalSeparator == "" {
+		return "."
+	}
+	return f.DecimalSeparator
+}
+
+func (f NumberFormat) thousands() string {
+	if f.ThousandsSeparator != "" {
+		return f.ThousandsSeparator
+	}
+	if f.decimal() == "," {
+		return "."
+	}
+	return ","
+}
+
+func (f NumberFormat) stripSymbols() []string {
+	if f.StripSymbols == nil {
+		return defaultStripSymbols
+	}
+	return f.StripSymbols
+}
+
+// Normalize rewrites a numeric cell into the form strconv.ParseFloat
+// accepts, e.g. "$1,234.50" becomes "1234.50". Values that are not numbers
+// come back cleaned but still unparseable.
+func (f NumberFormat) Normalize(value string) string {
+	value = strings.TrimSpace(value)
+	for _, sym := range f.stripSymbols() {
+		value = strings.ReplaceAll(value, sym, "")
+	}
+	value = strings.ReplaceAll(value, f.thousands(), "")
+	if d := f.decimal(); d != "." {
+		value = strings.ReplaceAll(value, d, ".")
+	}
+	return strings.TrimSpace(value)
+}
+
+// ParseFloat parses a numeric cell after Normalize.
+func (f NumberFormat) ParseFloat(value string) (float64, error) {
+	return strconv.ParseFloat(f.Normalize(value), 64)
+}
+
+// validate checks that the separators are single, distinct characters.
+func (f NumberFormat) validate() error {
+	d, t := f.decimal(), f.thousands()
+	if len([]rune(d)) != 1 {
+		return fmt.Errorf("number_format.decimal_separator must be a single character, got %q", d)
+	}
+	if len([]rune(t)) != 1 {
+		return fmt.Errorf("number_format.thousands_separator must be a single character, got %q", t)
+	}
+	if d == t {
+		return fmt.Errorf("number_format separators must differ, both are %q", d)
+	}
+	for _, sym := range f.stripSymbols() {
+		if sym == "" || sym == d || sym == "-" {
+			return fmt.Errorf("number_format.strip_symbols cannot contain %q", sym)
+		}
+	}
+	return nil
+}
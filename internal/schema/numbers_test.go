@@ -0,0 +1,91 @@
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNumberFormat_ParseFloatDefaults(t *testing.T) {
+	var f NumberFormat
+
+	testCases := []struct {
+		in   string
+		want float64
+	}{
+		{"1,234", 1234},
+		{"$5,000", 5000},
+		{"5%", 5},
+		{" 12.5 ", 12.5},
+		{"-$1,234.56", -1234.56},
+		{"€3", 3},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := f.ParseFloat(tc.in)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	_, err := f.ParseFloat("n/a")
+	assert.Error(t, err)
+}
+
+func TestNumberFormat_DecimalComma(t *testing.T) {
+	f := NumberFormat{DecimalSeparator: ","}
+
+	got, err := f.ParseFloat("1.234,5")
+	require.NoError(t, err)
+	assert.Equal(t, 1234.5, got, "thousands separator defaults to '.' when decimals use ','")
+}
+
+func TestNumberFormat_EmptyStripSymbolsKeepsSymbols(t *testing.T) {
+	f := NumberFormat{StripSymbols: []string{}}
+
+	_, err := f.ParseFloat("$5")
+	assert.Error(t, err)
+}
+
+func TestResolve_NumberFormat(t *testing.T) {
+	global := json.RawMessage(`{
+		"site_id_column": "site_id",
+		"fields": {"site_id": {"type": "identifier", "required": true}},
+		"number_format": {"strip_symbols": ["$"]}
+	}`)
+
+	resolved, err := Resolve(global, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"$"}, resolved.NumberFormat.StripSymbols)
+
+	resolved, err = Resolve(global, json.RawMessage(`{"number_format": {"decimal_separator": ","}}`))
+	require.NoError(t, err)
+	assert.Equal(t, ",", resolved.NumberFormat.DecimalSeparator)
+	assert.Nil(t, resolved.NumberFormat.StripSymbols, "tenant format replaces the global one")
+
+	_, err = Resolve(global, json.RawMessage(`{"number_format": {"decimal_separator": ",", "thousands_separator": ","}}`))
+	assert.ErrorContains(t, err, "separators must differ")
+}
+
+func TestValidateRow_FormattedNumbers(t *testing.T) {
+	resolved := &ResolvedSchema{
+		SiteIDColumn: "site_id",
+		Fields: map[string]FieldDef{
+			"site_id":    {Type: TypeIdentifier, Required: true},
+			"population": {Type: TypePopulation, Required: true},
+			"income":     {Type: TypeNumeric, Required: true},
+			"rate":       {Type: TypePercentage, Required: true},
+		},
+	}
+
+	_, errs := ValidateRow(map[string]string{
+		"site_id":    "S1",
+		"population": "1,234",
+		"income":     "$5,000",
+		"rate":       "5%",
+	}, resolved, 2)
+
+	assert.Empty(t, errs)
+}
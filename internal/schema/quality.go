@@ -0,0 +1,56 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxQualityBuckets caps how many quality buckets a config may define.
+const MaxQualityBuckets = 10
+
+// maxQualityLabelLength caps a quality bucket label, in bytes.
+const maxQualityLabelLength = 64
+
+// QualityBucket names the quality of a factor whose normalized value is at
+// or above Threshold, for the factor's explanation reason. Buckets are
+// listed highest threshold first; a value takes the label of the first
+// bucket it reaches, and a value below every threshold takes the last one.
+type QualityBucket struct {
+	Threshold float64 `json:"threshold"`
+	Label     string  `json:"label"`
+}
+
+// QualityLabel returns the label buckets give a normalized value. buckets
+// must not be empty.
+func QualityLabel(buckets []QualityBucket, normalizedValue float64) string {
+	for _, b := range buckets {
+		if normalizedValue >= b.Threshold {
+			return b.Label
+		}
+	}
+	return buckets[len(buckets)-1].Label
+}
+
+// validateQualityBuckets checks that thresholds lie in 0-1 and strictly
+// decrease, and that every bucket has a label.
+func validateQualityBuckets(buckets []QualityBucket) error {
+	if len(buckets) > MaxQualityBuckets {
+		return fmt.Errorf("quality_buckets may define at most %d buckets, got %d", MaxQualityBuckets, len(buckets))
+	}
+	for i, b := range buckets {
+		if b.Threshold < 0 || b.Threshold > 1 {
+			return fmt.Errorf("quality_buckets.%d.threshold must be between 0 and 1, got %g", i, b.Threshold)
+		}
+		if i > 0 && b.Threshold >= buckets[i-1].Threshold {
+			return fmt.Errorf("quality_buckets must be sorted by threshold, highest first: %g follows %g", b.Threshold, buckets[i-1].Threshold)
+		}
+		label := strings.TrimSpace(b.Label)
+		if label == "" {
+			return fmt.Errorf("quality_buckets.%d.label must not be empty", i)
+		}
+		if len(label) > maxQualityLabelLength {
+			return fmt.Errorf("quality_buckets.%d.label must be at most %d characters", i, maxQualityLabelLength)
+		}
+	}
+	return nil
+}
@@ -4,19 +4,27 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
+	"strings"
+
+	"github.com/workforce-ai/site-selection-iq/internal/i18n"
 )
 
 // FieldType represents the data type of a field
 type FieldType string
 
 const (
-	TypePercentage   FieldType = "percentage"
-	TypeIndex        FieldType = "index"
-	TypeInteger      FieldType = "integer"
-	TypeNumeric      FieldType = "numeric"
-	TypePopulation   FieldType = "population"
-	TypeText         FieldType = "text"
-	TypeIdentifier   FieldType = "identifier"
+	TypePercentage FieldType = "percentage"
+	TypeIndex      FieldType = "index"
+	TypeInteger    FieldType = "integer"
+	TypeNumeric    FieldType = "numeric"
+	TypePopulation FieldType = "population"
+	TypeText       FieldType = "text"
+	TypeIdentifier FieldType = "identifier"
+
+	// TypeComputed fields are not read from the CSV; their value is derived
+	// per site from Expression over other numeric fields.
+	TypeComputed FieldType = "computed"
 )
 
 // Direction represents whether a field value should be maximized or minimized
@@ -25,34 +33,244 @@ type Direction string
 const (
 	DirectionMaximize Direction = "maximize"
 	DirectionMinimize Direction = "minimize"
+
+	// DirectionTarget scores values by proximity to the field's Target,
+	// best at the target and falling off toward the bounds.
+	DirectionTarget Direction = "target"
+)
+
+// Transform reshapes a field's values (and bounds) before normalization so
+// heavy-tailed fields such as population do not collapse toward zero
+type Transform string
+
+const (
+	TransformNone Transform = "none" // linear (default)
+	TransformLog  Transform = "log"  // ln(1+x)
+	TransformSqrt Transform = "sqrt" // square root
 )
 
+// Apply transforms v. Log and sqrt are undefined for negative values, so
+// those are clamped to 0 first; the zero value and none return v unchanged.
+func (t Transform) Apply(v float64) float64 {
+	switch t {
+	case TransformLog:
+		return math.Log1p(math.Max(v, 0))
+	case TransformSqrt:
+		return math.Sqrt(math.Max(v, 0))
+	default:
+		return v
+	}
+}
+
+// Clamps reports whether Apply clamps v before transforming it.
+func (t Transform) Clamps(v float64) bool {
+	return (t == TransformLog || t == TransformSqrt) && v < 0
+}
+
 // FieldDef defines the schema for a single field
 type FieldDef struct {
-	Type        FieldType  `json:"type"`
-	Required    bool       `json:"required"`
-	Min         *float64   `json:"min,omitempty"`
-	Max         *float64   `json:"max,omitempty"`
-	Weight      float64    `json:"weight"`
-	Direction   Direction  `json:"direction"`
-	Description string     `json:"description"`
+	Type        FieldType `json:"type"`
+	Required    bool      `json:"required"`
+	Min         *float64  `json:"min,omitempty"`
+	Max         *float64  `json:"max,omitempty"`
+	Weight      float64   `json:"weight"`
+	Direction   Direction `json:"direction"`
+	Target      *float64  `json:"target,omitempty"` // DirectionTarget only
+	Transform   Transform `json:"transform,omitempty"`
+	Description string    `json:"description"`
+	Expression  string    `json:"expression,omitempty"` // TypeComputed only
+	Category    string    `json:"category,omitempty"`   // group for two-level scoring
+
+	// UncertaintyColumn names a CSV column holding the ± error of this
+	// field's value, in the same units; it widens the site's score interval.
+	UncertaintyColumn string `json:"uncertainty_column,omitempty"`
+}
+
+// ScoreScale selects the range final scores are reported on
+type ScoreScale string
+
+const (
+	ScaleHundred ScoreScale = "hundred" // 0-100 (default)
+	ScaleTen     ScoreScale = "ten"     // 0-10
+	ScaleUnit    ScoreScale = "unit"    // 0-1
+)
+
+// Max returns the top of the scale; the bottom is always 0.
+// The zero value is the default 0-100 scale.
+func (s ScoreScale) Max() float64 {
+	switch s {
+	case ScaleTen:
+		return 10
+	case ScaleUnit:
+		return 1
+	default:
+		return 100
+	}
+}
+
+// ScoringMode selects how field values are turned into a score
+type ScoringMode string
+
+const (
+	// ModeLinear normalizes each value against the field's min/max bounds (default)
+	ModeLinear ScoringMode = "linear"
+	// ModeRankSum ranks sites per field across the whole upload and scores
+	// each site by its weighted average rank percentile
+	ModeRankSum ScoringMode = "rank_sum"
+)
+
+// ScoringOptions holds run-wide scoring behaviour that is not tied to a
+// single field. Global and tenant configs set defaults under "scoring"; a
+// run's scoring_config may override them.
+type ScoringOptions struct {
+	// TieBreakField orders sites with equal final scores by this field's
+	// value (respecting its direction) before falling back to site ID.
+	TieBreakField string `json:"tie_break_field,omitempty"`
+
+	// ScoreScale sets the range of final scores; empty means 0-100.
+	ScoreScale ScoreScale `json:"score_scale,omitempty"`
+
+	// Mode selects linear (default) or rank_sum scoring.
+	Mode ScoringMode `json:"mode,omitempty"`
+
+	// SummaryFactorCount is how many top factors the explanation summary
+	// names; unset means DefaultSummaryFactorCount.
+	SummaryFactorCount *int `json:"summary_factor_count,omitempty"`
+
+	// DefaultRanges overrides the built-in normalization range per field
+	// type for fields missing a configured min or max.
+	DefaultRanges map[FieldType]Range `json:"default_ranges,omitempty"`
+
+	// DeriveBounds uses the observed min and max of each field across the
+	// run's sites for bounds the schema does not configure.
+	DeriveBounds *bool `json:"derive_bounds,omitempty"`
+
+	// Precision is the number of decimal places scores and contributions
+	// are rounded to; unset means DefaultPrecision.
+	Precision *int `json:"precision,omitempty"`
+
+	// StoreTopN persists only the run's top N recommendations by rank;
+	// the rest are scored and counted but not stored. Unset stores all.
+	StoreTopN *int `json:"store_top_n,omitempty"`
+
+	// Freshness discounts sites with old data; nil disables it.
+	Freshness *Freshness `json:"freshness,omitempty"`
+
+	// Locale selects the language of explanation reasons and summaries;
+	// empty means the server's default locale.
+	Locale string `json:"locale,omitempty"`
+
+	// QualityBuckets replaces the default quality labels (excellent at
+	// 0.75, good at 0.5, fair at 0.25, else poor) in factor reasons. Custom
+	// labels are used as given, whatever the locale.
+	QualityBuckets []QualityBucket `json:"quality_buckets,omitempty"`
+}
+
+// DerivesBounds reports whether bounds should be derived from the run's data.
+func (o ScoringOptions) DerivesBounds() bool {
+	return o.DeriveBounds != nil && *o.DeriveBounds
+}
+
+// Range is a normalization range for a numeric field.
+type Range struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// builtinDefaultRanges are the normalization ranges used for fields without
+// configured bounds, unless overridden by ScoringOptions.DefaultRanges.
+var builtinDefaultRanges = map[FieldType]Range{
+	TypePercentage: {Min: 0, Max: 100},
+	TypeIndex:      {Min: 0, Max: 200}, // 100 = average
+	TypePopulation: {Min: 0, Max: 1000000},
+	TypeNumeric:    {Min: 0, Max: 100},
+	TypeInteger:    {Min: 0, Max: 100},
+	TypeComputed:   {Min: 0, Max: 100},
+}
+
+// DefaultRange returns the normalization range for fields of type t that
+// have no configured bounds.
+func (o ScoringOptions) DefaultRange(t FieldType) Range {
+	if r, ok := o.DefaultRanges[t]; ok {
+		return r
+	}
+	if r, ok := builtinDefaultRanges[t]; ok {
+		return r
+	}
+	return Range{Min: 0, Max: 100}
+}
+
+// DefaultSummaryFactorCount is the number of factors named in an explanation
+// summary when summary_factor_count is not configured.
+const DefaultSummaryFactorCount = 3
+
+// SummaryFactors returns the configured summary factor count or the default.
+func (o ScoringOptions) SummaryFactors() int {
+	if o.SummaryFactorCount == nil {
+		return DefaultSummaryFactorCount
+	}
+	return *o.SummaryFactorCount
+}
+
+// DefaultPrecision is the number of decimal places scores are rounded to
+// when precision is not configured; MaxPrecision is the most allowed.
+const (
+	DefaultPrecision = 2
+	MaxPrecision     = 10
+)
+
+// ScorePrecision returns the configured precision or the default.
+func (o ScoringOptions) ScorePrecision() int {
+	if o.Precision == nil {
+		return DefaultPrecision
+	}
+	return *o.Precision
 }
 
 // ResolvedSchema represents the final merged schema with all fields and weights
 type ResolvedSchema struct {
-	Fields        map[string]FieldDef `json:"fields"`
-	SiteIDColumn  string              `json:"site_id_column"`
-	Weights       map[string]float64  `json:"weights"`
+	Fields       map[string]FieldDef `json:"fields"`
+	SiteIDColumn string              `json:"site_id_column"`
+	Weights      map[string]float64  `json:"weights"`
+	Scoring      ScoringOptions      `json:"scoring"`
+	NumberFormat NumberFormat        `json:"number_format"`
+
+	// NullValues are cell values read as missing (see IsNull); nil means
+	// the defaults and an empty list disables them.
+	NullValues []string `json:"null_values"`
+
+	// MaxWeight caps every field weight; zero means DefaultMaxWeight. It is
+	// set only by the global config, so tenants cannot raise it.
+	MaxWeight float64 `json:"max_weight,omitempty"`
+
+	// CategoryWeights weights each field category. When set, scoring is
+	// two-level: fields are combined by weight within their category, and
+	// category subscores by category weight.
+	CategoryWeights map[string]float64 `json:"category_weights,omitempty"`
+
+	// DerivedBounds holds per-field bounds observed in the run's data when
+	// Scoring.DeriveBounds is set; it is recorded in the run's snapshot.
+	DerivedBounds map[string]Range `json:"derived_bounds,omitempty"`
+
+	// expressions caches parsed computed-field expressions by field name
+	expressions map[string]*Expression
 }
 
 // Resolver handles schema resolution logic
-type Resolver struct{}
+type Resolver struct {
+	cache *Cache // nil disables caching in ResolveActive
+}
 
 // NewResolver creates a new schema resolver
 func NewResolver() *Resolver {
 	return &Resolver{}
 }
 
+// SetCache makes ResolveActive serve resolved schemas from c; nil disables it.
+func (r *Resolver) SetCache(c *Cache) {
+	r.cache = c
+}
+
 // Resolve resolves a schema using the resolver
 func (r *Resolver) Resolve(ctx context.Context, config json.RawMessage, tenantConfig json.RawMessage) (*ResolvedSchema, error) {
 	return Resolve(config, tenantConfig)
@@ -60,15 +278,24 @@ func (r *Resolver) Resolve(ctx context.Context, config json.RawMessage, tenantCo
 
 // GlobalSchemaConfig represents the global schema configuration
 type GlobalSchemaConfig struct {
-	Fields       map[string]FieldDef `json:"fields"`
-	SiteIDColumn string              `json:"site_id_column"`
+	Fields          map[string]FieldDef `json:"fields"`
+	SiteIDColumn    string              `json:"site_id_column"`
+	Scoring         ScoringOptions      `json:"scoring,omitempty"`
+	NumberFormat    NumberFormat        `json:"number_format,omitempty"`
+	NullValues      []string            `json:"null_values,omitempty"`
+	CategoryWeights map[string]float64  `json:"category_weights,omitempty"`
+	MaxWeight       float64             `json:"max_weight,omitempty"`
 }
 
 // TenantSchemaOverride represents tenant-specific schema overrides
 type TenantSchemaOverride struct {
-	Fields       map[string]FieldDef `json:"fields,omitempty"`
-	SiteIDColumn *string             `json:"site_id_column,omitempty"`
-	Weights      map[string]float64  `json:"weights,omitempty"`
+	Fields          map[string]FieldDef `json:"fields,omitempty"`
+	SiteIDColumn    *string             `json:"site_id_column,omitempty"`
+	Weights         map[string]float64  `json:"weights,omitempty"`
+	Scoring         *ScoringOptions     `json:"scoring,omitempty"`
+	NumberFormat    *NumberFormat       `json:"number_format,omitempty"`
+	NullValues      *[]string           `json:"null_values,omitempty"`
+	CategoryWeights map[string]float64  `json:"category_weights,omitempty"`
 }
 
 // Resolve merges global defaults with tenant overrides to create a final resolved schema
@@ -92,6 +319,10 @@ func Resolve(globalConfig, tenantConfig json.RawMessage) (*ResolvedSchema, error
 		Fields:       make(map[string]FieldDef),
 		SiteIDColumn: global.SiteIDColumn,
 		Weights:      make(map[string]float64),
+		Scoring:      global.Scoring,
+		NumberFormat: global.NumberFormat,
+		NullValues:   global.NullValues,
+		MaxWeight:    global.MaxWeight,
 	}
 
 	// Copy global fields
@@ -99,6 +330,12 @@ func Resolve(globalConfig, tenantConfig json.RawMessage) (*ResolvedSchema, error
 		resolved.Fields[name] = fieldDef
 		resolved.Weights[name] = fieldDef.Weight
 	}
+	if len(global.CategoryWeights) > 0 {
+		resolved.CategoryWeights = make(map[string]float64, len(global.CategoryWeights))
+		for category, weight := range global.CategoryWeights {
+			resolved.CategoryWeights[category] = weight
+		}
+	}
 
 	// Parse and apply tenant overrides if provided
 	if len(tenantConfig) > 0 && string(tenantConfig) != "null" {
@@ -126,7 +363,464 @@ func Resolve(globalConfig, tenantConfig json.RawMessage) (*ResolvedSchema, error
 			}
 			resolved.Weights[name] = weight
 		}
+
+		// Tenant category weights add to or replace the global ones
+		if len(tenant.CategoryWeights) > 0 && resolved.CategoryWeights == nil {
+			resolved.CategoryWeights = make(map[string]float64, len(tenant.CategoryWeights))
+		}
+		for category, weight := range tenant.CategoryWeights {
+			resolved.CategoryWeights[category] = weight
+		}
+
+		if tenant.Scoring != nil {
+			resolved.Scoring.merge(*tenant.Scoring)
+		}
+
+		// A tenant number format replaces the global one wholesale
+		if tenant.NumberFormat != nil {
+			resolved.NumberFormat = *tenant.NumberFormat
+		}
+
+		// Tenant null values replace the global list; [] disables them
+		if tenant.NullValues != nil {
+			resolved.NullValues = *tenant.NullValues
+			if resolved.NullValues == nil {
+				resolved.NullValues = []string{}
+			}
+		}
+	}
+
+	if err := resolved.validateComputedFields(); err != nil {
+		return nil, err
+	}
+
+	if err := resolved.validateTargets(); err != nil {
+		return nil, err
+	}
+
+	if err := resolved.validateTransforms(); err != nil {
+		return nil, err
+	}
+
+	if err := resolved.validateUncertainty(); err != nil {
+		return nil, err
+	}
+
+	if err := resolved.validateMaxWeight(); err != nil {
+		return nil, err
+	}
+
+	if err := resolved.validateCategories(); err != nil {
+		return nil, err
+	}
+
+	if err := resolved.validateScoringOptions(); err != nil {
+		return nil, err
+	}
+
+	if err := resolved.NumberFormat.validate(); err != nil {
+		return nil, err
+	}
+
+	if err := resolved.validateNullValues(); err != nil {
+		return nil, err
 	}
 
 	return resolved, nil
 }
+
+// validateComputedFields parses every computed field's expression and checks
+// that it only references existing, non-computed numeric fields.
+func (s *ResolvedSchema) validateComputedFields() error {
+	for name, fieldDef := range s.Fields {
+		if fieldDef.Type != TypeComputed {
+			continue
+		}
+		if fieldDef.Expression == "" {
+			return fmt.Errorf("computed field '%s' must specify an expression", name)
+		}
+		expr, err := ParseExpression(fieldDef.Expression)
+		if err != nil {
+			return fmt.Errorf("computed field '%s': %w", name, err)
+		}
+		for _, ref := range expr.Fields() {
+			refDef, ok := s.Fields[ref]
+			if !ok {
+				return fmt.Errorf("computed field '%s' references unknown field: %s", name, ref)
+			}
+			if !refDef.Type.IsNumeric() {
+				return fmt.Errorf("computed field '%s' references non-numeric field: %s", name, ref)
+			}
+		}
+		if s.expressions == nil {
+			s.expressions = make(map[string]*Expression)
+		}
+		s.expressions[name] = expr
+	}
+	return nil
+}
+
+// validateTransforms checks that transforms are known and only set on
+// numeric fields.
+func (s *ResolvedSchema) validateTransforms() error {
+	for _, name := range sortedFieldNames(s.Fields) {
+		fieldDef := s.Fields[name]
+		switch fieldDef.Transform {
+		case "", TransformNone:
+			continue
+		case TransformLog, TransformSqrt:
+		default:
+			return fmt.Errorf("field '%s' has unknown transform '%s' (must be none, log or sqrt)", name, fieldDef.Transform)
+		}
+		if !fieldDef.Type.IsNumeric() && fieldDef.Type != TypeComputed {
+			return fmt.Errorf("field '%s' sets transform '%s' but is not numeric", name, fieldDef.Transform)
+		}
+	}
+	return nil
+}
+
+// validateUncertainty checks that only numeric fields read from the CSV
+// name an uncertainty column, and that it is not a schema field itself.
+func (s *ResolvedSchema) validateUncertainty() error {
+	for _, name := range sortedFieldNames(s.Fields) {
+		column := s.Fields[name].UncertaintyColumn
+		if column == "" {
+			continue
+		}
+		if !s.Fields[name].Type.IsNumeric() {
+			return fmt.Errorf("field '%s' sets uncertainty_column but is not a numeric CSV field", name)
+		}
+		if _, ok := s.Fields[column]; ok {
+			return fmt.Errorf("field '%s' uncertainty_column '%s' is itself a schema field", name, column)
+		}
+	}
+	return nil
+}
+
+// auxiliaryColumns returns the CSV columns the schema reads that are not
+// fields: uncertainty columns and the freshness as-of column.
+func (s *ResolvedSchema) auxiliaryColumns() map[string]bool {
+	columns := make(map[string]bool)
+	for _, fieldDef := range s.Fields {
+		if fieldDef.UncertaintyColumn != "" {
+			columns[fieldDef.UncertaintyColumn] = true
+		}
+	}
+	if f := s.Scoring.Freshness; f != nil {
+		columns[f.AsOfColumn] = true
+	}
+	return columns
+}
+
+// validateTargets checks that every target-direction field sets a target
+// inside its configured bounds, and that no other field sets one.
+func (s *ResolvedSchema) validateTargets() error {
+	for _, name := range sortedFieldNames(s.Fields) {
+		fieldDef := s.Fields[name]
+		if fieldDef.Direction != DirectionTarget {
+			if fieldDef.Target != nil {
+				return fmt.Errorf("field '%s' sets a target but its direction is not %q", name, DirectionTarget)
+			}
+			continue
+		}
+		if fieldDef.Target == nil {
+			return fmt.Errorf("field '%s' has direction %q but no target", name, DirectionTarget)
+		}
+		target := *fieldDef.Target
+		if fieldDef.Min != nil && target < *fieldDef.Min {
+			return fmt.Errorf("field '%s' target %g is below its min %g", name, target, *fieldDef.Min)
+		}
+		if fieldDef.Max != nil && target > *fieldDef.Max {
+			return fmt.Errorf("field '%s' target %g is above its max %g", name, target, *fieldDef.Max)
+		}
+	}
+	return nil
+}
+
+// Clone returns a copy of s that can be modified without affecting s:
+// maps and slices are copied; parsed expressions are shared, as they are
+// never modified after parsing.
+func (s *ResolvedSchema) Clone() *ResolvedSchema {
+	c := *s
+	c.Fields = make(map[string]FieldDef, len(s.Fields))
+	for name, fieldDef := range s.Fields {
+		c.Fields[name] = fieldDef
+	}
+	c.Weights = copyMap(s.Weights)
+	c.CategoryWeights = copyMap(s.CategoryWeights)
+	c.DerivedBounds = copyMap(s.DerivedBounds)
+	c.Scoring.DefaultRanges = copyMap(s.Scoring.DefaultRanges)
+	if s.Scoring.Freshness != nil {
+		freshness := *s.Scoring.Freshness
+		c.Scoring.Freshness = &freshness
+	}
+	if s.NullValues != nil {
+		c.NullValues = append([]string{}, s.NullValues...)
+	}
+	if s.NumberFormat.StripSymbols != nil {
+		c.NumberFormat.StripSymbols = append([]string{}, s.NumberFormat.StripSymbols...)
+	}
+	return &c
+}
+
+func copyMap[K comparable, V any](m map[K]V) map[K]V {
+	if m == nil {
+		return nil
+	}
+	out := make(map[K]V, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// Bounds returns the normalization range for a field. Each side is the
+// configured min or max if set, else the value derived from the run's data
+// (see DerivedBounds), else the type's default range. defaulted reports
+// whether a type default was used.
+func (s *ResolvedSchema) Bounds(name string) (r Range, defaulted bool) {
+	fieldDef := s.Fields[name]
+	fallback, derived := s.DerivedBounds[name]
+	if !derived {
+		fallback = s.Scoring.DefaultRange(fieldDef.Type)
+	}
+
+	r = fallback
+	if fieldDef.Min != nil {
+		r.Min = *fieldDef.Min
+	} else if !derived {
+		defaulted = true
+	}
+	if fieldDef.Max != nil {
+		r.Max = *fieldDef.Max
+	} else if !derived {
+		defaulted = true
+	}
+	return r, defaulted
+}
+
+// ComputedExpression returns the parsed expression for a computed field.
+// Schemas built without Resolve are parsed on demand.
+func (s *ResolvedSchema) ComputedExpression(name string) (*Expression, error) {
+	if expr, ok := s.expressions[name]; ok {
+		return expr, nil
+	}
+	fieldDef, ok := s.Fields[name]
+	if !ok || fieldDef.Type != TypeComputed {
+		return nil, fmt.Errorf("field '%s' is not a computed field", name)
+	}
+	return ParseExpression(fieldDef.Expression)
+}
+
+// IsNumeric reports whether values of this type are read from the CSV as numbers.
+func (t FieldType) IsNumeric() bool {
+	switch t {
+	case TypeNumeric, TypeInteger, TypePercentage, TypeIndex, TypePopulation:
+		return true
+	default:
+		return false
+	}
+}
+
+// runScoringConfig is the subset of a run's scoring_config that adjusts the
+// resolved schema. Other keys (model_version, name, ...) are ignored here.
+type runScoringConfig struct {
+	ScoringOptions
+	Weights map[string]float64 `json:"weights"`
+}
+
+// ApplyRunConfig overlays the options and weights in a run's scoring_config
+// onto the resolved schema. Empty config is a no-op.
+func (s *ResolvedSchema) ApplyRunConfig(runConfig json.RawMessage) error {
+	if len(runConfig) == 0 || string(runConfig) == "null" {
+		return nil
+	}
+
+	var cfg runScoringConfig
+	if err := json.Unmarshal(runConfig, &cfg); err != nil {
+		return fmt.Errorf("failed to parse run scoring config: %w", err)
+	}
+
+	if err := s.ValidateWeights(cfg.Weights); err != nil {
+		return err
+	}
+	for name, weight := range cfg.Weights {
+		s.Weights[name] = weight
+	}
+
+	s.Scoring.merge(cfg.ScoringOptions)
+	return s.validateScoringOptions()
+}
+
+// ValidateWeights checks that every entry of a weight override names a
+// numeric field of the schema and is neither negative nor above the
+// schema's maximum weight.
+func (s *ResolvedSchema) ValidateWeights(weights map[string]float64) error {
+	for name, weight := range weights {
+		fieldDef, ok := s.Fields[name]
+		if !ok {
+			return &ConfigError{Field: "weights." + name, Message: "unknown field"}
+		}
+		if !fieldDef.Type.IsNumeric() && fieldDef.Type != TypeComputed {
+			return &ConfigError{Field: "weights." + name, Message: fmt.Sprintf("%s field cannot be weighted", fieldDef.Type)}
+		}
+		if weight < 0 {
+			return &ConfigError{Field: "weights." + name, Message: fmt.Sprintf("must not be negative, got %g", weight)}
+		}
+		if limit := s.maxWeight(); weight > limit {
+			return &ConfigError{Field: "weights." + name, Message: fmt.Sprintf("must not exceed %g, got %g", limit, weight)}
+		}
+	}
+	return nil
+}
+
+// ApplyModelDefaults layers a scoring model version's defaults onto the
+// resolved schema: options it sets replace the schema's, and weights replace
+// those of fields the schema defines. Weights for other fields are ignored,
+// since a tenant's schema need not carry every field a model knows about.
+// Apply it before ApplyRunConfig so the run's own options still win.
+func (s *ResolvedSchema) ApplyModelDefaults(options ScoringOptions, weights map[string]float64) error {
+	for name, weight := range weights {
+		if _, exists := s.Fields[name]; exists {
+			s.Weights[name] = weight
+		}
+	}
+	s.Scoring.merge(options)
+	return s.validateScoringOptions()
+}
+
+// merge copies every option set in override onto o.
+func (o *ScoringOptions) merge(override ScoringOptions) {
+	if override.TieBreakField != "" {
+		o.TieBreakField = override.TieBreakField
+	}
+	if override.ScoreScale != "" {
+		o.ScoreScale = override.ScoreScale
+	}
+	if override.Mode != "" {
+		o.Mode = override.Mode
+	}
+	if override.SummaryFactorCount != nil {
+		o.SummaryFactorCount = override.SummaryFactorCount
+	}
+	if override.DeriveBounds != nil {
+		o.DeriveBounds = override.DeriveBounds
+	}
+	if override.Precision != nil {
+		o.Precision = override.Precision
+	}
+	if override.StoreTopN != nil {
+		o.StoreTopN = override.StoreTopN
+	}
+	if override.Freshness != nil {
+		freshness := *override.Freshness
+		o.Freshness = &freshness
+	}
+	if override.Locale != "" {
+		o.Locale = override.Locale
+	}
+	if len(override.QualityBuckets) > 0 {
+		o.QualityBuckets = append([]QualityBucket(nil), override.QualityBuckets...)
+	}
+	if len(override.DefaultRanges) > 0 {
+		merged := make(map[FieldType]Range, len(o.DefaultRanges)+len(override.DefaultRanges))
+		for t, r := range o.DefaultRanges {
+			merged[t] = r
+		}
+		for t, r := range override.DefaultRanges {
+			merged[t] = r
+		}
+		o.DefaultRanges = merged
+	}
+}
+
+// UsesCategories reports whether fields are scored in weighted categories.
+func (s *ResolvedSchema) UsesCategories() bool {
+	return len(s.CategoryWeights) > 0
+}
+
+// validateCategories checks that category weights are not negative and,
+// when categories are in use, that every weighted numeric field belongs to a
+// weighted category. A field may name a category only if it is weighted.
+func (s *ResolvedSchema) validateCategories() error {
+	for category, weight := range s.CategoryWeights {
+		if category == "" {
+			return fmt.Errorf("category_weights: category name must not be empty")
+		}
+		if weight < 0 {
+			return fmt.Errorf("category_weights: %s must not be negative, got %g", category, weight)
+		}
+	}
+
+	for name, fieldDef := range s.Fields {
+		if fieldDef.Category != "" {
+			if _, ok := s.CategoryWeights[fieldDef.Category]; !ok {
+				return fmt.Errorf("field '%s' belongs to category '%s', which has no category weight", name, fieldDef.Category)
+			}
+			continue
+		}
+		weighted := (fieldDef.Type.IsNumeric() || fieldDef.Type == TypeComputed) && fieldDef.Weight != 0
+		if s.UsesCategories() && weighted {
+			return fmt.Errorf("field '%s' is weighted but has no category", name)
+		}
+	}
+	return nil
+}
+
+// validateScoringOptions checks scoring options against the resolved fields.
+func (s *ResolvedSchema) validateScoringOptions() error {
+	if f := s.Scoring.TieBreakField; f != "" {
+		if _, ok := s.Fields[f]; !ok {
+			return fmt.Errorf("tie_break_field references unknown field: %s", f)
+		}
+	}
+	if f := s.Scoring.Freshness; f != nil {
+		if fieldDef, ok := s.Fields[f.AsOfColumn]; ok && (fieldDef.Type.IsNumeric() || fieldDef.Type == TypeComputed) {
+			return fmt.Errorf("freshness.as_of_column %s is a %s field, not a date", f.AsOfColumn, fieldDef.Type)
+		}
+	}
+	return s.Scoring.validate()
+}
+
+// validate checks the options that do not depend on the schema's fields.
+func (o ScoringOptions) validate() error {
+	switch o.ScoreScale {
+	case "", ScaleHundred, ScaleTen, ScaleUnit:
+	default:
+		return fmt.Errorf("invalid score_scale %q (must be hundred, ten, or unit)", o.ScoreScale)
+	}
+	switch o.Mode {
+	case "", ModeLinear, ModeRankSum:
+	default:
+		return fmt.Errorf("invalid scoring mode %q (must be linear or rank_sum)", o.Mode)
+	}
+	if n := o.SummaryFactorCount; n != nil && *n < 1 {
+		return fmt.Errorf("summary_factor_count must be at least 1, got %d", *n)
+	}
+	if n := o.Precision; n != nil && (*n < 0 || *n > MaxPrecision) {
+		return fmt.Errorf("precision must be between 0 and %d, got %d", MaxPrecision, *n)
+	}
+	if n := o.StoreTopN; n != nil && *n < 1 {
+		return fmt.Errorf("store_top_n must be at least 1, got %d", *n)
+	}
+	if o.Freshness != nil {
+		if err := o.Freshness.validate(); err != nil {
+			return err
+		}
+	}
+	if o.Locale != "" && !i18n.Supported(o.Locale) {
+		return fmt.Errorf("unsupported locale %q (must be one of %s)", o.Locale, strings.Join(i18n.Locales(), ", "))
+	}
+	if err := validateQualityBuckets(o.QualityBuckets); err != nil {
+		return err
+	}
+	for t, r := range o.DefaultRanges {
+		if !t.IsNumeric() && t != TypeComputed {
+			return fmt.Errorf("default_ranges: %q is not a numeric field type", t)
+		}
+		if !(r.Max > r.Min) {
+			return fmt.Errorf("default_ranges: %s max (%g) must be greater than min (%g)", t, r.Max, r.Min)
+		}
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package schema
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -253,3 +254,495 @@ func TestResolve_NullTenantConfig(t *testing.T) {
 	assert.Equal(t, "site_id", resolved.SiteIDColumn)
 	assert.Equal(t, 1.5, resolved.Weights["population"])
 }
+
+func TestResolve_ScoringOptionsLayering(t *testing.T) {
+	// Test that tenant scoring options override global, and run config overrides both
+	globalConfig := `{
+		"site_id_column": "site_id",
+		"fields": {
+			"population": {"type": "population", "weight": 1.0, "direction": "maximize"},
+			"labor_cost": {"type": "index", "weight": 1.0, "direction": "minimize"}
+		},
+		"scoring": {"tie_break_field": "population"}
+	}`
+
+	resolved, err := Resolve(json.RawMessage(globalConfig), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "population", resolved.Scoring.TieBreakField)
+
+	tenantConfig := `{"scoring": {"tie_break_field": "labor_cost"}}`
+	resolved, err = Resolve(json.RawMessage(globalConfig), json.RawMessage(tenantConfig))
+	require.NoError(t, err)
+	assert.Equal(t, "labor_cost", resolved.Scoring.TieBreakField)
+
+	require.NoError(t, resolved.ApplyRunConfig(json.RawMessage(`{"model_version": "latest", "tie_break_field": "population"}`)))
+	assert.Equal(t, "population", resolved.Scoring.TieBreakField)
+
+	// Empty run config leaves options alone
+	require.NoError(t, resolved.ApplyRunConfig(nil))
+	assert.Equal(t, "population", resolved.Scoring.TieBreakField)
+}
+
+func TestResolve_TieBreakUnknownField(t *testing.T) {
+	// Test that a tie-break field must exist in the resolved schema
+	globalConfig := `{
+		"site_id_column": "site_id",
+		"fields": {
+			"population": {"type": "population", "weight": 1.0, "direction": "maximize"}
+		}
+	}`
+
+	_, err := Resolve(json.RawMessage(globalConfig), json.RawMessage(`{"scoring": {"tie_break_field": "nope"}}`))
+	assert.ErrorContains(t, err, "tie_break_field")
+
+	resolved, err := Resolve(json.RawMessage(globalConfig), nil)
+	require.NoError(t, err)
+	assert.ErrorContains(t, resolved.ApplyRunConfig(json.RawMessage(`{"tie_break_field": "nope"}`)), "unknown field")
+}
+
+func TestResolve_ScoreScaleValidation(t *testing.T) {
+	// Test that score_scale accepts known scales and rejects others
+	globalConfig := `{
+		"site_id_column": "site_id",
+		"fields": {
+			"population": {"type": "population", "weight": 1.0, "direction": "maximize"}
+		}
+	}`
+
+	resolved, err := Resolve(json.RawMessage(globalConfig), json.RawMessage(`{"scoring": {"score_scale": "ten"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, ScaleTen, resolved.Scoring.ScoreScale)
+	assert.Equal(t, 10.0, resolved.Scoring.ScoreScale.Max())
+	assert.Equal(t, 100.0, ScoreScale("").Max())
+
+	assert.Error(t, resolved.ApplyRunConfig(json.RawMessage(`{"score_scale": "thousand"}`)))
+}
+
+func TestResolve_SummaryFactorCount(t *testing.T) {
+	// Test that summary_factor_count defaults to 3 and must be at least 1
+	globalConfig := `{
+		"site_id_column": "site_id",
+		"fields": {
+			"population": {"type": "population", "weight": 1.0, "direction": "maximize"}
+		}
+	}`
+
+	resolved, err := Resolve(json.RawMessage(globalConfig), nil)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultSummaryFactorCount, resolved.Scoring.SummaryFactors())
+
+	require.NoError(t, resolved.ApplyRunConfig(json.RawMessage(`{"summary_factor_count": 5}`)))
+	assert.Equal(t, 5, resolved.Scoring.SummaryFactors())
+
+	assert.ErrorContains(t, resolved.ApplyRunConfig(json.RawMessage(`{"summary_factor_count": 0}`)), "at least 1")
+}
+
+func TestResolve_Locale(t *testing.T) {
+	// Test that a run may pick a supported explanation locale and no other
+	globalConfig := `{
+		"site_id_column": "site_id",
+		"fields": {
+			"population": {"type": "population", "weight": 1.0, "direction": "maximize"}
+		}
+	}`
+
+	resolved, err := Resolve(json.RawMessage(globalConfig), nil)
+	require.NoError(t, err)
+	assert.Empty(t, resolved.Scoring.Locale)
+
+	require.NoError(t, resolved.ApplyRunConfig(json.RawMessage(`{"locale": "es"}`)))
+	assert.Equal(t, "es", resolved.Scoring.Locale)
+
+	assert.ErrorContains(t, resolved.ApplyRunConfig(json.RawMessage(`{"locale": "fr"}`)), `unsupported locale "fr"`)
+}
+
+func TestResolve_QualityBuckets(t *testing.T) {
+	// Test that quality buckets layer like other options and are validated
+	globalConfig := `{
+		"site_id_column": "site_id",
+		"fields": {
+			"population": {"type": "population", "weight": 1.0, "direction": "maximize"}
+		},
+		"scoring": {"quality_buckets": [{"threshold": 0.8, "label": "top tier"}, {"threshold": 0, "label": "other"}]}
+	}`
+
+	resolved, err := Resolve(json.RawMessage(globalConfig), nil)
+	require.NoError(t, err)
+	require.Len(t, resolved.Scoring.QualityBuckets, 2)
+	assert.Equal(t, "top tier", QualityLabel(resolved.Scoring.QualityBuckets, 0.8))
+	assert.Equal(t, "other", QualityLabel(resolved.Scoring.QualityBuckets, 0.79))
+
+	require.NoError(t, resolved.ApplyRunConfig(json.RawMessage(`{"quality_buckets": [{"threshold": 0.5, "label": "above median"}]}`)))
+	assert.Equal(t, []QualityBucket{{Threshold: 0.5, Label: "above median"}}, resolved.Scoring.QualityBuckets, "a run's buckets replace the schema's")
+
+	tests := []struct {
+		buckets string
+		message string
+	}{
+		{`[{"threshold": 0.25, "label": "low"}, {"threshold": 0.75, "label": "high"}]`, "sorted by threshold, highest first"},
+		{`[{"threshold": 0.5, "label": "a"}, {"threshold": 0.5, "label": "b"}]`, "sorted by threshold, highest first"},
+		{`[{"threshold": 1.5, "label": "great"}]`, "quality_buckets.0.threshold must be between 0 and 1"},
+		{`[{"threshold": 0.5, "label": "good"}, {"threshold": 0.2, "label": "  "}]`, "quality_buckets.1.label must not be empty"},
+	}
+	for _, tt := range tests {
+		err := ValidateScoringConfig(json.RawMessage(`{"quality_buckets": ` + tt.buckets + `}`))
+		assert.ErrorContains(t, err, tt.message, tt.buckets)
+	}
+}
+
+func TestResolve_DefaultRanges(t *testing.T) {
+	// Test that unbounded fields take their type's default range, which
+	// global, tenant and run configs can override per type
+	globalConfig := `{
+		"site_id_column": "site_id",
+		"fields": {
+			"population": {"type": "population", "min": 0, "weight": 1.0, "direction": "maximize"},
+			"unemployment": {"type": "percentage", "min": 0, "max": 50, "weight": 1.0, "direction": "minimize"},
+			"revenue": {"type": "numeric", "weight": 1.0, "direction": "maximize"}
+		},
+		"scoring": {"default_ranges": {"numeric": {"min": 0, "max": 5000}}}
+	}`
+
+	resolved, err := Resolve(json.RawMessage(globalConfig), json.RawMessage(`{
+		"scoring": {"default_ranges": {"population": {"min": 0, "max": 250000}}}
+	}`))
+	require.NoError(t, err)
+
+	bounds, defaulted := resolved.Bounds("unemployment")
+	assert.False(t, defaulted)
+	assert.Equal(t, Range{Min: 0, Max: 50}, bounds)
+
+	bounds, defaulted = resolved.Bounds("population")
+	assert.True(t, defaulted, "max comes from the tenant's population range")
+	assert.Equal(t, Range{Min: 0, Max: 250000}, bounds)
+
+	bounds, _ = resolved.Bounds("revenue")
+	assert.Equal(t, Range{Min: 0, Max: 5000}, bounds, "global numeric range still applies")
+
+	assert.Equal(t, Range{Min: 0, Max: 1000000}, ScoringOptions{}.DefaultRange(TypePopulation))
+}
+
+func TestResolve_DefaultRangesValidation(t *testing.T) {
+	globalConfig := `{
+		"site_id_column": "site_id",
+		"fields": {"population": {"type": "population", "weight": 1.0, "direction": "maximize"}}
+	}`
+
+	testCases := []struct {
+		runConfig string
+		expected  string
+	}{
+		{`{"default_ranges": {"population": {"min": 10, "max": 10}}}`, "must be greater than min"},
+		{`{"default_ranges": {"text": {"min": 0, "max": 1}}}`, "not a numeric field type"},
+	}
+
+	for _, tc := range testCases {
+		resolved, err := Resolve(json.RawMessage(globalConfig), nil)
+		require.NoError(t, err)
+		assert.ErrorContains(t, resolved.ApplyRunConfig(json.RawMessage(tc.runConfig)), tc.expected)
+	}
+}
+
+func TestResolve_RunConfigWeights(t *testing.T) {
+	// Test that run-level weights override the schema and are validated
+	globalConfig := `{
+		"site_id_column": "site_id",
+		"fields": {
+			"site_id": {"type": "identifier", "required": true},
+			"population": {"type": "population", "weight": 1.0, "direction": "maximize"},
+			"labor_cost": {"type": "numeric", "weight": 1.0, "direction": "minimize"}
+		}
+	}`
+
+	resolved, err := Resolve(json.RawMessage(globalConfig), nil)
+	require.NoError(t, err)
+	require.NoError(t, resolved.ApplyRunConfig(json.RawMessage(`{"weights": {"labor_cost": 3}}`)))
+	assert.Equal(t, 3.0, resolved.Weights["labor_cost"])
+	assert.Equal(t, 1.0, resolved.Weights["population"])
+
+	var cfgErr *ConfigError
+	err = resolved.ValidateWeights(map[string]float64{"nope": 1})
+	require.ErrorAs(t, err, &cfgErr)
+	assert.Equal(t, "weights.nope", cfgErr.Field)
+
+	assert.ErrorContains(t, resolved.ValidateWeights(map[string]float64{"site_id": 1}), "cannot be weighted")
+	assert.ErrorContains(t, resolved.ValidateWeights(map[string]float64{"population": -1}), "must not be negative")
+	assert.ErrorContains(t, resolved.ApplyRunConfig(json.RawMessage(`{"weights": {"nope": 1}}`)), "unknown field")
+}
+
+func TestResolve_CategoryWeights(t *testing.T) {
+	// Test that categories are parsed, overridden per tenant and validated
+	globalConfig := `{
+		"site_id_column": "site_id",
+		"category_weights": {"Economic": 2, "Talent": 1},
+		"fields": {
+			"site_id": {"type": "identifier", "required": true},
+			"wage": {"type": "numeric", "weight": 1, "direction": "minimize", "category": "Economic"},
+			"labor_pool": {"type": "numeric", "weight": 1, "direction": "maximize", "category": "Talent"}
+		}
+	}`
+
+	resolved, err := Resolve(json.RawMessage(globalConfig), json.RawMessage(`{"category_weights": {"Talent": 3}}`))
+	require.NoError(t, err)
+	assert.True(t, resolved.UsesCategories())
+	assert.Equal(t, map[string]float64{"Economic": 2, "Talent": 3}, resolved.CategoryWeights)
+	assert.Equal(t, "Economic", resolved.Fields["wage"].Category)
+
+	_, err = Resolve(json.RawMessage(globalConfig), json.RawMessage(`{"category_weights": {"Talent": -1}}`))
+	assert.ErrorContains(t, err, "must not be negative")
+
+	// A tenant field in an unweighted category is rejected
+	_, err = Resolve(json.RawMessage(globalConfig), json.RawMessage(`{
+		"fields": {"transit": {"type": "numeric", "weight": 1, "direction": "maximize", "category": "Infrastructure"}}
+	}`))
+	assert.ErrorContains(t, err, "has no category weight")
+
+	// Once categories are in use every weighted field needs one
+	_, err = Resolve(json.RawMessage(globalConfig), json.RawMessage(`{
+		"fields": {"transit": {"type": "numeric", "weight": 1, "direction": "maximize"}}
+	}`))
+	assert.ErrorContains(t, err, "has no category")
+
+	flat, err := Resolve(json.RawMessage(`{
+		"site_id_column": "site_id",
+		"fields": {"wage": {"type": "numeric", "weight": 1, "direction": "minimize"}}
+	}`), nil)
+	require.NoError(t, err)
+	assert.False(t, flat.UsesCategories())
+}
+
+func TestApplyRunConfig_Precision(t *testing.T) {
+	resolved, err := Resolve(json.RawMessage(`{
+		"site_id_column": "site_id",
+		"fields": {"wage": {"type": "numeric", "weight": 1}}
+	}`), nil)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultPrecision, resolved.Scoring.ScorePrecision())
+
+	require.NoError(t, resolved.ApplyRunConfig(json.RawMessage(`{"precision": 0}`)))
+	assert.Equal(t, 0, resolved.Scoring.ScorePrecision())
+
+	assert.ErrorContains(t, resolved.ApplyRunConfig(json.RawMessage(`{"precision": 11}`)), "precision must be between 0 and 10")
+	assert.Error(t, ValidateScoringConfig(json.RawMessage(`{"precision": -1}`)))
+}
+
+func TestApplyRunConfig_StoreTopN(t *testing.T) {
+	resolved, err := Resolve(json.RawMessage(`{
+		"site_id_column": "site_id",
+		"fields": {"wage": {"type": "numeric", "weight": 1}}
+	}`), nil)
+	require.NoError(t, err)
+	assert.Nil(t, resolved.Scoring.StoreTopN, "stores every recommendation by default")
+
+	require.NoError(t, resolved.ApplyRunConfig(json.RawMessage(`{"store_top_n": 250}`)))
+	require.NotNil(t, resolved.Scoring.StoreTopN)
+	assert.Equal(t, 250, *resolved.Scoring.StoreTopN)
+
+	assert.ErrorContains(t, resolved.ApplyRunConfig(json.RawMessage(`{"store_top_n": 0}`)), "store_top_n must be at least 1")
+	assert.Error(t, ValidateScoringConfig(json.RawMessage(`{"store_top_n": -5}`)))
+}
+
+func TestResolve_NullValues(t *testing.T) {
+	// Test that null values come from the global config and can be replaced
+	// or disabled per tenant
+	globalConfig := json.RawMessage(`{
+		"site_id_column": "site_id",
+		"null_values": ["NA", "--"],
+		"fields": {"site_id": {"type": "identifier", "required": true}}
+	}`)
+
+	resolved, err := Resolve(globalConfig, nil)
+	require.NoError(t, err)
+	assert.True(t, resolved.IsNull("--"))
+	assert.False(t, resolved.IsNull("N/A"))
+
+	resolved, err = Resolve(globalConfig, json.RawMessage(`{"null_values": ["unknown"]}`))
+	require.NoError(t, err)
+	assert.True(t, resolved.IsNull("Unknown"))
+	assert.False(t, resolved.IsNull("NA"))
+
+	resolved, err = Resolve(globalConfig, json.RawMessage(`{"null_values": []}`))
+	require.NoError(t, err)
+	assert.False(t, resolved.IsNull("NA"))
+
+	_, err = Resolve(globalConfig, json.RawMessage(`{"null_values": [" "]}`))
+	assert.ErrorContains(t, err, "null_values cannot contain a blank value")
+
+	// Without configuration the defaults apply
+	defaults, err := Resolve(json.RawMessage(`{
+		"site_id_column": "site_id",
+		"fields": {"site_id": {"type": "identifier", "required": true}}
+	}`), nil)
+	require.NoError(t, err)
+	assert.True(t, defaults.IsNull("N/A"))
+	assert.False(t, defaults.IsNull(""))
+}
+
+func TestResolve_MaxWeight(t *testing.T) {
+	// Test that weights above the cap are rejected wherever they are set
+	globalConfig := json.RawMessage(`{
+		"site_id_column": "site_id",
+		"fields": {
+			"site_id": {"type": "identifier", "required": true},
+			"population": {"type": "population", "weight": 1},
+			"income": {"type": "numeric", "weight": 1}
+		}
+	}`)
+
+	_, err := Resolve(globalConfig, json.RawMessage(`{"weights": {"population": 1e9}}`))
+	assert.EqualError(t, err, "weight for field 'population' is 1e+09, above the maximum of 100")
+
+	_, err = Resolve(globalConfig, json.RawMessage(`{"fields": {"income": {"type": "numeric", "weight": 101}}}`))
+	assert.ErrorContains(t, err, "above the maximum of 100")
+
+	resolved, err := Resolve(globalConfig, json.RawMessage(`{"weights": {"population": 100}}`))
+	require.NoError(t, err)
+	err = resolved.ApplyRunConfig(json.RawMessage(`{"weights": {"income": 500}}`))
+	var cfgErr *ConfigError
+	require.ErrorAs(t, err, &cfgErr)
+	assert.Equal(t, "weights.income", cfgErr.Field)
+	assert.Equal(t, "must not exceed 100, got 500", cfgErr.Message)
+
+	// The global config sets the cap
+	raised, err := Resolve(json.RawMessage(`{
+		"site_id_column": "site_id",
+		"max_weight": 1000,
+		"fields": {"population": {"type": "population", "weight": 500}}
+	}`), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 500.0, raised.Weights["population"])
+}
+
+func TestResolve_TargetDirection(t *testing.T) {
+	resolved, err := Resolve(json.RawMessage(`{
+		"site_id_column": "site_id",
+		"fields": {"commute_minutes": {"type": "numeric", "weight": 1, "direction": "target", "target": 25, "min": 0, "max": 90}}
+	}`), nil)
+	require.NoError(t, err)
+	require.NotNil(t, resolved.Fields["commute_minutes"].Target)
+	assert.Equal(t, 25.0, *resolved.Fields["commute_minutes"].Target)
+
+	testCases := []struct {
+		field    string
+		expected string
+	}{
+		{`{"type": "numeric", "weight": 1, "direction": "target"}`, "has direction \"target\" but no target"},
+		{`{"type": "numeric", "weight": 1, "direction": "target", "target": -5, "min": 0}`, "target -5 is below its min 0"},
+		{`{"type": "numeric", "weight": 1, "direction": "target", "target": 120, "max": 90}`, "target 120 is above its max 90"},
+		{`{"type": "numeric", "weight": 1, "direction": "maximize", "target": 25}`, "sets a target but its direction is not \"target\""},
+	}
+
+	for _, tc := range testCases {
+		_, err := Resolve(json.RawMessage(`{"site_id_column": "site_id", "fields": {"commute_minutes": `+tc.field+`}}`), nil)
+		assert.ErrorContains(t, err, tc.expected)
+	}
+}
+
+func TestResolve_Transforms(t *testing.T) {
+	resolved, err := Resolve(json.RawMessage(`{
+		"site_id_column": "site_id",
+		"fields": {"population": {"type": "population", "weight": 1, "transform": "log"}}
+	}`), nil)
+	require.NoError(t, err)
+	assert.Equal(t, TransformLog, resolved.Fields["population"].Transform)
+
+	_, err = Resolve(json.RawMessage(`{
+		"site_id_column": "site_id",
+		"fields": {"population": {"type": "population", "weight": 1, "transform": "cube"}}
+	}`), nil)
+	assert.EqualError(t, err, "field 'population' has unknown transform 'cube' (must be none, log or sqrt)")
+
+	_, err = Resolve(json.RawMessage(`{
+		"site_id_column": "site_id",
+		"fields": {"site_name": {"type": "text", "transform": "sqrt"}}
+	}`), nil)
+	assert.EqualError(t, err, "field 'site_name' sets transform 'sqrt' but is not numeric")
+}
+
+func TestResolvedSchema_WeightWarnings(t *testing.T) {
+	// Test that a field carrying most of the total weight is flagged
+	globalConfig := json.RawMessage(`{
+		"site_id_column": "site_id",
+		"fields": {
+			"site_id": {"type": "identifier", "required": true},
+			"population": {"type": "population", "weight": 1},
+			"income": {"type": "numeric", "weight": 1},
+			"growth": {"type": "percentage", "weight": 1}
+		}
+	}`)
+
+	balanced, err := Resolve(globalConfig, nil)
+	require.NoError(t, err)
+	assert.Empty(t, balanced.WeightWarnings())
+
+	dominant, err := Resolve(globalConfig, json.RawMessage(`{"weights": {"population": 18}}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"field 'population' carries 90% of the total weight; scores will mostly reflect it alone",
+	}, dominant.WeightWarnings())
+
+	// A single weighted field is trivially all of the weight
+	single, err := Resolve(globalConfig, json.RawMessage(`{"weights": {"income": 0, "growth": 0}}`))
+	require.NoError(t, err)
+	assert.Empty(t, single.WeightWarnings())
+}
+
+func TestResolve_Freshness(t *testing.T) {
+	globalConfig := json.RawMessage(`{
+		"site_id_column": "site_id",
+		"fields": {
+			"site_id": {"type": "identifier", "required": true},
+			"population": {"type": "population", "weight": 1}
+		}
+	}`)
+
+	resolved, err := Resolve(globalConfig, json.RawMessage(`{"scoring": {"freshness": {"as_of_column": "collected_on", "half_life_days": 180}}}`))
+	require.NoError(t, err)
+	require.NotNil(t, resolved.Scoring.Freshness)
+	assert.Equal(t, "collected_on", resolved.Scoring.Freshness.AsOfColumn)
+
+	// Pinning a clone's reference date leaves the original untouched
+	clone := resolved.Clone()
+	clone.Scoring.Freshness.Pin(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, "2024-06-01", clone.Scoring.Freshness.ReferenceDate)
+	assert.Empty(t, resolved.Scoring.Freshness.ReferenceDate)
+
+	for config, wantErr := range map[string]string{
+		`{"half_life_days": 30}`:                                                           "freshness.as_of_column must be set",
+		`{"as_of_column": "collected_on", "half_life_days": 0}`:                            "freshness.half_life_days must be positive, got 0",
+		`{"as_of_column": "collected_on", "half_life_days": 30, "reference_date": "June"}`: `freshness.reference_date must be a YYYY-MM-DD date, got "June"`,
+		`{"as_of_column": "population", "half_life_days": 30}`:                             "freshness.as_of_column population is a population field, not a date",
+	} {
+		_, err := Resolve(globalConfig, json.RawMessage(`{"scoring": {"freshness": `+config+`}}`))
+		assert.EqualError(t, err, wantErr, config)
+	}
+}
+
+func TestParseAsOf(t *testing.T) {
+	day, ok := ParseAsOf(" 2024-03-05 ")
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), day)
+
+	_, ok = ParseAsOf("2024-03-05T10:00:00Z")
+	assert.True(t, ok)
+
+	for _, v := range []interface{}{"", "03/05/2024", 20240305.0, nil} {
+		_, ok := ParseAsOf(v)
+		assert.False(t, ok, "%v", v)
+	}
+}
+
+func TestResolve_UncertaintyColumn(t *testing.T) {
+	resolve := func(fields string) error {
+		_, err := Resolve(json.RawMessage(`{"site_id_column": "site_id", "fields": `+fields+`}`), nil)
+		return err
+	}
+
+	assert.NoError(t, resolve(`{"population": {"type": "population", "weight": 1, "uncertainty_column": "population_moe"}}`))
+	assert.EqualError(t, resolve(`{"name": {"type": "text", "uncertainty_column": "name_err"}}`),
+		"field 'name' sets uncertainty_column but is not a numeric CSV field")
+	assert.EqualError(t, resolve(`{
+		"population": {"type": "population", "weight": 1, "uncertainty_column": "income"},
+		"income": {"type": "numeric", "weight": 1}
+	}`), "field 'population' uncertainty_column 'income' is itself a schema field")
+}
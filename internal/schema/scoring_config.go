@@ -0,0 +1,170 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ScoringConfig is the documented shape of a run's scoring_config body.
+// It is used for strict validation only; the pipeline reads the options it
+// needs through ResolvedSchema.ApplyRunConfig.
+type ScoringConfig struct {
+	ModelVersion string              `json:"model_version,omitempty"`
+	Scorer       string              `json:"scorer,omitempty"`
+	Name         string              `json:"name,omitempty"`
+	Description  string              `json:"description,omitempty"`
+	WeightPreset string              `json:"weight_preset,omitempty"`
+	Weights      map[string]float64  `json:"weights,omitempty"`
+	Factors      []ScoringFactor     `json:"factors,omitempty"`
+	Constraints  []ScoringConstraint `json:"constraints,omitempty"`
+	ScoringOptions
+}
+
+// ScoringFactor is a single factor entry in a scoring_config.
+type ScoringFactor struct {
+	FactorID          string  `json:"factor_id"`
+	Name              string  `json:"name"`
+	Weight            float64 `json:"weight"`
+	DataColumn        string  `json:"data_column"`
+	AggregationMethod string  `json:"aggregation_method,omitempty"`
+}
+
+// ScoringConstraint is a single site filter in a scoring_config.
+type ScoringConstraint struct {
+	ConstraintID string      `json:"constraint_id"`
+	Field        string      `json:"field"`
+	Operator     string      `json:"operator"`
+	Value        interface{} `json:"value"`
+}
+
+// ConfigError describes why a config body was rejected. Field is the dotted
+// JSON path of the offending key when known (e.g. "factors.0.weight").
+type ConfigError struct {
+	Field   string
+	Message string
+}
+
+func (e *ConfigError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidateScoringConfig strictly validates a run's scoring_config: unknown
+// keys and wrongly typed values are rejected, as are invalid option values.
+// Options that depend on the schema (tie_break_field) are checked when the
+// run is scored. Empty config is valid.
+func ValidateScoringConfig(raw json.RawMessage) error {
+	if isEmptyJSON(raw) {
+		return nil
+	}
+
+	var cfg ScoringConfig
+	if err := decodeStrict(raw, &cfg); err != nil {
+		return err
+	}
+	if err := cfg.ScoringOptions.validate(); err != nil {
+		return &ConfigError{Message: err.Error()}
+	}
+	for name, weight := range cfg.Weights {
+		if weight < 0 {
+			return &ConfigError{Field: "weights." + name, Message: fmt.Sprintf("must not be negative, got %g", weight)}
+		}
+	}
+	for i, f := range cfg.Factors {
+		if f.Weight < 0 {
+			return &ConfigError{Field: fmt.Sprintf("factors.%d.weight", i), Message: fmt.Sprintf("must not be negative, got %g", f.Weight)}
+		}
+	}
+	return nil
+}
+
+// ValidateTenantOverride strictly validates a tenant schema override body
+// with the same rules as ValidateScoringConfig. Whether the override
+// resolves against the global config is checked separately by Resolve.
+func ValidateTenantOverride(raw json.RawMessage) error {
+	if isEmptyJSON(raw) {
+		return &ConfigError{Message: "override body is required"}
+	}
+
+	var override TenantSchemaOverride
+	if err := decodeStrict(raw, &override); err != nil {
+		return err
+	}
+	if override.Scoring != nil {
+		if err := override.Scoring.validate(); err != nil {
+			return &ConfigError{Field: "scoring", Message: err.Error()}
+		}
+	}
+	return nil
+}
+
+// decodeStrict decodes raw into v, rejecting unknown fields and trailing
+// data, and converts decoder errors into a *ConfigError.
+func decodeStrict(raw json.RawMessage, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(v); err != nil {
+		return configErrorFrom(err)
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return &ConfigError{Message: "unexpected data after JSON object"}
+	}
+	return nil
+}
+
+// configErrorFrom turns encoding/json errors into descriptive ConfigErrors.
+func configErrorFrom(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		if typeErr.Field == "" {
+			return &ConfigError{Message: fmt.Sprintf("body must be %s", jsonTypeName(typeErr.Type.Kind().String()))}
+		}
+		return &ConfigError{
+			Field:   typeErr.Field,
+			Message: fmt.Sprintf("must be %s, got %s", jsonTypeName(typeErr.Type.Kind().String()), typeErr.Value),
+		}
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return &ConfigError{Message: fmt.Sprintf("malformed JSON at offset %d: %v", syntaxErr.Offset, syntaxErr)}
+	}
+
+	// The decoder reports unknown keys only as `json: unknown field "x"`
+	if name, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return &ConfigError{Field: strings.Trim(name, `"`), Message: "unknown field"}
+	}
+
+	return &ConfigError{Message: err.Error()}
+}
+
+// jsonTypeName maps a Go kind to the JSON type a client should send.
+func jsonTypeName(kind string) string {
+	switch kind {
+	case "float32", "float64", "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64":
+		return "a number"
+	case "string":
+		return "a string"
+	case "bool":
+		return "a boolean"
+	case "slice", "array":
+		return "an array"
+	case "map", "struct", "ptr":
+		return "an object"
+	default:
+		return kind
+	}
+}
+
+func isEmptyJSON(raw json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(raw)
+	return len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null"))
+}
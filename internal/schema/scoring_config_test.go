@@ -0,0 +1,103 @@
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateScoringConfig_Valid(t *testing.T) {
+	raw := json.RawMessage(`{
+		"model_version": "site-selection-iq-v1.0",
+		"name": "Strategic Growth Markets",
+		"factors": [
+			{"factor_id": "POP", "name": "Population", "weight": 0.25, "data_column": "population"}
+		],
+		"constraints": [
+			{"constraint_id": "MIN_POP", "field": "population", "operator": "gte", "value": 50000}
+		],
+		"tie_break_field": "population",
+		"score_scale": "ten",
+		"mode": "rank_sum",
+		"summary_factor_count": 2
+	}`)
+
+	assert.NoError(t, ValidateScoringConfig(raw))
+	assert.NoError(t, ValidateScoringConfig(nil))
+	assert.NoError(t, ValidateScoringConfig(json.RawMessage(`null`)))
+}
+
+func TestValidateScoringConfig_TypoedKey(t *testing.T) {
+	raw := json.RawMessage(`{
+		"factors": [
+			{"factor_id": "POP", "name": "Population", "wieght": 0.25, "data_column": "population"}
+		]
+	}`)
+
+	err := ValidateScoringConfig(raw)
+
+	var cfgErr *ConfigError
+	require.ErrorAs(t, err, &cfgErr)
+	assert.Equal(t, "wieght", cfgErr.Field)
+	assert.Equal(t, "wieght: unknown field", err.Error())
+}
+
+func TestValidateScoringConfig_WrongTypedWeight(t *testing.T) {
+	raw := json.RawMessage(`{
+		"factors": [
+			{"factor_id": "POP", "name": "Population", "weight": "heavy", "data_column": "population"}
+		]
+	}`)
+
+	err := ValidateScoringConfig(raw)
+
+	var cfgErr *ConfigError
+	require.ErrorAs(t, err, &cfgErr)
+	assert.Equal(t, "factors.0.weight", cfgErr.Field)
+	assert.Equal(t, "factors.0.weight: must be a number, got string", err.Error())
+}
+
+func TestValidateScoringConfig_Rejections(t *testing.T) {
+	testCases := []struct {
+		name     string
+		raw      string
+		expected string
+	}{
+		{"unknown top-level key", `{"scoring_mode": "linear"}`, "scoring_mode: unknown field"},
+		{"invalid option value", `{"score_scale": "thousand"}`, "invalid score_scale"},
+		{"wrong-typed option", `{"summary_factor_count": "3"}`, "summary_factor_count: must be a number, got string"},
+		{"negative weight", `{"factors": [{"factor_id": "A", "name": "A", "weight": -1, "data_column": "a"}]}`, "factors.0.weight: must not be negative"},
+		{"not an object", `["mode"]`, "body must be an object"},
+		{"trailing data", `{"mode": "linear"} {}`, "unexpected data after JSON object"},
+		{"malformed", `{"mode": }`, "malformed JSON"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateScoringConfig(json.RawMessage(tc.raw))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expected)
+		})
+	}
+}
+
+func TestValidateTenantOverride(t *testing.T) {
+	assert.NoError(t, ValidateTenantOverride(json.RawMessage(`{
+		"weights": {"unemployment_rate": 0.5},
+		"fields": {"median_income": {"type": "numeric", "weight": 0.2, "direction": "maximize"}},
+		"scoring": {"mode": "rank_sum"}
+	}`)))
+
+	err := ValidateTenantOverride(json.RawMessage(`{"fields": {"median_income": {"type": "numeric", "wieght": 0.2}}}`))
+	assert.EqualError(t, err, "wieght: unknown field")
+
+	err = ValidateTenantOverride(json.RawMessage(`{"weights": {"unemployment_rate": "high"}}`))
+	assert.EqualError(t, err, "weights.unemployment_rate: must be a number, got string")
+
+	err = ValidateTenantOverride(json.RawMessage(`{"scoring": {"mode": "fastest"}}`))
+	assert.ErrorContains(t, err, "scoring: invalid scoring mode")
+
+	assert.Error(t, ValidateTenantOverride(nil))
+}
@@ -0,0 +1,51 @@
+package schema
+
+import "strings"
+
+// suggestHeader returns the header closest to name by edit distance,
+// ignoring case, or "" if none is close enough to be a likely typo.
+// Headers that are themselves schema fields (or the site ID column) are
+// never suggested, since they already match something else. Ties go to the
+// header that appears first.
+func suggestHeader(name string, headers []string, schema *ResolvedSchema) string {
+	best, bestDistance := "", maxSuggestionDistance(name)+1
+	target := strings.ToLower(name)
+	for _, header := range headers {
+		if _, isField := schema.Fields[header]; isField || header == schema.SiteIDColumn {
+			continue
+		}
+		if d := levenshtein(target, strings.ToLower(header)); d < bestDistance {
+			best, bestDistance = header, d
+		}
+	}
+	return best
+}
+
+// maxSuggestionDistance is the largest edit distance at which a header is
+// still suggested for name: about one edit per three characters, at most 3.
+func maxSuggestionDistance(name string) int {
+	return min(3, max(1, len(name)/3))
+}
+
+// levenshtein returns the edit distance between a and b, counting
+// insertions, deletions and substitutions of runes.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -15,21 +15,28 @@ func ValidateHeaders(headers []string, schema *ResolvedSchema) (warnings []strin
 
 	// Check for required fields
 	for fieldName, fieldDef := range schema.Fields {
+		// Computed fields are derived at scoring time, never read from the CSV
+		if fieldDef.Type == TypeComputed {
+			continue
+		}
 		if fieldDef.Required {
 			if !headerSet[fieldName] {
-				errors = append(errors, fmt.Sprintf("required field '%s' not found in headers", fieldName))
+				errors = append(errors, fmt.Sprintf("required field '%s' not found in headers", fieldName)+
+					didYouMean(fieldName, headers, schema))
 			}
 		}
 	}
 
 	// Check for site_id_column
 	if !headerSet[schema.SiteIDColumn] {
-		errors = append(errors, fmt.Sprintf("site_id_column '%s' not found in headers", schema.SiteIDColumn))
+		errors = append(errors, fmt.Sprintf("site_id_column '%s' not found in headers", schema.SiteIDColumn)+
+			didYouMean(schema.SiteIDColumn, headers, schema))
 	}
 
 	// Flag unexpected columns (headers that don't match any defined field and aren't the site_id_column)
+	auxiliary := schema.auxiliaryColumns()
 	for _, header := range headers {
-		if header == schema.SiteIDColumn {
+		if header == schema.SiteIDColumn || auxiliary[header] {
 			continue
 		}
 		if _, exists := schema.Fields[header]; !exists {
@@ -40,10 +47,22 @@ func ValidateHeaders(headers []string, schema *ResolvedSchema) (warnings []strin
 	return warnings, errors
 }
 
+// didYouMean returns a "; did you mean 'x'?" hint naming the header closest
+// to a missing column, or "" if no header is a near miss.
+func didYouMean(name string, headers []string, schema *ResolvedSchema) string {
+	if suggestion := suggestHeader(name, headers, schema); suggestion != "" {
+		return fmt.Sprintf("; did you mean '%s'?", suggestion)
+	}
+	return ""
+}
+
 // ValidateRow validates each field value in a row against type and range constraints
 func ValidateRow(row map[string]string, schema *ResolvedSchema, rowNum int) (warnings []string, errors []string) {
 	// Validate each defined field
 	for fieldName, fieldDef := range schema.Fields {
+		if fieldDef.Type == TypeComputed {
+			continue
+		}
 		value, exists := row[fieldName]
 
 		// Check if required field is present
@@ -57,11 +76,24 @@ func ValidateRow(row map[string]string, schema *ResolvedSchema, rowNum int) (war
 			continue
 		}
 
+		// Null sentinels ("NA", "-") are missing values, not invalid ones
+		if schema.IsNull(value) {
+			if fieldDef.Required {
+				errors = append(errors, fmt.Sprintf("row %d: required field '%s' is missing", rowNum, fieldName))
+			}
+			continue
+		}
+
 		// Skip validation if field is empty and not required
 		if value == "" && !fieldDef.Required {
 			continue
 		}
 
+		// Read numbers per the schema's number format ("1,234", "$5", "5%")
+		if fieldDef.Type.IsNumeric() {
+			value = schema.NumberFormat.Normalize(value)
+		}
+
 		// Validate the value based on type
 		if err := validateFieldValue(fieldName, value, fieldDef, rowNum); err != nil {
 			errors = append(errors, err.Error())
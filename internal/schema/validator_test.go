@@ -472,3 +472,100 @@ func TestValidateRow_IdentifierCannotBeEmpty(t *testing.T) {
 	assert.NotEmpty(t, errors, "Should have errors")
 	assert.Contains(t, errors[0], "cannot be empty")
 }
+
+func suggestionSchema() *ResolvedSchema {
+	return &ResolvedSchema{
+		SiteIDColumn: "site_id",
+		Fields: map[string]FieldDef{
+			"site_id":    {Type: TypeIdentifier, Required: true},
+			"population": {Type: TypePopulation, Required: true, Weight: 1},
+		},
+	}
+}
+
+func TestValidateHeaders_SuggestsNearMissHeaders(t *testing.T) {
+	// Test that a misspelled required column gets a "did you mean" hint
+	_, errors := ValidateHeaders([]string{"site_id", "populaton"}, suggestionSchema())
+	assert.Equal(t, []string{"required field 'population' not found in headers; did you mean 'populaton'?"}, errors)
+
+	// Case differences count as a near miss
+	_, errors = ValidateHeaders([]string{"Site_ID", "population"}, suggestionSchema())
+	assert.Contains(t, errors, "site_id_column 'site_id' not found in headers; did you mean 'Site_ID'?")
+}
+
+func TestValidateHeaders_NoSuggestionForUnrelatedHeaders(t *testing.T) {
+	// Test that distant headers are not suggested
+	_, errors := ValidateHeaders([]string{"site_id", "median_income"}, suggestionSchema())
+	assert.Equal(t, []string{"required field 'population' not found in headers"}, errors)
+
+	// A header that already matches another field is never suggested
+	schema := suggestionSchema()
+	schema.Fields["populations"] = FieldDef{Type: TypePopulation}
+	_, errors = ValidateHeaders([]string{"site_id", "populations"}, schema)
+	assert.Equal(t, []string{"required field 'population' not found in headers"}, errors)
+}
+
+func TestSuggestHeader_PicksClosest(t *testing.T) {
+	// Test that the closest of several near misses wins
+	headers := []string{"site_id", "popultn", "populaton"}
+	assert.Equal(t, "populaton", suggestHeader("population", headers, suggestionSchema()))
+	assert.Equal(t, 3, levenshtein("kitten", "sitting"))
+	assert.Equal(t, 0, levenshtein("", ""))
+}
+
+func nullSchema() *ResolvedSchema {
+	return &ResolvedSchema{
+		SiteIDColumn: "site_id",
+		Fields: map[string]FieldDef{
+			"site_id":      {Type: TypeIdentifier, Required: true},
+			"population":   {Type: TypePopulation, Required: true},
+			"unemployment": {Type: TypePercentage},
+			"stores":       {Type: TypeInteger},
+		},
+	}
+}
+
+func TestValidateRow_NullSentinelsInOptionalFields(t *testing.T) {
+	// Test that null sentinels in optional numeric fields pass validation
+	for _, sentinel := range []string{"NA", "N/A", "n/a", "null", "NULL", "-", " NA "} {
+		row := map[string]string{"site_id": "S1", "population": "5000", "unemployment": sentinel, "stores": sentinel}
+		_, errors := ValidateRow(row, nullSchema(), 2)
+		assert.Empty(t, errors, "sentinel %q", sentinel)
+	}
+}
+
+func TestValidateRow_NullSentinelInRequiredFieldIsMissing(t *testing.T) {
+	// Test that a sentinel in a required field is reported as missing
+	row := map[string]string{"site_id": "S1", "population": "N/A"}
+	_, errors := ValidateRow(row, nullSchema(), 2)
+	assert.Equal(t, []string{"row 2: required field 'population' is missing"}, errors)
+}
+
+func TestValidateRow_ConfiguredNullValues(t *testing.T) {
+	// Test that a configured list replaces the defaults
+	schema := nullSchema()
+	schema.NullValues = []string{"missing"}
+
+	row := map[string]string{"site_id": "S1", "population": "5000", "unemployment": "missing"}
+	_, errors := ValidateRow(row, schema, 2)
+	assert.Empty(t, errors)
+
+	row["unemployment"] = "NA"
+	_, errors = ValidateRow(row, schema, 2)
+	assert.Equal(t, []string{"row 2: field 'unemployment' must be a valid number, got 'NA'"}, errors)
+}
+
+func TestValidateHeaders_AuxiliaryColumnsExpected(t *testing.T) {
+	schema := &ResolvedSchema{
+		SiteIDColumn: "site_id",
+		Fields: map[string]FieldDef{
+			"population": {Type: TypePopulation, Weight: 1, UncertaintyColumn: "population_moe"},
+		},
+		Scoring: ScoringOptions{Freshness: &Freshness{AsOfColumn: "collected_on", HalfLifeDays: 365}},
+	}
+
+	warnings, errors := ValidateHeaders([]string{"site_id", "population", "population_moe", "collected_on", "notes"}, schema)
+
+	assert.Empty(t, errors)
+	assert.Equal(t, []string{"unexpected column 'notes' found in CSV; will be included in record data but not validated"}, warnings)
+}
@@ -0,0 +1,79 @@
+package schema
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DefaultMaxWeight is the largest weight a field may carry when the global
+// schema config does not set max_weight.
+const DefaultMaxWeight = 100.0
+
+// DominantWeightShare is the share of the total weight above which a single
+// field is reported by WeightWarnings.
+const DominantWeightShare = 0.8
+
+// maxWeight returns the configured weight cap or the default.
+func (s *ResolvedSchema) maxWeight() float64 {
+	if s.MaxWeight == 0 {
+		return DefaultMaxWeight
+	}
+	return s.MaxWeight
+}
+
+// validateMaxWeight rejects any field weight above the schema's cap.
+func (s *ResolvedSchema) validateMaxWeight() error {
+	if s.MaxWeight < 0 {
+		return fmt.Errorf("max_weight must not be negative, got %g", s.MaxWeight)
+	}
+	limit := s.maxWeight()
+	for _, name := range sortedKeys(s.Weights) {
+		if w := s.Weights[name]; w > limit {
+			return fmt.Errorf("weight for field '%s' is %g, above the maximum of %g", name, w, limit)
+		}
+	}
+	return nil
+}
+
+// WeightWarnings flags weight configurations that are valid but probably
+// unintended: a single field carrying more than DominantWeightShare of the
+// total weight makes scoring effectively single-factor.
+func (s *ResolvedSchema) WeightWarnings() []string {
+	var total float64
+	weighted := 0
+	for name, fieldDef := range s.Fields {
+		if !fieldDef.Type.IsNumeric() && fieldDef.Type != TypeComputed {
+			continue
+		}
+		if w := s.Weights[name]; w > 0 {
+			total += w
+			weighted++
+		}
+	}
+	if weighted < 2 {
+		return nil
+	}
+
+	var warnings []string
+	for _, name := range sortedKeys(s.Weights) {
+		fieldDef, ok := s.Fields[name]
+		if !ok || (!fieldDef.Type.IsNumeric() && fieldDef.Type != TypeComputed) {
+			continue
+		}
+		if share := s.Weights[name] / total; share > DominantWeightShare {
+			warnings = append(warnings, fmt.Sprintf(
+				"field '%s' carries %.0f%% of the total weight; scores will mostly reflect it alone", name, share*100))
+		}
+	}
+	return warnings
+}
+
+// sortedKeys returns m's keys in ascending order.
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
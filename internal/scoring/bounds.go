@@ -0,0 +1,72 @@
+package scoring
+
+import (
+	"log/slog"
+
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
+)
+
+// deriveBounds returns the observed min and max of every weighted numeric
+// field that lacks a configured min or max, across all sites. Fields with
+// no numeric values are omitted. A field with a single distinct value gets
+// min == max, which normalizeValue scores as 0.5 for every site.
+func deriveBounds(sites []map[string]interface{}, resolvedSchema *schema.ResolvedSchema) map[string]schema.Range {
+	derived := make(map[string]schema.Range)
+
+	for _, fieldName := range sortedFieldNames(resolvedSchema) {
+		fieldDef := resolvedSchema.Fields[fieldName]
+		if !isNumericFieldType(fieldDef.Type) || fieldDef.Weight == 0 {
+			continue
+		}
+		if fieldDef.Min != nil && fieldDef.Max != nil {
+			continue
+		}
+
+		var r schema.Range
+		seen := false
+		for _, siteData := range sites {
+			raw, ok := siteData[fieldName]
+			if !ok {
+				continue
+			}
+			v, err := toFloat64(raw)
+			if err != nil {
+				continue
+			}
+			if !seen {
+				r = schema.Range{Min: v, Max: v}
+				seen = true
+				continue
+			}
+			if v < r.Min {
+				r.Min = v
+			}
+			if v > r.Max {
+				r.Max = v
+			}
+		}
+		if seen {
+			derived[fieldName] = r
+		}
+	}
+
+	return derived
+}
+
+// warnDefaultBounds logs each weighted numeric field whose normalization
+// falls back to its type's default range.
+func warnDefaultBounds(logger *slog.Logger, resolvedSchema *schema.ResolvedSchema) {
+	for _, fieldName := range sortedFieldNames(resolvedSchema) {
+		fieldDef := resolvedSchema.Fields[fieldName]
+		if !isNumericFieldType(fieldDef.Type) || fieldDef.Weight == 0 {
+			continue
+		}
+		if bounds, defaulted := resolvedSchema.Bounds(fieldName); defaulted {
+			logger.Warn("field has no configured bounds, using type default range",
+				slog.String("field", fieldName),
+				slog.String("type", string(fieldDef.Type)),
+				slog.Float64("min", bounds.Min),
+				slog.Float64("max", bounds.Max))
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package scoring
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
+)
+
+func TestDeriveBounds(t *testing.T) {
+	min, max := 0.0, 100.0
+	resolved := &schema.ResolvedSchema{
+		Fields: map[string]schema.FieldDef{
+			"population":   {Type: schema.TypePopulation, Weight: 1.0, Direction: schema.DirectionMaximize},
+			"unemployment": {Type: schema.TypePercentage, Min: &min, Max: &max, Weight: 1.0, Direction: schema.DirectionMinimize},
+			"growth":       {Type: schema.TypeNumeric, Min: &min, Weight: 1.0, Direction: schema.DirectionMaximize},
+			"flat":         {Type: schema.TypeNumeric, Weight: 1.0, Direction: schema.DirectionMaximize},
+			"empty":        {Type: schema.TypeNumeric, Weight: 1.0, Direction: schema.DirectionMaximize},
+			"unweighted":   {Type: schema.TypeNumeric, Direction: schema.DirectionMaximize},
+		},
+		Weights: map[string]float64{"population": 1, "unemployment": 1, "growth": 1, "flat": 1, "empty": 1},
+	}
+	sites := []map[string]interface{}{
+		{"population": 5000.0, "unemployment": 4.0, "growth": 2.0, "flat": 7.0, "unweighted": 1.0},
+		{"population": "1200", "unemployment": 9.0, "growth": 8.0, "flat": 7.0},
+		{"population": 90000.0, "growth": "n/a", "flat": 7.0},
+	}
+
+	derived := deriveBounds(sites, resolved)
+
+	assert.Equal(t, map[string]schema.Range{
+		"population": {Min: 1200, Max: 90000},
+		"growth":     {Min: 2, Max: 8},
+		"flat":       {Min: 7, Max: 7},
+	}, derived, "fully bounded, unweighted and valueless fields are not derived")
+}
+
+func TestDefaultScoreFunc_DerivedBounds(t *testing.T) {
+	min := 0.0
+	resolved := &schema.ResolvedSchema{
+		Fields: map[string]schema.FieldDef{
+			"population": {Type: schema.TypePopulation, Weight: 1.0, Direction: schema.DirectionMaximize},
+			"growth":     {Type: schema.TypeNumeric, Min: &min, Weight: 1.0, Direction: schema.DirectionMaximize},
+			"flat":       {Type: schema.TypeNumeric, Weight: 1.0, Direction: schema.DirectionMaximize},
+		},
+		Weights: map[string]float64{"population": 1, "growth": 1, "flat": 1},
+		DerivedBounds: map[string]schema.Range{
+			"population": {Min: 1000, Max: 5000},
+			"growth":     {Min: 2, Max: 8},
+			"flat":       {Min: 7, Max: 7},
+		},
+	}
+
+	_, _, explanation, err := DefaultScoreFunc(map[string]interface{}{"population": 2000.0, "growth": 4.0, "flat": 7.0}, resolved)
+	require.NoError(t, err)
+
+	contributions := map[string]float64{}
+	for _, f := range explanation.Factors {
+		contributions[f.Name] = f.Contribution
+	}
+	assert.InDelta(t, 0.25, contributions["population"], 1e-9)
+	assert.InDelta(t, 0.5, contributions["growth"], 1e-9, "configured min 0 wins over derived min 2")
+	assert.InDelta(t, 0.5, contributions["flat"], 1e-9, "a single distinct value scores 0.5")
+}
+
+func TestPipelineExecute_DerivedBoundsSpreadScores(t *testing.T) {
+	p, fakes := newTestPipeline([]models.SiteRecord{
+		testSiteRecord("A", 1000, 0),
+		testSiteRecord("B", 3000, 0),
+		testSiteRecord("C", 5000, 0),
+	})
+	fakes.configs.global.Config = json.RawMessage(`{
+		"site_id_column": "site_id",
+		"fields": {
+			"site_id": {"type": "identifier", "required": true},
+			"population": {"type": "population", "weight": 1.0, "direction": "maximize"}
+		}
+	}`)
+
+	run := testRun()
+	run.ScoringConfig = json.RawMessage(`{"derive_bounds": true}`)
+	require.NoError(t, p.Execute(context.Background(), run))
+
+	scores := map[string]float64{}
+	for _, rec := range fakes.recs.inserted {
+		scores[rec.SiteID] = rec.FinalScore
+	}
+	assert.InDelta(t, 0.0, scores["A"], 1e-9)
+	assert.InDelta(t, 50.0, scores["B"], 1e-9)
+	assert.InDelta(t, 100.0, scores["C"], 1e-9, "derived bounds use the full 0-1 range")
+
+	// The snapshot records the bounds the run actually used
+	require.Len(t, fakes.configs.snapshots, 1)
+	var snapshot schema.ResolvedSchema
+	require.NoError(t, json.Unmarshal(fakes.configs.snapshots[0].SnapshotData, &snapshot))
+	assert.Equal(t, schema.Range{Min: 1000, Max: 5000}, snapshot.DerivedBounds["population"])
+}
+
+func TestPipelineExecute_WithoutDeriveBoundsUsesTypeDefault(t *testing.T) {
+	p, fakes := newTestPipeline([]models.SiteRecord{testSiteRecord("A", 5000, 0)})
+	fakes.configs.global.Config = json.RawMessage(`{
+		"site_id_column": "site_id",
+		"fields": {
+			"site_id": {"type": "identifier", "required": true},
+			"population": {"type": "population", "weight": 1.0, "direction": "maximize"}
+		}
+	}`)
+
+	require.NoError(t, p.Execute(context.Background(), testRun()))
+
+	require.Len(t, fakes.recs.inserted, 1)
+	assert.InDelta(t, 0.5, fakes.recs.inserted[0].FinalScore, 1e-9, "5000 of the 0-1,000,000 population default")
+	require.Len(t, fakes.configs.snapshots, 1)
+	assert.NotContains(t, string(fakes.configs.snapshots[0].SnapshotData), "derived_bounds")
+}
@@ -0,0 +1,55 @@
+package scoring
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
+)
+
+// applyComputedFields evaluates each computed field in the schema against the
+// site's data and stores the result in siteData under the field's name, so the
+// score function treats it like any other numeric field.
+//
+// A field that cannot be evaluated (division by zero, missing input) is left
+// unset and reported in the returned errors; it then simply does not
+// contribute to the score. Fields are evaluated in name order.
+func applyComputedFields(siteData map[string]interface{}, resolved *schema.ResolvedSchema) []error {
+	var names []string
+	for name, fieldDef := range resolved.Fields {
+		if fieldDef.Type == schema.TypeComputed {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	lookup := func(field string) (float64, bool) {
+		v, ok := siteData[field]
+		if !ok {
+			return 0, false
+		}
+		num, err := toFloat64(v)
+		return num, err == nil
+	}
+
+	var errs []error
+	for _, name := range names {
+		delete(siteData, name)
+
+		expr, err := resolved.ComputedExpression(name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		value, err := expr.Eval(lookup)
+		if err == nil && !isFinite(value) {
+			err = errNonFinite
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("computed field '%s' (%s): %w", name, expr, err))
+			continue
+		}
+		siteData[name] = value
+	}
+	return errs
+}
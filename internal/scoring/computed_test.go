@@ -0,0 +1,72 @@
+package scoring
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
+)
+
+func jobsPerCapitaSchema(t *testing.T) *schema.ResolvedSchema {
+	t.Helper()
+	resolved, err := schema.Resolve(json.RawMessage(`{
+		"site_id_column": "site_id",
+		"fields": {
+			"site_id": {"type": "identifier", "required": true},
+			"employment": {"type": "integer", "required": true},
+			"population": {"type": "population", "required": true},
+			"jobs_per_capita": {
+				"type": "computed",
+				"expression": "employment / population",
+				"min": 0, "max": 1, "weight": 1.0, "direction": "maximize"
+			}
+		}
+	}`), nil)
+	require.NoError(t, err)
+	return resolved
+}
+
+func TestApplyComputedFields_Ratio(t *testing.T) {
+	resolved := jobsPerCapitaSchema(t)
+	siteData := map[string]interface{}{"site_id": "A", "employment": 300.0, "population": 1200.0}
+
+	errs := applyComputedFields(siteData, resolved)
+	assert.Empty(t, errs)
+	assert.Equal(t, 0.25, siteData["jobs_per_capita"])
+
+	// The computed value is scored like any other numeric field
+	_, finalScore, explanation, err := DefaultScoreFunc(siteData, resolved)
+	require.NoError(t, err)
+	assert.InDelta(t, 25.0, finalScore, 1e-9)
+	require.Len(t, explanation.Factors, 1)
+	assert.Equal(t, "jobs_per_capita", explanation.Factors[0].Name)
+}
+
+func TestApplyComputedFields_DivideByZeroSkipsField(t *testing.T) {
+	resolved := jobsPerCapitaSchema(t)
+	siteData := map[string]interface{}{"site_id": "A", "employment": 300.0, "population": 0.0}
+
+	errs := applyComputedFields(siteData, resolved)
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], schema.ErrDivisionByZero)
+	assert.NotContains(t, siteData, "jobs_per_capita")
+
+	// The site is still scorable; the field just doesn't contribute
+	_, finalScore, explanation, err := DefaultScoreFunc(siteData, resolved)
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, finalScore)
+	assert.Empty(t, explanation.Factors)
+}
+
+func TestApplyComputedFields_OverflowSkipsField(t *testing.T) {
+	resolved := jobsPerCapitaSchema(t)
+	siteData := map[string]interface{}{"site_id": "A", "employment": 1e300, "population": 1e-300}
+
+	errs := applyComputedFields(siteData, resolved)
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], errNonFinite)
+	assert.NotContains(t, siteData, "jobs_per_capita")
+}
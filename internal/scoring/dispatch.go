@@ -0,0 +1,393 @@
+package scoring
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+)
+
+const (
+	// interruptedReason is recorded on runs still executing when shutdown times out.
+	interruptedReason = "scoring run interrupted by server shutdown"
+	// orphanedReason is recorded on runs abandoned by a previous process.
+	orphanedReason = "scoring run orphaned by server restart"
+
+	// defaultPollInterval is how often idle queue workers check for runs
+	// when no positive interval is configured.
+	defaultPollInterval = 2 * time.Second
+)
+
+// Dispatch launches ExecuteWithRetry for the run in a tracked background
+// goroutine. Runs dispatched this way are awaited by Shutdown.
+//
+// Once queue workers are started, the run must already be stored as
+// "queued"; Dispatch only wakes a worker, and the run executes on whichever
+// instance claims it first.
+func (p *Pipeline) Dispatch(run *models.ScoringRun) {
+	if p.workers > 0 {
+		p.wakeWorker()
+		return
+	}
+
+	p.mu.Lock()
+	p.inFlight[run.ID] = run
+	p.mu.Unlock()
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer func() {
+			p.mu.Lock()
+			delete(p.inFlight, run.ID)
+			p.mu.Unlock()
+		}()
+		p.executeDispatched(run)
+	}()
+}
+
+// DispatchBatch launches ExecuteWithRetry for each run in the background,
+// running at most concurrency of them at a time (at least one). The runs are
+// in flight from the moment DispatchBatch returns and are awaited by
+// Shutdown like those launched by Dispatch.
+//
+// With queue workers started, the runs are left to the workers as with
+// Dispatch, and concurrency is ignored.
+func (p *Pipeline) DispatchBatch(runs []*models.ScoringRun, concurrency int) {
+	if p.workers > 0 {
+		p.wakeWorker()
+		return
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	p.mu.Lock()
+	for _, run := range runs {
+		p.inFlight[run.ID] = run
+	}
+	p.mu.Unlock()
+
+	sem := make(chan struct{}, concurrency)
+	p.wg.Add(len(runs))
+	go func() {
+		for _, run := range runs {
+			sem <- struct{}{}
+			go func(run *models.ScoringRun) {
+				defer p.wg.Done()
+				defer func() {
+					<-sem
+					p.mu.Lock()
+					delete(p.inFlight, run.ID)
+					p.mu.Unlock()
+				}()
+				p.executeDispatched(run)
+			}(run)
+		}
+	}()
+}
+
+// executeDispatched runs ExecuteWithRetry for a dispatched run once a run
+// slot is free, keeping it live on the heartbeat while it waits and runs. A
+// run still waiting at shutdown never starts; Shutdown marks it failed along
+// with the others in flight.
+func (p *Pipeline) executeDispatched(run *models.ScoringRun) {
+	defer p.startHeartbeat(run)()
+	if p.runSlots != nil {
+		select {
+		case p.runSlots <- struct{}{}:
+		default:
+			runLogger(run).Info("waiting for a free run slot", slog.Int("max_concurrent_runs", cap(p.runSlots)))
+			select {
+			case p.runSlots <- struct{}{}:
+			case <-p.baseCtx.Done():
+				return
+			}
+		}
+		defer func() { <-p.runSlots }()
+	}
+	_ = p.ExecuteWithRetry(p.baseCtx, run)
+}
+
+// StartWorkers starts n workers that claim queued runs from the run store
+// and execute them, so runs created on any instance sharing the database
+// are shared out among all of their workers and a run outlives the process
+// that created it. Idle workers are woken by Dispatch and otherwise check
+// the queue every pollInterval for runs queued elsewhere. The workers bound
+// how many runs this instance executes at once. Call at most once, before
+// RecoverOrphanedRuns and before any run is dispatched; a non-positive n
+// starts none and leaves Dispatch running runs in-process.
+func (p *Pipeline) StartWorkers(n int, pollInterval time.Duration) {
+	if n <= 0 {
+		return
+	}
+	if pollInterval <= 0 {
+		pollInterval = defaultPollInterval
+	}
+	p.workers = n
+	p.pollInterval = pollInterval
+
+	p.wg.Add(n)
+	for i := 0; i < n; i++ {
+		go p.work()
+	}
+	slog.Info("scoring queue workers started",
+		slog.Int("workers", n),
+		slog.String("instance_id", p.instanceID.String()))
+}
+
+// work claims and executes queued runs until Shutdown. A run claimed before
+// Shutdown is finished (or interrupted at its deadline) before returning.
+func (p *Pipeline) work() {
+	defer p.wg.Done()
+	ticker := time.NewTicker(p.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stopWorkers:
+			return
+		default:
+		}
+
+		run, err := p.runRepo.ClaimNext(p.baseCtx, p.instanceID)
+		if err != nil && p.baseCtx.Err() == nil {
+			slog.Error("failed to claim queued run",
+				slog.String("instance_id", p.instanceID.String()),
+				slog.String("error", err.Error()))
+		}
+		if run != nil {
+			// There may be more; let an idle worker look while this one is busy
+			p.wakeWorker()
+			p.executeClaimed(run)
+			continue
+		}
+
+		select {
+		case <-p.stopWorkers:
+			return
+		case <-p.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// executeClaimed runs ExecuteWithRetry for a run claimed from the queue,
+// tracking it as in flight so Shutdown can requeue it if interrupted.
+func (p *Pipeline) executeClaimed(run *models.ScoringRun) {
+	p.mu.Lock()
+	p.inFlight[run.ID] = run
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		delete(p.inFlight, run.ID)
+		p.mu.Unlock()
+	}()
+
+	runLogger(run).Info("claimed queued run")
+	defer p.startHeartbeat(run)()
+	_ = p.ExecuteWithRetry(p.baseCtx, run)
+}
+
+// startHeartbeat refreshes the run's updated_at every heartbeat interval
+// until the returned function is called, which waits for the last refresh
+// to finish. A failed refresh is logged and retried at the next tick.
+func (p *Pipeline) startHeartbeat(run *models.ScoringRun) func() {
+	if p.heartbeat <= 0 {
+		return func() {}
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(p.heartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if err := p.runRepo.Heartbeat(p.baseCtx, run.ID, p.instanceID); err != nil && p.baseCtx.Err() == nil {
+					runLogger(run).Warn("failed to refresh run heartbeat", slog.String("error", err.Error()))
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+		<-done
+	}
+}
+
+// wakeWorker signals one idle queue worker, if none is already signalled.
+func (p *Pipeline) wakeWorker() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// InFlight returns the number of dispatched runs that have not yet finished.
+func (p *Pipeline) InFlight() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.inFlight)
+}
+
+// Shutdown waits for dispatched runs to finish. If ctx expires first, the
+// remaining runs are cancelled and marked failed so they can be retried later.
+// Queue workers stop claiming runs; runs they are still executing at the
+// deadline are put back on the queue for another instance instead. A
+// requeued run starts over, and its next execution replaces any
+// recommendations the interrupted one stored.
+func (p *Pipeline) Shutdown(ctx context.Context) error {
+	p.stopOnce.Do(func() { close(p.stopWorkers) })
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	p.mu.Lock()
+	interrupted := make([]*models.ScoringRun, 0, len(p.inFlight))
+	for _, run := range p.inFlight {
+		interrupted = append(interrupted, run)
+	}
+	p.mu.Unlock()
+
+	p.cancelBase()
+
+	// The shutdown context has expired, so use a short fresh one to record
+	// the interruption.
+	markCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, run := range interrupted {
+		logger := runLogger(run)
+		if p.workers > 0 {
+			// Resetting our own run to queued hands it to the next worker
+			if _, err := p.runRepo.ClaimOrphan(markCtx, run.ID, p.instanceID, p.instanceID); err != nil {
+				logger.Error("failed to requeue interrupted run", slog.String("error", err.Error()))
+				continue
+			}
+			logger.Warn("requeued in-flight scoring run at shutdown")
+			continue
+		}
+		logger.Warn("interrupting in-flight scoring run")
+		if err := p.runRepo.UpdateStatus(markCtx, run.ID, "failed", nil, stringPtr(interruptedReason), nil); err != nil {
+			logger.Error("failed to mark interrupted run", slog.String("error", err.Error()))
+		}
+	}
+
+	return ctx.Err()
+}
+
+// StartOrphanSweep calls RecoverOrphanedRuns every interval until Shutdown,
+// so runs abandoned by an instance that died after this one started are
+// recovered too, not only those left over at startup. Call at most once,
+// after the startup recovery; a non-positive interval starts no sweep.
+func (p *Pipeline) StartOrphanSweep(interval, olderThan time.Duration, requeue bool) {
+	if interval <= 0 {
+		return
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.stopWorkers:
+				return
+			case <-ticker.C:
+				if _, err := p.RecoverOrphanedRuns(p.baseCtx, olderThan, requeue); err != nil && p.baseCtx.Err() == nil {
+					slog.Error("failed to recover orphaned runs",
+						slog.String("instance_id", p.instanceID.String()),
+						slog.String("error", err.Error()))
+				}
+			}
+		}
+	}()
+}
+
+// InstanceID returns the identifier of this process, stamped on every run it
+// dispatches so orphaned runs can be told apart from live ones.
+func (p *Pipeline) InstanceID() uuid.UUID {
+	return p.instanceID
+}
+
+// RecoverOrphanedRuns finds runs left "queued" or "running" by another
+// (presumably dead) instance for longer than olderThan. With requeue set they
+// are claimed by this instance and dispatched again, replacing any results
+// they stored before being orphaned; otherwise they are marked
+// failed. Either way a run is only touched if it is still unfinished and
+// owned by the instance it was listed under, so any number of instances may
+// recover at once. Call at startup, then see StartOrphanSweep. Returns the
+// number of runs recovered.
+//
+// Live instances refresh their runs' updated_at on a heartbeat (see
+// SetHeartbeatInterval), so olderThan must comfortably exceed the heartbeat
+// interval used across the deployment.
+//
+// With queue workers started, a "queued" run is waiting for any worker
+// rather than orphaned, so only "running" runs are recovered, and requeued
+// runs go back on the queue.
+func (p *Pipeline) RecoverOrphanedRuns(ctx context.Context, olderThan time.Duration, requeue bool) (int, error) {
+	stale, err := p.runRepo.ListStale(ctx, olderThan, p.instanceID)
+	if err != nil {
+		return 0, err
+	}
+
+	recovered := 0
+	for i := range stale {
+		run := &stale[i]
+		if p.workers > 0 && run.Status == "queued" {
+			continue
+		}
+		logger := runLogger(run).With(slog.String("previous_status", run.Status))
+
+		if !requeue {
+			failed, err := p.runRepo.FailOrphan(ctx, run.ID, run.InstanceID, orphanedReason)
+			if err != nil {
+				logger.Error("failed to mark orphaned run failed", slog.String("error", err.Error()))
+				continue
+			}
+			if !failed {
+				// Another instance claimed it, or the run finished meanwhile
+				continue
+			}
+			logger.Warn("marked orphaned run failed")
+			recovered++
+			continue
+		}
+
+		claimed, err := p.runRepo.ClaimOrphan(ctx, run.ID, run.InstanceID, p.instanceID)
+		if err != nil {
+			logger.Error("failed to claim orphaned run", slog.String("error", err.Error()))
+			continue
+		}
+		if !claimed {
+			// Another instance got there first, or the run finished meanwhile
+			continue
+		}
+
+		run.InstanceID = p.instanceID
+		run.Status = "queued"
+		logger.Warn("requeued orphaned run")
+		p.Dispatch(run)
+		recovered++
+	}
+
+	return recovered, nil
+}
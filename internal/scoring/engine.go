@@ -2,12 +2,15 @@ package scoring
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"math"
 	"sort"
 	"strings"
 	"unicode"
 
+	"github.com/workforce-ai/site-selection-iq/internal/i18n"
 	"github.com/workforce-ai/site-selection-iq/internal/models"
 	"github.com/workforce-ai/site-selection-iq/internal/schema"
 )
@@ -31,6 +34,11 @@ type ScoreFunc func(
 // 4. Sum all weighted contributions for raw score
 // 5. Normalize raw score to 0-100 range for final score
 // Each factor produces detailed explanation including contribution and reasoning.
+//
+// When the schema sets category weights, steps 4-5 are two-level instead:
+// each category's subscore is the weighted mean of its fields' normalized
+// values, and the raw score sums subscores times category weights. Factor
+// weights and contributions are then within their category.
 func DefaultScoreFunc(
 	siteData map[string]interface{},
 	resolvedSchema *schema.ResolvedSchema,
@@ -43,13 +51,25 @@ func DefaultScoreFunc(
 		return 0, 0, models.Explanation{}, fmt.Errorf("site data cannot be empty")
 	}
 
+	msgs := i18n.Lookup(resolvedSchema.Scoring.Locale)
 	explanation.Factors = []models.ExplanationFactor{}
 	var totalWeightedScore float64
 	var totalWeight float64
+	var schemaWeight float64
 	maxPossibleScore := 0.0
+	categories := make(map[string]*categoryTotals)
+	categoryOf := func(fieldDef schema.FieldDef) *categoryTotals {
+		totals, ok := categories[fieldDef.Category]
+		if !ok {
+			totals = &categoryTotals{}
+			categories[fieldDef.Category] = totals
+		}
+		return totals
+	}
 
-	// Iterate through all fields in the resolved schema
-	for fieldName, fieldDef := range resolvedSchema.Fields {
+	// Iterate through fields in name order so output never depends on map order
+	for _, fieldName := range sortedFieldNames(resolvedSchema) {
+		fieldDef := resolvedSchema.Fields[fieldName]
 		// Only process numeric fields that have weights
 		if !isNumericFieldType(fieldDef.Type) || fieldDef.Weight == 0 {
 			continue
@@ -59,6 +79,8 @@ func DefaultScoreFunc(
 		if weight == 0 {
 			continue
 		}
+		schemaWeight += weight
+		categoryOf(fieldDef).schemaWeight += weight
 
 		// Extract the value from site data
 		rawValue, exists := siteData[fieldName]
@@ -71,27 +93,33 @@ func DefaultScoreFunc(
 		numValue, err := toFloat64(rawValue)
 		if err != nil {
 			// Skip fields that can't be converted to numeric
+			if errors.Is(err, errNonFinite) {
+				slog.Warn("skipping non-finite factor value", "field", fieldName, "value", rawValue)
+			}
 			continue
 		}
 
+		if fieldDef.Transform.Clamps(numValue) {
+			slog.Warn("clamping negative value to 0 before transform",
+				"field", fieldName, "transform", fieldDef.Transform, "value", numValue)
+		}
+
 		// Normalize value to 0-1 range
-		normalizedValue := normalizeValue(numValue, fieldDef.Min, fieldDef.Max, fieldDef.Direction)
+		bounds, _ := resolvedSchema.Bounds(fieldName)
+		normalizedValue, unclamped := normalizeValue(numValue, bounds, fieldDef)
 
 		// Calculate contribution (normalized value * weight)
 		contribution := normalizedValue * weight
 		totalWeightedScore += contribution
 		totalWeight += weight
+		categoryOf(fieldDef).scored += contribution
+		categoryOf(fieldDef).weight += weight
 
 		// Determine if this is a positive or negative contribution
-		direction := string(fieldDef.Direction)
-		if fieldDef.Direction == schema.DirectionMinimize {
-			direction = "minimize"
-		} else {
-			direction = "maximize"
-		}
+		direction := rankDirection(fieldDef.Direction)
 
 		// Generate reason string for this factor
-		reason := generateReasonString(fieldName, numValue, normalizedValue, fieldDef.Direction)
+		reason := generateReasonString(msgs, resolvedSchema.Scoring.QualityBuckets, fieldName, numValue, normalizedValue, fieldDef)
 
 		// Create explanation factor
 		factor := models.ExplanationFactor{
@@ -102,6 +130,11 @@ func DefaultScoreFunc(
 			Direction:    direction,
 			Reason:       reason,
 		}
+		if outOfBounds(numValue, bounds) {
+			factor.Clamped = true
+			factor.UnclampedNormalized = &unclamped
+			factor.Reason += clampNote(msgs, numValue, bounds)
+		}
 
 		explanation.Factors = append(explanation.Factors, factor)
 		maxPossibleScore += weight // Each weight can contribute max of 1 * weight
@@ -109,33 +142,157 @@ func DefaultScoreFunc(
 
 	// Calculate raw score
 	rawScore = totalWeightedScore
+	scale := resolvedSchema.Scoring.ScoreScale
+	explanation.Coverage = coverage(maxPossibleScore, schemaWeight)
+
+	if resolvedSchema.UsesCategories() {
+		rawScore, maxPossibleScore, explanation.Coverage, explanation.Categories =
+			combineCategories(categories, resolvedSchema.CategoryWeights, scale)
+		explanation.CategoryScores = categoryScoreMap(explanation.Categories)
+		explanation.CategorySummary = categorySummary(explanation.Categories, scale)
+	}
 
-	// Normalize raw score to 0-100 range
+	// Normalize raw score to the configured scale (0-100 by default)
 	if maxPossibleScore > 0 {
-		finalScore = (rawScore / maxPossibleScore) * 100
+		finalScore = (rawScore / maxPossibleScore) * scale.Max()
 	} else {
 		finalScore = 0
 	}
 
-	// Ensure final score is bounded to 0-100
-	finalScore = math.Max(0, math.Min(100, finalScore))
+	// Ensure final score is a finite number bounded to the scale; extreme
+	// weights can still overflow the sums
+	if !isFinite(rawScore) || !isFinite(finalScore) {
+		slog.Warn("non-finite score computed, scoring as 0", "raw_score", rawScore)
+		rawScore, finalScore = 0, 0
+	}
+	finalScore = math.Max(0, math.Min(scale.Max(), finalScore))
 
 	// Sort factors by contribution (descending) for summary generation
-	sort.Slice(explanation.Factors, func(i, j int) bool {
-		return math.Abs(explanation.Factors[i].Contribution) > math.Abs(explanation.Factors[j].Contribution)
-	})
+	sortFactors(explanation.Factors)
 
 	// Generate summary from top contributing factors
-	explanation.Summary = generateSummary(explanation.Factors, finalScore)
+	explanation.Summary = generateSummary(msgs, explanation.Factors, finalScore, scale, resolvedSchema.Scoring.SummaryFactors()) +
+		coverageNote(msgs, explanation.Coverage, len(explanation.Factors))
 
 	return rawScore, finalScore, explanation, nil
 }
 
+// categoryTotals accumulates one category's weighted field scores.
+type categoryTotals struct {
+	scored       float64 // sum of normalized value * weight over scored fields
+	weight       float64 // weight of the scored fields
+	schemaWeight float64 // weight of every weighted field in the category
+}
+
+// combineCategories turns per-category totals into a two-level score. Each
+// category's subscore is its scored weighted mean; the raw score sums
+// subscores times category weights, and maxPossible sums the weights of the
+// categories that scored. Categories with no weighted fields, or a zero
+// weight, are left out. Coverage is the category-weighted share of field
+// weight scored.
+func combineCategories(
+	totals map[string]*categoryTotals,
+	categoryWeights map[string]float64,
+	scale schema.ScoreScale,
+) (rawScore, maxPossible, cov float64, scores []models.CategoryScore) {
+	names := make([]string, 0, len(categoryWeights))
+	for name := range categoryWeights {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var covered, totalCategoryWeight float64
+	for _, name := range names {
+		categoryWeight := categoryWeights[name]
+		t := totals[name]
+		if categoryWeight == 0 || t == nil || t.schemaWeight == 0 {
+			continue
+		}
+		totalCategoryWeight += categoryWeight
+		if t.weight == 0 {
+			continue
+		}
+
+		subscore := t.scored / t.weight
+		rawScore += categoryWeight * subscore
+		maxPossible += categoryWeight
+		covered += categoryWeight * t.weight / t.schemaWeight
+		scores = append(scores, models.CategoryScore{
+			Name:         name,
+			Weight:       categoryWeight,
+			Score:        subscore * scale.Max(),
+			Contribution: categoryWeight * subscore,
+		})
+	}
+
+	return rawScore, maxPossible, coverage(covered, totalCategoryWeight), scores
+}
+
+// categoryScoreMap indexes category subscores by category name.
+func categoryScoreMap(scores []models.CategoryScore) map[string]float64 {
+	if len(scores) == 0 {
+		return nil
+	}
+	byName := make(map[string]float64, len(scores))
+	for _, cs := range scores {
+		byName[cs.Name] = cs.Score
+	}
+	return byName
+}
+
+// categorySummary lists category subscores in order, e.g.
+// "Economic: 82, Talent: 45". Scores are rounded to the scale's precision.
+func categorySummary(scores []models.CategoryScore, scale schema.ScoreScale) string {
+	precision := 0
+	switch scale {
+	case schema.ScaleTen:
+		precision = 1
+	case schema.ScaleUnit:
+		precision = 2
+	}
+
+	parts := make([]string, len(scores))
+	for i, cs := range scores {
+		parts[i] = fmt.Sprintf("%s: %.*f", cs.Name, precision, cs.Score)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Determinism: scoring the same site against the same schema must produce
+// byte-identical output. The sources of non-determinism to guard against are
+//   - map iteration over resolvedSchema.Fields (and siteData), which Go
+//     randomizes: iterate sortedFieldNames instead
+//   - unstable sorts, which may reorder equal elements between runs: use
+//     sortFactors, whose name tie-break makes the order total
+// Ranking across sites is handled separately by rankSites.
+
+// sortedFieldNames returns the schema's field names in ascending order.
+func sortedFieldNames(resolvedSchema *schema.ResolvedSchema) []string {
+	names := make([]string, 0, len(resolvedSchema.Fields))
+	for name := range resolvedSchema.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sortFactors orders factors by absolute contribution, largest first, with
+// equal contributions ordered by name so "top factors" never flip.
+func sortFactors(factors []models.ExplanationFactor) {
+	sort.Slice(factors, func(i, j int) bool {
+		ci, cj := math.Abs(factors[i].Contribution), math.Abs(factors[j].Contribution)
+		if ci != cj {
+			return ci > cj
+		}
+		return factors[i].Name < factors[j].Name
+	})
+}
+
 // isNumericFieldType checks if a field type is numeric
 func isNumericFieldType(fieldType schema.FieldType) bool {
 	switch fieldType {
 	case schema.TypeNumeric, schema.TypeInteger, schema.TypePercentage,
-		schema.TypeIndex, schema.TypePopulation:
+		schema.TypeIndex, schema.TypePopulation, schema.TypeComputed:
 		return true
 	default:
 		return false
@@ -144,147 +301,290 @@ func isNumericFieldType(fieldType schema.FieldType) bool {
 
 // toFloat64 converts a value to float64
 func toFloat64(value interface{}) (float64, error) {
+	var num float64
 	switch v := value.(type) {
 	case float64:
-		return v, nil
+		num = v
 	case float32:
-		return float64(v), nil
+		num = float64(v)
 	case int:
-		return float64(v), nil
+		num = float64(v)
 	case int64:
-		return float64(v), nil
+		num = float64(v)
 	case int32:
-		return float64(v), nil
+		num = float64(v)
 	case string:
-		// Try to parse as JSON number
-		var num float64
+		// Try to parse as JSON number; this rejects "NaN", "Inf" and
+		// out-of-range literals such as "1e400"
 		if err := json.Unmarshal([]byte(v), &num); err != nil {
 			return 0, fmt.Errorf("cannot convert string to float64: %w", err)
 		}
-		return num, nil
 	default:
 		return 0, fmt.Errorf("unsupported type for numeric conversion: %T", value)
 	}
-}
 
-// normalizeValue normalizes a numeric value to 0-1 range based on min/max bounds
-// Takes direction into account: for minimize, higher actual values become lower normalized values
-func normalizeValue(value float64, min, max *float64, direction schema.Direction) float64 {
-	// Handle cases where min/max are not defined
-	if min == nil || max == nil {
-		// Without bounds, use a default normalization strategy
-		// Clamp value to prevent extreme outliers
-		// Assume a reasonable range; values are normalized relative to a reference
-		return math.Max(0, math.Min(1, value/100.0))
+	if !isFinite(num) {
+		return 0, fmt.Errorf("%w: %v", errNonFinite, num)
 	}
+	return num, nil
+}
+
+// errNonFinite is returned by toFloat64 for NaN and infinite values, which
+// would otherwise corrupt scores, rankings and JSON serialization.
+var errNonFinite = errors.New("value is not a finite number")
 
-	minVal := *min
-	maxVal := *max
+// isFinite reports whether f is neither NaN nor infinite.
+func isFinite(f float64) bool {
+	return !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+// normalizeValue normalizes a numeric value to 0-1 range based on its bounds
+// (see ResolvedSchema.Bounds)
+// Takes direction into account: for minimize, higher actual values become lower normalized values;
+// for target, the value's proximity to the field's target is scored (1 at the target, 0 at the bounds).
+// The field's transform is applied to the value, bounds and target first.
+// It also returns the normalized value before clamping to 0-1, which differs
+// from the first result when value lies outside the bounds.
+func normalizeValue(value float64, bounds schema.Range, fieldDef schema.FieldDef) (normalized, unclamped float64) {
+	transform := fieldDef.Transform
+	value = transform.Apply(value)
+	minVal := transform.Apply(bounds.Min)
+	maxVal := transform.Apply(bounds.Max)
+
+	// Non-finite bounds cannot produce a meaningful position
+	if !isFinite(minVal) || !isFinite(maxVal) {
+		return 0.5, 0.5
+	}
 
 	// Prevent division by zero
 	if maxVal == minVal {
-		return 0.5
+		return 0.5, 0.5
+	}
+
+	if fieldDef.Direction == schema.DirectionTarget {
+		target := (minVal + maxVal) / 2
+		if fieldDef.Target != nil {
+			target = transform.Apply(*fieldDef.Target)
+		}
+		return targetProximity(value, target, schema.Range{Min: minVal, Max: maxVal})
 	}
 
 	// Normalize to 0-1 range
-	normalized := (value - minVal) / (maxVal - minVal)
-	normalized = math.Max(0, math.Min(1, normalized)) // Clamp to 0-1
+	unclamped = (value - minVal) / (maxVal - minVal)
 
 	// For minimize direction, invert the normalized value
 	// So lower actual values = higher normalized scores
-	if direction == schema.DirectionMinimize {
-		normalized = 1.0 - normalized
+	if fieldDef.Direction == schema.DirectionMinimize {
+		unclamped = 1.0 - unclamped
 	}
 
-	return normalized
+	return math.Max(0, math.Min(1, unclamped)), unclamped // Clamp to 0-1
 }
 
-// generateReasonString creates a human-readable explanation for a field's contribution
+// targetProximity scores value by its distance from target, falling
+// linearly from 1 at the target to 0 at the bound on the value's side.
+// Values beyond that bound score 0; the second result is the score
+// before that clamp.
+func targetProximity(value, target float64, bounds schema.Range) (float64, float64) {
+	span := bounds.Max - target
+	if value < target {
+		span = target - bounds.Min
+	}
+	if span <= 0 {
+		if value == target {
+			return 1, 1
+		}
+		return 0, 0
+	}
+	unclamped := 1 - math.Abs(value-target)/span
+	return math.Max(0, unclamped), unclamped
+}
+
+// outOfBounds reports whether value lies outside usable bounds, in which
+// case normalizeValue capped its normalized value.
+func outOfBounds(value float64, bounds schema.Range) bool {
+	if !isFinite(bounds.Min) || !isFinite(bounds.Max) || bounds.Max == bounds.Min {
+		return false
+	}
+	return value < bounds.Min || value > bounds.Max
+}
+
+// clampNote explains in a factor's reason that value lay outside bounds
+// and its normalized value was capped.
+func clampNote(msgs *i18n.Catalog, value float64, bounds schema.Range) string {
+	if value > bounds.Max {
+		return fmt.Sprintf(msgs.ClampAbove, bounds.Max)
+	}
+	return fmt.Sprintf(msgs.ClampBelow, bounds.Min)
+}
+
+// generateReasonString creates a human-readable explanation for a field's
+// contribution in the catalog's language, describing its quality with
+// buckets or, when none are configured, the catalog's default labels
 func generateReasonString(
+	msgs *i18n.Catalog,
+	buckets []schema.QualityBucket,
 	fieldName string,
 	value float64,
 	normalizedValue float64,
-	direction schema.Direction,
+	fieldDef schema.FieldDef,
 ) string {
 	// Format the field name for readability
 	readableName := strings.ReplaceAll(fieldName, "_", " ")
 	readableName = capitalizeWords(readableName)
 
 	// Determine quality description based on normalized value
-	quality := "neutral"
-	if normalizedValue >= 0.75 {
-		quality = "excellent"
-	} else if normalizedValue >= 0.5 {
-		quality = "good"
-	} else if normalizedValue >= 0.25 {
-		quality = "fair"
-	} else {
-		quality = "poor"
+	if len(buckets) == 0 {
+		buckets = defaultQualityBuckets(msgs)
 	}
+	quality := schema.QualityLabel(buckets, normalizedValue)
 
 	// Build the reason string
-	if direction == schema.DirectionMaximize {
-		return fmt.Sprintf("%s value is %.2f, which is %s for this metric (higher is better)",
-			readableName, value, quality)
-	} else {
-		return fmt.Sprintf("%s value is %.2f, which is %s for this metric (lower is better)",
-			readableName, value, quality)
+	switch {
+	case fieldDef.Direction == schema.DirectionTarget && fieldDef.Target != nil:
+		return fmt.Sprintf(msgs.ReasonTarget, readableName, value, *fieldDef.Target, quality, msgs.TargetIsBest)
+	case fieldDef.Direction == schema.DirectionMaximize:
+		return fmt.Sprintf(msgs.Reason, readableName, value, quality, msgs.HigherIsBetter)
+	default:
+		return fmt.Sprintf(msgs.Reason, readableName, value, quality, msgs.LowerIsBetter)
+	}
+}
+
+// defaultQualityBuckets are the quality buckets used when the schema
+// configures none, labelled in the catalog's language.
+func defaultQualityBuckets(msgs *i18n.Catalog) []schema.QualityBucket {
+	return []schema.QualityBucket{
+		{Threshold: 0.75, Label: msgs.QualityExcellent},
+		{Threshold: 0.5, Label: msgs.QualityGood},
+		{Threshold: 0.25, Label: msgs.QualityFair},
+		{Threshold: 0, Label: msgs.QualityPoor},
 	}
 }
 
-// generateSummary creates a summary from the top contributing factors
-func generateSummary(factors []models.ExplanationFactor, finalScore float64) string {
+// generateSummary creates a summary naming up to count of the top
+// positively contributing factors, in the catalog's language
+func generateSummary(msgs *i18n.Catalog, factors []models.ExplanationFactor, finalScore float64, scale schema.ScoreScale, count int) string {
 	if len(factors) == 0 {
-		return "No scoring factors contributed to this site's score."
+		return msgs.SummaryNoFactors
 	}
 
-	// Get top 3 contributing factors
-	topCount := 3
+	topCount := count
 	if len(factors) < topCount {
 		topCount = len(factors)
 	}
 
+	// Weak factors are called out separately rather than credited as drivers
+	weak := weakestFactors(factors)
+	isWeak := make(map[string]bool, len(weak))
+	for _, f := range weak {
+		isWeak[f.Name] = true
+	}
+
 	var topFactors []string
 	for i := 0; i < topCount; i++ {
 		factor := factors[i]
-		if factor.Contribution > 0 {
-			topFactors = append(topFactors, factor.Name)
+		if factor.Contribution > 0 && !isWeak[factor.Name] {
+			topFactors = append(topFactors, strings.ReplaceAll(factor.Name, "_", " "))
 		}
 	}
 
+	var summary string
 	if len(topFactors) == 0 {
-		return fmt.Sprintf("Final score is %.1f based on weighted factor analysis.", finalScore)
-	}
-
-	// Build summary statement
-	summary := fmt.Sprintf("Final score is %.1f.", finalScore)
-
-	if len(topFactors) == 1 {
-		summary += fmt.Sprintf(" The primary contributing factor is %s.",
-			strings.ReplaceAll(topFactors[0], "_", " "))
-	} else if len(topFactors) == 2 {
-		summary += fmt.Sprintf(" Top contributing factors are %s and %s.",
-			strings.ReplaceAll(topFactors[0], "_", " "),
-			strings.ReplaceAll(topFactors[1], "_", " "))
-	} else if len(topFactors) >= 3 {
-		lastFactor := topFactors[len(topFactors)-1]
-		otherFactors := strings.Join(
-			func() []string {
-				var cleaned []string
-				for _, f := range topFactors[:len(topFactors)-1] {
-					cleaned = append(cleaned, strings.ReplaceAll(f, "_", " "))
-				}
-				return cleaned
-			}(),
-			", ",
-		)
-		summary += fmt.Sprintf(" Top contributing factors are %s, and %s.",
-			otherFactors, strings.ReplaceAll(lastFactor, "_", " "))
+		summary = fmt.Sprintf(msgs.SummaryScoreOnly, formatScore(msgs, finalScore, scale))
+	} else {
+		// Build summary statement
+		summary = fmt.Sprintf(msgs.SummaryScore, formatScore(msgs, finalScore, scale))
+
+		if len(topFactors) == 1 {
+			summary += fmt.Sprintf(msgs.SummaryPrimary, topFactors[0])
+		} else {
+			summary += fmt.Sprintf(msgs.SummaryTop, msgs.List(topFactors))
+		}
+	}
+
+	if len(weak) > 0 {
+		phrases := make([]string, len(weak))
+		for i, f := range weak {
+			phrase := msgs.WeakLow
+			switch f.Direction {
+			case string(schema.DirectionMinimize):
+				phrase = msgs.WeakHigh
+			case string(schema.DirectionTarget):
+				phrase = msgs.WeakOffTarget
+			}
+			phrases[i] = fmt.Sprintf(phrase, strings.ReplaceAll(f.Name, "_", " "))
+		}
+		summary += fmt.Sprintf(msgs.SummaryHeldBack, msgs.List(phrases))
 	}
 
 	return summary
 }
 
+// Weak-factor callouts: a factor is weak when its normalized value
+// (contribution / weight) is below weakFactorThreshold. At most
+// maxWeakFactors are named, weakest first, as e.g. "high unemployment rate"
+// for minimize fields or "low working age pop" for maximize fields.
+const (
+	weakFactorThreshold = 0.5
+	maxWeakFactors      = 2
+)
+
+// weakestFactors returns up to maxWeakFactors weak factors, weakest first.
+func weakestFactors(factors []models.ExplanationFactor) []models.ExplanationFactor {
+	var weak []models.ExplanationFactor
+	for _, f := range factors {
+		if f.Weight != 0 && f.Contribution/f.Weight < weakFactorThreshold {
+			weak = append(weak, f)
+		}
+	}
+
+	sort.Slice(weak, func(i, j int) bool {
+		ni, nj := weak[i].Contribution/weak[i].Weight, weak[j].Contribution/weak[j].Weight
+		if ni != nj {
+			return ni < nj
+		}
+		return weak[i].Name < weak[j].Name
+	})
+	if len(weak) > maxWeakFactors {
+		weak = weak[:maxWeakFactors]
+	}
+	return weak
+}
+
+// lowCoverageThreshold is the coverage below which the summary warns that
+// the score rests on partial data.
+const lowCoverageThreshold = 0.8
+
+// coverage returns the share of the schema's weight that was actually scored.
+func coverage(scoredWeight, schemaWeight float64) float64 {
+	if schemaWeight <= 0 {
+		return 0
+	}
+	return math.Min(1, scoredWeight/schemaWeight)
+}
+
+// coverageNote returns a summary sentence warning about low coverage, or ""
+// when coverage is adequate or nothing was scored at all.
+func coverageNote(msgs *i18n.Catalog, coverage float64, factorCount int) string {
+	if factorCount == 0 || coverage >= lowCoverageThreshold {
+		return ""
+	}
+	return fmt.Sprintf(msgs.SummaryCoverage, coverage*100)
+}
+
+// formatScore renders a final score for summary text. The default 0-100
+// scale keeps the bare number; other scales name their upper bound.
+func formatScore(msgs *i18n.Catalog, score float64, scale schema.ScoreScale) string {
+	switch scale {
+	case schema.ScaleTen:
+		return fmt.Sprintf(msgs.ScoreOutOfTen, score)
+	case schema.ScaleUnit:
+		return fmt.Sprintf(msgs.ScoreOutOfOne, score)
+	default:
+		return fmt.Sprintf("%.1f", score)
+	}
+}
+
 // capitalizeWords capitalizes the first letter of each word in a string
 func capitalizeWords(s string) string {
 	if len(s) == 0 {
@@ -1,12 +1,15 @@
 package scoring
 
 import (
+	"encoding/json"
 	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/workforce-ai/site-selection-iq/internal/i18n"
+	"github.com/workforce-ai/site-selection-iq/internal/models"
 	"github.com/workforce-ai/site-selection-iq/internal/schema"
 )
 
@@ -348,6 +351,52 @@ func TestDefaultScoreFunc_MissingFieldsSkipped(t *testing.T) {
 	assert.LessOrEqual(t, finalScore, 100.0)
 }
 
+func TestDefaultScoreFunc_CoverageFromMissingFields(t *testing.T) {
+	// Test that missing weighted fields lower coverage and trigger a summary warning
+	min := 0.0
+	max := 100.0
+
+	resolvedSchema := &schema.ResolvedSchema{
+		SiteIDColumn: "site_id",
+		Fields: map[string]schema.FieldDef{
+			"unemployment": {Type: schema.TypePercentage, Min: &min, Max: &max, Weight: 2.0, Direction: schema.DirectionMinimize},
+			"growth_rate":  {Type: schema.TypePercentage, Min: &min, Max: &max, Weight: 1.0, Direction: schema.DirectionMaximize},
+			"income":       {Type: schema.TypePercentage, Min: &min, Max: &max, Weight: 2.0, Direction: schema.DirectionMaximize},
+		},
+		Weights: map[string]float64{
+			"unemployment": 2.0,
+			"growth_rate":  1.0,
+			"income":       2.0,
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		siteData map[string]interface{}
+		coverage float64
+		warning  string
+	}{
+		{"all fields present", map[string]interface{}{"unemployment": 10.0, "growth_rate": 80.0, "income": 90.0}, 1.0, ""},
+		{"light field missing", map[string]interface{}{"unemployment": 10.0, "income": 90.0}, 0.8, ""},
+		{"most weight missing", map[string]interface{}{"unemployment": 10.0}, 0.4, "Score based on only 40% of weighted factors."},
+		{"only unweighted data", map[string]interface{}{"site_name": "Nowhere"}, 0.0, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, explanation, err := DefaultScoreFunc(tc.siteData, resolvedSchema)
+
+			require.NoError(t, err)
+			assert.InDelta(t, tc.coverage, explanation.Coverage, 1e-9)
+			if tc.warning == "" {
+				assert.NotContains(t, explanation.Summary, "Score based on only")
+			} else {
+				assert.Contains(t, explanation.Summary, tc.warning)
+			}
+		})
+	}
+}
+
 func TestDefaultScoreFunc_InvalidTypeHandling(t *testing.T) {
 	// Test that non-numeric fields are skipped gracefully
 	min := 0.0
@@ -390,6 +439,86 @@ func TestDefaultScoreFunc_InvalidTypeHandling(t *testing.T) {
 	assert.LessOrEqual(t, finalScore, 100.0)
 }
 
+func TestDefaultScoreFunc_NonFiniteValuesSkipped(t *testing.T) {
+	// Test that NaN/Inf inputs are skipped and never reach the final score
+	min := 0.0
+	max := 100.0
+
+	resolvedSchema := &schema.ResolvedSchema{
+		SiteIDColumn: "site_id",
+		Fields: map[string]schema.FieldDef{
+			"unemployment": {Type: schema.TypePercentage, Min: &min, Max: &max, Weight: 1.0, Direction: schema.DirectionMinimize},
+			"growth_rate":  {Type: schema.TypePercentage, Min: &min, Max: &max, Weight: 1.0, Direction: schema.DirectionMaximize},
+		},
+		Weights: map[string]float64{
+			"unemployment": 1.0,
+			"growth_rate":  1.0,
+		},
+	}
+
+	testCases := []struct {
+		name  string
+		value interface{}
+	}{
+		{"string Inf", "Inf"},
+		{"string NaN", "NaN"},
+		{"string 1e400", "1e400"},
+		{"float +Inf", math.Inf(1)},
+		{"float -Inf", math.Inf(-1)},
+		{"float NaN", math.NaN()},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			siteData := map[string]interface{}{
+				"unemployment": 20.0,
+				"growth_rate":  tc.value,
+			}
+
+			rawScore, finalScore, explanation, err := DefaultScoreFunc(siteData, resolvedSchema)
+
+			require.NoError(t, err)
+			require.Len(t, explanation.Factors, 1, "non-finite factor should be skipped")
+			assert.Equal(t, "unemployment", explanation.Factors[0].Name)
+			assert.InDelta(t, 80.0, finalScore, 1e-9)
+			assert.False(t, math.IsNaN(rawScore) || math.IsInf(rawScore, 0))
+
+			_, err = json.Marshal(explanation)
+			assert.NoError(t, err, "explanation must serialize as JSON")
+		})
+	}
+}
+
+func TestDefaultScoreFunc_OverflowingWeightsStayFinite(t *testing.T) {
+	// Test that sums overflowing to Inf still produce a finite final score
+	resolvedSchema := &schema.ResolvedSchema{
+		SiteIDColumn: "site_id",
+		Fields: map[string]schema.FieldDef{
+			"a": {Type: schema.TypeNumeric, Weight: math.MaxFloat64, Direction: schema.DirectionMaximize},
+			"b": {Type: schema.TypeNumeric, Weight: math.MaxFloat64, Direction: schema.DirectionMaximize},
+		},
+		Weights: map[string]float64{
+			"a": math.MaxFloat64,
+			"b": math.MaxFloat64,
+		},
+	}
+
+	rawScore, finalScore, _, err := DefaultScoreFunc(map[string]interface{}{"a": 100.0, "b": 100.0}, resolvedSchema)
+
+	require.NoError(t, err)
+	assert.False(t, math.IsNaN(finalScore) || math.IsInf(finalScore, 0))
+	assert.False(t, math.IsNaN(rawScore) || math.IsInf(rawScore, 0))
+}
+
+func TestToFloat64_RejectsNonFinite(t *testing.T) {
+	for _, v := range []interface{}{"Inf", "NaN", "1e400", math.Inf(1), math.NaN(), float32(math.Inf(-1))} {
+		_, err := toFloat64(v)
+		assert.Error(t, err, "%v", v)
+	}
+	_, err := toFloat64(math.Inf(1))
+	assert.ErrorIs(t, err, errNonFinite)
+}
+
 func TestDefaultScoreFunc_NilSchemaReturnsError(t *testing.T) {
 	// Test that nil schema returns error
 	siteData := map[string]interface{}{
@@ -561,3 +690,594 @@ func TestDefaultScoreFunc_BoundedNormalization(t *testing.T) {
 	assert.GreaterOrEqual(t, finalScore, 0.0)
 	assert.LessOrEqual(t, finalScore, 100.0)
 }
+
+func TestDefaultScoreFunc_UnboundedFieldsUseTypeDefaultRange(t *testing.T) {
+	// Test that unbounded population values spread out instead of collapsing to 1.0
+	min := 0.0
+
+	resolvedSchema := &schema.ResolvedSchema{
+		SiteIDColumn: "site_id",
+		Fields: map[string]schema.FieldDef{
+			"working_age_pop": {
+				Type:      schema.TypePopulation,
+				Min:       &min, // no max, as in the default global config
+				Weight:    1.0,
+				Direction: schema.DirectionMaximize,
+			},
+		},
+		Weights: map[string]float64{"working_age_pop": 1.0},
+	}
+
+	scores := make([]float64, 0, 3)
+	for _, pop := range []float64{50000, 250000, 800000} {
+		_, finalScore, _, err := DefaultScoreFunc(map[string]interface{}{"working_age_pop": pop}, resolvedSchema)
+		require.NoError(t, err)
+		scores = append(scores, finalScore)
+	}
+
+	assert.InDelta(t, 5.0, scores[0], 1e-9)
+	assert.InDelta(t, 25.0, scores[1], 1e-9)
+	assert.InDelta(t, 80.0, scores[2], 1e-9)
+
+	// A configured default range for the type takes precedence
+	resolvedSchema.Scoring.DefaultRanges = map[schema.FieldType]schema.Range{
+		schema.TypePopulation: {Min: 0, Max: 100000},
+	}
+	_, finalScore, _, err := DefaultScoreFunc(map[string]interface{}{"working_age_pop": 50000.0}, resolvedSchema)
+	require.NoError(t, err)
+	assert.InDelta(t, 50.0, finalScore, 1e-9)
+}
+
+func TestDefaultScoreFunc_UnboundedMinimizeIsInverted(t *testing.T) {
+	// Test that the default range respects direction like configured bounds do
+	resolvedSchema := &schema.ResolvedSchema{
+		SiteIDColumn: "site_id",
+		Fields: map[string]schema.FieldDef{
+			"local_competitors": {Type: schema.TypeInteger, Weight: 1.0, Direction: schema.DirectionMinimize},
+		},
+		Weights: map[string]float64{"local_competitors": 1.0},
+	}
+
+	_, finalScore, _, err := DefaultScoreFunc(map[string]interface{}{"local_competitors": 20.0}, resolvedSchema)
+	require.NoError(t, err)
+	assert.InDelta(t, 80.0, finalScore, 1e-9)
+}
+
+func TestDefaultScoreFunc_TargetDirectionPeaksAtTarget(t *testing.T) {
+	// Test that a target field scores highest at the target and falls off
+	// linearly towards whichever bound lies on the value's side
+	min, max, target := 0.0, 100.0, 40.0
+	resolvedSchema := &schema.ResolvedSchema{
+		SiteIDColumn: "site_id",
+		Fields: map[string]schema.FieldDef{
+			"avg_age": {Type: schema.TypeNumeric, Min: &min, Max: &max, Weight: 1.0, Direction: schema.DirectionTarget, Target: &target},
+		},
+		Weights: map[string]float64{"avg_age": 1.0},
+	}
+
+	testCases := []struct {
+		value    float64
+		expected float64
+	}{
+		{40, 100},
+		{20, 50},
+		{0, 0},
+		{70, 50},
+		{100, 0},
+		{150, 0},
+	}
+
+	for _, tc := range testCases {
+		_, finalScore, explanation, err := DefaultScoreFunc(map[string]interface{}{"avg_age": tc.value}, resolvedSchema)
+		require.NoError(t, err)
+		assert.InDelta(t, tc.expected, finalScore, 1e-9, "value %v", tc.value)
+		require.Len(t, explanation.Factors, 1)
+		assert.Equal(t, "target", explanation.Factors[0].Direction)
+		assert.Contains(t, explanation.Factors[0].Reason, "against a target of 40.00")
+	}
+}
+
+func TestDefaultScoreFunc_LogTransformSpreadsSkewedField(t *testing.T) {
+	// Test that a log transform keeps ordinary sites apart when an outlier
+	// stretches the bounds, where linear normalization crushes them
+	sites := skewedSites()
+	linear := skewedSchema(schema.ModeLinear)
+	logged := skewedSchema(schema.ModeLinear)
+	population := logged.Fields["population"]
+	population.Transform = schema.TransformLog
+	logged.Fields["population"] = population
+
+	var linearScores, logScores []float64
+	for _, site := range sites {
+		_, linearScore, _, err := DefaultScoreFunc(site, linear)
+		require.NoError(t, err)
+		linearScores = append(linearScores, linearScore)
+
+		_, logScore, _, err := DefaultScoreFunc(site, logged)
+		require.NoError(t, err)
+		logScores = append(logScores, logScore)
+	}
+
+	// Linear: every non-outlier lands under 1 point
+	for _, score := range linearScores[:4] {
+		assert.Less(t, score, 1.0)
+	}
+
+	// Log: ln(1+x) over ln(1+1e6) puts 1,000 at about half the range
+	assert.InDelta(t, 100*math.Log1p(1000)/math.Log1p(1000000), logScores[0], 1e-9)
+	assert.Greater(t, logScores[3]-logScores[0], 5.0)
+	assert.InDelta(t, 100.0, logScores[4], 1e-9)
+
+	// Both keep the same order
+	for i := 1; i < len(sites); i++ {
+		assert.Greater(t, linearScores[i], linearScores[i-1])
+		assert.Greater(t, logScores[i], logScores[i-1])
+	}
+}
+
+func TestNormalizeValue_Transforms(t *testing.T) {
+	bounds := schema.Range{Min: 0, Max: 100}
+	normalized := func(value float64, fieldDef schema.FieldDef) float64 {
+		n, _ := normalizeValue(value, bounds, fieldDef)
+		return n
+	}
+
+	sqrt := schema.FieldDef{Direction: schema.DirectionMaximize, Transform: schema.TransformSqrt}
+	assert.InDelta(t, 0.5, normalized(25, sqrt), 1e-9)
+
+	// Negative values are clamped to 0 rather than producing NaN
+	logField := schema.FieldDef{Direction: schema.DirectionMaximize, Transform: schema.TransformLog}
+	assert.InDelta(t, 0.0, normalized(-40, logField), 1e-9)
+	assert.True(t, schema.TransformLog.Clamps(-40))
+	assert.False(t, schema.TransformNone.Clamps(-40))
+
+	// The target is transformed along with the value and bounds
+	target := 25.0
+	targeted := schema.FieldDef{Direction: schema.DirectionTarget, Target: &target, Transform: schema.TransformSqrt}
+	assert.InDelta(t, 1.0, normalized(25, targeted), 1e-9)
+	assert.InDelta(t, 0.5, normalized(56.25, targeted), 1e-9)
+
+	// No transform is linear
+	assert.InDelta(t, 0.25, normalized(25, schema.FieldDef{Direction: schema.DirectionMaximize}), 1e-9)
+}
+
+func TestDefaultScoreFunc_ClampedValueIsFlagged(t *testing.T) {
+	min, max := 0.0, 100.0
+	resolvedSchema := &schema.ResolvedSchema{
+		SiteIDColumn: "site_id",
+		Fields: map[string]schema.FieldDef{
+			"growth_rate":  {Type: schema.TypePercentage, Min: &min, Max: &max, Weight: 1.0, Direction: schema.DirectionMaximize},
+			"unemployment": {Type: schema.TypePercentage, Min: &min, Max: &max, Weight: 1.0, Direction: schema.DirectionMinimize},
+		},
+		Weights: map[string]float64{"growth_rate": 1.0, "unemployment": 1.0},
+	}
+
+	_, _, explanation, err := DefaultScoreFunc(map[string]interface{}{
+		"growth_rate":  150.0, // above max
+		"unemployment": 40.0,  // in range
+	}, resolvedSchema)
+	require.NoError(t, err)
+
+	factors := make(map[string]models.ExplanationFactor)
+	for _, f := range explanation.Factors {
+		factors[f.Name] = f
+	}
+
+	growth := factors["growth_rate"]
+	assert.True(t, growth.Clamped)
+	require.NotNil(t, growth.UnclampedNormalized)
+	assert.InDelta(t, 1.5, *growth.UnclampedNormalized, 1e-9)
+	assert.InDelta(t, 1.0, growth.Contribution, 1e-9, "contribution is still capped")
+	assert.Contains(t, growth.Reason, "value exceeds configured max of 100 and was capped")
+
+	unemployment := factors["unemployment"]
+	assert.False(t, unemployment.Clamped)
+	assert.Nil(t, unemployment.UnclampedNormalized)
+	assert.NotContains(t, unemployment.Reason, "capped")
+
+	// Below min under minimize: the inverted position exceeds 1
+	_, _, explanation, err = DefaultScoreFunc(map[string]interface{}{"unemployment": -20.0}, resolvedSchema)
+	require.NoError(t, err)
+	require.Len(t, explanation.Factors, 1)
+	assert.True(t, explanation.Factors[0].Clamped)
+	assert.InDelta(t, 1.2, *explanation.Factors[0].UnclampedNormalized, 1e-9)
+	assert.Contains(t, explanation.Factors[0].Reason, "value is below configured min of 0 and was capped")
+}
+
+func TestDefaultScoreFunc_ScoreScales(t *testing.T) {
+	// Test that each score scale produces proportional final scores and matching summary text
+	min := 0.0
+	max := 100.0
+
+	newSchema := func(scale schema.ScoreScale) *schema.ResolvedSchema {
+		return &schema.ResolvedSchema{
+			SiteIDColumn: "site_id",
+			Fields: map[string]schema.FieldDef{
+				"population": {
+					Type:      schema.TypePopulation,
+					Min:       &min,
+					Max:       &max,
+					Weight:    1.0,
+					Direction: schema.DirectionMaximize,
+				},
+			},
+			Weights: map[string]float64{"population": 1.0},
+			Scoring: schema.ScoringOptions{ScoreScale: scale},
+		}
+	}
+
+	siteData := map[string]interface{}{"population": 72.0}
+
+	testCases := []struct {
+		scale    schema.ScoreScale
+		expected float64
+		summary  string
+	}{
+		{"", 72, "Final score is 72.0."},
+		{schema.ScaleHundred, 72, "Final score is 72.0."},
+		{schema.ScaleTen, 7.2, "Final score is 7.2 out of 10."},
+		{schema.ScaleUnit, 0.72, "Final score is 0.72 out of 1."},
+	}
+
+	for _, tc := range testCases {
+		rawScore, finalScore, explanation, err := DefaultScoreFunc(siteData, newSchema(tc.scale))
+		require.NoError(t, err)
+		assert.InDelta(t, tc.expected, finalScore, 1e-9, "scale %q", tc.scale)
+		assert.InDelta(t, 0.72, rawScore, 1e-9, "raw score is scale-independent")
+		assert.Contains(t, explanation.Summary, tc.summary, "scale %q", tc.scale)
+	}
+
+	// Scores are clamped to the top of the scale
+	siteData = map[string]interface{}{"population": 500.0}
+	_, finalScore, _, err := DefaultScoreFunc(siteData, newSchema(schema.ScaleTen))
+	require.NoError(t, err)
+	assert.Equal(t, 10.0, finalScore)
+}
+
+func TestGenerateSummary_FactorCounts(t *testing.T) {
+	// Test that the summary names the configured number of factors with correct grammar
+	factors := []models.ExplanationFactor{
+		{Name: "working_age_pop", Contribution: 0.9},
+		{Name: "unemployment_rate", Contribution: 0.8},
+		{Name: "labor_cost_index", Contribution: 0.7},
+		{Name: "avg_commute_time", Contribution: 0.6},
+		{Name: "local_competitors", Contribution: 0.5},
+		{Name: "public_transport_access", Contribution: 0.4},
+	}
+
+	testCases := []struct {
+		count    int
+		expected string
+	}{
+		{1, "Final score is 81.0. The primary contributing factor is working age pop."},
+		{2, "Final score is 81.0. Top contributing factors are working age pop and unemployment rate."},
+		{3, "Final score is 81.0. Top contributing factors are working age pop, unemployment rate, and labor cost index."},
+		{5, "Final score is 81.0. Top contributing factors are working age pop, unemployment rate, labor cost index, avg commute time, and local competitors."},
+		{10, "Final score is 81.0. Top contributing factors are working age pop, unemployment rate, labor cost index, avg commute time, local competitors, and public transport access."},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, generateSummary(i18n.Lookup(i18n.DefaultLocale), factors, 81, schema.ScaleHundred, tc.count), "count %d", tc.count)
+	}
+}
+
+func TestGenerateSummary_WeakFactorCallouts(t *testing.T) {
+	// Test that weak factors are named weakest first, capped, and not credited as drivers
+	factors := []models.ExplanationFactor{
+		{Name: "working_age_pop", Weight: 1, Contribution: 0.9, Direction: "maximize"},
+		{Name: "growth_rate", Weight: 1, Contribution: 0.3, Direction: "maximize"},
+		{Name: "unemployment_rate", Weight: 2, Contribution: 0.2, Direction: "minimize"},
+		{Name: "labor_cost_index", Weight: 1, Contribution: 0.4, Direction: "minimize"},
+	}
+
+	summary := generateSummary(i18n.Lookup(i18n.DefaultLocale), factors, 45, schema.ScaleHundred, 3)
+
+	assert.Equal(t,
+		"Final score is 45.0. The primary contributing factor is working age pop. Held back by high unemployment rate and low growth rate.",
+		summary)
+}
+
+func TestGenerateSummary_NoCalloutForStrongFactors(t *testing.T) {
+	// Test that a summary without weak factors has no callout
+	factors := []models.ExplanationFactor{
+		{Name: "working_age_pop", Weight: 1, Contribution: 0.9, Direction: "maximize"},
+		{Name: "unemployment_rate", Weight: 2, Contribution: 1.2, Direction: "minimize"},
+	}
+
+	summary := generateSummary(i18n.Lookup(i18n.DefaultLocale), factors, 90, schema.ScaleHundred, 3)
+
+	assert.NotContains(t, summary, "Held back by")
+}
+
+func TestDefaultScoreFunc_LowScoringSiteNamesWeakestFactors(t *testing.T) {
+	// Test that a low-scoring site's summary names its weakest factors
+	min := 0.0
+	max := 100.0
+
+	resolvedSchema := &schema.ResolvedSchema{
+		SiteIDColumn: "site_id",
+		Fields: map[string]schema.FieldDef{
+			"unemployment": {
+				Type:      schema.TypePercentage,
+				Min:       &min,
+				Max:       &max,
+				Weight:    3.0,
+				Direction: schema.DirectionMinimize,
+			},
+			"growth": {
+				Type:      schema.TypePercentage,
+				Min:       &min,
+				Max:       &max,
+				Weight:    1.0,
+				Direction: schema.DirectionMaximize,
+			},
+			"income": {
+				Type:      schema.TypePercentage,
+				Min:       &min,
+				Max:       &max,
+				Weight:    2.0,
+				Direction: schema.DirectionMaximize,
+			},
+		},
+		Weights: map[string]float64{
+			"unemployment": 3.0,
+			"growth":       1.0,
+			"income":       2.0,
+		},
+	}
+
+	siteData := map[string]interface{}{
+		"unemployment": 90.0,
+		"growth":       20.0,
+		"income":       40.0,
+	}
+
+	_, _, explanation, err := DefaultScoreFunc(siteData, resolvedSchema)
+
+	require.NoError(t, err)
+	assert.Equal(t,
+		"Final score is 21.7. The primary contributing factor is income. Held back by high unemployment and low growth.",
+		explanation.Summary)
+}
+
+func TestGenerateReasonString_QualityBuckets(t *testing.T) {
+	// Test that configured buckets relabel the same normalized value
+	msgs := i18n.Lookup(i18n.DefaultLocale)
+	fieldDef := schema.FieldDef{Type: schema.TypePercentage, Direction: schema.DirectionMaximize}
+
+	assert.Equal(t, "Growth value is 60.00, which is good for this metric (higher is better)",
+		generateReasonString(msgs, nil, "growth", 60, 0.6, fieldDef), "default buckets")
+
+	strict := []schema.QualityBucket{
+		{Threshold: 0.9, Label: "outstanding"},
+		{Threshold: 0.7, Label: "solid"},
+		{Threshold: 0.4, Label: "below par"},
+	}
+	assert.Equal(t, "Growth value is 60.00, which is below par for this metric (higher is better)",
+		generateReasonString(msgs, strict, "growth", 60, 0.6, fieldDef))
+	assert.Equal(t, "Growth value is 10.00, which is below par for this metric (higher is better)",
+		generateReasonString(msgs, strict, "growth", 10, 0.1, fieldDef), "values below every threshold take the last label")
+
+	lenient := []schema.QualityBucket{
+		{Threshold: 0.5, Label: "promising"},
+		{Threshold: 0, Label: "worth a look"},
+	}
+	assert.Equal(t, "Growth value is 60.00, which is promising for this metric (higher is better)",
+		generateReasonString(msgs, lenient, "growth", 60, 0.6, fieldDef))
+}
+
+func TestDefaultScoreFunc_QualityBucketsOption(t *testing.T) {
+	// Test that the scoring option reaches the factor reasons
+	resolved := goldenSchema()
+	resolved.Scoring.QualityBuckets = []schema.QualityBucket{
+		{Threshold: 0.5, Label: "strong"},
+		{Threshold: 0, Label: "weak"},
+	}
+
+	_, _, explanation, err := DefaultScoreFunc(goldenSite(), resolved)
+	require.NoError(t, err)
+
+	require.NotEmpty(t, explanation.Factors)
+	for _, factor := range explanation.Factors {
+		assert.Contains(t, factor.Reason, "which is strong for this metric", factor.Name)
+	}
+}
+
+func TestDefaultScoreFunc_SpanishLocale(t *testing.T) {
+	// Test that the schema's locale selects the catalog explanations are rendered with
+	min := 0.0
+	max := 100.0
+
+	resolvedSchema := &schema.ResolvedSchema{
+		SiteIDColumn: "site_id",
+		Fields: map[string]schema.FieldDef{
+			"unemployment": {
+				Type:      schema.TypePercentage,
+				Min:       &min,
+				Max:       &max,
+				Weight:    3.0,
+				Direction: schema.DirectionMinimize,
+			},
+			"growth": {
+				Type:      schema.TypePercentage,
+				Min:       &min,
+				Max:       &max,
+				Weight:    1.0,
+				Direction: schema.DirectionMaximize,
+			},
+			"income": {
+				Type:      schema.TypePercentage,
+				Min:       &min,
+				Max:       &max,
+				Weight:    2.0,
+				Direction: schema.DirectionMaximize,
+			},
+		},
+		Weights: map[string]float64{
+			"unemployment": 3.0,
+			"growth":       1.0,
+			"income":       2.0,
+		},
+		Scoring: schema.ScoringOptions{Locale: "es"},
+	}
+
+	siteData := map[string]interface{}{
+		"unemployment": 90.0,
+		"growth":       20.0,
+		"income":       40.0,
+	}
+
+	_, _, explanation, err := DefaultScoreFunc(siteData, resolvedSchema)
+
+	require.NoError(t, err)
+	assert.Equal(t,
+		"La puntuación final es 21.7. El factor que más contribuye es income. Penalizada por valor alto de unemployment y valor bajo de growth.",
+		explanation.Summary)
+
+	reasons := make(map[string]string)
+	for _, factor := range explanation.Factors {
+		reasons[factor.Name] = factor.Reason
+	}
+	assert.Equal(t, "El valor de Unemployment es 90.00, lo que es deficiente para esta métrica (cuanto más bajo, mejor)", reasons["unemployment"])
+	assert.Equal(t, "El valor de Income es 40.00, lo que es regular para esta métrica (cuanto más alto, mejor)", reasons["income"])
+}
+
+func TestDefaultScoreFunc_SummaryFactorCountOption(t *testing.T) {
+	// Test that the scoring option reaches the summary
+	resolved := goldenSchema()
+	one := 1
+	resolved.Scoring.SummaryFactorCount = &one
+
+	_, _, explanation, err := DefaultScoreFunc(goldenSite(), resolved)
+	require.NoError(t, err)
+	assert.Equal(t, "Final score is 60.0. The primary contributing factor is access score.", explanation.Summary)
+}
+
+// categorySchema groups wage and rent under Economic (weight 2) and
+// labor_pool under Talent (weight 1).
+func categorySchema(t *testing.T) *schema.ResolvedSchema {
+	t.Helper()
+	resolved, err := schema.Resolve(json.RawMessage(`{
+		"site_id_column": "site_id",
+		"category_weights": {"Economic": 2, "Talent": 1},
+		"fields": {
+			"site_id": {"type": "identifier", "required": true},
+			"wage": {"type": "numeric", "min": 0, "max": 100, "weight": 1, "direction": "minimize", "category": "Economic"},
+			"rent": {"type": "numeric", "min": 0, "max": 100, "weight": 3, "direction": "minimize", "category": "Economic"},
+			"labor_pool": {"type": "numeric", "min": 0, "max": 100, "weight": 1, "direction": "maximize", "category": "Talent"}
+		}
+	}`), nil)
+	require.NoError(t, err)
+	return resolved
+}
+
+func TestDefaultScoreFunc_CategoryTwoLevelAggregation(t *testing.T) {
+	resolved := categorySchema(t)
+
+	raw, final, explanation, err := DefaultScoreFunc(map[string]interface{}{
+		"wage": 20.0, "rent": 60.0, "labor_pool": 90.0,
+	}, resolved)
+	require.NoError(t, err)
+
+	// Economic = (0.8*1 + 0.4*3) / 4 = 0.5; Talent = 0.9
+	// raw = 2*0.5 + 1*0.9; final = raw / 3 * 100
+	assert.InDelta(t, 1.9, raw, 1e-9)
+	assert.InDelta(t, 190.0/3, final, 1e-9)
+	assert.InDelta(t, 1.0, explanation.Coverage, 1e-9)
+
+	require.Len(t, explanation.Categories, 2)
+	assert.Equal(t, models.CategoryScore{Name: "Economic", Weight: 2, Score: 50, Contribution: 1}, roundCategory(explanation.Categories[0]))
+	assert.Equal(t, models.CategoryScore{Name: "Talent", Weight: 1, Score: 90, Contribution: 0.9}, roundCategory(explanation.Categories[1]))
+
+	// Factors stay field-level, weighted within their category
+	require.Len(t, explanation.Factors, 3)
+	for _, f := range explanation.Factors {
+		if f.Name == "rent" {
+			assert.Equal(t, 3.0, f.Weight)
+			assert.InDelta(t, 1.2, f.Contribution, 1e-9)
+		}
+	}
+
+	// The flat weighted mean of the same site differs: (0.8 + 1.2 + 0.9) / 5
+	resolved.CategoryWeights = nil
+	_, flat, explanation, err := DefaultScoreFunc(map[string]interface{}{
+		"wage": 20.0, "rent": 60.0, "labor_pool": 90.0,
+	}, resolved)
+	require.NoError(t, err)
+	assert.InDelta(t, 58.0, flat, 1e-9)
+	assert.Empty(t, explanation.Categories)
+}
+
+func TestDefaultScoreFunc_CategoryWithNoScoredFieldsIsLeftOut(t *testing.T) {
+	resolved := categorySchema(t)
+
+	_, final, explanation, err := DefaultScoreFunc(map[string]interface{}{
+		"wage": 20.0, "rent": 60.0,
+	}, resolved)
+	require.NoError(t, err)
+
+	// Only Economic scored, so the final score is its subscore
+	assert.InDelta(t, 50.0, final, 1e-9)
+	require.Len(t, explanation.Categories, 1)
+	assert.Equal(t, "Economic", explanation.Categories[0].Name)
+	// Talent's weight (1 of 3) went unscored
+	assert.InDelta(t, 2.0/3, explanation.Coverage, 1e-9)
+}
+
+// roundCategory rounds a category score's floats to 9 places for comparison.
+func roundCategory(c models.CategoryScore) models.CategoryScore {
+	round := func(f float64) float64 { return math.Round(f*1e9) / 1e9 }
+	c.Score = round(c.Score)
+	c.Contribution = round(c.Contribution)
+	return c
+}
+
+func TestDefaultScoreFunc_CategoryScoresCombineIntoFinalScore(t *testing.T) {
+	resolved := categorySchema(t)
+
+	_, final, explanation, err := DefaultScoreFunc(map[string]interface{}{
+		"wage": 20.0, "rent": 60.0, "labor_pool": 90.0,
+	}, resolved)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]float64{"Economic": 50, "Talent": 90}, roundScores(explanation.CategoryScores))
+	assert.Equal(t, "Economic: 50, Talent: 90", explanation.CategorySummary)
+
+	// The final score is the category-weighted mean of the subscores
+	var weighted, totalWeight float64
+	for name, score := range explanation.CategoryScores {
+		weighted += resolved.CategoryWeights[name] * score
+		totalWeight += resolved.CategoryWeights[name]
+	}
+	assert.InDelta(t, final, weighted/totalWeight, 1e-9)
+
+	// Subscores follow the score scale
+	resolved.Scoring.ScoreScale = schema.ScaleUnit
+	_, final, explanation, err = DefaultScoreFunc(map[string]interface{}{
+		"wage": 20.0, "rent": 60.0, "labor_pool": 90.0,
+	}, resolved)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"Economic": 0.5, "Talent": 0.9}, roundScores(explanation.CategoryScores))
+	assert.Equal(t, "Economic: 0.50, Talent: 0.90", explanation.CategorySummary)
+	assert.InDelta(t, 1.9/3, final, 1e-9)
+}
+
+func TestGeometricMeanScoreFunc_OmitsCategoryScores(t *testing.T) {
+	_, _, explanation, err := GeometricMeanScoreFunc(map[string]interface{}{
+		"wage": 20.0, "rent": 60.0, "labor_pool": 90.0,
+	}, categorySchema(t))
+	require.NoError(t, err)
+
+	assert.Empty(t, explanation.Categories)
+	assert.Empty(t, explanation.CategoryScores)
+	assert.Empty(t, explanation.CategorySummary)
+}
+
+// roundScores rounds each score to 9 places for comparison.
+func roundScores(scores map[string]float64) map[string]float64 {
+	rounded := make(map[string]float64, len(scores))
+	for name, score := range scores {
+		rounded[name] = math.Round(score*1e9) / 1e9
+	}
+	return rounded
+}
@@ -0,0 +1,46 @@
+package scoring
+
+import "errors"
+
+// ErrRunTimeout is wrapped by the error of a run that exceeded the pipeline's
+// run timeout. Such runs are failed permanently rather than retried.
+var ErrRunTimeout = errors.New("scoring run timed out")
+
+// ErrTooManyRecommendations is wrapped by the error of a run that scored more
+// sites than the pipeline's recommendation cap allows. Such runs are failed
+// permanently rather than retried.
+var ErrTooManyRecommendations = errors.New("run exceeds the recommendation cap")
+
+// ErrUnknownModelVersion is wrapped by ModelRegistry.Lookup when a version
+// is not registered.
+var ErrUnknownModelVersion = errors.New("unknown model version")
+
+// ErrUnknownScorer is wrapped by ScoreFuncRegistry.Lookup when no scorer is
+// registered under a name.
+var ErrUnknownScorer = errors.New("unknown scorer")
+
+// PermanentError marks a pipeline failure that retrying cannot fix, such as a
+// missing or unparseable schema configuration. ExecuteWithRetry stops on the
+// first PermanentError instead of backing off and trying again.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// permanent wraps err as a PermanentError.
+func permanent(err error) error {
+	return &PermanentError{Err: err}
+}
+
+// IsPermanent reports whether err (or any error it wraps) is a PermanentError.
+func IsPermanent(err error) bool {
+	var permErr *PermanentError
+	return errors.As(err, &permErr)
+}
@@ -0,0 +1,54 @@
+package scoring
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/workforce-ai/site-selection-iq/internal/i18n"
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
+)
+
+const hoursPerDay = 24
+
+// applyFreshness discounts each result whose data predates the schema's
+// freshness reference date by 0.5^(age/half-life), recording the discount
+// in its explanation. It is a no-op when freshness is not configured.
+func applyFreshness(results []siteScore, resolvedSchema *schema.ResolvedSchema) {
+	f := resolvedSchema.Scoring.Freshness
+	if f == nil {
+		return
+	}
+	ref := f.Reference(time.Now())
+	scale := resolvedSchema.Scoring.ScoreScale
+	msgs := i18n.Lookup(resolvedSchema.Scoring.Locale)
+
+	for i := range results {
+		r := &results[i]
+		asOf, ok := schema.ParseAsOf(r.site.data[f.AsOfColumn])
+		if !ok {
+			continue
+		}
+		ageDays := ref.Sub(asOf).Hours() / hoursPerDay
+		if ageDays <= 0 {
+			continue
+		}
+		factor := math.Pow(0.5, ageDays/f.HalfLifeDays)
+
+		before := r.finalScore
+		r.rawScore *= factor
+		r.finalScore *= factor
+		if r.interval != nil {
+			r.interval.low *= factor
+			r.interval.high *= factor
+		}
+		r.explanation.Freshness = &models.FreshnessPenalty{
+			AsOf:    asOf.Format("2006-01-02"),
+			AgeDays: math.Round(ageDays),
+			Factor:  factor,
+		}
+		r.explanation.Summary += fmt.Sprintf(msgs.SummaryFreshness,
+			formatScore(msgs, before, scale), formatScore(msgs, r.finalScore, scale), ageDays, asOf.Format("2006-01-02"), f.HalfLifeDays)
+	}
+}
@@ -0,0 +1,80 @@
+package scoring
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files in testdata/")
+
+// goldenSchema has several fields with identical weights and bounds so that
+// equal contributions are common and factor ordering is actually exercised.
+func goldenSchema() *schema.ResolvedSchema {
+	min, max := 0.0, 100.0
+	field := func(direction schema.Direction) schema.FieldDef {
+		return schema.FieldDef{Type: schema.TypeNumeric, Min: &min, Max: &max, Weight: 1.0, Direction: direction}
+	}
+	return &schema.ResolvedSchema{
+		SiteIDColumn: "site_id",
+		Fields: map[string]schema.FieldDef{
+			"site_id":         {Type: schema.TypeIdentifier},
+			"access_score":    field(schema.DirectionMaximize),
+			"labor_pool":      field(schema.DirectionMaximize),
+			"talent_density":  field(schema.DirectionMaximize),
+			"wage_pressure":   field(schema.DirectionMinimize),
+			"commute_minutes": field(schema.DirectionMinimize),
+			"vacancy_rate":    field(schema.DirectionMinimize),
+		},
+		Weights: map[string]float64{
+			"access_score": 1.0, "labor_pool": 1.0, "talent_density": 1.0,
+			"wage_pressure": 1.0, "commute_minutes": 1.0, "vacancy_rate": 1.0,
+		},
+	}
+}
+
+func goldenSite() map[string]interface{} {
+	// Every factor normalizes to 0.6, so all contributions tie
+	return map[string]interface{}{
+		"site_id":         "GOLD-1",
+		"access_score":    60.0,
+		"labor_pool":      60.0,
+		"talent_density":  60.0,
+		"wage_pressure":   40.0,
+		"commute_minutes": 40.0,
+		"vacancy_rate":    40.0,
+	}
+}
+
+func TestDefaultScoreFunc_GoldenExplanation(t *testing.T) {
+	golden := filepath.Join("testdata", "explanation.golden.json")
+
+	score := func() []byte {
+		_, _, explanation, err := DefaultScoreFunc(goldenSite(), goldenSchema())
+		require.NoError(t, err)
+		out, err := json.MarshalIndent(explanation, "", "  ")
+		require.NoError(t, err)
+		return append(out, '\n')
+	}
+
+	first := score()
+	// Map iteration order is randomized per range statement, so repeat enough
+	// times that any order dependence would surface
+	for i := 0; i < 50; i++ {
+		require.Equal(t, string(first), string(score()), "explanation changed on repeat %d", i)
+	}
+
+	if *updateGolden {
+		require.NoError(t, os.WriteFile(golden, first, 0o644))
+	}
+	want, err := os.ReadFile(golden)
+	require.NoError(t, err, "run with -update to create the golden file")
+	assert.Equal(t, string(want), string(first))
+}
@@ -0,0 +1,133 @@
+package scoring
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
+)
+
+// DefaultModelVersion is the model used by runs that request no version or
+// "latest".
+const DefaultModelVersion = "site-selection-iq-v1.0"
+
+// Model is a registered scoring model version. A run's model_version selects
+// one, and the model decides how sites are aggregated and normalized on top
+// of the tenant's resolved schema.
+type Model struct {
+	// Version is the canonical name recorded on runs.
+	Version string
+
+	// Aliases are shorthand names that resolve to Version, e.g. "v1".
+	Aliases []string
+
+	Description string
+
+	// Options are scoring defaults applied over the resolved schema's own,
+	// e.g. Mode for aggregation and DeriveBounds for normalization. A run's
+	// scoring_config still overrides them.
+	Options schema.ScoringOptions
+
+	// Weights replace the schema's weight for each named field.
+	Weights map[string]float64
+
+	// Scorer names the ScoreFuncRegistry entry that scores each site in
+	// linear mode; empty means DefaultScorer.
+	Scorer string
+}
+
+// builtinModels are registered in every new ModelRegistry.
+var builtinModels = []Model{
+	{
+		Version:     DefaultModelVersion,
+		Aliases:     []string{"v1"},
+		Description: "Weighted linear scoring normalized against configured or type-default bounds",
+	},
+	{
+		Version:     "site-selection-iq-v2.0",
+		Aliases:     []string{"v2"},
+		Description: "Weighted rank-sum scoring: each site is scored by its weighted average percentile rank within the upload",
+		Options:     schema.ScoringOptions{Mode: schema.ModeRankSum},
+	},
+}
+
+// ModelRegistry maps model version names to scoring models. It is safe for
+// concurrent use.
+type ModelRegistry struct {
+	mu      sync.RWMutex
+	models  map[string]Model
+	aliases map[string]string
+}
+
+// NewModelRegistry creates a registry holding the built-in models.
+func NewModelRegistry() *ModelRegistry {
+	r := &ModelRegistry{
+		models:  make(map[string]Model),
+		aliases: make(map[string]string),
+	}
+	for _, m := range builtinModels {
+		if err := r.Register(m); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// Register adds a model. Its version and aliases must not already be taken.
+func (r *ModelRegistry) Register(m Model) error {
+	if m.Version == "" {
+		return fmt.Errorf("model version must not be empty")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, name := range append([]string{m.Version}, m.Aliases...) {
+		if _, taken := r.models[name]; taken {
+			return fmt.Errorf("model version %q is already registered", name)
+		}
+		if _, taken := r.aliases[name]; taken {
+			return fmt.Errorf("model version %q is already registered", name)
+		}
+	}
+
+	r.models[m.Version] = m
+	for _, alias := range m.Aliases {
+		r.aliases[alias] = m.Version
+	}
+	return nil
+}
+
+// Lookup returns the model for version, which may be a canonical version or
+// an alias. Empty and "latest" select DefaultModelVersion.
+func (r *ModelRegistry) Lookup(version string) (Model, error) {
+	if version == "" || version == "latest" {
+		version = DefaultModelVersion
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if canonical, ok := r.aliases[version]; ok {
+		version = canonical
+	}
+	m, ok := r.models[version]
+	if !ok {
+		return Model{}, fmt.Errorf("%w: %q", ErrUnknownModelVersion, version)
+	}
+	return m, nil
+}
+
+// Versions returns the canonical versions of all registered models, sorted.
+func (r *ModelRegistry) Versions() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	versions := make([]string, 0, len(r.models))
+	for v := range r.models {
+		versions = append(versions, v)
+	}
+	sort.Strings(versions)
+	return versions
+}
@@ -0,0 +1,38 @@
+package scoring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModelRegistry_LookupDefaultAndAliases(t *testing.T) {
+	r := NewModelRegistry()
+
+	for _, version := range []string{"", "latest", "v1", DefaultModelVersion} {
+		m, err := r.Lookup(version)
+		require.NoError(t, err, "version %q", version)
+		assert.Equal(t, DefaultModelVersion, m.Version, "version %q", version)
+	}
+
+	m, err := r.Lookup("v2")
+	require.NoError(t, err)
+	assert.Equal(t, "site-selection-iq-v2.0", m.Version)
+}
+
+func TestModelRegistry_UnknownVersion(t *testing.T) {
+	_, err := NewModelRegistry().Lookup("v9")
+	assert.ErrorIs(t, err, ErrUnknownModelVersion)
+}
+
+func TestModelRegistry_RegisterRejectsDuplicates(t *testing.T) {
+	r := NewModelRegistry()
+
+	assert.Error(t, r.Register(Model{Version: DefaultModelVersion}))
+	assert.Error(t, r.Register(Model{Version: "custom", Aliases: []string{"v2"}}))
+	assert.Error(t, r.Register(Model{}))
+
+	require.NoError(t, r.Register(Model{Version: "custom", Aliases: []string{"c"}}))
+	assert.Contains(t, r.Versions(), "custom")
+}
@@ -2,78 +2,320 @@ package scoring
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
-	"math"
 	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/workforce-ai/site-selection-iq/internal/i18n"
 	"github.com/workforce-ai/site-selection-iq/internal/models"
-	"github.com/workforce-ai/site-selection-iq/internal/repository"
 	"github.com/workforce-ai/site-selection-iq/internal/schema"
+	"github.com/workforce-ai/site-selection-iq/pkg/clock"
+	"github.com/workforce-ai/site-selection-iq/pkg/timing"
 )
 
+// RunStore is the subset of run persistence the pipeline depends on.
+// *repository.RunRepository satisfies it.
+type RunStore interface {
+	UpdateStatus(ctx context.Context, runID uuid.UUID, status string, scoredCount *int, lastError *string, durationMs *int) error
+	IncrementAttempt(ctx context.Context, runID uuid.UUID) error
+	UpdateStats(ctx context.Context, runID uuid.UUID, stats *models.RunStats) error
+	UpdateSkipped(ctx context.Context, runID uuid.UUID, skippedCount int, sample []models.SkippedSite) error
+	UpdateResultKey(ctx context.Context, runID uuid.UUID, resultKey *string) error
+	ListStale(ctx context.Context, olderThan time.Duration, excludeInstance uuid.UUID) ([]models.ScoringRun, error)
+	ClaimOrphan(ctx context.Context, runID, fromInstance, toInstance uuid.UUID) (bool, error)
+	FailOrphan(ctx context.Context, runID, fromInstance uuid.UUID, reason string) (bool, error)
+	Heartbeat(ctx context.Context, runID, instanceID uuid.UUID) error
+	ClaimNext(ctx context.Context, instanceID uuid.UUID) (*models.ScoringRun, error)
+}
+
+// SiteRecordStore is the subset of site record persistence the pipeline depends on.
+type SiteRecordStore interface {
+	GetByUpload(ctx context.Context, uploadID uuid.UUID) ([]models.SiteRecord, error)
+}
+
+// RecommendationStore is the subset of recommendation persistence the pipeline depends on.
+type RecommendationStore interface {
+	ReplaceByRun(ctx context.Context, runID uuid.UUID, recs []models.Recommendation) error
+}
+
+// SkippedSiteStore persists the sites a run skipped.
+// *repository.SkippedSiteRepository satisfies it.
+type SkippedSiteStore interface {
+	BulkInsert(ctx context.Context, sites []models.RunSkippedSite) error
+}
+
+// UploadStore looks up the upload a run scores, for its content hash.
+// *repository.UploadRepository satisfies it.
+type UploadStore interface {
+	GetByID(ctx context.Context, tenantID, uploadID uuid.UUID) (*models.Upload, error)
+}
+
+// SchemaConfigStore is the subset of schema config persistence the pipeline depends on.
+type SchemaConfigStore interface {
+	GetGlobalActive(ctx context.Context) (*models.SchemaConfig, error)
+	GetTenantActive(ctx context.Context, tenantID uuid.UUID) (*models.SchemaConfig, error)
+	GetActiveVersions(ctx context.Context, tenantID uuid.UUID) (schema.ConfigVersions, error)
+	CreateSnapshot(ctx context.Context, snapshot *models.SchemaConfigSnapshot) error
+}
+
 // Pipeline manages the asynchronous scoring execution workflow.
 // It coordinates between repositories, schema resolution, and the scoring function.
 type Pipeline struct {
-	runRepo            *repository.RunRepository
-	siteRecordRepo     *repository.SiteRecordRepository
-	recommendationRepo *repository.RecommendationRepository
-	schemaConfigRepo   *repository.SchemaConfigRepository
+	runRepo            RunStore
+	siteRecordRepo     SiteRecordStore
+	recommendationRepo RecommendationStore
+	schemaConfigRepo   SchemaConfigStore
 	schemaResolver     *schema.Resolver
-	scoreFunc          ScoreFunc
+	scoreFuncs         *ScoreFuncRegistry
+	models             *ModelRegistry
 	maxRetries         int
 	retryBaseWait      time.Duration
+
+	// runTimeout bounds a single Execute; zero means no deadline
+	runTimeout time.Duration
+
+	// rng drives backoff jitter; guarded by rngMu since runs retry concurrently
+	rngMu sync.Mutex
+	rng   *rand.Rand
+
+	// clock supplies timestamps and durations; tests swap in a fake
+	clock clock.Clock
+
+	// instanceID identifies this process as the owner of the runs it dispatches
+	instanceID uuid.UUID
+
+	// runSlots caps how many dispatched runs execute at once across all
+	// callers; nil means no limit
+	runSlots chan struct{}
+
+	// maxRecommendations caps the recommendations one run may persist; zero
+	// means no cap. Past the cap a run fails unless truncateRecommendations
+	// is set, in which case only the top maxRecommendations are kept.
+	maxRecommendations      int
+	truncateRecommendations bool
+
+	// skipTolerance is the fraction of a run's sites that may be skipped
+	// while the run still succeeds; past it the run is completed_with_errors
+	skipTolerance float64
+
+	// skippedSiteRepo, when set, stores up to maxSkippedSites of each run's
+	// skipped sites with their reasons
+	skippedSiteRepo SkippedSiteStore
+	maxSkippedSites int
+
+	// uploadRepo, when set, supplies the content hash each run's result key
+	// is computed from; without it no run's results are reusable
+	uploadRepo UploadStore
+
+	// defaultLocale is the explanation language of runs whose schema and
+	// scoring_config set none
+	defaultLocale string
+
+	// Queue workers, once started, claim queued runs from runRepo; Dispatch
+	// then only wakes them. wake holds at most one pending signal.
+	workers      int
+	pollInterval time.Duration
+	wake         chan struct{}
+	stopWorkers  chan struct{}
+	stopOnce     sync.Once
+
+	// heartbeat is how often a dispatched run's updated_at is refreshed
+	// while it executes, so other instances never take it for an orphan
+	heartbeat time.Duration
+
+	// In-flight tracking for runs launched via Dispatch
+	baseCtx    context.Context
+	cancelBase context.CancelFunc
+	wg         sync.WaitGroup
+	mu         sync.Mutex
+	inFlight   map[uuid.UUID]*models.ScoringRun
 }
 
-// NewPipeline creates a new scoring pipeline
+const (
+	// defaultRetryBaseWait is used when the configured base wait is not positive
+	defaultRetryBaseWait = 2 * time.Second
+
+	// minBackoff keeps retries from degenerating into a tight loop against the DB
+	minBackoff = 50 * time.Millisecond
+
+	// maxBackoff caps the wait between attempts
+	maxBackoff = 5 * time.Minute
+)
+
+// NewPipeline creates a new scoring pipeline. Each run's scorer is looked up
+// in scoreFuncs; nil uses a registry holding only the built-in scorers.
+// A non-positive retryBaseWait is replaced with defaultRetryBaseWait. A
+// positive runTimeout caps how long one execution of a run may take.
 func NewPipeline(
-	runRepo *repository.RunRepository,
-	siteRecordRepo *repository.SiteRecordRepository,
-	recommendationRepo *repository.RecommendationRepository,
-	schemaConfigRepo *repository.SchemaConfigRepository,
+	runRepo RunStore,
+	siteRecordRepo SiteRecordStore,
+	recommendationRepo RecommendationStore,
+	schemaConfigRepo SchemaConfigStore,
 	schemaResolver *schema.Resolver,
-	scoreFunc ScoreFunc,
+	scoreFuncs *ScoreFuncRegistry,
 	maxRetries int,
 	retryBaseWait time.Duration,
+	runTimeout time.Duration,
 ) *Pipeline {
-	if scoreFunc == nil {
-		scoreFunc = DefaultScoreFunc
+	if scoreFuncs == nil {
+		scoreFuncs = NewScoreFuncRegistry()
+	}
+	if retryBaseWait <= 0 {
+		retryBaseWait = defaultRetryBaseWait
 	}
+	baseCtx, cancelBase := context.WithCancel(context.Background())
 	return &Pipeline{
 		runRepo:            runRepo,
 		siteRecordRepo:     siteRecordRepo,
 		recommendationRepo: recommendationRepo,
 		schemaConfigRepo:   schemaConfigRepo,
 		schemaResolver:     schemaResolver,
-		scoreFunc:          scoreFunc,
+		scoreFuncs:         scoreFuncs,
+		models:             NewModelRegistry(),
 		maxRetries:         maxRetries,
 		retryBaseWait:      retryBaseWait,
+		runTimeout:         runTimeout,
+		rng:                rand.New(rand.NewSource(time.Now().UnixNano())),
+		clock:              clock.Real{},
+		instanceID:         uuid.New(),
+		wake:               make(chan struct{}, 1),
+		stopWorkers:        make(chan struct{}),
+		baseCtx:            baseCtx,
+		cancelBase:         cancelBase,
+		inFlight:           make(map[uuid.UUID]*models.ScoringRun),
 	}
 }
 
+// SetClock replaces the clock used for run timestamps and durations.
+// It must be called before any run is executed.
+func (p *Pipeline) SetClock(c clock.Clock) {
+	p.clock = c
+}
+
+// SetMaxConcurrentRuns limits how many dispatched runs execute at once
+// across Dispatch and DispatchBatch; excess runs wait, still "queued", for
+// a free slot. A non-positive n removes the limit. It must be called before
+// any run is dispatched.
+func (p *Pipeline) SetMaxConcurrentRuns(n int) {
+	if n <= 0 {
+		p.runSlots = nil
+		return
+	}
+	p.runSlots = make(chan struct{}, n)
+}
+
+// SetHeartbeatInterval sets how often a run executing on this instance has
+// its updated_at refreshed, marking it live to instances recovering orphans.
+// It should be well under the orphan age other instances use. A
+// non-positive interval disables the heartbeat. It must be called before
+// any run is dispatched.
+func (p *Pipeline) SetHeartbeatInterval(d time.Duration) {
+	p.heartbeat = d
+}
+
+// SetRecommendationCap limits how many recommendations a run may persist, so
+// an oversized upload cannot flood the recommendations table. A run scoring
+// more than max sites fails permanently with ErrTooManyRecommendations, or,
+// when truncate is set, succeeds persisting only its top max sites by score;
+// its scored count and stats still cover every site it scored.
+// A non-positive max removes the cap. It must be called before any run is
+// executed.
+func (p *Pipeline) SetRecommendationCap(max int, truncate bool) {
+	p.maxRecommendations = max
+	p.truncateRecommendations = truncate
+}
+
+// SetSkipTolerance sets the fraction (0 to 1) of a run's sites that may fail
+// to parse or score while the run still reports "succeeded". A run skipping
+// more ends as "completed_with_errors"; its results are stored either way.
+// The default of 0 flags any skipped site. It must be called before any run
+// is executed.
+func (p *Pipeline) SetSkipTolerance(fraction float64) {
+	p.skipTolerance = max(0, fraction)
+}
+
+// SetSkippedSiteStore stores the sites each run skips, with their reasons,
+// in store, up to max per run; the run itself keeps only the count and a
+// short sample. A nil store or non-positive max stores none. It must be
+// called before any run is executed.
+func (p *Pipeline) SetSkippedSiteStore(store SkippedSiteStore, max int) {
+	p.skippedSiteRepo = store
+	p.maxSkippedSites = max
+}
+
+// SetUploadStore looks up each run's upload in store so the run's result
+// key can be saved when it completes, making its results reusable by later
+// runs of the same content. It must be called before any run is executed.
+func (p *Pipeline) SetUploadStore(store UploadStore) {
+	p.uploadRepo = store
+}
+
+// SetDefaultLocale sets the language explanations are written in when
+// neither the schema nor the run's scoring_config sets a locale. The locale
+// used is recorded in the run's snapshot. An empty or unsupported locale
+// means i18n.DefaultLocale. It must be called before any run is executed.
+func (p *Pipeline) SetDefaultLocale(locale string) {
+	p.defaultLocale = locale
+}
+
+// Models returns the registry resolving run model versions. Models
+// registered on it are available to runs executed afterwards.
+func (p *Pipeline) Models() *ModelRegistry {
+	return p.models
+}
+
+// ScoreFuncs returns the registry resolving run scorers.
+func (p *Pipeline) ScoreFuncs() *ScoreFuncRegistry {
+	return p.scoreFuncs
+}
+
 // Execute performs the synchronous scoring pipeline execution.
 // Steps:
 // a. Updates run status to "running"
 // b. Resolves schema config (global + tenant)
-// c. Creates schema config snapshot
-// d. Fetches site records for the upload
+// c. Fetches and parses site records, deriving bounds if configured
+// d. Creates schema config snapshot
 // e. Scores each site using the ScoreFunc
 // f. Ranks results by final_score DESC
-// g. Bulk inserts recommendations
-// h. Updates run status to "succeeded" with duration_ms and scored_count
+// g. Replaces the run's recommendations, dropping any an earlier attempt stored
+// h. Updates run status to "succeeded" (or "completed_with_errors" when more
+// sites were skipped than the skip tolerance allows) with duration_ms,
+// scored_count and the result key of the schema it was scored with
 // On error: updates run status to "failed" with last_error
+//
+// With a run timeout configured, a run still going at the deadline is
+// aborted and fails with a permanent ErrRunTimeout.
 func (p *Pipeline) Execute(ctx context.Context, run *models.ScoringRun) error {
-	startTime := time.Now()
-	logger := slog.Default().With(
-		slog.String("service", "scoring-pipeline"),
-		slog.String("instance_id", run.InstanceID.String()),
-		slog.String("transaction_id", run.TransactionID.String()),
-		slog.String("tenant_id", run.TenantID.String()),
-		slog.String("run_id", run.ID.String()),
-	)
+	if p.runTimeout <= 0 {
+		return p.execute(ctx, run)
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, p.runTimeout)
+	defer cancel()
+
+	err := p.execute(runCtx, run)
+	if err == nil || runCtx.Err() != context.DeadlineExceeded || ctx.Err() != nil {
+		return err
+	}
+
+	// The run's own deadline fired (not the caller's), so the failure
+	// recorded under runCtx may not have been written. Record the timeout
+	// with the caller's context instead.
+	timeoutErr := permanent(fmt.Errorf("%w after %s", ErrRunTimeout, p.runTimeout))
+	return p.handleExecutionError(ctx, runLogger(run), run, timeoutErr)
+}
+
+// execute runs the pipeline steps listed on Execute under ctx.
+func (p *Pipeline) execute(ctx context.Context, run *models.ScoringRun) error {
+	startTime := p.clock.Now()
+	logger := runLogger(run)
 
 	// Step a: Update run status to "running"
 	stepLogger := logger.With(slog.String("step", "update_status_running"))
@@ -88,40 +330,55 @@ func (p *Pipeline) Execute(ctx context.Context, run *models.ScoringRun) error {
 	stepLogger = logger.With(slog.String("step", "resolve_schema_config"))
 	stepLogger.Info("resolving schema configuration")
 
-	globalConfig, err := p.schemaConfigRepo.GetGlobalActive(ctx)
+	eff, err := p.effectiveSchema(ctx, run)
 	if err != nil {
-		stepLogger.Error("failed to get global schema config", slog.String("error", err.Error()))
+		stepLogger.Error("failed to resolve schema", slog.String("error", err.Error()))
 		return p.handleExecutionError(ctx, logger, run, err)
 	}
+	globalConfig, resolvedSchema := eff.global, eff.schema
+	scorerName, scoreFunc := eff.scorerName, eff.scoreFunc
 
-	if globalConfig == nil {
-		err := fmt.Errorf("no active global schema configuration found")
-		stepLogger.Error("schema configuration missing", slog.String("error", err.Error()))
-		return p.handleExecutionError(ctx, logger, run, err)
-	}
+	// Key the results by the schema the run is scored with, before bounds
+	// are derived from the data its upload's content hash already covers
+	resultKey := p.runResultKey(ctx, run, eff, stepLogger)
 
-	tenantConfig, err := p.schemaConfigRepo.GetTenantActive(ctx, run.TenantID)
-	if err != nil {
-		stepLogger.Error("failed to get tenant schema config", slog.String("error", err.Error()))
-		return p.handleExecutionError(ctx, logger, run, err)
-	}
+	stepLogger.Info("schema resolved successfully",
+		slog.Int("field_count", len(resolvedSchema.Fields)))
 
-	// Resolve schema with tenant overrides via the resolver instance
-	var tenantConfigBytes json.RawMessage
-	if tenantConfig != nil {
-		tenantConfigBytes = tenantConfig.Config
-	}
+	// Step c: Fetch site records for the upload
+	stepLogger = logger.With(slog.String("step", "fetch_site_records"))
+	stepLogger.Info("fetching site records for upload")
 
-	resolvedSchema, err := p.schemaResolver.Resolve(ctx, globalConfig.Config, tenantConfigBytes)
+	siteRecords, err := p.siteRecordRepo.GetByUpload(ctx, run.UploadID)
 	if err != nil {
-		stepLogger.Error("failed to resolve schema", slog.String("error", err.Error()))
+		stepLogger.Error("failed to fetch site records", slog.String("error", err.Error()))
 		return p.handleExecutionError(ctx, logger, run, err)
 	}
 
-	stepLogger.Info("schema resolved successfully",
-		slog.Int("field_count", len(resolvedSchema.Fields)))
+	stepLogger.Info("site records fetched", slog.Int("count", len(siteRecords)))
 
-	// Step c: Create schema config snapshot
+	// Parse each site's data and derive computed fields
+	stepLogger = logger.With(slog.String("step", "parse_sites"))
+	skipped := &skippedSites{limit: max(models.MaxSkippedSites, p.maxSkippedSites)}
+	parsed := parseSites(siteRecords, resolvedSchema, skipped, stepLogger)
+
+	// Linear scoring normalizes against bounds: derive missing ones from the
+	// data when enabled, and warn about fields left on type defaults
+	if resolvedSchema.Scoring.Mode != schema.ModeRankSum {
+		if resolvedSchema.Scoring.DerivesBounds() {
+			data := make([]map[string]interface{}, len(parsed))
+			for i, site := range parsed {
+				data[i] = site.data
+			}
+			resolvedSchema.DerivedBounds = deriveBounds(data, resolvedSchema)
+			stepLogger.Info("derived normalization bounds from site data",
+				slog.Int("field_count", len(resolvedSchema.DerivedBounds)))
+		}
+		warnDefaultBounds(stepLogger, resolvedSchema)
+	}
+
+	// Step d: Create schema config snapshot (after bounds are derived so
+	// the snapshot records them)
 	stepLogger = logger.With(slog.String("step", "create_snapshot"))
 	stepLogger.Info("creating schema config snapshot")
 
@@ -131,7 +388,7 @@ func (p *Pipeline) Execute(ctx context.Context, run *models.ScoringRun) error {
 	snapshotData, err := json.Marshal(resolvedSchema)
 	if err != nil {
 		stepLogger.Error("failed to marshal snapshot data", slog.String("error", err.Error()))
-		return p.handleExecutionError(ctx, logger, run, err)
+		return p.handleExecutionError(ctx, logger, run, permanent(err))
 	}
 
 	snapshot := &models.SchemaConfigSnapshot{
@@ -141,7 +398,7 @@ func (p *Pipeline) Execute(ctx context.Context, run *models.ScoringRun) error {
 		UploadID:       &run.UploadID,
 		Config:         globalConfig.Config,
 		SnapshotData:   snapshotData,
-		CreatedAt:      time.Now(),
+		CreatedAt:      p.clock.Now(),
 	}
 
 	if err := p.schemaConfigRepo.CreateSnapshot(ctx, snapshot); err != nil {
@@ -154,21 +411,10 @@ func (p *Pipeline) Execute(ctx context.Context, run *models.ScoringRun) error {
 
 	stepLogger.Info("snapshot created", slog.String("snapshot_id", snapshotID.String()))
 
-	// Step d: Fetch site records for the upload
-	stepLogger = logger.With(slog.String("step", "fetch_site_records"))
-	stepLogger.Info("fetching site records for upload")
-
-	siteRecords, err := p.siteRecordRepo.GetByUpload(ctx, run.UploadID)
-	if err != nil {
-		stepLogger.Error("failed to fetch site records", slog.String("error", err.Error()))
-		return p.handleExecutionError(ctx, logger, run, err)
-	}
-
-	stepLogger.Info("site records fetched", slog.Int("count", len(siteRecords)))
-
 	if len(siteRecords) == 0 {
 		// Update run status to succeeded with 0 scored count
-		completeDuration := int(time.Since(startTime).Milliseconds())
+		p.storeResultKey(ctx, run, resultKey, logger)
+		completeDuration := int(p.clock.Now().Sub(startTime).Milliseconds())
 		if err := p.runRepo.UpdateStatus(ctx, run.ID, "succeeded", intPtr(0), nil, intPtr(completeDuration)); err != nil {
 			logger.Error("failed to update final status", slog.String("error", err.Error()))
 		}
@@ -179,118 +425,159 @@ func (p *Pipeline) Execute(ctx context.Context, run *models.ScoringRun) error {
 	stepLogger = logger.With(slog.String("step", "score_sites"))
 	stepLogger.Info("scoring sites")
 
-	recommendations := make([]models.Recommendation, 0, len(siteRecords))
-
-	for idx, siteRecord := range siteRecords {
-		// Parse site data from JSON
-		var siteData map[string]interface{}
-		if err := json.Unmarshal(siteRecord.Data, &siteData); err != nil {
-			stepLogger.Warn("failed to parse site data, skipping site",
-				slog.String("site_id", siteRecord.SiteID),
-				slog.String("error", err.Error()))
-			continue
-		}
-
-		// Score the site
-		rawScore, finalScore, explanation, err := p.scoreFunc(siteData, resolvedSchema)
-		if err != nil {
-			stepLogger.Warn("failed to score site, skipping",
-				slog.String("site_id", siteRecord.SiteID),
-				slog.String("error", err.Error()))
-			continue
+	scored := make([]scoredSite, 0, len(parsed))
+	addScored := func(result siteScore) {
+		site := result.site
+		// Build metadata with raw score info and any score interval
+		metadata := map[string]interface{}{
+			"raw_score":     result.rawScore,
+			"model_version": run.ModelVersion,
+			"scorer":        scorerName,
 		}
-
-		// Serialize explanation to JSON — stored in component_scores DB column
-		explanationJSON, err := json.Marshal(explanation)
-		if err != nil {
-			stepLogger.Warn("failed to marshal explanation, using empty",
-				slog.String("site_id", siteRecord.SiteID))
-			explanationJSON = []byte("{}")
+		if result.interval != nil {
+			metadata["score_low"] = result.interval.low
+			metadata["score_high"] = result.interval.high
 		}
-
-		// Build metadata with raw score info
-		metadataJSON, _ := json.Marshal(map[string]interface{}{
-			"raw_score":    rawScore,
-			"model_version": run.ModelVersion,
-		})
+		metadataJSON, _ := json.Marshal(metadata)
 
 		// Create recommendation (Ranking set to 0, will be assigned after sorting)
 		rec := models.Recommendation{
-			ID:              uuid.New(),
-			RunID:           run.ID,
-			TenantID:        run.TenantID,
-			SiteID:          siteRecord.SiteID,
-			SiteName:        siteRecord.SiteName,
-			Ranking:         0,
-			FinalScore:      finalScore,
-			RawScore:        rawScore,
-			ComponentScores: explanationJSON,
-			Metadata:        metadataJSON,
-			CreatedAt:       time.Now(),
+			ID:         uuid.New(),
+			RunID:      run.ID,
+			TenantID:   run.TenantID,
+			SiteID:     site.record.SiteID,
+			SiteName:   site.record.SiteName,
+			Ranking:    0,
+			FinalScore: result.finalScore,
+			RawScore:   result.rawScore,
+			Metadata:   metadataJSON,
+			CreatedAt:  p.clock.Now(),
 		}
 
-		recommendations = append(recommendations, rec)
+		scored = append(scored, scoredSite{rec: rec, explanation: result.explanation, data: site.data})
+	}
 
-		if (idx+1)%100 == 0 {
-			stepLogger.Info("scoring progress",
-				slog.Int("scored", idx+1),
-				slog.Int("total", len(siteRecords)))
-		}
+	results, err := scoreSites(ctx, parsed, resolvedSchema, scoreFunc, skipped, stepLogger)
+	if err != nil {
+		return p.handleExecutionError(ctx, logger, run, err)
+	}
+	for _, result := range results {
+		addScored(result)
 	}
 
 	stepLogger.Info("sites scored",
-		slog.Int("scored_count", len(recommendations)),
+		slog.Int("scored_count", len(scored)),
+		slog.Int("skipped_count", skipped.count),
 		slog.Int("total_count", len(siteRecords)))
 
-	// Sort recommendations by final_score DESC and assign rankings
-	sortRecommendationsByScore(recommendations)
-	for i := range recommendations {
-		recommendations[i].Ranking = i + 1
+	// Sort by final_score DESC (ties broken deterministically) and assign rankings
+	rankSites(scored, resolvedSchema)
+
+	// Enforce the recommendation cap before anything is serialized or stored
+	overCap := p.maxRecommendations > 0 && len(scored) > p.maxRecommendations
+	if overCap && !p.truncateRecommendations {
+		err := permanent(fmt.Errorf("%w: run scored %d sites, cap is %d",
+			ErrTooManyRecommendations, len(scored), p.maxRecommendations))
+		stepLogger.Error("recommendation cap exceeded", slog.String("error", err.Error()))
+		return p.handleExecutionError(ctx, logger, run, err)
 	}
 
-	// Step g: Bulk insert recommendations
+	// Record stats and the scored count over every scored site, then keep
+	// only the top sites the cap and store_top_n allow for storage
+	stats := computeRunStats(scoredRecommendations(scored))
+	scoredCount := len(scored)
+	if overCap {
+		stepLogger.Warn("truncating recommendations to cap",
+			slog.Int("scored_count", scoredCount),
+			slog.Int("cap", p.maxRecommendations))
+		scored = scored[:p.maxRecommendations]
+	}
+	if n := resolvedSchema.Scoring.StoreTopN; n != nil && *n < len(scored) {
+		stepLogger.Info("storing top recommendations only",
+			slog.Int("scored_count", scoredCount),
+			slog.Int("store_top_n", *n))
+		scored = scored[:*n]
+	}
+
+	recommendations := make([]models.Recommendation, len(scored))
+	for i, site := range scored {
+		// Serialize explanation to JSON — stored in component_scores DB column
+		explanationJSON, err := json.Marshal(site.explanation)
+		if err != nil {
+			stepLogger.Warn("failed to marshal explanation, using empty",
+				slog.String("site_id", site.rec.SiteID))
+			explanationJSON = []byte("{}")
+		}
+		site.rec.ComponentScores = explanationJSON
+		recommendations[i] = site.rec
+	}
+
+	// Step g: Replace the run's recommendations in one transaction
 	stepLogger = logger.With(slog.String("step", "bulk_insert_recommendations"))
 	stepLogger.Info("bulk inserting recommendations")
 
-	if err := p.recommendationRepo.BulkInsert(ctx, recommendations); err != nil {
+	if err := p.recommendationRepo.ReplaceByRun(ctx, run.ID, recommendations); err != nil {
 		stepLogger.Error("failed to bulk insert recommendations", slog.String("error", err.Error()))
 		return p.handleExecutionError(ctx, logger, run, err)
 	}
 
 	stepLogger.Info("recommendations inserted", slog.Int("count", len(recommendations)))
 
-	// Step h: Update run status to "succeeded"
+	// Record the score distribution. Recommendations are already persisted, so
+	// a failure here is logged rather than failing (and retrying) the run.
+	if err := p.runRepo.UpdateStats(ctx, run.ID, stats); err != nil {
+		stepLogger.Error("failed to store run stats", slog.String("error", err.Error()))
+	} else {
+		run.Stats = stats
+	}
+
+	// Record the sites that could not be scored, likewise best-effort
+	if skipped.count > 0 {
+		sample := skipped.sample()
+		if err := p.runRepo.UpdateSkipped(ctx, run.ID, skipped.count, sample); err != nil {
+			stepLogger.Error("failed to record skipped sites", slog.String("error", err.Error()))
+		} else {
+			run.SkippedCount = intPtr(skipped.count)
+			run.SkippedSites = sample
+		}
+		p.storeSkippedSites(ctx, run, skipped, stepLogger)
+	}
+
+	// Step h: Update run status to "succeeded", or "completed_with_errors"
+	// when too many sites were skipped
+	status := "succeeded"
+	if float64(skipped.count) > p.skipTolerance*float64(len(siteRecords)) {
+		status = "completed_with_errors"
+	}
 	stepLogger = logger.With(slog.String("step", "update_status_succeeded"))
-	stepLogger.Info("updating run status to succeeded")
+	stepLogger.Info("updating run status", slog.String("status", status))
 
-	completeDuration := int(time.Since(startTime).Milliseconds())
-	scoredCount := len(recommendations)
+	p.storeResultKey(ctx, run, resultKey, stepLogger)
+	completeDuration := int(p.clock.Now().Sub(startTime).Milliseconds())
 
-	if err := p.runRepo.UpdateStatus(ctx, run.ID, "succeeded", intPtr(scoredCount), nil, intPtr(completeDuration)); err != nil {
+	if err := p.runRepo.UpdateStatus(ctx, run.ID, status, intPtr(scoredCount), nil, intPtr(completeDuration)); err != nil {
 		stepLogger.Error("failed to update final status", slog.String("error", err.Error()))
 		return err
 	}
 
 	logger.Info("scoring pipeline completed successfully",
+		slog.String("status", status),
 		slog.Int("duration_ms", completeDuration),
-		slog.Int("scored_count", scoredCount))
+		slog.Int("scored_count", scoredCount),
+		slog.Int("skipped_count", skipped.count))
 
 	return nil
 }
 
 // ExecuteWithRetry wraps Execute with exponential backoff + jitter retry logic
 func (p *Pipeline) ExecuteWithRetry(ctx context.Context, run *models.ScoringRun) error {
-	logger := slog.Default().With(
-		slog.String("service", "scoring-pipeline"),
-		slog.String("instance_id", run.InstanceID.String()),
-		slog.String("transaction_id", run.TransactionID.String()),
-		slog.String("tenant_id", run.TenantID.String()),
-		slog.String("run_id", run.ID.String()),
-	)
+	logger := runLogger(run)
 
 	var lastErr error
+	attempts := 0
 
 	for attempt := 0; attempt <= p.maxRetries; attempt++ {
+		attempts = attempt + 1
 		logger.Info("executing scoring pipeline",
 			slog.Int("attempt", attempt+1),
 			slog.Int("max_retries", p.maxRetries))
@@ -312,6 +599,12 @@ func (p *Pipeline) ExecuteWithRetry(ctx context.Context, run *models.ScoringRun)
 			slog.String("error", err.Error()),
 			slog.Int("attempt", attempt+1))
 
+		// Permanent failures (bad configuration etc.) will not succeed on retry
+		if IsPermanent(err) {
+			logger.Warn("permanent error, not retrying")
+			break
+		}
+
 		// Don't retry if we've exhausted retries
 		if attempt >= p.maxRetries {
 			break
@@ -334,35 +627,59 @@ func (p *Pipeline) ExecuteWithRetry(ctx context.Context, run *models.ScoringRun)
 		}
 	}
 
-	// All retries exhausted
-	errorMsg := fmt.Sprintf("scoring pipeline failed after %d attempts: %v", p.maxRetries+1, lastErr)
+	// All retries exhausted (or a permanent error)
+	finalErr := fmt.Errorf("scoring pipeline failed after %d attempts: %w", attempts, lastErr)
+	errorMsg := finalErr.Error()
 	logger.Error("all retry attempts exhausted", slog.String("error", errorMsg))
 
 	if err := p.runRepo.UpdateStatus(ctx, run.ID, "failed", nil, stringPtr(errorMsg), nil); err != nil {
 		logger.Error("failed to update failed status", slog.String("error", err.Error()))
 	}
 
-	return fmt.Errorf("%s", errorMsg)
+	return finalErr
+}
+
+// runLogger returns the base logger for a run, carrying the identifiers needed
+// to trace pipeline output back to the originating request.
+func runLogger(run *models.ScoringRun) *slog.Logger {
+	logger := slog.Default().With(
+		slog.String("service", "scoring-pipeline"),
+		slog.String("instance_id", run.InstanceID.String()),
+		slog.String("transaction_id", run.TransactionID.String()),
+		slog.String("tenant_id", run.TenantID.String()),
+		slog.String("run_id", run.ID.String()),
+	)
+	if run.CorrelationID != "" {
+		logger = logger.With(slog.String("correlation_id", run.CorrelationID))
+	}
+	return logger
 }
 
 // calculateBackoff calculates exponential backoff with jitter
-// Formula: min(baseWait * 2^attempt + random jitter, maxWait)
+// Formula: clamp(baseWait * 2^attempt + random jitter, minBackoff, maxBackoff)
 func (p *Pipeline) calculateBackoff(attempt int) time.Duration {
-	// Exponential backoff: base * 2^attempt
-	exponentialMs := p.retryBaseWait.Milliseconds() * int64(math.Pow(2, float64(attempt)))
-
-	// Add jitter: random value between 0 and exponentialMs * 0.1
-	jitterMs := rand.Int63n(exponentialMs/10 + 1)
+	// Exponential backoff: base * 2^attempt, capped before it can overflow
+	exponential := maxBackoff
+	if attempt < 32 {
+		if scaled := p.retryBaseWait * time.Duration(int64(1)<<uint(attempt)); scaled > 0 && scaled < maxBackoff {
+			exponential = scaled
+		}
+	}
 
-	totalMs := exponentialMs + jitterMs
+	// Add jitter: random value between 0 and exponential * 0.1
+	p.rngMu.Lock()
+	jitter := time.Duration(p.rng.Int63n(int64(exponential/10) + 1))
+	p.rngMu.Unlock()
 
-	// Cap at reasonable maximum (5 minutes)
-	maxMs := int64(5 * 60 * 1000)
-	if totalMs > maxMs {
-		totalMs = maxMs
+	total := exponential + jitter
+	if total > maxBackoff {
+		total = maxBackoff
+	}
+	if total < minBackoff {
+		total = minBackoff
 	}
 
-	return time.Duration(totalMs) * time.Millisecond
+	return total
 }
 
 // handleExecutionError updates run status to "failed" and returns the error
@@ -383,15 +700,343 @@ func (p *Pipeline) handleExecutionError(
 	return err
 }
 
-// sortRecommendationsByScore sorts recommendations by final_score in descending order
-func sortRecommendationsByScore(recommendations []models.Recommendation) {
-	for i := 0; i < len(recommendations); i++ {
-		for j := i + 1; j < len(recommendations); j++ {
-			if recommendations[j].FinalScore > recommendations[i].FinalScore {
-				recommendations[i], recommendations[j] = recommendations[j], recommendations[i]
-			}
+// effective is the schema and scorer a run is scored with.
+type effective struct {
+	global     *models.SchemaConfig
+	schema     *schema.ResolvedSchema
+	scorerName string
+	scoreFunc  ScoreFunc
+}
+
+// effectiveSchema resolves the tenant's active schema and layers the run's
+// model version defaults, then run-level options (e.g. tie-breaking) from
+// its scoring_config, on top. Errors that retrying cannot fix are permanent.
+func (p *Pipeline) effectiveSchema(ctx context.Context, run *models.ScoringRun) (*effective, error) {
+	resolution, err := p.schemaResolver.ResolveActive(ctx, p.schemaConfigRepo, run.TenantID)
+	var resolveErr *schema.ResolveError
+	switch {
+	case errors.Is(err, schema.ErrNoGlobalConfig), errors.As(err, &resolveErr):
+		return nil, permanent(err)
+	case err != nil:
+		return nil, err
+	}
+	resolvedSchema := resolution.Schema
+
+	model, err := p.models.Lookup(run.ModelVersion)
+	if err != nil {
+		return nil, permanent(err)
+	}
+	if err := resolvedSchema.ApplyModelDefaults(model.Options, model.Weights); err != nil {
+		return nil, permanent(fmt.Errorf("invalid model defaults: %w", err))
+	}
+	scorerName, scoreFunc, err := p.resolveScorer(run, model)
+	if err != nil {
+		return nil, permanent(err)
+	}
+	if err := resolvedSchema.ApplyRunConfig(run.ScoringConfig); err != nil {
+		return nil, permanent(fmt.Errorf("invalid run scoring config: %w", err))
+	}
+	// Pin the explanation language so reranks and verification reuse it
+	if resolvedSchema.Scoring.Locale == "" {
+		resolvedSchema.Scoring.Locale = i18n.Lookup(p.defaultLocale).Locale
+	}
+	// Age data from the day the run is scored, recorded in its snapshot
+	if f := resolvedSchema.Scoring.Freshness; f != nil {
+		f.Pin(p.clock.Now())
+	}
+
+	return &effective{
+		global:     resolution.Global,
+		schema:     resolvedSchema,
+		scorerName: scorerName,
+		scoreFunc:  scoreFunc,
+	}, nil
+}
+
+// ResultKey identifies the results run would produce if executed now
+// against an upload with content hash contentHash. Two runs of the same
+// upload with equal keys score identical data with an identical effective
+// schema, model and scorer, so the earlier one's recommendations can stand
+// in for the later one's. A run's own key is computed and saved when it
+// executes; this is for looking up a run to reuse before creating one.
+func (p *Pipeline) ResultKey(ctx context.Context, run *models.ScoringRun, contentHash string) (string, error) {
+	eff, err := p.effectiveSchema(ctx, run)
+	if err != nil {
+		return "", err
+	}
+	return p.resultKey(run, eff, contentHash)
+}
+
+// resultKey computes ResultKey from the run's effective schema eff.
+func (p *Pipeline) resultKey(run *models.ScoringRun, eff *effective, contentHash string) (string, error) {
+	keyData, err := json.Marshal(struct {
+		ContentHash        string                 `json:"content_hash"`
+		ModelVersion       string                 `json:"model_version"`
+		Scorer             string                 `json:"scorer"`
+		Schema             *schema.ResolvedSchema `json:"schema"`
+		MaxRecommendations int                    `json:"max_recommendations"`
+		Truncate           bool                   `json:"truncate"`
+	}{contentHash, run.ModelVersion, eff.scorerName, eff.schema, p.maxRecommendations, p.truncateRecommendations})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(keyData)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// runResultKey computes the result key of run, scored with eff, from its
+// upload's content hash. It returns nil, leaving the run's results
+// unreusable, when no upload store is set, the upload has no content hash
+// or the key cannot be computed.
+func (p *Pipeline) runResultKey(ctx context.Context, run *models.ScoringRun, eff *effective, logger *slog.Logger) *string {
+	if p.uploadRepo == nil {
+		return nil
+	}
+	upload, err := p.uploadRepo.GetByID(ctx, run.TenantID, run.UploadID)
+	if err != nil {
+		logger.Warn("failed to look up upload for result key", slog.String("error", err.Error()))
+		return nil
+	}
+	if upload == nil || upload.ContentHash == nil || *upload.ContentHash == "" {
+		return nil
+	}
+	key, err := p.resultKey(run, eff, *upload.ContentHash)
+	if err != nil {
+		logger.Warn("failed to compute result key", slog.String("error", err.Error()))
+		return nil
+	}
+	return &key
+}
+
+// storeResultKey saves key as the run's result key, replacing any earlier
+// one. Like stats, a failure is logged rather than failing the run; the
+// run's results are then just not reused.
+func (p *Pipeline) storeResultKey(ctx context.Context, run *models.ScoringRun, key *string, logger *slog.Logger) {
+	if err := p.runRepo.UpdateResultKey(ctx, run.ID, key); err != nil {
+		logger.Error("failed to store result key", slog.String("error", err.Error()))
+		return
+	}
+	run.ResultKey = key
+}
+
+// resolveScorer picks the scorer for a run: the one its scoring_config names,
+// else its model's, else DefaultScorer.
+func (p *Pipeline) resolveScorer(run *models.ScoringRun, model Model) (string, ScoreFunc, error) {
+	name := model.Scorer
+	if requested := requestedScorer(run.ScoringConfig); requested != "" {
+		name = requested
+	}
+	if name == "" {
+		name = DefaultScorer
+	}
+	scoreFunc, err := p.scoreFuncs.Lookup(name)
+	if err != nil {
+		return "", nil, err
+	}
+	return name, scoreFunc, nil
+}
+
+// parsedSite is a site record with its data decoded and computed fields
+// derived.
+type parsedSite struct {
+	record   models.SiteRecord
+	position int // 1-based, among the upload's site records
+	data     map[string]interface{}
+}
+
+// storeSkippedSites writes up to maxSkippedSites of the run's skipped sites
+// to the skipped site store, if one is set. A failure is logged rather than
+// failing the run, whose results are already persisted.
+func (p *Pipeline) storeSkippedSites(ctx context.Context, run *models.ScoringRun, skipped *skippedSites, logger *slog.Logger) {
+	if p.skippedSiteRepo == nil || p.maxSkippedSites <= 0 {
+		return
+	}
+	sites := skipped.sites[:min(len(skipped.sites), p.maxSkippedSites)]
+	now := p.clock.Now()
+	for i := range sites {
+		sites[i].RunID = run.ID
+		sites[i].TenantID = run.TenantID
+		sites[i].CreatedAt = now
+	}
+	if err := p.skippedSiteRepo.BulkInsert(ctx, sites); err != nil {
+		logger.Error("failed to store skipped sites", slog.String("error", err.Error()))
+		return
+	}
+	if len(sites) < skipped.count {
+		logger.Warn("stored skipped sites up to cap",
+			slog.Int("skipped_count", skipped.count),
+			slog.Int("cap", p.maxSkippedSites))
+	}
+}
+
+// skippedSites collects the sites a run could not parse or score: how many,
+// and the first limit of them with reasons. Methods on a nil *skippedSites
+// discard everything.
+type skippedSites struct {
+	count int
+	limit int
+	sites []models.RunSkippedSite
+}
+
+func (s *skippedSites) add(site parsedSite, reason string) {
+	if s == nil {
+		return
+	}
+	s.count++
+	if len(s.sites) < s.limit {
+		s.sites = append(s.sites, models.RunSkippedSite{
+			Position: site.position,
+			SiteID:   site.record.SiteID,
+			SiteName: site.record.SiteName,
+			Reason:   reason,
+		})
+	}
+}
+
+// sample returns the first models.MaxSkippedSites skipped sites, as kept on
+// the run itself.
+func (s *skippedSites) sample() []models.SkippedSite {
+	sample := make([]models.SkippedSite, 0, min(len(s.sites), models.MaxSkippedSites))
+	for _, site := range s.sites[:cap(sample)] {
+		sample = append(sample, models.SkippedSite{SiteID: site.SiteID, Reason: site.Reason})
+	}
+	return sample
+}
+
+// parseSites decodes each record's data and derives computed fields. Records
+// whose data cannot be decoded are skipped and added to skipped, which may be
+// nil; computed fields that fail are skipped without skipping the site.
+func parseSites(siteRecords []models.SiteRecord, resolvedSchema *schema.ResolvedSchema, skipped *skippedSites, logger *slog.Logger) []parsedSite {
+	parsed := make([]parsedSite, 0, len(siteRecords))
+
+	for i, siteRecord := range siteRecords {
+		// Parse site data from JSON
+		var siteData map[string]interface{}
+		if err := json.Unmarshal(siteRecord.Data, &siteData); err != nil {
+			logger.Warn("failed to parse site data, skipping site",
+				slog.String("site_id", siteRecord.SiteID),
+				slog.String("error", err.Error()))
+			skipped.add(parsedSite{record: siteRecord, position: i + 1}, "invalid site data: "+err.Error())
+			continue
 		}
+
+		// Derive computed fields; any that fail are skipped, not fatal
+		for _, computeErr := range applyComputedFields(siteData, resolvedSchema) {
+			logger.Warn("failed to compute derived field, skipping field",
+				slog.String("site_id", siteRecord.SiteID),
+				slog.String("error", computeErr.Error()))
+		}
+
+		parsed = append(parsed, parsedSite{record: siteRecord, position: i + 1, data: siteData})
+	}
+
+	return parsed
+}
+
+// siteScore is the outcome of scoring one parsed site.
+type siteScore struct {
+	site        parsedSite
+	rawScore    float64
+	finalScore  float64
+	explanation models.Explanation
+	interval    *scoreInterval // nil unless the schema has uncertainty columns
+}
+
+// scoreSites scores every parsed site: in one pass for rank-sum mode,
+// otherwise one at a time with scoreFunc, skipping sites it rejects and
+// adding them to skipped, which may be nil. Scores
+// are rounded to the schema's precision before they are returned, so sites
+// that tie once rounded are ordered by the tie-breaker. It stops with ctx's
+// error if ctx is done; rank-sum failures are permanent. Time spent here is
+// recorded as scoring time on any timing.Recorder in ctx.
+func scoreSites(
+	ctx context.Context,
+	parsed []parsedSite,
+	resolvedSchema *schema.ResolvedSchema,
+	scoreFunc ScoreFunc,
+	skipped *skippedSites,
+	logger *slog.Logger,
+) ([]siteScore, error) {
+	defer timing.Track(ctx, timing.SpanScoring)()
+
+	results := make([]siteScore, 0, len(parsed))
+
+	if resolvedSchema.Scoring.Mode == schema.ModeRankSum {
+		// Rank-sum scores each site relative to the whole set in one pass
+		if err := ctx.Err(); err != nil {
+			logger.Warn("scoring cancelled", slog.String("error", err.Error()))
+			return nil, err
+		}
+
+		batch := make([]map[string]interface{}, len(parsed))
+		for i, site := range parsed {
+			batch[i] = site.data
+		}
+		rankSum, err := RankSumScore(batch, resolvedSchema)
+		if err != nil {
+			logger.Error("rank-sum scoring failed", slog.String("error", err.Error()))
+			return nil, permanent(err)
+		}
+		for i, site := range parsed {
+			results = append(results, siteScore{
+				site:        site,
+				rawScore:    rankSum[i].RawScore,
+				finalScore:  rankSum[i].FinalScore,
+				explanation: rankSum[i].Explanation,
+			})
+		}
+		applyFreshness(results, resolvedSchema)
+		roundResults(results, resolvedSchema.Scoring.ScorePrecision())
+		return results, nil
+	}
+
+	for idx, site := range parsed {
+		// Abort promptly if the run was cancelled (e.g. during shutdown)
+		if err := ctx.Err(); err != nil {
+			logger.Warn("scoring cancelled", slog.String("error", err.Error()))
+			return nil, err
+		}
+
+		// Score the site
+		rawScore, finalScore, explanation, err := scoreFunc(site.data, resolvedSchema)
+		if err != nil {
+			logger.Warn("failed to score site, skipping",
+				slog.String("site_id", site.record.SiteID),
+				slog.String("error", err.Error()))
+			skipped.add(site, err.Error())
+			continue
+		}
+
+		results = append(results, siteScore{
+			site:        site,
+			rawScore:    rawScore,
+			finalScore:  finalScore,
+			explanation: explanation,
+			interval:    intervalFor(site.data, resolvedSchema, scoreFunc, finalScore),
+		})
+
+		if (idx+1)%100 == 0 {
+			logger.Info("scoring progress",
+				slog.Int("scored", idx+1),
+				slog.Int("total", len(parsed)))
+		}
+	}
+
+	applyFreshness(results, resolvedSchema)
+	roundResults(results, resolvedSchema.Scoring.ScorePrecision())
+	return results, nil
+}
+
+// requestedScorer returns the "scorer" named in a run's scoring_config, or
+// "" when the config is empty or names none.
+func requestedScorer(runConfig json.RawMessage) string {
+	var cfg struct {
+		Scorer string `json:"scorer"`
+	}
+	if len(runConfig) > 0 {
+		_ = json.Unmarshal(runConfig, &cfg)
 	}
+	return cfg.Scorer
 }
 
 // Helper functions for pointer creation
@@ -0,0 +1,1419 @@
+package scoring
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
+	"github.com/workforce-ai/site-selection-iq/pkg/clock"
+)
+
+// ---------------------------------------------------------------------------
+// In-memory fakes for the pipeline's store interfaces
+// ---------------------------------------------------------------------------
+
+type fakeRunStore struct {
+	mu          sync.Mutex
+	statuses    []string
+	errors      []string
+	lastError   *string
+	scoredCount *int
+	durationMs  *int
+	attempts    int
+	stats       *models.RunStats
+	skipped     []models.SkippedSite
+	skipCount   int
+	resultKey   *string
+
+	stale    []models.ScoringRun
+	claimed  []uuid.UUID
+	finished []uuid.UUID // runs FailOrphan finds already terminal
+	beats    int         // Heartbeat calls
+
+	queue     []*models.ScoringRun // waiting for ClaimNext
+	dequeued  []uuid.UUID          // runs handed out by ClaimNext, in order
+	claimErrs int                  // ClaimNext calls to fail before succeeding
+}
+
+func (f *fakeRunStore) UpdateStatus(_ context.Context, _ uuid.UUID, status string, scoredCount *int, lastError *string, durationMs *int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.statuses = append(f.statuses, status)
+	if scoredCount != nil {
+		f.scoredCount = scoredCount
+	}
+	if lastError != nil {
+		f.lastError = lastError
+		f.errors = append(f.errors, *lastError)
+	}
+	if durationMs != nil {
+		f.durationMs = durationMs
+	}
+	return nil
+}
+
+func (f *fakeRunStore) IncrementAttempt(_ context.Context, _ uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.attempts++
+	return nil
+}
+
+func (f *fakeRunStore) UpdateStats(_ context.Context, _ uuid.UUID, stats *models.RunStats) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stats = stats
+	return nil
+}
+
+func (f *fakeRunStore) UpdateSkipped(_ context.Context, _ uuid.UUID, skippedCount int, sample []models.SkippedSite) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.skipCount = skippedCount
+	f.skipped = sample
+	return nil
+}
+
+func (f *fakeRunStore) UpdateResultKey(_ context.Context, _ uuid.UUID, resultKey *string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.resultKey = resultKey
+	return nil
+}
+
+func (f *fakeRunStore) ListStale(_ context.Context, _ time.Duration, excludeInstance uuid.UUID) ([]models.ScoringRun, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var runs []models.ScoringRun
+	for _, run := range f.stale {
+		if run.InstanceID != excludeInstance {
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
+
+func (f *fakeRunStore) ClaimOrphan(_ context.Context, runID, _, _ uuid.UUID) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, id := range f.claimed {
+		if id == runID {
+			return false, nil
+		}
+	}
+	f.claimed = append(f.claimed, runID)
+	return true, nil
+}
+
+func (f *fakeRunStore) FailOrphan(_ context.Context, runID, _ uuid.UUID, reason string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, id := range append(f.claimed, f.finished...) {
+		if id == runID {
+			return false, nil
+		}
+	}
+	f.finished = append(f.finished, runID)
+	f.statuses = append(f.statuses, "failed")
+	f.lastError = &reason
+	f.errors = append(f.errors, reason)
+	return true, nil
+}
+
+func (f *fakeRunStore) Heartbeat(_ context.Context, _, _ uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.beats++
+	return nil
+}
+
+func (f *fakeRunStore) ClaimNext(_ context.Context, instanceID uuid.UUID) (*models.ScoringRun, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.claimErrs > 0 {
+		f.claimErrs--
+		return nil, errors.New("connection reset")
+	}
+	if len(f.queue) == 0 {
+		return nil, nil
+	}
+	run := f.queue[0]
+	f.queue = f.queue[1:]
+	run.Status = "running"
+	run.InstanceID = instanceID
+	f.dequeued = append(f.dequeued, run.ID)
+	return run, nil
+}
+
+// enqueue adds runs to the queue ClaimNext serves.
+func (f *fakeRunStore) enqueue(runs ...*models.ScoringRun) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.queue = append(f.queue, runs...)
+}
+
+func (f *fakeRunStore) recordedErrors() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.errors...)
+}
+
+func (f *fakeRunStore) lastStatus() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.statuses) == 0 {
+		return ""
+	}
+	return f.statuses[len(f.statuses)-1]
+}
+
+type fakeSiteRecordStore struct {
+	records []models.SiteRecord
+	err     error
+}
+
+func (f *fakeSiteRecordStore) GetByUpload(_ context.Context, _ uuid.UUID) ([]models.SiteRecord, error) {
+	return f.records, f.err
+}
+
+type fakeRecommendationStore struct {
+	mu       sync.Mutex
+	inserted []models.Recommendation
+}
+
+func (f *fakeRecommendationStore) ReplaceByRun(_ context.Context, runID uuid.UUID, recs []models.Recommendation) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	kept := f.inserted[:0]
+	for _, rec := range f.inserted {
+		if rec.RunID != runID {
+			kept = append(kept, rec)
+		}
+	}
+	f.inserted = append(kept, recs...)
+	return nil
+}
+
+type fakeSkippedSiteStore struct {
+	mu       sync.Mutex
+	inserted []models.RunSkippedSite
+}
+
+func (f *fakeSkippedSiteStore) BulkInsert(_ context.Context, sites []models.RunSkippedSite) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.inserted = append(f.inserted, sites...)
+	return nil
+}
+
+type fakeSchemaConfigStore struct {
+	mu        sync.Mutex
+	global    *models.SchemaConfig
+	tenant    *models.SchemaConfig
+	snapshots []*models.SchemaConfigSnapshot
+}
+
+func (f *fakeSchemaConfigStore) GetGlobalActive(_ context.Context) (*models.SchemaConfig, error) {
+	return f.global, nil
+}
+
+func (f *fakeSchemaConfigStore) GetTenantActive(_ context.Context, _ uuid.UUID) (*models.SchemaConfig, error) {
+	return f.tenant, nil
+}
+
+func (f *fakeSchemaConfigStore) GetActiveVersions(_ context.Context, _ uuid.UUID) (schema.ConfigVersions, error) {
+	var v schema.ConfigVersions
+	if f.global != nil {
+		v.GlobalID, v.GlobalUpdatedAt = f.global.ID, f.global.UpdatedAt
+	}
+	if f.tenant != nil {
+		v.TenantID, v.TenantUpdatedAt = f.tenant.ID, f.tenant.UpdatedAt
+	}
+	return v, nil
+}
+
+func (f *fakeSchemaConfigStore) CreateSnapshot(_ context.Context, snapshot *models.SchemaConfigSnapshot) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.snapshots = append(f.snapshots, snapshot)
+	return nil
+}
+
+// testGlobalConfig is a minimal two-field global schema used by pipeline tests.
+const testGlobalConfig = `{
+	"site_id_column": "site_id",
+	"fields": {
+		"site_id": {"type": "identifier", "required": true},
+		"population": {"type": "population", "min": 0, "max": 1000, "weight": 1.0, "direction": "maximize"},
+		"unemployment": {"type": "percentage", "min": 0, "max": 100, "weight": 1.0, "direction": "minimize"}
+	}
+}`
+
+// fakeUploadStore serves every run's upload with contentHash.
+type fakeUploadStore struct {
+	contentHash string
+}
+
+func (f *fakeUploadStore) GetByID(_ context.Context, tenantID, uploadID uuid.UUID) (*models.Upload, error) {
+	return &models.Upload{ID: uploadID, TenantID: tenantID, ContentHash: &f.contentHash}, nil
+}
+
+type pipelineFakes struct {
+	runs    *fakeRunStore
+	sites   *fakeSiteRecordStore
+	recs    *fakeRecommendationStore
+	configs *fakeSchemaConfigStore
+}
+
+func newTestPipeline(records []models.SiteRecord) (*Pipeline, *pipelineFakes) {
+	fakes := &pipelineFakes{
+		runs:  &fakeRunStore{},
+		sites: &fakeSiteRecordStore{records: records},
+		recs:  &fakeRecommendationStore{},
+		configs: &fakeSchemaConfigStore{
+			global: &models.SchemaConfig{ID: uuid.New(), Version: "v1.0", Config: json.RawMessage(testGlobalConfig)},
+		},
+	}
+	p := NewPipeline(fakes.runs, fakes.sites, fakes.recs, fakes.configs, schema.NewResolver(), nil, 0, time.Millisecond, 0)
+	return p, fakes
+}
+
+// setDefaultScorer replaces the pipeline's default scorer with fn.
+func setDefaultScorer(t *testing.T, p *Pipeline, fn ScoreFunc) {
+	t.Helper()
+	require.NoError(t, p.ScoreFuncs().Register(DefaultScorer, fn))
+}
+
+func testSiteRecord(siteID string, population, unemployment float64) models.SiteRecord {
+	data, _ := json.Marshal(map[string]interface{}{
+		"site_id":      siteID,
+		"population":   population,
+		"unemployment": unemployment,
+	})
+	return models.SiteRecord{ID: uuid.New(), SiteID: siteID, SiteName: siteID, Data: data}
+}
+
+func testRun() *models.ScoringRun {
+	return &models.ScoringRun{
+		ID:            uuid.New(),
+		UploadID:      uuid.New(),
+		TenantID:      uuid.New(),
+		Status:        "queued",
+		ModelVersion:  "site-selection-iq-v1.0",
+		InstanceID:    uuid.New(),
+		TransactionID: uuid.New(),
+	}
+}
+
+// captureLogs swaps the default slog logger for one writing JSON to a buffer
+// and restores the original when the test completes.
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	original := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	t.Cleanup(func() { slog.SetDefault(original) })
+	return &buf
+}
+
+// decodeLogLines parses each JSON log line written to buf.
+func decodeLogLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var lines []map[string]interface{}
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal(line, &entry), "log line should be valid JSON: %s", line)
+		lines = append(lines, entry)
+	}
+	return lines
+}
+
+// ---------------------------------------------------------------------------
+// Correlation ID propagation
+// ---------------------------------------------------------------------------
+
+func TestPipelineExecute_LogsCarryCorrelationID(t *testing.T) {
+	buf := captureLogs(t)
+
+	p, fakes := newTestPipeline([]models.SiteRecord{
+		testSiteRecord("A", 800, 5),
+		testSiteRecord("B", 200, 40),
+	})
+	run := testRun()
+	run.CorrelationID = "req-1234"
+
+	require.NoError(t, p.Execute(context.Background(), run))
+	assert.Equal(t, "succeeded", fakes.runs.lastStatus())
+
+	lines := decodeLogLines(t, buf)
+	require.NotEmpty(t, lines, "pipeline should emit logs")
+	for _, entry := range lines {
+		assert.Equal(t, "req-1234", entry["correlation_id"],
+			"every pipeline log line should carry the correlation ID: %v", entry["msg"])
+	}
+}
+
+func TestPipelineExecute_NoCorrelationIDOmitsAttribute(t *testing.T) {
+	buf := captureLogs(t)
+
+	p, _ := newTestPipeline([]models.SiteRecord{testSiteRecord("A", 800, 5)})
+	require.NoError(t, p.Execute(context.Background(), testRun()))
+
+	for _, entry := range decodeLogLines(t, buf) {
+		_, ok := entry["correlation_id"]
+		assert.False(t, ok, fmt.Sprintf("unexpected correlation_id on %v", entry["msg"]))
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Dispatch, shutdown, and crash recovery
+// ---------------------------------------------------------------------------
+
+// blockingScoreFunc returns a ScoreFunc that waits for release to be closed
+// before delegating to DefaultScoreFunc, signalling started on first call.
+func blockingScoreFunc(started chan<- struct{}, release <-chan struct{}) ScoreFunc {
+	var once sync.Once
+	return func(siteData map[string]interface{}, resolved *schema.ResolvedSchema) (float64, float64, models.Explanation, error) {
+		once.Do(func() { close(started) })
+		<-release
+		return DefaultScoreFunc(siteData, resolved)
+	}
+}
+
+func TestPipelineShutdown_WaitsForInFlightRuns(t *testing.T) {
+	p, fakes := newTestPipeline([]models.SiteRecord{testSiteRecord("A", 800, 5)})
+	started := make(chan struct{})
+	release := make(chan struct{})
+	setDefaultScorer(t, p, blockingScoreFunc(started, release))
+
+	p.Dispatch(testRun())
+	<-started
+	assert.Equal(t, 1, p.InFlight())
+
+	// Let the run finish shortly after shutdown starts waiting
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, p.Shutdown(ctx))
+
+	assert.Equal(t, 0, p.InFlight())
+	assert.Equal(t, "succeeded", fakes.runs.lastStatus())
+}
+
+func TestPipelineDispatchBatch_RespectsConcurrencyLimit(t *testing.T) {
+	p, fakes := newTestPipeline([]models.SiteRecord{testSiteRecord("A", 800, 5)})
+
+	var mu sync.Mutex
+	active, peak := 0, 0
+	setDefaultScorer(t, p, func(siteData map[string]interface{}, resolved *schema.ResolvedSchema) (float64, float64, models.Explanation, error) {
+		mu.Lock()
+		active++
+		peak = max(peak, active)
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		active--
+		mu.Unlock()
+		return DefaultScoreFunc(siteData, resolved)
+	})
+
+	runs := make([]*models.ScoringRun, 6)
+	for i := range runs {
+		runs[i] = testRun()
+	}
+	p.DispatchBatch(runs, 2)
+	assert.Equal(t, len(runs), p.InFlight())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, p.Shutdown(ctx))
+
+	assert.Equal(t, 0, p.InFlight())
+	assert.LessOrEqual(t, peak, 2)
+	assert.Len(t, fakes.recs.inserted, len(runs))
+}
+
+func TestPipelineDispatch_MaxConcurrentRunsQueuesExcess(t *testing.T) {
+	p, fakes := newTestPipeline([]models.SiteRecord{testSiteRecord("A", 800, 5)})
+	p.SetMaxConcurrentRuns(1)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	setDefaultScorer(t, p, blockingScoreFunc(started, release))
+
+	p.Dispatch(testRun())
+	<-started
+	p.Dispatch(testRun())
+
+	// The second run waits for the first's slot without starting
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, 2, p.InFlight())
+	fakes.runs.mu.Lock()
+	assert.Equal(t, 1, fakes.runs.attempts, "second run has not started")
+	assert.Equal(t, []string{"running"}, fakes.runs.statuses, "second run is still queued")
+	fakes.runs.mu.Unlock()
+
+	close(release)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, p.Shutdown(ctx))
+
+	assert.Equal(t, 2, fakes.runs.attempts)
+	assert.Equal(t, []string{"running", "succeeded", "running", "succeeded"}, fakes.runs.statuses)
+	assert.Len(t, fakes.recs.inserted, 2)
+}
+
+func TestExecuteWithRetry_RecommendationCap(t *testing.T) {
+	records := []models.SiteRecord{
+		testSiteRecord("A", 800, 5),
+		testSiteRecord("B", 200, 40),
+		testSiteRecord("C", 500, 20),
+		testSiteRecord("D", 100, 60),
+	}
+
+	t.Run("fails the run without inserting", func(t *testing.T) {
+		p, fakes := newTestPipeline(records)
+		p.maxRetries = 3
+		p.SetRecommendationCap(3, false)
+
+		err := p.ExecuteWithRetry(context.Background(), testRun())
+
+		require.ErrorIs(t, err, ErrTooManyRecommendations)
+		assert.True(t, IsPermanent(err))
+		assert.Equal(t, 1, fakes.runs.attempts, "an over-cap run is not retried")
+		assert.Equal(t, "failed", fakes.runs.lastStatus())
+		assert.Contains(t, *fakes.runs.lastError, "run scored 4 sites, cap is 3")
+		assert.Empty(t, fakes.recs.inserted)
+	})
+
+	t.Run("truncates to the top sites", func(t *testing.T) {
+		p, fakes := newTestPipeline(records)
+		p.SetRecommendationCap(2, true)
+
+		require.NoError(t, p.ExecuteWithRetry(context.Background(), testRun()))
+
+		assert.Equal(t, "succeeded", fakes.runs.lastStatus())
+		require.Len(t, fakes.recs.inserted, 2)
+		assert.Equal(t, "A", fakes.recs.inserted[0].SiteID)
+		assert.Equal(t, 1, fakes.recs.inserted[0].Ranking)
+		assert.Equal(t, "C", fakes.recs.inserted[1].SiteID)
+		assert.Equal(t, 2, fakes.recs.inserted[1].Ranking)
+		require.NotNil(t, fakes.runs.scoredCount)
+		assert.Equal(t, len(records), *fakes.runs.scoredCount, "scored_count covers every scored site")
+		require.NotNil(t, fakes.runs.stats)
+		assert.Less(t, fakes.runs.stats.Min, fakes.recs.inserted[1].FinalScore, "stats cover the truncated sites")
+	})
+
+	t.Run("at the cap is allowed", func(t *testing.T) {
+		p, fakes := newTestPipeline(records)
+		p.SetRecommendationCap(len(records), false)
+
+		require.NoError(t, p.ExecuteWithRetry(context.Background(), testRun()))
+		assert.Len(t, fakes.recs.inserted, len(records))
+	})
+}
+
+func TestPipelineExecute_RerunReplacesRecommendations(t *testing.T) {
+	p, fakes := newTestPipeline([]models.SiteRecord{
+		testSiteRecord("A", 800, 5),
+		testSiteRecord("B", 200, 40),
+	})
+	run := testRun()
+
+	// A requeued run executes again; its first results must not be kept twice
+	require.NoError(t, p.Execute(context.Background(), run))
+	require.NoError(t, p.Execute(context.Background(), run))
+
+	require.Len(t, fakes.recs.inserted, 2)
+	assert.Equal(t, 1, fakes.recs.inserted[0].Ranking)
+	assert.Equal(t, 2, fakes.recs.inserted[1].Ranking)
+}
+
+func TestPipelineExecute_StoreTopN(t *testing.T) {
+	p, fakes := newTestPipeline([]models.SiteRecord{
+		testSiteRecord("A", 800, 5),
+		testSiteRecord("B", 200, 40),
+		testSiteRecord("C", 500, 20),
+		testSiteRecord("D", 100, 60),
+	})
+	run := testRun()
+	run.ScoringConfig = json.RawMessage(`{"store_top_n": 2}`)
+
+	require.NoError(t, p.Execute(context.Background(), run))
+
+	assert.Equal(t, "succeeded", fakes.runs.lastStatus())
+	require.Len(t, fakes.recs.inserted, 2, "only the top N recommendations are stored")
+	assert.Equal(t, "A", fakes.recs.inserted[0].SiteID)
+	assert.Equal(t, "C", fakes.recs.inserted[1].SiteID)
+	require.NotNil(t, fakes.runs.scoredCount)
+	assert.Equal(t, 4, *fakes.runs.scoredCount, "the run records every scored site")
+	require.NotNil(t, run.Stats)
+	assert.Less(t, run.Stats.Min, fakes.recs.inserted[1].FinalScore, "stats cover unstored sites too")
+}
+
+func TestPipelineExecute_RecordsSkippedSites(t *testing.T) {
+	bad := testSiteRecord("BAD", 0, 0)
+	bad.Data = json.RawMessage(`not json`)
+	p, fakes := newTestPipeline([]models.SiteRecord{
+		testSiteRecord("A", 800, 5),
+		bad,
+		testSiteRecord("B", 200, 40),
+		testSiteRecord("NEG", -1, 10),
+		testSiteRecord("C", 500, 20),
+	})
+	setDefaultScorer(t, p, func(siteData map[string]interface{}, resolved *schema.ResolvedSchema) (float64, float64, models.Explanation, error) {
+		if siteData["population"].(float64) < 0 {
+			return 0, 0, models.Explanation{}, errors.New("population must not be negative")
+		}
+		return DefaultScoreFunc(siteData, resolved)
+	})
+
+	run := testRun()
+	require.NoError(t, p.Execute(context.Background(), run))
+
+	assert.Equal(t, "completed_with_errors", fakes.runs.lastStatus())
+	require.Len(t, fakes.recs.inserted, 3, "scored sites are still stored")
+	require.NotNil(t, fakes.runs.scoredCount)
+	assert.Equal(t, 3, *fakes.runs.scoredCount)
+
+	assert.Equal(t, 2, fakes.runs.skipCount)
+	require.Len(t, fakes.runs.skipped, 2)
+	assert.Equal(t, "BAD", fakes.runs.skipped[0].SiteID)
+	assert.Contains(t, fakes.runs.skipped[0].Reason, "invalid site data")
+	assert.Equal(t, models.SkippedSite{SiteID: "NEG", Reason: "population must not be negative"}, fakes.runs.skipped[1])
+	require.NotNil(t, run.SkippedCount)
+	assert.Equal(t, 2, *run.SkippedCount)
+	assert.Equal(t, fakes.runs.skipped, run.SkippedSites)
+}
+
+func TestPipelineExecute_StoresSkippedSiteReasons(t *testing.T) {
+	records := []models.SiteRecord{
+		testSiteRecord("A", 800, 5),
+		testSiteRecord("NEG", -1, 10),
+		testSiteRecord("B", 200, 40),
+		testSiteRecord("BAD1", 0, 0),
+		testSiteRecord("BAD2", 0, 0),
+	}
+	records[3].Data = json.RawMessage(`not json`)
+	records[4].Data = json.RawMessage(`[]`)
+	p, fakes := newTestPipeline(records)
+	setDefaultScorer(t, p, func(siteData map[string]interface{}, resolved *schema.ResolvedSchema) (float64, float64, models.Explanation, error) {
+		if siteData["population"].(float64) < 0 {
+			return 0, 0, models.Explanation{}, errors.New("population must not be negative")
+		}
+		return DefaultScoreFunc(siteData, resolved)
+	})
+	store := &fakeSkippedSiteStore{}
+	p.SetSkippedSiteStore(store, 2)
+
+	run := testRun()
+	require.NoError(t, p.Execute(context.Background(), run))
+
+	// Parse failures are found first; the cap keeps the first two found
+	require.Len(t, store.inserted, 2)
+	for i, want := range []struct {
+		position int
+		siteID   string
+	}{{4, "BAD1"}, {5, "BAD2"}} {
+		got := store.inserted[i]
+		assert.Equal(t, want.position, got.Position)
+		assert.Equal(t, want.siteID, got.SiteID)
+		assert.Equal(t, want.siteID, got.SiteName)
+		assert.Contains(t, got.Reason, "invalid site data")
+		assert.Equal(t, run.ID, got.RunID)
+		assert.Equal(t, run.TenantID, got.TenantID)
+		assert.False(t, got.CreatedAt.IsZero())
+	}
+
+	// The run's own sample and count are not limited by the store's cap
+	assert.Equal(t, 3, fakes.runs.skipCount)
+	require.Len(t, fakes.runs.skipped, 3)
+	assert.Equal(t, models.SkippedSite{SiteID: "NEG", Reason: "population must not be negative"}, fakes.runs.skipped[2])
+}
+
+func TestPipelineExecute_SkipTolerance(t *testing.T) {
+	records := make([]models.SiteRecord, 0, 10)
+	for i := 0; i < 10; i++ {
+		records = append(records, testSiteRecord(fmt.Sprintf("S%d", i), float64(100*i), 10))
+	}
+	records[0].Data = json.RawMessage(`{`)
+
+	p, fakes := newTestPipeline(records)
+	p.SetSkipTolerance(0.1)
+	require.NoError(t, p.Execute(context.Background(), testRun()))
+	assert.Equal(t, "succeeded", fakes.runs.lastStatus(), "1 of 10 skipped is within a 10% tolerance")
+	assert.Equal(t, 1, fakes.runs.skipCount, "skips are recorded even when tolerated")
+
+	records[1].Data = json.RawMessage(`{`)
+	p, fakes = newTestPipeline(records)
+	p.SetSkipTolerance(0.1)
+	require.NoError(t, p.Execute(context.Background(), testRun()))
+	assert.Equal(t, "completed_with_errors", fakes.runs.lastStatus())
+	assert.Equal(t, 2, fakes.runs.skipCount)
+
+	// The sample of reasons is capped; the count is not
+	bulk := make([]models.SiteRecord, models.MaxSkippedSites+5)
+	for i := range bulk {
+		bulk[i] = models.SiteRecord{ID: uuid.New(), SiteID: fmt.Sprintf("X%d", i), Data: json.RawMessage(`[]`)}
+	}
+	p, fakes = newTestPipeline(append(bulk, testSiteRecord("A", 800, 5)))
+	require.NoError(t, p.Execute(context.Background(), testRun()))
+	assert.Equal(t, models.MaxSkippedSites+5, fakes.runs.skipCount)
+	assert.Len(t, fakes.runs.skipped, models.MaxSkippedSites)
+}
+
+func TestExecuteWithRetry_RunTimeoutFailsWithoutRetry(t *testing.T) {
+	p, fakes := newTestPipeline([]models.SiteRecord{
+		testSiteRecord("A", 800, 5),
+		testSiteRecord("B", 200, 40),
+		testSiteRecord("C", 500, 20),
+		testSiteRecord("D", 100, 60),
+	})
+	p.maxRetries = 3
+	p.runTimeout = 30 * time.Millisecond
+	setDefaultScorer(t, p, func(siteData map[string]interface{}, resolved *schema.ResolvedSchema) (float64, float64, models.Explanation, error) {
+		time.Sleep(20 * time.Millisecond)
+		return DefaultScoreFunc(siteData, resolved)
+	})
+
+	err := p.ExecuteWithRetry(context.Background(), testRun())
+
+	require.ErrorIs(t, err, ErrRunTimeout)
+	assert.True(t, IsPermanent(err))
+	assert.Equal(t, 1, fakes.runs.attempts, "a timed-out run is not retried")
+	assert.Equal(t, "failed", fakes.runs.lastStatus())
+	assert.Contains(t, *fakes.runs.lastError, "timed out after 30ms")
+	assert.Empty(t, fakes.recs.inserted)
+}
+
+func TestPipelineShutdown_DeadlineMarksRunsInterrupted(t *testing.T) {
+	p, fakes := newTestPipeline([]models.SiteRecord{
+		testSiteRecord("A", 800, 5),
+		testSiteRecord("B", 200, 40),
+	})
+	started := make(chan struct{})
+	release := make(chan struct{})
+	setDefaultScorer(t, p, blockingScoreFunc(started, release))
+
+	p.Dispatch(testRun())
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := p.Shutdown(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, fakes.runs.recordedErrors(), interruptedReason)
+
+	// Unblock the goroutine; it must observe cancellation rather than succeed
+	close(release)
+	require.NoError(t, p.Shutdown(context.Background()))
+	assert.Equal(t, "failed", fakes.runs.lastStatus())
+	fakes.recs.mu.Lock()
+	assert.Empty(t, fakes.recs.inserted, "cancelled run should not persist recommendations")
+	fakes.recs.mu.Unlock()
+}
+
+func TestPipelineRecoverOrphanedRuns_FailsByDefault(t *testing.T) {
+	p, fakes := newTestPipeline(nil)
+	orphan := testRun()
+	orphan.Status = "running"
+	fakes.runs.stale = []models.ScoringRun{*orphan}
+
+	count, err := p.RecoverOrphanedRuns(context.Background(), time.Minute, false)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, "failed", fakes.runs.lastStatus())
+	assert.Equal(t, []string{orphanedReason}, fakes.runs.recordedErrors())
+	assert.Empty(t, fakes.runs.claimed, "fail policy should not claim runs")
+}
+
+func TestPipelineRecoverOrphanedRuns_LeavesFinishedRuns(t *testing.T) {
+	p, fakes := newTestPipeline(nil)
+	orphan := testRun()
+	orphan.Status = "running"
+	fakes.runs.stale = []models.ScoringRun{*orphan}
+	fakes.runs.finished = []uuid.UUID{orphan.ID} // succeeded after being listed
+
+	count, err := p.RecoverOrphanedRuns(context.Background(), time.Minute, false)
+
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	assert.Empty(t, fakes.runs.recordedErrors(), "a finished run is not overwritten")
+}
+
+func TestPipelineStartOrphanSweep_RecoversRunsOrphanedLater(t *testing.T) {
+	p, fakes := newTestPipeline(nil)
+	p.StartOrphanSweep(5*time.Millisecond, time.Minute, false)
+
+	// Orphaned after startup, by an instance that died while this one ran
+	orphan := testRun()
+	orphan.Status = "running"
+	fakes.runs.mu.Lock()
+	fakes.runs.stale = []models.ScoringRun{*orphan}
+	fakes.runs.mu.Unlock()
+
+	require.Eventually(t, func() bool { return len(fakes.runs.recordedErrors()) > 0 },
+		5*time.Second, 5*time.Millisecond)
+	require.NoError(t, p.Shutdown(context.Background()))
+	assert.Equal(t, []string{orphanedReason}, fakes.runs.recordedErrors(), "each orphan is recovered once")
+}
+
+func TestPipelineRecoverOrphanedRuns_RequeueDispatchesRun(t *testing.T) {
+	p, fakes := newTestPipeline([]models.SiteRecord{testSiteRecord("A", 800, 5)})
+	orphan := testRun()
+	orphan.Status = "running"
+	fakes.runs.stale = []models.ScoringRun{*orphan}
+
+	count, err := p.RecoverOrphanedRuns(context.Background(), time.Minute, true)
+	require.NoError(t, err)
+	require.NoError(t, p.Shutdown(context.Background()))
+
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []uuid.UUID{orphan.ID}, fakes.runs.claimed)
+	assert.Equal(t, "succeeded", fakes.runs.lastStatus())
+}
+
+func TestPipelineRecoverOrphanedRuns_SkipsOwnRuns(t *testing.T) {
+	p, fakes := newTestPipeline(nil)
+	own := testRun()
+	own.InstanceID = p.InstanceID()
+	fakes.runs.stale = []models.ScoringRun{*own}
+
+	count, err := p.RecoverOrphanedRuns(context.Background(), 0, false)
+
+	require.NoError(t, err)
+	assert.Zero(t, count, "runs owned by this instance are not orphans")
+	assert.Empty(t, fakes.runs.recordedErrors())
+}
+
+func TestPipelineWorkers_DispatchWakesWorkers(t *testing.T) {
+	p, fakes := newTestPipeline([]models.SiteRecord{testSiteRecord("A", 800, 5)})
+	p.StartWorkers(3, time.Hour) // no polling; only Dispatch wakes them
+
+	runs := make([]*models.ScoringRun, 6)
+	for i := range runs {
+		runs[i] = testRun()
+	}
+	fakes.runs.enqueue(runs...)
+	p.DispatchBatch(runs, 1)
+
+	require.Eventually(t, func() bool {
+		fakes.recs.mu.Lock()
+		defer fakes.recs.mu.Unlock()
+		return len(fakes.recs.inserted) == len(runs)
+	}, 5*time.Second, 5*time.Millisecond)
+	require.NoError(t, p.Shutdown(context.Background()))
+
+	fakes.runs.mu.Lock()
+	defer fakes.runs.mu.Unlock()
+	assert.Len(t, fakes.runs.dequeued, len(runs), "each run is claimed once")
+	for _, run := range runs {
+		assert.Equal(t, p.InstanceID(), run.InstanceID, "the claiming instance owns the run")
+	}
+	assert.Equal(t, 0, p.InFlight())
+}
+
+func TestPipelineWorkers_HeartbeatWhileRunning(t *testing.T) {
+	p, fakes := newTestPipeline([]models.SiteRecord{testSiteRecord("A", 800, 5)})
+	started := make(chan struct{})
+	release := make(chan struct{})
+	setDefaultScorer(t, p, blockingScoreFunc(started, release))
+	p.SetHeartbeatInterval(time.Millisecond)
+	p.StartWorkers(1, time.Hour)
+
+	run := testRun()
+	fakes.runs.enqueue(run)
+	p.Dispatch(run)
+	<-started
+
+	require.Eventually(t, func() bool {
+		fakes.runs.mu.Lock()
+		defer fakes.runs.mu.Unlock()
+		return fakes.runs.beats >= 2
+	}, 5*time.Second, time.Millisecond, "an executing run is kept live")
+
+	close(release)
+	require.NoError(t, p.Shutdown(context.Background()))
+	fakes.runs.mu.Lock()
+	beats := fakes.runs.beats
+	fakes.runs.mu.Unlock()
+	time.Sleep(10 * time.Millisecond)
+	fakes.runs.mu.Lock()
+	assert.Equal(t, beats, fakes.runs.beats, "the heartbeat stops with the run")
+	fakes.runs.mu.Unlock()
+}
+
+func TestPipelineWorkers_PollForRunsQueuedElsewhere(t *testing.T) {
+	p, fakes := newTestPipeline([]models.SiteRecord{testSiteRecord("A", 800, 5)})
+	fakes.runs.claimErrs = 1 // a failed claim is logged and retried on the next poll
+	p.StartWorkers(1, 5*time.Millisecond)
+
+	// Queued by another instance, so nothing here calls Dispatch
+	fakes.runs.enqueue(testRun())
+
+	require.Eventually(t, func() bool { return fakes.runs.lastStatus() == "succeeded" },
+		5*time.Second, 5*time.Millisecond)
+	require.NoError(t, p.Shutdown(context.Background()))
+}
+
+func TestPipelineWorkers_ShutdownDeadlineRequeuesRuns(t *testing.T) {
+	p, fakes := newTestPipeline([]models.SiteRecord{testSiteRecord("A", 800, 5)})
+	started := make(chan struct{})
+	release := make(chan struct{})
+	setDefaultScorer(t, p, blockingScoreFunc(started, release))
+	p.StartWorkers(1, time.Hour)
+
+	run := testRun()
+	fakes.runs.enqueue(run, testRun())
+	p.Dispatch(run)
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := p.Shutdown(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, []uuid.UUID{run.ID}, fakes.runs.claimed, "the interrupted run is put back on the queue")
+	assert.NotContains(t, fakes.runs.recordedErrors(), interruptedReason)
+
+	close(release)
+	require.NoError(t, p.Shutdown(context.Background()))
+	fakes.runs.mu.Lock()
+	assert.Equal(t, []uuid.UUID{run.ID}, fakes.runs.dequeued, "no new run is claimed after shutdown starts")
+	fakes.runs.mu.Unlock()
+}
+
+func TestPipelineWorkers_RequeuedRunDoesNotDuplicateResults(t *testing.T) {
+	p, fakes := newTestPipeline([]models.SiteRecord{
+		testSiteRecord("A", 800, 5),
+		testSiteRecord("B", 200, 40),
+	})
+	p.StartWorkers(1, time.Hour)
+
+	run := testRun()
+	fakes.runs.enqueue(run)
+	p.Dispatch(run)
+	require.Eventually(t, func() bool { return fakes.runs.lastStatus() == "succeeded" },
+		5*time.Second, 5*time.Millisecond)
+
+	// Put back on the queue as by a shutdown after its results were stored
+	fakes.runs.enqueue(run)
+	p.Dispatch(run)
+	require.Eventually(t, func() bool {
+		fakes.runs.mu.Lock()
+		defer fakes.runs.mu.Unlock()
+		return len(fakes.runs.dequeued) == 2 && fakes.runs.statuses[len(fakes.runs.statuses)-1] == "succeeded"
+	}, 5*time.Second, 5*time.Millisecond)
+	require.NoError(t, p.Shutdown(context.Background()))
+
+	fakes.recs.mu.Lock()
+	defer fakes.recs.mu.Unlock()
+	assert.Len(t, fakes.recs.inserted, 2, "the second execution replaces the first one's recommendations")
+}
+
+func TestPipelineWorkers_RecoverLeavesQueuedRuns(t *testing.T) {
+	p, fakes := newTestPipeline(nil)
+	p.StartWorkers(1, time.Hour)
+	defer p.Shutdown(context.Background())
+
+	waiting := testRun()
+	orphan := testRun()
+	orphan.Status = "running"
+	fakes.runs.stale = []models.ScoringRun{*waiting, *orphan}
+
+	count, err := p.RecoverOrphanedRuns(context.Background(), time.Minute, true)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, count, "a queued run is waiting for a worker, not orphaned")
+	assert.Equal(t, []uuid.UUID{orphan.ID}, fakes.runs.claimed)
+}
+
+// ---------------------------------------------------------------------------
+// Retry classification
+// ---------------------------------------------------------------------------
+
+func TestExecuteWithRetry_PermanentErrorFailsAfterOneAttempt(t *testing.T) {
+	p, fakes := newTestPipeline([]models.SiteRecord{testSiteRecord("A", 800, 5)})
+	p.maxRetries = 3
+	fakes.configs.global = nil // no active global config: cannot succeed on retry
+
+	err := p.ExecuteWithRetry(context.Background(), testRun())
+
+	require.Error(t, err)
+	assert.True(t, IsPermanent(err), "permanent error should survive wrapping")
+	assert.Equal(t, 1, fakes.runs.attempts, "permanent errors must not be retried")
+	assert.Equal(t, "failed", fakes.runs.lastStatus())
+	assert.Contains(t, *fakes.runs.lastError, "after 1 attempts")
+}
+
+func TestExecuteWithRetry_TransientErrorRetries(t *testing.T) {
+	p, fakes := newTestPipeline(nil)
+	p.maxRetries = 2
+	fakes.sites.err = fmt.Errorf("connection reset by peer")
+
+	err := p.ExecuteWithRetry(context.Background(), testRun())
+
+	require.Error(t, err)
+	assert.False(t, IsPermanent(err))
+	assert.Equal(t, 3, fakes.runs.attempts, "transient errors should use every retry")
+	assert.Equal(t, "failed", fakes.runs.lastStatus())
+}
+
+func TestExecuteWithRetry_TransientErrorThenSuccess(t *testing.T) {
+	p, fakes := newTestPipeline([]models.SiteRecord{testSiteRecord("A", 800, 5)})
+	p.maxRetries = 2
+	p.siteRecordRepo = &flakySiteRecordStore{failures: 1, records: fakes.sites.records}
+
+	require.NoError(t, p.ExecuteWithRetry(context.Background(), testRun()))
+	assert.Equal(t, 2, fakes.runs.attempts)
+	assert.Equal(t, "succeeded", fakes.runs.lastStatus())
+}
+
+// flakySiteRecordStore fails the first n fetches, then succeeds.
+type flakySiteRecordStore struct {
+	failures int
+	records  []models.SiteRecord
+}
+
+func (f *flakySiteRecordStore) GetByUpload(_ context.Context, _ uuid.UUID) ([]models.SiteRecord, error) {
+	if f.failures > 0 {
+		f.failures--
+		return nil, fmt.Errorf("connection reset by peer")
+	}
+	return f.records, nil
+}
+
+func TestCalculateBackoff_WithinJitterBounds(t *testing.T) {
+	p, _ := newTestPipeline(nil)
+	p.retryBaseWait = 100 * time.Millisecond
+
+	for attempt := 0; attempt < 8; attempt++ {
+		lower := p.retryBaseWait * time.Duration(1<<attempt)
+		upper := lower + lower/10
+		for i := 0; i < 200; i++ {
+			got := p.calculateBackoff(attempt)
+			assert.GreaterOrEqual(t, got, lower, "attempt %d", attempt)
+			assert.LessOrEqual(t, got, upper, "attempt %d", attempt)
+		}
+	}
+}
+
+func TestCalculateBackoff_NeverZero(t *testing.T) {
+	p, _ := newTestPipeline(nil)
+	p.retryBaseWait = time.Nanosecond
+
+	for attempt := 0; attempt < 5; attempt++ {
+		assert.GreaterOrEqual(t, p.calculateBackoff(attempt), minBackoff)
+	}
+}
+
+func TestCalculateBackoff_CapsLargeAttempts(t *testing.T) {
+	p, _ := newTestPipeline(nil)
+
+	assert.Equal(t, maxBackoff, p.calculateBackoff(100))
+}
+
+func TestNewPipeline_DefaultsNonPositiveBaseWait(t *testing.T) {
+	for _, wait := range []time.Duration{0, -time.Second} {
+		p := NewPipeline(&fakeRunStore{}, &fakeSiteRecordStore{}, &fakeRecommendationStore{}, &fakeSchemaConfigStore{}, schema.NewResolver(), nil, 0, wait, 0)
+		assert.Equal(t, defaultRetryBaseWait, p.retryBaseWait)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Clock
+// ---------------------------------------------------------------------------
+
+func TestPipelineExecute_DurationUsesClock(t *testing.T) {
+	p, fakes := newTestPipeline([]models.SiteRecord{
+		testSiteRecord("A", 800, 5),
+		testSiteRecord("B", 200, 40),
+	})
+	start := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	p.SetClock(fake)
+
+	// Each scored site takes exactly 750ms of fake time
+	setDefaultScorer(t, p, func(siteData map[string]interface{}, resolved *schema.ResolvedSchema) (float64, float64, models.Explanation, error) {
+		fake.Advance(750 * time.Millisecond)
+		return DefaultScoreFunc(siteData, resolved)
+	})
+
+	require.NoError(t, p.Execute(context.Background(), testRun()))
+
+	require.NotNil(t, fakes.runs.durationMs)
+	assert.Equal(t, 1500, *fakes.runs.durationMs)
+
+	require.Len(t, fakes.configs.snapshots, 1)
+	assert.Equal(t, start, fakes.configs.snapshots[0].CreatedAt)
+	require.Len(t, fakes.recs.inserted, 2)
+	for _, rec := range fakes.recs.inserted {
+		assert.True(t, rec.CreatedAt.After(start))
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Run stats
+// ---------------------------------------------------------------------------
+
+func TestPipelineExecute_StoresRunStats(t *testing.T) {
+	p, fakes := newTestPipeline([]models.SiteRecord{
+		testSiteRecord("A", 100, 0),
+		testSiteRecord("B", 400, 0),
+		testSiteRecord("C", 200, 0),
+		testSiteRecord("D", 300, 0),
+	})
+
+	// Final score is population / 10, giving scores 10, 40, 20, 30
+	setDefaultScorer(t, p, func(siteData map[string]interface{}, resolved *schema.ResolvedSchema) (float64, float64, models.Explanation, error) {
+		_, _, explanation, err := DefaultScoreFunc(siteData, resolved)
+		score := siteData["population"].(float64) / 10
+		return score, score, explanation, err
+	})
+
+	run := testRun()
+	require.NoError(t, p.Execute(context.Background(), run))
+
+	require.NotNil(t, fakes.runs.stats)
+	stats := fakes.runs.stats
+	assert.Equal(t, 10.0, stats.Min)
+	assert.Equal(t, 40.0, stats.Max)
+	assert.Equal(t, 25.0, stats.Mean)
+	assert.Equal(t, 25.0, stats.Median)
+	assert.InDelta(t, math.Sqrt(125), stats.StdDev, 1e-9)
+	assert.Equal(t, stats, run.Stats)
+}
+
+// ---------------------------------------------------------------------------
+// Model versions
+// ---------------------------------------------------------------------------
+
+func TestPipelineExecute_ModelVersionSelectsScoring(t *testing.T) {
+	records := []models.SiteRecord{
+		testSiteRecord("A", 800, 5),
+		testSiteRecord("B", 200, 40),
+		testSiteRecord("C", 500, 20),
+	}
+
+	scoresFor := func(version string) map[string]float64 {
+		p, fakes := newTestPipeline(records)
+		run := testRun()
+		run.ModelVersion = version
+		require.NoError(t, p.Execute(context.Background(), run))
+
+		scores := make(map[string]float64)
+		for _, rec := range fakes.recs.inserted {
+			scores[rec.SiteID] = rec.FinalScore
+		}
+		require.Len(t, scores, 3)
+		return scores
+	}
+
+	v1 := scoresFor(DefaultModelVersion)
+	v2 := scoresFor("site-selection-iq-v2.0")
+
+	// v1 normalizes against the configured bounds: (0.8 + 0.95) / 2
+	assert.InDelta(t, 87.5, v1["A"], 1e-9)
+	// v2 scores by rank within the upload, so the best site on every factor
+	// gets the full score
+	assert.InDelta(t, 100.0, v2["A"], 1e-9)
+	assert.NotEqual(t, v1["C"], v2["C"])
+}
+
+func TestPipelineExecute_RunConfigWeightsOverrideSchema(t *testing.T) {
+	p, fakes := newTestPipeline([]models.SiteRecord{testSiteRecord("A", 800, 5)})
+	run := testRun()
+	// As recorded by the run handler for a run created from a weight preset
+	run.ScoringConfig = json.RawMessage(`{"weight_preset": "growth", "weights": {"population": 3}}`)
+	require.NoError(t, p.Execute(context.Background(), run))
+
+	require.Len(t, fakes.recs.inserted, 1)
+	// (3 * 0.8 + 1 * 0.95) / 4
+	assert.InDelta(t, 83.75, fakes.recs.inserted[0].FinalScore, 1e-9)
+
+	var snapshot schema.ResolvedSchema
+	require.NoError(t, json.Unmarshal(fakes.configs.snapshots[0].SnapshotData, &snapshot))
+	assert.Equal(t, 3.0, snapshot.Weights["population"])
+}
+
+func TestPipelineExecute_UnknownModelVersionFailsPermanently(t *testing.T) {
+	p, fakes := newTestPipeline([]models.SiteRecord{testSiteRecord("A", 800, 5)})
+	run := testRun()
+	run.ModelVersion = "does-not-exist"
+
+	err := p.Execute(context.Background(), run)
+	require.Error(t, err)
+	assert.True(t, IsPermanent(err))
+	assert.ErrorIs(t, err, ErrUnknownModelVersion)
+	assert.Equal(t, "failed", fakes.runs.lastStatus())
+}
+
+func TestPipelineExecute_ModelScorer(t *testing.T) {
+	p, fakes := newTestPipeline([]models.SiteRecord{testSiteRecord("A", 800, 5)})
+	require.NoError(t, p.ScoreFuncs().Register("constant", func(map[string]interface{}, *schema.ResolvedSchema) (float64, float64, models.Explanation, error) {
+		return 1, 42, models.Explanation{}, nil
+	}))
+	require.NoError(t, p.Models().Register(Model{Version: "constant-model", Scorer: "constant"}))
+	run := testRun()
+	run.ModelVersion = "constant-model"
+
+	require.NoError(t, p.Execute(context.Background(), run))
+	require.Len(t, fakes.recs.inserted, 1)
+	assert.Equal(t, 42.0, fakes.recs.inserted[0].FinalScore)
+}
+
+// ---------------------------------------------------------------------------
+// Scorers
+// ---------------------------------------------------------------------------
+
+func TestPipelineExecute_CustomScorerSelectedByScoringConfig(t *testing.T) {
+	p, fakes := newTestPipeline([]models.SiteRecord{
+		testSiteRecord("A", 800, 5),
+		testSiteRecord("B", 200, 40),
+	})
+	var calls int
+	require.NoError(t, p.ScoreFuncs().Register("population_only", func(siteData map[string]interface{}, _ *schema.ResolvedSchema) (float64, float64, models.Explanation, error) {
+		calls++
+		pop := siteData["population"].(float64)
+		return pop, pop / 10, models.Explanation{}, nil
+	}))
+
+	run := testRun()
+	run.ScoringConfig = json.RawMessage(`{"scorer": "population_only"}`)
+	require.NoError(t, p.Execute(context.Background(), run))
+
+	assert.Equal(t, 2, calls)
+	require.Len(t, fakes.recs.inserted, 2)
+	assert.Equal(t, "A", fakes.recs.inserted[0].SiteID)
+	assert.Equal(t, 80.0, fakes.recs.inserted[0].FinalScore)
+	assert.Equal(t, 20.0, fakes.recs.inserted[1].FinalScore)
+}
+
+func TestPipelineExecute_UnknownScorerFailsPermanently(t *testing.T) {
+	p, fakes := newTestPipeline([]models.SiteRecord{testSiteRecord("A", 800, 5)})
+	run := testRun()
+	run.ScoringConfig = json.RawMessage(`{"scorer": "nope"}`)
+
+	err := p.Execute(context.Background(), run)
+	require.Error(t, err)
+	assert.True(t, IsPermanent(err))
+	assert.ErrorIs(t, err, ErrUnknownScorer)
+	assert.Equal(t, "failed", fakes.runs.lastStatus())
+}
+
+func TestResultKey_IdenticalRunsShareKey(t *testing.T) {
+	p, fakes := newTestPipeline(nil)
+	ctx := context.Background()
+
+	run := testRun()
+	key, err := p.ResultKey(ctx, run, "hash-a")
+	require.NoError(t, err)
+	assert.Len(t, key, 64)
+
+	// A second run of the same upload and schema short-circuits to the first
+	again := testRun()
+	again.TenantID = run.TenantID
+	sameKey, err := p.ResultKey(ctx, again, "hash-a")
+	require.NoError(t, err)
+	assert.Equal(t, key, sameKey)
+
+	otherContent, err := p.ResultKey(ctx, run, "hash-b")
+	require.NoError(t, err)
+	assert.NotEqual(t, key, otherContent, "appended or different content must not reuse results")
+
+	weighted := testRun()
+	weighted.ScoringConfig = json.RawMessage(`{"weights": {"population": 3}}`)
+	weightedKey, err := p.ResultKey(ctx, weighted, "hash-a")
+	require.NoError(t, err)
+	assert.NotEqual(t, key, weightedKey)
+
+	fakes.configs.tenant = &models.SchemaConfig{ID: uuid.New(), Config: json.RawMessage(`{"weights": {"unemployment": 2}}`)}
+	overridden, err := p.ResultKey(ctx, run, "hash-a")
+	require.NoError(t, err)
+	assert.NotEqual(t, key, overridden, "a changed tenant override changes the effective schema")
+}
+
+func TestPipelineExecute_StoresResultKeyOfSchemaUsed(t *testing.T) {
+	p, fakes := newTestPipeline([]models.SiteRecord{testSiteRecord("A", 800, 5)})
+	p.SetUploadStore(&fakeUploadStore{contentHash: "hash-a"})
+	ctx := context.Background()
+	run := testRun()
+
+	queuedKey, err := p.ResultKey(ctx, run, "hash-a")
+	require.NoError(t, err)
+
+	// The tenant's override changes while the run waits in the queue
+	fakes.configs.tenant = &models.SchemaConfig{ID: uuid.New(), Config: json.RawMessage(`{"weights": {"unemployment": 2}}`)}
+	require.NoError(t, p.Execute(ctx, run))
+
+	executedKey, err := p.ResultKey(ctx, run, "hash-a")
+	require.NoError(t, err)
+	require.NotNil(t, fakes.runs.resultKey)
+	assert.Equal(t, executedKey, *fakes.runs.resultKey)
+	assert.NotEqual(t, queuedKey, *fakes.runs.resultKey)
+	assert.Equal(t, fakes.runs.resultKey, run.ResultKey)
+}
+
+func TestPipelineExecute_NoUploadStoreStoresNoResultKey(t *testing.T) {
+	p, fakes := newTestPipeline([]models.SiteRecord{testSiteRecord("A", 800, 5)})
+	run := testRun()
+	key := "stale"
+	run.ResultKey = &key
+
+	require.NoError(t, p.Execute(context.Background(), run))
+	assert.Nil(t, fakes.runs.resultKey)
+	assert.Nil(t, run.ResultKey)
+}
+
+func TestResultKey_UnresolvableSchema(t *testing.T) {
+	p, fakes := newTestPipeline(nil)
+	fakes.configs.global = nil
+
+	_, err := p.ResultKey(context.Background(), testRun(), "hash-a")
+	assert.ErrorIs(t, err, schema.ErrNoGlobalConfig)
+	assert.True(t, IsPermanent(err))
+}
+
+func TestPipelineExecute_FreshnessPenalizesStaleData(t *testing.T) {
+	dated := func(siteID, asOf string) models.SiteRecord {
+		data, _ := json.Marshal(map[string]interface{}{
+			"site_id":      siteID,
+			"population":   600,
+			"unemployment": 10,
+			"collected_on": asOf,
+		})
+		return models.SiteRecord{ID: uuid.New(), SiteID: siteID, SiteName: siteID, Data: data}
+	}
+	p, fakes := newTestPipeline([]models.SiteRecord{
+		dated("STALE", "2023-06-01"),
+		dated("FRESH", "2024-06-01"),
+		dated("UNDATED", ""),
+	})
+	p.SetClock(clock.NewFake(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)))
+	fakes.configs.tenant = &models.SchemaConfig{ID: uuid.New(), Config: json.RawMessage(
+		`{"scoring": {"freshness": {"as_of_column": "collected_on", "half_life_days": 366}}}`)}
+
+	require.NoError(t, p.Execute(context.Background(), testRun()))
+	require.Len(t, fakes.recs.inserted, 3)
+
+	bySite := make(map[string]models.Recommendation)
+	explanations := make(map[string]models.Explanation)
+	for _, rec := range fakes.recs.inserted {
+		bySite[rec.SiteID] = rec
+		var explanation models.Explanation
+		require.NoError(t, json.Unmarshal(rec.ComponentScores, &explanation))
+		explanations[rec.SiteID] = explanation
+	}
+
+	// The identical site with year-old data scores half as much
+	fresh, stale := bySite["FRESH"].FinalScore, bySite["STALE"].FinalScore
+	assert.Greater(t, fresh, stale)
+	assert.InDelta(t, fresh/2, stale, 0.01)
+	assert.Equal(t, fresh, bySite["UNDATED"].FinalScore, "sites without a date are not penalized")
+	assert.Equal(t, 3, bySite["STALE"].Ranking)
+
+	penalty := explanations["STALE"].Freshness
+	require.NotNil(t, penalty)
+	assert.Equal(t, "2023-06-01", penalty.AsOf)
+	assert.Equal(t, 366.0, penalty.AgeDays)
+	assert.InDelta(t, 0.5, penalty.Factor, 1e-9)
+	assert.Contains(t, explanations["STALE"].Summary, "because the data is 366 days old (collected 2023-06-01; half-life 366 days)")
+	assert.Nil(t, explanations["FRESH"].Freshness)
+	assert.NotContains(t, explanations["FRESH"].Summary, "days old")
+
+	// The reference date is pinned in the snapshot so reranks age data alike
+	require.Len(t, fakes.configs.snapshots, 1)
+	assert.Contains(t, string(fakes.configs.snapshots[0].SnapshotData), `"reference_date":"2024-06-01"`)
+}
+
+func TestPipelineExecute_ScoreIntervalWidensWithUncertainty(t *testing.T) {
+	withError := func(siteID string, populationError interface{}) models.SiteRecord {
+		data, _ := json.Marshal(map[string]interface{}{
+			"site_id":          siteID,
+			"population":       500,
+			"unemployment":     10,
+			"population_error": populationError,
+		})
+		return models.SiteRecord{ID: uuid.New(), SiteID: siteID, SiteName: siteID, Data: data}
+	}
+	p, fakes := newTestPipeline([]models.SiteRecord{
+		withError("EXACT", "0"),
+		withError("NARROW", "50"),
+		withError("WIDE", "200"),
+		withError("UNREPORTED", ""),
+	})
+	fakes.configs.tenant = &models.SchemaConfig{ID: uuid.New(), Config: json.RawMessage(`{"fields": {
+		"population": {"type": "population", "min": 0, "max": 1000, "weight": 1.0, "direction": "maximize", "uncertainty_column": "population_error"}
+	}}`)}
+
+	require.NoError(t, p.Execute(context.Background(), testRun()))
+	require.Len(t, fakes.recs.inserted, 4)
+
+	type interval struct {
+		Low  *float64 `json:"score_low"`
+		High *float64 `json:"score_high"`
+	}
+	intervals := make(map[string]interval)
+	for _, rec := range fakes.recs.inserted {
+		var iv interval
+		require.NoError(t, json.Unmarshal(rec.Metadata, &iv))
+		intervals[rec.SiteID] = iv
+		assert.Equal(t, 70.0, rec.FinalScore, "uncertainty does not move the score itself")
+	}
+
+	assert.Nil(t, intervals["EXACT"].Low, "a zero error gives no interval")
+	assert.Nil(t, intervals["UNREPORTED"].Low)
+
+	// Population carries half the weight, so ±50 of a 0-1000 range moves
+	// the 0-100 score by ±2.5, and ±200 by ±10
+	narrow, wide := intervals["NARROW"], intervals["WIDE"]
+	require.NotNil(t, narrow.Low)
+	require.NotNil(t, wide.Low)
+	assert.Equal(t, 67.5, *narrow.Low)
+	assert.Equal(t, 72.5, *narrow.High)
+	assert.Equal(t, 60.0, *wide.Low)
+	assert.Equal(t, 80.0, *wide.High)
+	assert.Greater(t, *wide.High-*wide.Low, *narrow.High-*narrow.Low)
+}
+
+func TestIntervalFor_CombinesFieldsInQuadrature(t *testing.T) {
+	resolved, err := schema.Resolve(json.RawMessage(`{
+		"site_id_column": "site_id",
+		"fields": {
+			"a": {"type": "numeric", "min": 0, "max": 100, "weight": 1, "direction": "maximize", "uncertainty_column": "a_err"},
+			"b": {"type": "numeric", "min": 0, "max": 100, "weight": 1, "direction": "minimize", "uncertainty_column": "b_err"}
+		}
+	}`), nil)
+	require.NoError(t, err)
+
+	site := map[string]interface{}{"a": 50.0, "b": 50.0, "a_err": 6.0, "b_err": -8.0}
+	_, final, _, err := DefaultScoreFunc(site, resolved)
+	require.NoError(t, err)
+
+	// Each field moves the score by half its relative error: 3 and 4 points
+	iv := intervalFor(site, resolved, DefaultScoreFunc, final)
+	require.NotNil(t, iv)
+	assert.InDelta(t, final-5, iv.low, 1e-9)
+	assert.InDelta(t, final+5, iv.high, 1e-9)
+}
@@ -0,0 +1,42 @@
+package scoring
+
+import "math"
+
+// roundResults rounds each result's raw and final score, factor
+// contributions and category subscores to precision decimal places.
+func roundResults(results []siteScore, precision int) {
+	for i := range results {
+		r := &results[i]
+		r.rawScore = roundHalfEven(r.rawScore, precision)
+		r.finalScore = roundHalfEven(r.finalScore, precision)
+		if r.interval != nil {
+			r.interval.low = roundHalfEven(r.interval.low, precision)
+			r.interval.high = roundHalfEven(r.interval.high, precision)
+		}
+		for j := range r.explanation.Factors {
+			f := &r.explanation.Factors[j]
+			f.Contribution = roundHalfEven(f.Contribution, precision)
+		}
+		for j := range r.explanation.Categories {
+			cs := &r.explanation.Categories[j]
+			cs.Score = roundHalfEven(cs.Score, precision)
+			cs.Contribution = roundHalfEven(cs.Contribution, precision)
+		}
+		for name, score := range r.explanation.CategoryScores {
+			r.explanation.CategoryScores[name] = roundHalfEven(score, precision)
+		}
+	}
+}
+
+// roundHalfEven rounds v to precision decimal places, rounding halves to
+// the nearest even digit. Values are rounded as stored in binary, so a
+// literal like 2.675 (really 2.67499...) rounds down.
+func roundHalfEven(v float64, precision int) float64 {
+	scale := math.Pow10(precision)
+	rounded := math.RoundToEven(v*scale) / scale
+	if math.IsInf(rounded, 0) || math.IsNaN(rounded) {
+		// v*scale overflowed; v is too large to carry that many decimals
+		return v
+	}
+	return rounded
+}
@@ -0,0 +1,90 @@
+package scoring
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+)
+
+func TestRoundHalfEven(t *testing.T) {
+	tests := []struct {
+		value     float64
+		precision int
+		want      float64
+	}{
+		{73.33333333333333, 2, 73.33},
+		{66.66666666666667, 2, 66.67},
+		{0.125, 2, 0.12}, // half rounds to even
+		{0.375, 2, 0.38},
+		{2.5, 0, 2},
+		{3.5, 0, 4},
+		{-1.25, 1, -1.2},
+		{87.5, 4, 87.5},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, roundHalfEven(tt.value, tt.precision), "roundHalfEven(%v, %d)", tt.value, tt.precision)
+	}
+
+	// Values too large to scale are returned unchanged
+	assert.Equal(t, math.MaxFloat64, roundHalfEven(math.MaxFloat64, 10))
+}
+
+func TestPipelineExecute_RoundsScoresToDefaultPrecision(t *testing.T) {
+	// (0.8 + (1 - 1/3 / 100)) / 2 * 100 = 89.8333...
+	p, fakes := newTestPipeline([]models.SiteRecord{testSiteRecord("A", 800, 1.0/3)})
+	require.NoError(t, p.Execute(context.Background(), testRun()))
+
+	require.Len(t, fakes.recs.inserted, 1)
+	rec := fakes.recs.inserted[0]
+	assert.Equal(t, 89.83, rec.FinalScore)
+	assert.Equal(t, 1.8, rec.RawScore)
+
+	var explanation models.Explanation
+	require.NoError(t, json.Unmarshal(rec.ComponentScores, &explanation))
+	for _, f := range explanation.Factors {
+		assert.Equal(t, roundHalfEven(f.Contribution, 2), f.Contribution, f.Name)
+	}
+}
+
+func TestPipelineExecute_RoundedTiesUseTieBreaker(t *testing.T) {
+	// B scores 87.505 and A 87.5; at one decimal place both are 87.5, so
+	// the site_id tie-break puts A first
+	records := []models.SiteRecord{
+		testSiteRecord("B", 800.1, 5),
+		testSiteRecord("A", 800, 5),
+	}
+
+	p, fakes := newTestPipeline(records)
+	run := testRun()
+	run.ScoringConfig = json.RawMessage(`{"precision": 1}`)
+	require.NoError(t, p.Execute(context.Background(), run))
+
+	require.Len(t, fakes.recs.inserted, 2)
+	byRank := map[int]models.Recommendation{}
+	for _, rec := range fakes.recs.inserted {
+		byRank[rec.Ranking] = rec
+	}
+	assert.Equal(t, "A", byRank[1].SiteID)
+	assert.Equal(t, "B", byRank[2].SiteID)
+	assert.Equal(t, 87.5, byRank[1].FinalScore)
+	assert.Equal(t, 87.5, byRank[2].FinalScore)
+
+	// At higher precision the scores differ and B ranks first
+	p, fakes = newTestPipeline(records)
+	run = testRun()
+	run.ScoringConfig = json.RawMessage(`{"precision": 4}`)
+	require.NoError(t, p.Execute(context.Background(), run))
+
+	for _, rec := range fakes.recs.inserted {
+		if rec.SiteID == "B" {
+			assert.Equal(t, 1, rec.Ranking)
+			assert.Equal(t, 87.505, rec.FinalScore)
+		}
+	}
+}
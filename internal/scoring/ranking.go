@@ -0,0 +1,147 @@
+package scoring
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
+)
+
+// scoredSite carries a recommendation through ranking together with the
+// inputs needed to order it and explain the order.
+type scoredSite struct {
+	rec         models.Recommendation
+	explanation models.Explanation
+	data        map[string]interface{}
+}
+
+// scoredRecommendations returns the recommendation of each scored site.
+func scoredRecommendations(sites []scoredSite) []models.Recommendation {
+	recs := make([]models.Recommendation, len(sites))
+	for i, site := range sites {
+		recs[i] = site.rec
+	}
+	return recs
+}
+
+// rankScores ranks scoring results with rankSites. The recommendations it
+// returns carry only the site ID and name, scores and ranking.
+func rankScores(results []siteScore, resolved *schema.ResolvedSchema) []scoredSite {
+	sites := make([]scoredSite, len(results))
+	for i, result := range results {
+		sites[i] = scoredSite{
+			rec: models.Recommendation{
+				SiteID:     result.site.record.SiteID,
+				SiteName:   result.site.record.SiteName,
+				FinalScore: result.finalScore,
+				RawScore:   result.rawScore,
+			},
+			explanation: result.explanation,
+			data:        result.site.data,
+		}
+	}
+	rankSites(sites, resolved)
+	return sites
+}
+
+// rankSites orders sites by final score, highest first, and assigns rankings.
+// Equal scores are resolved deterministically:
+//  1. by the configured tie-break field, if any (following its direction;
+//     sites missing the value sort last)
+//  2. by site ID, ascending
+//
+// Every site that shares its score with another gets a tie_break note in its
+// explanation describing this order.
+func rankSites(sites []scoredSite, resolved *schema.ResolvedSchema) {
+	tieField := resolved.Scoring.TieBreakField
+	var tieDir schema.Direction
+	if tieField != "" {
+		tieDir = resolved.Fields[tieField].Direction
+	}
+
+	sort.SliceStable(sites, func(i, j int) bool {
+		a, b := sites[i], sites[j]
+		if a.rec.FinalScore != b.rec.FinalScore {
+			return a.rec.FinalScore > b.rec.FinalScore
+		}
+		if tieField != "" {
+			if c := compareTieValues(a.data[tieField], b.data[tieField], tieDir); c != 0 {
+				return c < 0
+			}
+		}
+		return a.rec.SiteID < b.rec.SiteID
+	})
+
+	for i := range sites {
+		sites[i].rec.Ranking = i + 1
+	}
+
+	// Annotate each run of equal scores
+	for start := 0; start < len(sites); {
+		end := start + 1
+		for end < len(sites) && sites[end].rec.FinalScore == sites[start].rec.FinalScore {
+			end++
+		}
+		if end-start > 1 {
+			note := tieBreakNote(end-start, tieField, tieDir)
+			for k := start; k < end; k++ {
+				sites[k].explanation.TieBreak = note
+			}
+		}
+		start = end
+	}
+}
+
+// compareTieValues returns a negative number when a should rank ahead of b,
+// positive when b should, and 0 when they cannot be told apart.
+// Numbers follow dir (maximize: higher first; minimize: lower first); strings
+// sort ascending; a missing or non-comparable value ranks after a present one.
+func compareTieValues(a, b interface{}, dir schema.Direction) int {
+	switch av := a.(type) {
+	case float64:
+		bv, ok := b.(float64)
+		if !ok {
+			return -1
+		}
+		if av == bv {
+			return 0
+		}
+		higherFirst := dir != schema.DirectionMinimize
+		if (av > bv) == higherFirst {
+			return -1
+		}
+		return 1
+	case string:
+		bv, ok := b.(string)
+		if !ok {
+			return -1
+		}
+		switch {
+		case av < bv:
+			return -1
+		case av > bv:
+			return 1
+		}
+		return 0
+	}
+
+	switch b.(type) {
+	case float64, string:
+		return 1
+	}
+	return 0
+}
+
+// tieBreakNote describes how a group of tiedCount equal scores was ordered.
+func tieBreakNote(tiedCount int, field string, dir schema.Direction) string {
+	order := "site_id ascending"
+	if field != "" {
+		fieldOrder := "higher first"
+		if dir == schema.DirectionMinimize {
+			fieldOrder = "lower first"
+		}
+		order = fmt.Sprintf("%s (%s), then %s", field, fieldOrder, order)
+	}
+	return fmt.Sprintf("Tied on final score with %d other site(s); ordered by %s", tiedCount-1, order)
+}
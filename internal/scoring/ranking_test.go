@@ -0,0 +1,86 @@
+package scoring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
+)
+
+func tiedSites() []scoredSite {
+	site := func(id string, score float64, labor interface{}) scoredSite {
+		data := map[string]interface{}{"site_id": id}
+		if labor != nil {
+			data["labor_cost"] = labor
+		}
+		return scoredSite{rec: models.Recommendation{SiteID: id, FinalScore: score}, data: data}
+	}
+	return []scoredSite{
+		site("D", 70, 90.0),
+		site("B", 80, 120.0),
+		site("E", 70, nil),
+		site("A", 80, 100.0),
+		site("C", 80, 100.0),
+		site("F", 95, 50.0),
+	}
+}
+
+// rankedIDs returns site IDs in slice order, checking rankings are 1..n.
+func rankedIDs(t *testing.T, sites []scoredSite) []string {
+	t.Helper()
+	ids := make([]string, len(sites))
+	for i, s := range sites {
+		ids[i] = s.rec.SiteID
+		assert.Equal(t, i+1, s.rec.Ranking)
+	}
+	return ids
+}
+
+func reversed(sites []scoredSite) []scoredSite {
+	out := make([]scoredSite, len(sites))
+	for i, s := range sites {
+		out[len(sites)-1-i] = s
+	}
+	return out
+}
+
+func TestRankSites_TiesFallBackToSiteID(t *testing.T) {
+	resolved := &schema.ResolvedSchema{Fields: map[string]schema.FieldDef{}}
+
+	sites := tiedSites()
+	rankSites(sites, resolved)
+	assert.Equal(t, []string{"F", "A", "B", "C", "D", "E"}, rankedIDs(t, sites))
+
+	// Input order does not matter
+	other := reversed(tiedSites())
+	rankSites(other, resolved)
+	assert.Equal(t, rankedIDs(t, sites), rankedIDs(t, other))
+
+	assert.Empty(t, sites[0].explanation.TieBreak, "untied site has no note")
+	assert.Equal(t, "Tied on final score with 2 other site(s); ordered by site_id ascending", sites[1].explanation.TieBreak)
+}
+
+func TestRankSites_TieBreakFieldRespectsDirection(t *testing.T) {
+	resolved := &schema.ResolvedSchema{
+		Fields: map[string]schema.FieldDef{
+			"labor_cost": {Type: schema.TypeIndex, Direction: schema.DirectionMinimize},
+		},
+		Scoring: schema.ScoringOptions{TieBreakField: "labor_cost"},
+	}
+
+	sites := tiedSites()
+	rankSites(sites, resolved)
+	// 80s: A and C share labor_cost 100 (site_id decides), B is costlier.
+	// 70s: D has a value, E is missing it and sorts last.
+	assert.Equal(t, []string{"F", "A", "C", "B", "D", "E"}, rankedIDs(t, sites))
+	assert.Equal(t,
+		"Tied on final score with 1 other site(s); ordered by labor_cost (lower first), then site_id ascending",
+		sites[4].explanation.TieBreak)
+
+	resolved.Fields["labor_cost"] = schema.FieldDef{Type: schema.TypeIndex, Direction: schema.DirectionMaximize}
+	sites = reversed(tiedSites())
+	rankSites(sites, resolved)
+	assert.Equal(t, []string{"F", "B", "A", "C", "D", "E"}, rankedIDs(t, sites))
+}
@@ -0,0 +1,211 @@
+package scoring
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/workforce-ai/site-selection-iq/internal/i18n"
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
+)
+
+// SiteScore is one site's result from a batch scoring function.
+type SiteScore struct {
+	RawScore    float64
+	FinalScore  float64
+	Explanation models.Explanation
+}
+
+// RankSumScore scores a whole set of sites by weighted rank percentile.
+// For each weighted numeric field:
+// 1. Rank the sites that have a value, best first according to direction
+// 2. Convert the rank to a 0-1 percentile (tied values share a percentile)
+// 3. Multiply the percentile by the field's weight
+//
+// The raw score is the sum of weighted percentiles; the final score divides
+// by the total weight of the fields the site had values for and scales to the
+// configured score scale. With category weights configured, percentiles are
+// averaged within each category and the categories combined by weight, as in
+// DefaultScoreFunc. Unlike DefaultScoreFunc, results depend only on the
+// order of values, so outliers and skewed distributions do not compress the
+// rest of the field. Results are returned in the order of sites.
+func RankSumScore(sites []map[string]interface{}, resolvedSchema *schema.ResolvedSchema) ([]SiteScore, error) {
+	if resolvedSchema == nil {
+		return nil, fmt.Errorf("resolved schema cannot be nil")
+	}
+
+	msgs := i18n.Lookup(resolvedSchema.Scoring.Locale)
+	results := make([]SiteScore, len(sites))
+	totalWeights := make([]float64, len(sites))
+	var schemaWeight float64
+	for i := range results {
+		results[i].Explanation.Factors = []models.ExplanationFactor{}
+	}
+
+	// Per-site category totals, kept only when categories are scored
+	usesCategories := resolvedSchema.UsesCategories()
+	var categories []map[string]*categoryTotals
+	if usesCategories {
+		categories = make([]map[string]*categoryTotals, len(sites))
+		for i := range categories {
+			categories[i] = make(map[string]*categoryTotals)
+		}
+	}
+	categoryOf := func(site int, category string) *categoryTotals {
+		totals, ok := categories[site][category]
+		if !ok {
+			totals = &categoryTotals{}
+			categories[site][category] = totals
+		}
+		return totals
+	}
+
+	for _, fieldName := range sortedFieldNames(resolvedSchema) {
+		fieldDef := resolvedSchema.Fields[fieldName]
+		if !isNumericFieldType(fieldDef.Type) || fieldDef.Weight == 0 {
+			continue
+		}
+		weight := resolvedSchema.Weights[fieldName]
+		if weight == 0 {
+			continue
+		}
+		schemaWeight += weight
+		if usesCategories {
+			for i := range sites {
+				categoryOf(i, fieldDef.Category).schemaWeight += weight
+			}
+		}
+
+		// Collect the sites that have a numeric value for this field
+		type entry struct {
+			site  int
+			value float64
+			merit float64 // higher is better for this field's direction
+		}
+		var entries []entry
+		for i, siteData := range sites {
+			raw, ok := siteData[fieldName]
+			if !ok {
+				continue
+			}
+			v, err := toFloat64(raw)
+			if err != nil {
+				continue
+			}
+			entries = append(entries, entry{site: i, value: v, merit: rankMerit(v, fieldDef)})
+		}
+		if len(entries) == 0 {
+			continue
+		}
+
+		// Best first: descending for maximize, ascending for minimize,
+		// nearest first for target
+		sort.SliceStable(entries, func(a, b int) bool {
+			return entries[a].merit > entries[b].merit
+		})
+
+		n := len(entries)
+		for start := 0; start < n; {
+			// Group equally good values so ties share a rank and percentile
+			end := start + 1
+			for end < n && entries[end].merit == entries[start].merit {
+				end++
+			}
+
+			rank := start + 1
+			percentile := 1.0
+			if n > 1 {
+				// Sites beaten, counting half of the other tied sites
+				beaten := float64(n-end) + float64(end-start-1)/2
+				percentile = beaten / float64(n-1)
+			}
+
+			for _, e := range entries[start:end] {
+				contribution := percentile * weight
+				results[e.site].RawScore += contribution
+				totalWeights[e.site] += weight
+				if usesCategories {
+					totals := categoryOf(e.site, fieldDef.Category)
+					totals.scored += contribution
+					totals.weight += weight
+				}
+				results[e.site].Explanation.Factors = append(results[e.site].Explanation.Factors, models.ExplanationFactor{
+					Name:         fieldName,
+					Value:        e.value,
+					Weight:       weight,
+					Contribution: contribution,
+					Direction:    rankDirection(fieldDef.Direction),
+					Reason:       rankReason(msgs, fieldName, rank, n, fieldDef.Direction),
+				})
+			}
+			start = end
+		}
+	}
+
+	scale := resolvedSchema.Scoring.ScoreScale
+	for i := range results {
+		maxPossible := totalWeights[i]
+		results[i].Explanation.Coverage = coverage(totalWeights[i], schemaWeight)
+		if usesCategories {
+			explanation := &results[i].Explanation
+			results[i].RawScore, maxPossible, explanation.Coverage, explanation.Categories =
+				combineCategories(categories[i], resolvedSchema.CategoryWeights, scale)
+			explanation.CategoryScores = categoryScoreMap(explanation.Categories)
+			explanation.CategorySummary = categorySummary(explanation.Categories, scale)
+		}
+
+		if maxPossible > 0 {
+			results[i].FinalScore = results[i].RawScore / maxPossible * scale.Max()
+		}
+		results[i].FinalScore = math.Max(0, math.Min(scale.Max(), results[i].FinalScore))
+
+		factors := results[i].Explanation.Factors
+		sortFactors(factors)
+		results[i].Explanation.Summary = generateSummary(msgs, factors, results[i].FinalScore, scale, resolvedSchema.Scoring.SummaryFactors()) +
+			coverageNote(msgs, results[i].Explanation.Coverage, len(factors))
+	}
+
+	return results, nil
+}
+
+// rankMerit orders a field's values best first when sorted descending: the
+// value itself for maximize, its negation for minimize, and its negated
+// distance from the target for target.
+func rankMerit(value float64, fieldDef schema.FieldDef) float64 {
+	switch fieldDef.Direction {
+	case schema.DirectionMinimize:
+		return -value
+	case schema.DirectionTarget:
+		if fieldDef.Target != nil {
+			return -math.Abs(value - *fieldDef.Target)
+		}
+	}
+	return value
+}
+
+// rankDirection returns the explanation direction label for a field.
+func rankDirection(direction schema.Direction) string {
+	switch direction {
+	case schema.DirectionMinimize:
+		return "minimize"
+	case schema.DirectionTarget:
+		return "target"
+	}
+	return "maximize"
+}
+
+// rankReason describes a site's position on a field in the catalog's
+// language, e.g. "3rd of 120 on population (higher is better)".
+func rankReason(msgs *i18n.Catalog, fieldName string, rank, total int, direction schema.Direction) string {
+	better := msgs.HigherIsBetter
+	switch direction {
+	case schema.DirectionMinimize:
+		better = msgs.LowerIsBetter
+	case schema.DirectionTarget:
+		better = msgs.TargetIsBest
+	}
+	return fmt.Sprintf(msgs.RankReason,
+		msgs.Ordinal(rank), total, strings.ReplaceAll(fieldName, "_", " "), better)
+}
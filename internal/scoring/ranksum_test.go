@@ -0,0 +1,205 @@
+package scoring
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/i18n"
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
+)
+
+// skewedSchema has one population field whose bounds are stretched by an outlier.
+func skewedSchema(mode schema.ScoringMode) *schema.ResolvedSchema {
+	min, max := 0.0, 1000000.0
+	return &schema.ResolvedSchema{
+		SiteIDColumn: "site_id",
+		Fields: map[string]schema.FieldDef{
+			"population": {
+				Type:      schema.TypePopulation,
+				Min:       &min,
+				Max:       &max,
+				Weight:    1.0,
+				Direction: schema.DirectionMaximize,
+			},
+		},
+		Weights: map[string]float64{"population": 1.0},
+		Scoring: schema.ScoringOptions{Mode: mode},
+	}
+}
+
+func skewedSites() []map[string]interface{} {
+	return []map[string]interface{}{
+		{"site_id": "A", "population": 1000.0},
+		{"site_id": "B", "population": 2000.0},
+		{"site_id": "C", "population": 3000.0},
+		{"site_id": "D", "population": 4000.0},
+		{"site_id": "E", "population": 1000000.0}, // outlier
+	}
+}
+
+func TestRankSumScore_SkewedVersusLinear(t *testing.T) {
+	sites := skewedSites()
+
+	// Linear: the outlier compresses every other site to under 1 point
+	linear := skewedSchema(schema.ModeLinear)
+	for _, site := range sites[:4] {
+		_, finalScore, _, err := DefaultScoreFunc(site, linear)
+		require.NoError(t, err)
+		assert.Less(t, finalScore, 1.0)
+	}
+
+	// Rank-sum: sites are spread evenly by order alone
+	results, err := RankSumScore(sites, skewedSchema(schema.ModeRankSum))
+	require.NoError(t, err)
+	require.Len(t, results, 5)
+
+	expected := []float64{0, 25, 50, 75, 100}
+	for i, result := range results {
+		assert.InDelta(t, expected[i], result.FinalScore, 1e-9, "site %s", sites[i]["site_id"])
+	}
+
+	// Ordering is the same under both models
+	for i := 1; i < len(results); i++ {
+		assert.Greater(t, results[i].FinalScore, results[i-1].FinalScore)
+	}
+}
+
+func TestRankSumScore_ExplanationReportsRank(t *testing.T) {
+	results, err := RankSumScore(skewedSites(), skewedSchema(schema.ModeRankSum))
+	require.NoError(t, err)
+
+	require.Len(t, results[2].Explanation.Factors, 1)
+	assert.Equal(t, "3rd of 5 on population (higher is better)", results[2].Explanation.Factors[0].Reason)
+	assert.Equal(t, "1st of 5 on population (higher is better)", results[4].Explanation.Factors[0].Reason)
+	assert.Equal(t, 3000.0, results[2].Explanation.Factors[0].Value)
+	assert.Contains(t, results[2].Explanation.Summary, "Final score is 50.0.")
+}
+
+func TestRankSumScore_CategoryWeights(t *testing.T) {
+	resolved := categorySchema(t)
+	resolved.Scoring.Mode = schema.ModeRankSum
+
+	results, err := RankSumScore([]map[string]interface{}{
+		{"site_id": "A", "wage": 10.0, "rent": 90.0, "labor_pool": 20.0},
+		{"site_id": "B", "wage": 50.0, "rent": 50.0, "labor_pool": 90.0},
+		{"site_id": "C", "wage": 90.0, "rent": 10.0},
+	}, resolved)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	// A: Economic = (1*1 + 0*3) / 4 = 0.25, Talent = 0 (2nd of 2)
+	// raw = 2*0.25 + 1*0; final = raw / 3 * 100
+	assert.InDelta(t, 0.5, results[0].RawScore, 1e-9)
+	assert.InDelta(t, 50.0/3, results[0].FinalScore, 1e-9)
+	require.Len(t, results[0].Explanation.Categories, 2)
+	assert.Equal(t, models.CategoryScore{Name: "Economic", Weight: 2, Score: 25, Contribution: 0.5}, roundCategory(results[0].Explanation.Categories[0]))
+	assert.Equal(t, models.CategoryScore{Name: "Talent", Weight: 1, Score: 0, Contribution: 0}, roundCategory(results[0].Explanation.Categories[1]))
+	assert.Equal(t, "Economic: 25, Talent: 0", results[0].Explanation.CategorySummary)
+
+	// B: Economic = (0.5*1 + 0.5*3) / 4 = 0.5, Talent = 1
+	assert.InDelta(t, 200.0/3, results[1].FinalScore, 1e-9)
+	assert.Equal(t, map[string]float64{"Economic": 50, "Talent": 100}, roundScores(results[1].Explanation.CategoryScores))
+
+	// C has no labor_pool, so only Economic scores: (0*1 + 1*3) / 4 = 0.75
+	assert.InDelta(t, 75.0, results[2].FinalScore, 1e-9)
+	require.Len(t, results[2].Explanation.Categories, 1)
+	assert.InDelta(t, 2.0/3, results[2].Explanation.Coverage, 1e-9)
+
+	// Without category weights the same sites score by their flat weighted mean
+	resolved.CategoryWeights = nil
+	results, err = RankSumScore([]map[string]interface{}{
+		{"site_id": "A", "wage": 10.0, "rent": 90.0, "labor_pool": 20.0},
+		{"site_id": "B", "wage": 50.0, "rent": 50.0, "labor_pool": 90.0},
+	}, resolved)
+	require.NoError(t, err)
+	assert.InDelta(t, 20.0, results[0].FinalScore, 1e-9)
+	assert.Empty(t, results[0].Explanation.Categories)
+}
+
+func TestRankSumScore_MinimizeTiesAndMissing(t *testing.T) {
+	resolved := &schema.ResolvedSchema{
+		Fields: map[string]schema.FieldDef{
+			"unemployment": {Type: schema.TypePercentage, Weight: 1.0, Direction: schema.DirectionMinimize},
+		},
+		Weights: map[string]float64{"unemployment": 1.0},
+	}
+	sites := []map[string]interface{}{
+		{"unemployment": 3.0},
+		{"unemployment": 5.0},
+		{"unemployment": 5.0},
+		{"unemployment": 9.0},
+		{}, // no value: not ranked, scores 0
+	}
+
+	results, err := RankSumScore(sites, resolved)
+	require.NoError(t, err)
+
+	assert.InDelta(t, 100.0, results[0].FinalScore, 1e-9)
+	assert.InDelta(t, 50.0, results[1].FinalScore, 1e-9, "tied sites share the middle percentile")
+	assert.InDelta(t, 50.0, results[2].FinalScore, 1e-9)
+	assert.InDelta(t, 0.0, results[3].FinalScore, 1e-9)
+	assert.Equal(t, "2nd of 4 on unemployment (lower is better)", results[2].Explanation.Factors[0].Reason)
+	assert.Empty(t, results[4].Explanation.Factors)
+	assert.Equal(t, 0.0, results[4].FinalScore)
+	assert.Equal(t, 1.0, results[0].Explanation.Coverage)
+	assert.Equal(t, 0.0, results[4].Explanation.Coverage)
+}
+
+func TestRankSumScore_TargetRanksByDistance(t *testing.T) {
+	target := 10.0
+	resolved := &schema.ResolvedSchema{
+		Fields: map[string]schema.FieldDef{
+			"avg_age": {Type: schema.TypeNumeric, Weight: 1.0, Direction: schema.DirectionTarget, Target: &target},
+		},
+		Weights: map[string]float64{"avg_age": 1.0},
+	}
+	sites := []map[string]interface{}{
+		{"avg_age": 30.0},
+		{"avg_age": 10.0},
+		{"avg_age": 8.0},
+		{"avg_age": 12.0}, // same distance as 8: tied
+	}
+
+	results, err := RankSumScore(sites, resolved)
+	require.NoError(t, err)
+
+	assert.InDelta(t, 0.0, results[0].FinalScore, 1e-9)
+	assert.InDelta(t, 100.0, results[1].FinalScore, 1e-9)
+	assert.InDelta(t, 50.0, results[2].FinalScore, 1e-9)
+	assert.InDelta(t, 50.0, results[3].FinalScore, 1e-9)
+	assert.Equal(t, "1st of 4 on avg age (closest to target is best)", results[1].Explanation.Factors[0].Reason)
+	assert.Equal(t, "target", results[1].Explanation.Factors[0].Direction)
+}
+
+func TestOrdinal(t *testing.T) {
+	for n, want := range map[int]string{1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 11: "11th", 12: "12th", 13: "13th", 21: "21st", 102: "102nd", 113: "113th"} {
+		assert.Equal(t, want, i18n.Lookup("en").Ordinal(n))
+	}
+}
+
+func TestPipelineExecute_RankSumMode(t *testing.T) {
+	p, fakes := newTestPipeline([]models.SiteRecord{
+		testSiteRecord("A", 10, 50),
+		testSiteRecord("B", 900, 50),
+		testSiteRecord("C", 500, 50),
+	})
+	run := testRun()
+	run.ScoringConfig = json.RawMessage(`{"mode": "rank_sum"}`)
+
+	require.NoError(t, p.Execute(context.Background(), run))
+
+	require.Len(t, fakes.recs.inserted, 3)
+	ranked := make([]string, len(fakes.recs.inserted))
+	for i, rec := range fakes.recs.inserted {
+		ranked[i] = rec.SiteID
+	}
+	assert.Equal(t, []string{"B", "C", "A"}, ranked)
+	// Population percentiles 1, .5, 0 averaged with a three-way unemployment tie at .5
+	assert.InDelta(t, 75.0, fakes.recs.inserted[0].FinalScore, 1e-9)
+	assert.InDelta(t, 25.0, fakes.recs.inserted[2].FinalScore, 1e-9)
+}
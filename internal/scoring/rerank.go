@@ -0,0 +1,142 @@
+package scoring
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
+)
+
+// DefaultRerankTopN is how many reranked sites are returned when
+// RerankOptions.TopN is unset.
+const DefaultRerankTopN = 50
+
+// RerankOptions configures an in-memory rerank of a finished run.
+type RerankOptions struct {
+	// Weights override the snapshot's field weights; fields not named keep
+	// the weight the run was scored with.
+	Weights map[string]float64
+	// TopN is how many of the reranked sites are returned; zero means
+	// DefaultRerankTopN.
+	TopN int
+	// MaxSites rejects uploads with more site records; zero means no cap.
+	MaxSites int
+}
+
+// RerankedSite is one site's place in a rerank, alongside its rank in the
+// stored run. PreviousRank is nil when the run did not store the site.
+type RerankedSite struct {
+	Rank         int     `json:"rank"`
+	SiteID       string  `json:"site_id"`
+	SiteName     string  `json:"site_name"`
+	FinalScore   float64 `json:"final_score"`
+	RawScore     float64 `json:"raw_score"`
+	PreviousRank *int    `json:"previous_rank"`
+}
+
+// RerankResult is the ranking a run would have produced with different
+// weights. Nothing in it is persisted.
+type RerankResult struct {
+	RunID        uuid.UUID          `json:"run_id"`
+	SnapshotID   uuid.UUID          `json:"snapshot_id"`
+	ModelVersion string             `json:"model_version"`
+	Scorer       string             `json:"scorer"`
+	SiteCount    int                `json:"site_count"`
+	Weights      map[string]float64 `json:"weights"`
+	Sites        []RerankedSite     `json:"sites"`
+}
+
+// Rerank re-scores the run's site records in memory against the resolved
+// schema recorded in snapshot, with opts.Weights layered over its weights,
+// and returns the top opts.TopN of the new ranking. stored supplies each
+// site's rank in the run as scored. Invalid weights are reported as a
+// *schema.ConfigError. Nothing is written.
+func (p *Pipeline) Rerank(
+	ctx context.Context,
+	run *models.ScoringRun,
+	snapshot *models.SchemaConfigSnapshot,
+	stored []models.Recommendation,
+	opts RerankOptions,
+) (*RerankResult, error) {
+	logger := runLogger(run).With(slog.String("step", "rerank"))
+
+	var resolvedSchema schema.ResolvedSchema
+	if err := json.Unmarshal(snapshot.SnapshotData, &resolvedSchema); err != nil {
+		return nil, fmt.Errorf("parse snapshot %s: %w", snapshot.ID, err)
+	}
+	if err := resolvedSchema.ValidateWeights(opts.Weights); err != nil {
+		return nil, err
+	}
+	if resolvedSchema.Weights == nil {
+		resolvedSchema.Weights = make(map[string]float64, len(opts.Weights))
+	}
+	for name, weight := range opts.Weights {
+		resolvedSchema.Weights[name] = weight
+	}
+
+	model, err := p.models.Lookup(run.ModelVersion)
+	if err != nil {
+		return nil, err
+	}
+	scorerName, scoreFunc, err := p.resolveScorer(run, model)
+	if err != nil {
+		return nil, err
+	}
+
+	siteRecords, err := p.siteRecordRepo.GetByUpload(ctx, run.UploadID)
+	if err != nil {
+		return nil, fmt.Errorf("fetch site records: %w", err)
+	}
+	if opts.MaxSites > 0 && len(siteRecords) > opts.MaxSites {
+		return nil, fmt.Errorf("%w: upload has %d, limit is %d", ErrTooManySites, len(siteRecords), opts.MaxSites)
+	}
+
+	parsed := parseSites(siteRecords, &resolvedSchema, nil, logger)
+	results, err := scoreSites(ctx, parsed, &resolvedSchema, scoreFunc, nil, logger)
+	if err != nil {
+		return nil, err
+	}
+	ranked := rankScores(results, &resolvedSchema)
+
+	previous := make(map[string]int, len(stored))
+	for _, rec := range stored {
+		previous[rec.SiteID] = rec.Ranking
+	}
+
+	topN := opts.TopN
+	if topN <= 0 {
+		topN = DefaultRerankTopN
+	}
+	topN = min(topN, len(ranked))
+
+	result := &RerankResult{
+		RunID:        run.ID,
+		SnapshotID:   snapshot.ID,
+		ModelVersion: model.Version,
+		Scorer:       scorerName,
+		SiteCount:    len(ranked),
+		Weights:      resolvedSchema.Weights,
+		Sites:        make([]RerankedSite, topN),
+	}
+	for i, site := range ranked[:topN] {
+		result.Sites[i] = RerankedSite{
+			Rank:       site.rec.Ranking,
+			SiteID:     site.rec.SiteID,
+			SiteName:   site.rec.SiteName,
+			FinalScore: site.rec.FinalScore,
+			RawScore:   site.rec.RawScore,
+		}
+		if rank, ok := previous[site.rec.SiteID]; ok {
+			result.Sites[i].PreviousRank = &rank
+		}
+	}
+
+	logger.Info("run reranked",
+		slog.Int("site_count", result.SiteCount),
+		slog.Int("weight_overrides", len(opts.Weights)))
+	return result, nil
+}
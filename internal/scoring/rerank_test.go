@@ -0,0 +1,85 @@
+package scoring
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
+)
+
+// rerankOrder returns the site IDs of a rerank in rank order.
+func rerankOrder(r *RerankResult) []string {
+	order := make([]string, len(r.Sites))
+	for i, site := range r.Sites {
+		order[i] = site.SiteID
+	}
+	return order
+}
+
+func TestPipelineRerank_WeightChangeReordersSites(t *testing.T) {
+	p, fakes := newTestPipeline(verifyTestRecords())
+	run := testRun()
+	require.NoError(t, p.Execute(context.Background(), run))
+	require.Len(t, fakes.configs.snapshots, 1)
+	snapshot := fakes.configs.snapshots[0]
+	inserted := len(fakes.recs.inserted)
+
+	baseline, err := p.Rerank(context.Background(), run, snapshot, fakes.recs.inserted, RerankOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"A", "C", "D", "B"}, rerankOrder(baseline), "no overrides reproduces the run")
+	for _, site := range baseline.Sites {
+		require.NotNil(t, site.PreviousRank)
+		assert.Equal(t, site.Rank, *site.PreviousRank)
+	}
+
+	reranked, err := p.Rerank(context.Background(), run, snapshot, fakes.recs.inserted, RerankOptions{
+		Weights: map[string]float64{"population": 10},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"D", "A", "C", "B"}, rerankOrder(reranked))
+	assert.Equal(t, 4, reranked.SiteCount)
+	assert.Equal(t, 10.0, reranked.Weights["population"])
+	assert.Equal(t, 1.0, reranked.Weights["unemployment"], "unnamed weights are kept")
+	require.NotNil(t, reranked.Sites[0].PreviousRank)
+	assert.Equal(t, 1, reranked.Sites[0].Rank)
+	assert.Equal(t, 3, *reranked.Sites[0].PreviousRank)
+	assert.Len(t, fakes.recs.inserted, inserted, "rerank must not persist anything")
+}
+
+func TestPipelineRerank_TopNAndPreviousRank(t *testing.T) {
+	p, fakes := newTestPipeline(verifyTestRecords())
+	run := testRun()
+	require.NoError(t, p.Execute(context.Background(), run))
+
+	// As if the run stored only its top two
+	stored := fakes.recs.inserted[:2]
+	r, err := p.Rerank(context.Background(), run, fakes.configs.snapshots[0], stored, RerankOptions{TopN: 3})
+	require.NoError(t, err)
+
+	require.Len(t, r.Sites, 3)
+	assert.Equal(t, 4, r.SiteCount)
+	assert.NotNil(t, r.Sites[1].PreviousRank)
+	assert.Nil(t, r.Sites[2].PreviousRank, "sites the run did not store have no previous rank")
+}
+
+func TestPipelineRerank_Rejections(t *testing.T) {
+	p, fakes := newTestPipeline(verifyTestRecords())
+	run := testRun()
+	require.NoError(t, p.Execute(context.Background(), run))
+	snapshot := fakes.configs.snapshots[0]
+
+	_, err := p.Rerank(context.Background(), run, snapshot, nil, RerankOptions{MaxSites: 3})
+	assert.True(t, errors.Is(err, ErrTooManySites))
+
+	_, err = p.Rerank(context.Background(), run, snapshot, nil, RerankOptions{
+		Weights: map[string]float64{"nope": 1},
+	})
+	var cfgErr *schema.ConfigError
+	require.ErrorAs(t, err, &cfgErr)
+	assert.Equal(t, "weights.nope", cfgErr.Field)
+}
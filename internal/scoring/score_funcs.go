@@ -0,0 +1,138 @@
+package scoring
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/workforce-ai/site-selection-iq/internal/i18n"
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
+)
+
+// Built-in scorer names. A run selects one with scoring_config "scorer",
+// falling back to its model's scorer and then DefaultScorer.
+const (
+	// ScorerWeightedMean is DefaultScoreFunc.
+	ScorerWeightedMean = "weighted_mean"
+	// ScorerGeometricMean is GeometricMeanScoreFunc.
+	ScorerGeometricMean = "geometric_mean"
+
+	// DefaultScorer is used when neither the run nor its model names one.
+	DefaultScorer = ScorerWeightedMean
+)
+
+// ScoreFuncRegistry maps scorer names to ScoreFunc implementations. It is
+// safe for concurrent use.
+type ScoreFuncRegistry struct {
+	mu    sync.RWMutex
+	funcs map[string]ScoreFunc
+}
+
+// NewScoreFuncRegistry creates a registry holding the built-in scorers.
+func NewScoreFuncRegistry() *ScoreFuncRegistry {
+	return &ScoreFuncRegistry{
+		funcs: map[string]ScoreFunc{
+			ScorerWeightedMean:  DefaultScoreFunc,
+			ScorerGeometricMean: GeometricMeanScoreFunc,
+		},
+	}
+}
+
+// Register adds fn under name, replacing any scorer already registered
+// there (including a built-in).
+func (r *ScoreFuncRegistry) Register(name string, fn ScoreFunc) error {
+	if name == "" {
+		return fmt.Errorf("scorer name must not be empty")
+	}
+	if fn == nil {
+		return fmt.Errorf("scorer %q has no score function", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.funcs[name] = fn
+	return nil
+}
+
+// Lookup returns the scorer registered under name. Empty selects
+// DefaultScorer.
+func (r *ScoreFuncRegistry) Lookup(name string) (ScoreFunc, error) {
+	if name == "" {
+		name = DefaultScorer
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	fn, ok := r.funcs[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownScorer, name)
+	}
+	return fn, nil
+}
+
+// Names returns the registered scorer names, sorted.
+func (r *ScoreFuncRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.funcs))
+	for name := range r.funcs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GeometricMeanScoreFunc scores a site by the weighted geometric mean of its
+// normalized factor values instead of the arithmetic mean. Strength in one
+// factor cannot make up for weakness in another: a site at the bottom of
+// any weighted factor scores 0. Factors, explanations and coverage are as
+// for DefaultScoreFunc; the raw score is the mean on a 0-1 scale.
+func GeometricMeanScoreFunc(
+	siteData map[string]interface{},
+	resolvedSchema *schema.ResolvedSchema,
+) (rawScore float64, finalScore float64, explanation models.Explanation, err error) {
+	_, _, explanation, err = DefaultScoreFunc(siteData, resolvedSchema)
+	if err != nil {
+		return 0, 0, explanation, err
+	}
+	// The geometric mean is taken over all factors; category subscores do
+	// not apply
+	explanation.Categories = nil
+	explanation.CategoryScores = nil
+	explanation.CategorySummary = ""
+
+	var logSum, totalWeight float64
+	for _, f := range explanation.Factors {
+		if f.Weight <= 0 {
+			continue
+		}
+		normalized := f.Contribution / f.Weight
+		if normalized <= 0 {
+			logSum = math.Inf(-1)
+			totalWeight += f.Weight
+			continue
+		}
+		logSum += f.Weight * math.Log(normalized)
+		totalWeight += f.Weight
+	}
+
+	if totalWeight > 0 {
+		rawScore = math.Exp(logSum / totalWeight)
+	}
+	if !isFinite(rawScore) {
+		rawScore = 0
+	}
+
+	scale := resolvedSchema.Scoring.ScoreScale
+	finalScore = math.Max(0, math.Min(scale.Max(), rawScore*scale.Max()))
+
+	msgs := i18n.Lookup(resolvedSchema.Scoring.Locale)
+	explanation.Summary = generateSummary(msgs, explanation.Factors, finalScore, scale, resolvedSchema.Scoring.SummaryFactors()) +
+		coverageNote(msgs, explanation.Coverage, len(explanation.Factors))
+
+	return rawScore, finalScore, explanation, nil
+}
@@ -0,0 +1,69 @@
+package scoring
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
+)
+
+func TestScoreFuncRegistry_Builtins(t *testing.T) {
+	r := NewScoreFuncRegistry()
+
+	assert.Equal(t, []string{ScorerGeometricMean, ScorerWeightedMean}, r.Names())
+
+	fn, err := r.Lookup("")
+	require.NoError(t, err)
+	assert.NotNil(t, fn)
+
+	_, err = r.Lookup("median")
+	assert.ErrorIs(t, err, ErrUnknownScorer)
+}
+
+func TestScoreFuncRegistry_RegisterValidates(t *testing.T) {
+	r := NewScoreFuncRegistry()
+	assert.Error(t, r.Register("", DefaultScoreFunc))
+	assert.Error(t, r.Register("nil", nil))
+	require.NoError(t, r.Register("custom", DefaultScoreFunc))
+	assert.Contains(t, r.Names(), "custom")
+}
+
+func geometricTestSchema() *schema.ResolvedSchema {
+	zero, hundred := 0.0, 100.0
+	return &schema.ResolvedSchema{
+		Fields: map[string]schema.FieldDef{
+			"a": {Type: schema.TypeNumeric, Weight: 1, Min: &zero, Max: &hundred, Direction: schema.DirectionMaximize},
+			"b": {Type: schema.TypeNumeric, Weight: 1, Min: &zero, Max: &hundred, Direction: schema.DirectionMaximize},
+		},
+		Weights: map[string]float64{"a": 1, "b": 1},
+	}
+}
+
+func TestGeometricMeanScoreFunc(t *testing.T) {
+	resolved := geometricTestSchema()
+	siteData := map[string]interface{}{"a": 100.0, "b": 25.0}
+
+	rawScore, finalScore, explanation, err := GeometricMeanScoreFunc(siteData, resolved)
+	require.NoError(t, err)
+
+	// sqrt(1.0 * 0.25) = 0.5, below the arithmetic mean of 0.625
+	assert.InDelta(t, 0.5, rawScore, 1e-9)
+	assert.InDelta(t, 50.0, finalScore, 1e-9)
+	assert.Len(t, explanation.Factors, 2)
+	assert.Contains(t, explanation.Summary, "Final score is 50.0.")
+
+	_, linearScore, _, err := DefaultScoreFunc(siteData, resolved)
+	require.NoError(t, err)
+	assert.InDelta(t, 62.5, linearScore, 1e-9)
+}
+
+func TestGeometricMeanScoreFunc_ZeroFactorZeroesScore(t *testing.T) {
+	rawScore, finalScore, _, err := GeometricMeanScoreFunc(map[string]interface{}{"a": 100.0, "b": 0.0}, geometricTestSchema())
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, rawScore)
+	assert.Equal(t, 0.0, finalScore)
+	assert.False(t, math.IsNaN(finalScore))
+}
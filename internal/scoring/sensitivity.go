@@ -0,0 +1,283 @@
+package scoring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
+)
+
+// ErrTooManySites is wrapped by Sensitivity and Rerank when an upload has
+// more sites than they will score in memory.
+var ErrTooManySites = errors.New("too many sites to score in memory")
+
+// Defaults for SensitivityOptions fields left unset.
+const (
+	DefaultPerturbation    = 0.1
+	DefaultSensitivityTopN = 10
+)
+
+// SensitivityOptions configures a weight sensitivity analysis.
+type SensitivityOptions struct {
+	// Perturbation is the fraction each weight is raised and lowered by,
+	// e.g. 0.1 for ±10%. Must be in (0, 1); zero means DefaultPerturbation.
+	Perturbation float64
+	// TopN is how many of the baseline's best sites are compared; zero
+	// means DefaultSensitivityTopN.
+	TopN int
+	// MaxSites rejects uploads with more site records; zero means no cap.
+	MaxSites int
+}
+
+// PerturbationResult compares the top-N ranking after one weight change with
+// the baseline.
+type PerturbationResult struct {
+	Weight float64 `json:"weight"`
+	// RankCorrelation is Spearman's rho between the baseline top-N sites'
+	// baseline order and their order after the change (1 = unchanged).
+	RankCorrelation float64 `json:"rank_correlation"`
+	// TopNOverlap is the share of the baseline top N still in the top N.
+	TopNOverlap float64 `json:"top_n_overlap"`
+}
+
+// FieldSensitivity reports how the top-N ranking responds to one field's
+// weight being raised and lowered.
+type FieldSensitivity struct {
+	Field  string             `json:"field"`
+	Weight float64            `json:"weight"`
+	Up     PerturbationResult `json:"up"`
+	Down   PerturbationResult `json:"down"`
+	// Sensitivity averages, over both changes, the top N's reordering
+	// ((1 - rank_correlation) / 2) and turnover (1 - top_n_overlap), giving
+	// a value in [0, 1] where 0 means the top N kept its members and order.
+	Sensitivity float64 `json:"sensitivity"`
+}
+
+// SensitivityReport is the result of a weight sensitivity analysis.
+type SensitivityReport struct {
+	UploadID     uuid.UUID          `json:"upload_id"`
+	ModelVersion string             `json:"model_version"`
+	Scorer       string             `json:"scorer"`
+	SiteCount    int                `json:"site_count"`
+	TopN         int                `json:"top_n"`
+	Perturbation float64            `json:"perturbation"`
+	Fields       []FieldSensitivity `json:"fields"`
+}
+
+// Sensitivity scores the upload of run as a dry run, without persisting
+// anything, then rescores it with each weighted field's weight raised and
+// lowered by opts.Perturbation and reports how much the top-N ranking moves.
+// run describes the hypothetical run (tenant, upload, model version and
+// scoring_config); it need not exist. Fields are ordered most sensitive
+// first.
+func (p *Pipeline) Sensitivity(ctx context.Context, run *models.ScoringRun, opts SensitivityOptions) (*SensitivityReport, error) {
+	if opts.Perturbation == 0 {
+		opts.Perturbation = DefaultPerturbation
+	}
+	if opts.Perturbation <= 0 || opts.Perturbation >= 1 {
+		return nil, fmt.Errorf("perturbation must be between 0 and 1, got %g", opts.Perturbation)
+	}
+	if opts.TopN <= 0 {
+		opts.TopN = DefaultSensitivityTopN
+	}
+	logger := runLogger(run).With(slog.String("step", "sensitivity"))
+
+	model, err := p.models.Lookup(run.ModelVersion)
+	if err != nil {
+		return nil, err
+	}
+	scorerName, scoreFunc, err := p.resolveScorer(run, model)
+	if err != nil {
+		return nil, err
+	}
+	resolvedSchema, err := p.resolveDryRunSchema(ctx, run, model)
+	if err != nil {
+		return nil, err
+	}
+
+	siteRecords, err := p.siteRecordRepo.GetByUpload(ctx, run.UploadID)
+	if err != nil {
+		return nil, fmt.Errorf("fetch site records: %w", err)
+	}
+	if opts.MaxSites > 0 && len(siteRecords) > opts.MaxSites {
+		return nil, fmt.Errorf("%w: upload has %d, limit is %d", ErrTooManySites, len(siteRecords), opts.MaxSites)
+	}
+
+	parsed := parseSites(siteRecords, resolvedSchema, nil, logger)
+	if resolvedSchema.Scoring.Mode != schema.ModeRankSum && resolvedSchema.Scoring.DerivesBounds() {
+		data := make([]map[string]interface{}, len(parsed))
+		for i, site := range parsed {
+			data[i] = site.data
+		}
+		resolvedSchema.DerivedBounds = deriveBounds(data, resolvedSchema)
+	}
+
+	rank := func(s *schema.ResolvedSchema) ([]string, error) {
+		results, err := scoreSites(ctx, parsed, s, scoreFunc, nil, logger)
+		if err != nil {
+			return nil, err
+		}
+		ranked := rankScores(results, s)
+		order := make([]string, len(ranked))
+		for i, site := range ranked {
+			order[i] = site.rec.SiteID
+		}
+		return order, nil
+	}
+
+	baseline, err := rank(resolvedSchema)
+	if err != nil {
+		return nil, err
+	}
+	topN := min(opts.TopN, len(baseline))
+
+	report := &SensitivityReport{
+		UploadID:     run.UploadID,
+		ModelVersion: model.Version,
+		Scorer:       scorerName,
+		SiteCount:    len(baseline),
+		TopN:         topN,
+		Perturbation: opts.Perturbation,
+		Fields:       []FieldSensitivity{},
+	}
+	if topN == 0 {
+		return report, nil
+	}
+
+	for _, field := range weightedFields(resolvedSchema) {
+		weight := resolvedSchema.Weights[field]
+		fs := FieldSensitivity{Field: field, Weight: weight}
+
+		for _, dir := range []struct {
+			result *PerturbationResult
+			factor float64
+		}{
+			{&fs.Up, 1 + opts.Perturbation},
+			{&fs.Down, 1 - opts.Perturbation},
+		} {
+			perturbed := withWeight(resolvedSchema, field, weight*dir.factor)
+			order, err := rank(perturbed)
+			if err != nil {
+				return nil, err
+			}
+			*dir.result = compareTopN(baseline, order, topN)
+			dir.result.Weight = weight * dir.factor
+		}
+
+		fs.Sensitivity = (topNChange(fs.Up) + topNChange(fs.Down)) / 2
+		report.Fields = append(report.Fields, fs)
+	}
+
+	sort.SliceStable(report.Fields, func(i, j int) bool {
+		if report.Fields[i].Sensitivity != report.Fields[j].Sensitivity {
+			return report.Fields[i].Sensitivity > report.Fields[j].Sensitivity
+		}
+		return report.Fields[i].Field < report.Fields[j].Field
+	})
+
+	logger.Info("sensitivity analysis completed",
+		slog.Int("site_count", report.SiteCount),
+		slog.Int("field_count", len(report.Fields)))
+	return report, nil
+}
+
+// resolveDryRunSchema resolves the schema run would be scored with: the
+// active global config, the tenant override, model defaults and the run's
+// scoring_config, in that order.
+func (p *Pipeline) resolveDryRunSchema(ctx context.Context, run *models.ScoringRun, model Model) (*schema.ResolvedSchema, error) {
+	resolution, err := p.schemaResolver.ResolveActive(ctx, p.schemaConfigRepo, run.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	resolvedSchema := resolution.Schema
+	if err := resolvedSchema.ApplyModelDefaults(model.Options, model.Weights); err != nil {
+		return nil, err
+	}
+	if err := resolvedSchema.ApplyRunConfig(run.ScoringConfig); err != nil {
+		return nil, err
+	}
+	return resolvedSchema, nil
+}
+
+// weightedFields returns, sorted, the fields that contribute to scores: the
+// numeric fields with a non-zero weight.
+func weightedFields(s *schema.ResolvedSchema) []string {
+	var fields []string
+	for name, fieldDef := range s.Fields {
+		if isNumericFieldType(fieldDef.Type) && fieldDef.Weight != 0 && s.Weights[name] != 0 {
+			fields = append(fields, name)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// withWeight returns a shallow copy of s with field's weight replaced.
+func withWeight(s *schema.ResolvedSchema, field string, weight float64) *schema.ResolvedSchema {
+	perturbed := *s
+	perturbed.Weights = make(map[string]float64, len(s.Weights))
+	for name, w := range s.Weights {
+		perturbed.Weights[name] = w
+	}
+	perturbed.Weights[field] = weight
+	return &perturbed
+}
+
+// compareTopN compares the first n sites of baseline with their positions in
+// perturbed. The rank correlation is taken over the baseline top-n sites,
+// ranked among themselves in each order.
+func compareTopN(baseline, perturbed []string, n int) PerturbationResult {
+	position := make(map[string]int, len(perturbed))
+	for i, id := range perturbed {
+		position[id] = i
+	}
+
+	top := baseline[:n]
+	retained := 0
+	for _, id := range top {
+		if pos, ok := position[id]; ok && pos < n {
+			retained++
+		}
+	}
+
+	// Order the baseline top n by their perturbed positions; a site's index
+	// in that order is its perturbed rank among them
+	reordered := append([]string(nil), top...)
+	sort.SliceStable(reordered, func(i, j int) bool {
+		return position[reordered[i]] < position[reordered[j]]
+	})
+	perturbedRank := make(map[string]int, n)
+	for i, id := range reordered {
+		perturbedRank[id] = i
+	}
+
+	rho := 1.0
+	if n > 1 {
+		var sumSq float64
+		for i, id := range top {
+			d := float64(i - perturbedRank[id])
+			sumSq += d * d
+		}
+		nf := float64(n)
+		rho = 1 - 6*sumSq/(nf*(nf*nf-1))
+	}
+
+	return PerturbationResult{
+		RankCorrelation: rho,
+		TopNOverlap:     float64(retained) / float64(n),
+	}
+}
+
+// topNChange combines a perturbation's reordering and membership change
+// into [0, 1].
+func topNChange(r PerturbationResult) float64 {
+	reorder := (1 - r.RankCorrelation) / 2
+	turnover := 1 - r.TopNOverlap
+	return math.Max(0, math.Min(1, (reorder+turnover)/2))
+}
@@ -0,0 +1,103 @@
+package scoring
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+)
+
+// sensitivityGlobalConfig weights labor_pool far above the other two fields.
+const sensitivityGlobalConfig = `{
+	"site_id_column": "site_id",
+	"fields": {
+		"site_id": {"type": "identifier", "required": true},
+		"labor_pool": {"type": "numeric", "min": 0, "max": 100, "weight": 6.0, "direction": "maximize"},
+		"rent": {"type": "numeric", "min": 0, "max": 100, "weight": 1.0, "direction": "minimize"},
+		"transit": {"type": "numeric", "min": 0, "max": 100, "weight": 1.0, "direction": "maximize"}
+	}
+}`
+
+func sensitivityRecords(n int) []models.SiteRecord {
+	records := make([]models.SiteRecord, n)
+	for i := range records {
+		siteID := fmt.Sprintf("S%02d", i)
+		// Deterministic spread where strong labor pools come with high rent
+		// and weak transit, so the weights trade off against each other
+		laborPool := float64((i * 37) % 100)
+		data, _ := json.Marshal(map[string]interface{}{
+			"site_id":    siteID,
+			"labor_pool": laborPool,
+			"rent":       float64((int(laborPool)*3+i*11)%100) * 0.9,
+			"transit":    float64((i*53)%100) * (1 - laborPool/200),
+		})
+		records[i] = models.SiteRecord{ID: uuid.New(), SiteID: siteID, Data: data}
+	}
+	return records
+}
+
+func newSensitivityPipeline(records []models.SiteRecord) *Pipeline {
+	p, fakes := newTestPipeline(records)
+	fakes.configs.global.Config = json.RawMessage(sensitivityGlobalConfig)
+	return p
+}
+
+func TestPipelineSensitivity_DominantFieldMostSensitive(t *testing.T) {
+	p := newSensitivityPipeline(sensitivityRecords(40))
+
+	report, err := p.Sensitivity(context.Background(), testRun(), SensitivityOptions{Perturbation: 0.5, TopN: 10})
+	require.NoError(t, err)
+
+	assert.Equal(t, 40, report.SiteCount)
+	assert.Equal(t, 10, report.TopN)
+	require.Len(t, report.Fields, 3)
+
+	byField := make(map[string]FieldSensitivity)
+	for _, fs := range report.Fields {
+		byField[fs.Field] = fs
+		assert.InDelta(t, fs.Weight*1.5, fs.Up.Weight, 1e-9)
+		assert.InDelta(t, fs.Weight*0.5, fs.Down.Weight, 1e-9)
+		assert.GreaterOrEqual(t, fs.Sensitivity, 0.0)
+		assert.LessOrEqual(t, fs.Sensitivity, 1.0)
+	}
+
+	assert.Equal(t, "labor_pool", report.Fields[0].Field, "fields are ordered most sensitive first")
+	assert.Positive(t, byField["labor_pool"].Sensitivity)
+	assert.Greater(t, byField["labor_pool"].Sensitivity, byField["rent"].Sensitivity)
+	assert.Greater(t, byField["labor_pool"].Sensitivity, byField["transit"].Sensitivity)
+}
+
+func TestPipelineSensitivity_RejectsOversizedUpload(t *testing.T) {
+	p := newSensitivityPipeline(sensitivityRecords(5))
+
+	_, err := p.Sensitivity(context.Background(), testRun(), SensitivityOptions{MaxSites: 4})
+	assert.True(t, errors.Is(err, ErrTooManySites))
+
+	_, err = p.Sensitivity(context.Background(), testRun(), SensitivityOptions{Perturbation: 1.5})
+	assert.ErrorContains(t, err, "perturbation")
+}
+
+func TestCompareTopN(t *testing.T) {
+	baseline := []string{"A", "B", "C", "D", "E"}
+
+	same := compareTopN(baseline, baseline, 3)
+	assert.Equal(t, 1.0, same.RankCorrelation)
+	assert.Equal(t, 1.0, same.TopNOverlap)
+	assert.Zero(t, topNChange(same))
+
+	reversed := compareTopN(baseline, []string{"C", "B", "A", "D", "E"}, 3)
+	assert.Equal(t, -1.0, reversed.RankCorrelation)
+	assert.Equal(t, 1.0, reversed.TopNOverlap)
+
+	// D displaces C from the top 3; A and B keep their order
+	displaced := compareTopN(baseline, []string{"A", "B", "D", "C", "E"}, 3)
+	assert.Equal(t, 1.0, displaced.RankCorrelation)
+	assert.InDelta(t, 2.0/3.0, displaced.TopNOverlap, 1e-9)
+}
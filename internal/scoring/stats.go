@@ -0,0 +1,47 @@
+package scoring
+
+import (
+	"math"
+	"sort"
+
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+)
+
+// computeRunStats summarizes the final scores of a run's recommendations.
+// It returns nil when there are no recommendations. StdDev is the population
+// standard deviation.
+func computeRunStats(recs []models.Recommendation) *models.RunStats {
+	if len(recs) == 0 {
+		return nil
+	}
+
+	scores := make([]float64, len(recs))
+	sum := 0.0
+	for i, rec := range recs {
+		scores[i] = rec.FinalScore
+		sum += rec.FinalScore
+	}
+	sort.Float64s(scores)
+
+	n := len(scores)
+	mean := sum / float64(n)
+
+	median := scores[n/2]
+	if n%2 == 0 {
+		median = (scores[n/2-1] + scores[n/2]) / 2
+	}
+
+	variance := 0.0
+	for _, s := range scores {
+		variance += (s - mean) * (s - mean)
+	}
+	variance /= float64(n)
+
+	return &models.RunStats{
+		Min:    scores[0],
+		Max:    scores[n-1],
+		Mean:   mean,
+		Median: median,
+		StdDev: math.Sqrt(variance),
+	}
+}
@@ -0,0 +1,19 @@
+package scoring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+)
+
+func TestComputeRunStats(t *testing.T) {
+	assert.Nil(t, computeRunStats(nil))
+
+	single := computeRunStats([]models.Recommendation{{FinalScore: 42}})
+	assert.Equal(t, &models.RunStats{Min: 42, Max: 42, Mean: 42, Median: 42, StdDev: 0}, single)
+
+	odd := computeRunStats([]models.Recommendation{{FinalScore: 90}, {FinalScore: 10}, {FinalScore: 50}})
+	assert.Equal(t, 50.0, odd.Median)
+}
@@ -0,0 +1,74 @@
+package scoring
+
+import (
+	"math"
+
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
+)
+
+// scoreInterval is the range a site's final score may fall in given the
+// uncertainty of its inputs.
+type scoreInterval struct {
+	low, high float64
+}
+
+// intervalFor propagates the ± error of every weighted field with an
+// uncertainty column into an interval around finalScore. Each field's
+// effect is half the change in score across value - error to value + error;
+// effects are combined in quadrature, as for independent errors on a
+// weighted sum. It returns nil when no field of the site has an error.
+func intervalFor(
+	siteData map[string]interface{},
+	resolvedSchema *schema.ResolvedSchema,
+	scoreFunc ScoreFunc,
+	finalScore float64,
+) *scoreInterval {
+	var variance float64
+	var perturbed map[string]interface{}
+	for _, fieldName := range sortedFieldNames(resolvedSchema) {
+		fieldDef := resolvedSchema.Fields[fieldName]
+		if fieldDef.UncertaintyColumn == "" || resolvedSchema.Weights[fieldName] == 0 {
+			continue
+		}
+		value, err := toFloat64(siteData[fieldName])
+		if err != nil {
+			continue
+		}
+		spread, err := toFloat64(siteData[fieldDef.UncertaintyColumn])
+		if err != nil || spread == 0 {
+			continue
+		}
+		spread = math.Abs(spread)
+
+		if perturbed == nil {
+			perturbed = make(map[string]interface{}, len(siteData))
+			for k, v := range siteData {
+				perturbed[k] = v
+			}
+		}
+		lower := value - spread
+		if fieldDef.Transform.Clamps(lower) {
+			lower = 0
+		}
+		perturbed[fieldName] = value + spread
+		_, upScore, _, errUp := scoreFunc(perturbed, resolvedSchema)
+		perturbed[fieldName] = lower
+		_, downScore, _, errDown := scoreFunc(perturbed, resolvedSchema)
+		perturbed[fieldName] = siteData[fieldName]
+		if errUp != nil || errDown != nil {
+			continue
+		}
+
+		effect := (upScore - downScore) / 2
+		variance += effect * effect
+	}
+	if perturbed == nil {
+		return nil
+	}
+
+	margin := math.Sqrt(variance)
+	return &scoreInterval{
+		low:  math.Max(0, finalScore-margin),
+		high: math.Min(resolvedSchema.Scoring.ScoreScale.Max(), finalScore+margin),
+	}
+}
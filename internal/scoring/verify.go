@@ -0,0 +1,156 @@
+package scoring
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
+)
+
+// verifyTolerance is how far a recomputed final score may differ from the
+// stored one and still match. Recommendations store scores to four decimal
+// places.
+const verifyTolerance = 1e-4
+
+// maxReportedDrift caps how many drifting sites a Verification lists; the
+// counts always cover every site.
+const maxReportedDrift = 100
+
+// SiteDrift describes a site whose recomputed result differs from the stored
+// recommendation. A nil score means the site is missing on that side.
+type SiteDrift struct {
+	SiteID            string   `json:"site_id"`
+	StoredScore       *float64 `json:"stored_score"`
+	RecomputedScore   *float64 `json:"recomputed_score"`
+	StoredRanking     int      `json:"stored_ranking,omitempty"`
+	RecomputedRanking int      `json:"recomputed_ranking,omitempty"`
+}
+
+// Verification reports whether re-scoring a run from its schema snapshot
+// reproduces the stored recommendations.
+type Verification struct {
+	RunID        uuid.UUID   `json:"run_id"`
+	SnapshotID   uuid.UUID   `json:"snapshot_id"`
+	ModelVersion string      `json:"model_version"`
+	Scorer       string      `json:"scorer"`
+	SiteCount    int         `json:"site_count"`
+	Matched      int         `json:"matched"`
+	Mismatched   int         `json:"mismatched"`
+	Reproducible bool        `json:"reproducible"`
+	Drift        []SiteDrift `json:"drift"`
+}
+
+// Verify re-scores the run's site records against the resolved schema
+// recorded in snapshot, with the run's scorer, and compares the results to
+// stored. A site matches when both sides have it with the same ranking and
+// final scores within verifyTolerance. Drift is listed by site ID.
+func (p *Pipeline) Verify(
+	ctx context.Context,
+	run *models.ScoringRun,
+	snapshot *models.SchemaConfigSnapshot,
+	stored []models.Recommendation,
+) (*Verification, error) {
+	logger := runLogger(run).With(slog.String("step", "verify"))
+
+	var resolvedSchema schema.ResolvedSchema
+	if err := json.Unmarshal(snapshot.SnapshotData, &resolvedSchema); err != nil {
+		return nil, fmt.Errorf("parse snapshot %s: %w", snapshot.ID, err)
+	}
+
+	model, err := p.models.Lookup(run.ModelVersion)
+	if err != nil {
+		return nil, err
+	}
+	scorerName, scoreFunc, err := p.resolveScorer(run, model)
+	if err != nil {
+		return nil, err
+	}
+
+	siteRecords, err := p.siteRecordRepo.GetByUpload(ctx, run.UploadID)
+	if err != nil {
+		return nil, fmt.Errorf("fetch site records: %w", err)
+	}
+
+	parsed := parseSites(siteRecords, &resolvedSchema, nil, logger)
+	results, err := scoreSites(ctx, parsed, &resolvedSchema, scoreFunc, nil, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	recomputed := rankScores(results, &resolvedSchema)
+
+	v := &Verification{
+		RunID:        run.ID,
+		SnapshotID:   snapshot.ID,
+		ModelVersion: model.Version,
+		Scorer:       scorerName,
+		Drift:        []SiteDrift{},
+	}
+	compareRecommendations(v, stored, recomputed)
+
+	logger.Info("run verified",
+		slog.Int("matched", v.Matched),
+		slog.Int("mismatched", v.Mismatched))
+	return v, nil
+}
+
+// compareRecommendations fills v's counts and drift from stored and
+// recomputed results, keyed by site ID.
+func compareRecommendations(v *Verification, stored []models.Recommendation, recomputed []scoredSite) {
+	type pair struct {
+		stored, recomputed *models.Recommendation
+	}
+	bySite := make(map[string]*pair, len(stored))
+	get := func(siteID string) *pair {
+		if bySite[siteID] == nil {
+			bySite[siteID] = &pair{}
+		}
+		return bySite[siteID]
+	}
+	for i := range stored {
+		get(stored[i].SiteID).stored = &stored[i]
+	}
+	for i := range recomputed {
+		get(recomputed[i].rec.SiteID).recomputed = &recomputed[i].rec
+	}
+
+	siteIDs := make([]string, 0, len(bySite))
+	for id := range bySite {
+		siteIDs = append(siteIDs, id)
+	}
+	sort.Strings(siteIDs)
+
+	for _, id := range siteIDs {
+		pr := bySite[id]
+		if pr.stored != nil && pr.recomputed != nil &&
+			pr.stored.Ranking == pr.recomputed.Ranking &&
+			math.Abs(pr.stored.FinalScore-pr.recomputed.FinalScore) <= verifyTolerance {
+			v.Matched++
+			continue
+		}
+
+		v.Mismatched++
+		if len(v.Drift) >= maxReportedDrift {
+			continue
+		}
+		drift := SiteDrift{SiteID: id}
+		if pr.stored != nil {
+			drift.StoredScore = &pr.stored.FinalScore
+			drift.StoredRanking = pr.stored.Ranking
+		}
+		if pr.recomputed != nil {
+			drift.RecomputedScore = &pr.recomputed.FinalScore
+			drift.RecomputedRanking = pr.recomputed.Ranking
+		}
+		v.Drift = append(v.Drift, drift)
+	}
+
+	v.SiteCount = len(siteIDs)
+	v.Reproducible = v.Mismatched == 0
+}
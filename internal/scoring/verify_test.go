@@ -0,0 +1,92 @@
+package scoring
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/internal/models"
+	"github.com/workforce-ai/site-selection-iq/internal/schema"
+)
+
+func verifyTestRecords() []models.SiteRecord {
+	return []models.SiteRecord{
+		testSiteRecord("A", 800, 5),
+		testSiteRecord("B", 200, 40),
+		testSiteRecord("C", 500, 20),
+		testSiteRecord("D", 900, 60),
+	}
+}
+
+func TestPipelineVerify_ReproducesStoredScores(t *testing.T) {
+	p, fakes := newTestPipeline(verifyTestRecords())
+	run := testRun()
+	require.NoError(t, p.Execute(context.Background(), run))
+	require.Len(t, fakes.configs.snapshots, 1)
+
+	v, err := p.Verify(context.Background(), run, fakes.configs.snapshots[0], fakes.recs.inserted)
+	require.NoError(t, err)
+
+	assert.True(t, v.Reproducible)
+	assert.Equal(t, 4, v.SiteCount)
+	assert.Equal(t, 4, v.Matched)
+	assert.Zero(t, v.Mismatched)
+	assert.Empty(t, v.Drift)
+	assert.Equal(t, DefaultModelVersion, v.ModelVersion)
+	assert.Equal(t, DefaultScorer, v.Scorer)
+}
+
+func TestPipelineVerify_SchemaChangeSurfacesDrift(t *testing.T) {
+	p, fakes := newTestPipeline(verifyTestRecords())
+	run := testRun()
+	require.NoError(t, p.Execute(context.Background(), run))
+	require.Len(t, fakes.configs.snapshots, 1)
+
+	// Reweight population in the snapshot so recomputed scores diverge
+	snapshot := *fakes.configs.snapshots[0]
+	var resolved schema.ResolvedSchema
+	require.NoError(t, json.Unmarshal(snapshot.SnapshotData, &resolved))
+	resolved.Weights["population"] = 5
+	data, err := json.Marshal(resolved)
+	require.NoError(t, err)
+	snapshot.SnapshotData = data
+
+	v, err := p.Verify(context.Background(), run, &snapshot, fakes.recs.inserted)
+	require.NoError(t, err)
+
+	assert.False(t, v.Reproducible)
+	assert.Equal(t, 4, v.SiteCount)
+	assert.Positive(t, v.Mismatched)
+	assert.Equal(t, v.SiteCount, v.Matched+v.Mismatched)
+	require.Len(t, v.Drift, v.Mismatched)
+	for _, d := range v.Drift {
+		require.NotNil(t, d.StoredScore)
+		require.NotNil(t, d.RecomputedScore)
+	}
+}
+
+func TestPipelineVerify_MissingStoredRecommendation(t *testing.T) {
+	p, fakes := newTestPipeline(verifyTestRecords())
+	run := testRun()
+	require.NoError(t, p.Execute(context.Background(), run))
+
+	var stored []models.Recommendation
+	for _, rec := range fakes.recs.inserted {
+		if rec.SiteID != "B" {
+			stored = append(stored, rec)
+		}
+	}
+
+	v, err := p.Verify(context.Background(), run, fakes.configs.snapshots[0], stored)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, v.Matched)
+	assert.Equal(t, 1, v.Mismatched)
+	require.Len(t, v.Drift, 1)
+	assert.Equal(t, "B", v.Drift[0].SiteID)
+	assert.Nil(t, v.Drift[0].StoredScore)
+	assert.NotNil(t, v.Drift[0].RecomputedScore)
+}
@@ -0,0 +1,41 @@
+// Package storage keeps files outside the database, such as the original
+// bytes of uploaded CSVs.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// ErrNotFound is returned by BlobStore.Get for a key with no blob.
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore stores opaque blobs by key. Keys are slash-separated relative
+// paths such as "<tenant_id>/<upload_id>". LocalBlobStore keeps blobs on
+// local disk; S3BlobStore keeps them in an S3-compatible bucket so every
+// instance of the service sees the same blobs. Implementations must be safe
+// for concurrent use.
+type BlobStore interface {
+	// Put stores everything read from r under key, replacing any blob
+	// already there. A failed Put leaves no partial blob behind.
+	Put(ctx context.Context, key string, r io.Reader) error
+
+	// Get opens the blob stored under key. The caller must close it.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes the blob under key; deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// validateKey rejects keys that are empty, absolute or not in clean form,
+// so no key can name a blob outside its store.
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || key == ".." || strings.HasPrefix(key, "../") {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+	return nil
+}
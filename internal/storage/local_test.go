@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalBlobStore_RoundTrip(t *testing.T) {
+	store := NewLocalBlobStore(t.TempDir())
+	ctx := context.Background()
+
+	require.NoError(t, store.Put(ctx, "tenant/upload", strings.NewReader("site_id,population\nA,100\n")))
+
+	rc, err := store.Get(ctx, "tenant/upload")
+	require.NoError(t, err)
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+	assert.Equal(t, "site_id,population\nA,100\n", string(data))
+
+	// Put replaces an existing blob
+	require.NoError(t, store.Put(ctx, "tenant/upload", strings.NewReader("replaced")))
+	rc, err = store.Get(ctx, "tenant/upload")
+	require.NoError(t, err)
+	data, _ = io.ReadAll(rc)
+	rc.Close()
+	assert.Equal(t, "replaced", string(data))
+
+	require.NoError(t, store.Delete(ctx, "tenant/upload"))
+	_, err = store.Get(ctx, "tenant/upload")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, store.Delete(ctx, "tenant/upload"), "deleting a missing blob is not an error")
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("connection reset") }
+
+func TestLocalBlobStore_FailedPutLeavesNoBlob(t *testing.T) {
+	store := NewLocalBlobStore(t.TempDir())
+	ctx := context.Background()
+
+	err := store.Put(ctx, "tenant/upload", io.MultiReader(strings.NewReader("partial"), failingReader{}))
+	assert.ErrorContains(t, err, "connection reset")
+
+	_, err = store.Get(ctx, "tenant/upload")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestLocalBlobStore_RejectsEscapingKeys(t *testing.T) {
+	store := NewLocalBlobStore(t.TempDir())
+	ctx := context.Background()
+
+	for _, key := range []string{"", "/etc/passwd", "../outside", "a/../../outside", "a//b", ".."} {
+		assert.Error(t, store.Put(ctx, key, strings.NewReader("x")), key)
+		_, err := store.Get(ctx, key)
+		assert.Error(t, err, key)
+	}
+}
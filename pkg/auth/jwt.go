@@ -6,6 +6,8 @@ import (
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+
+	"github.com/workforce-ai/site-selection-iq/pkg/clock"
 )
 
 // Claims represents the JWT claims for the platform.
@@ -13,11 +15,71 @@ type Claims struct {
 	TenantID uuid.UUID `json:"tenant_id"`
 	UserID   uuid.UUID `json:"user_id"`
 	Role     string    `json:"role"`
+	// Scopes narrow what the token may do beyond its role. Empty means
+	// the role alone decides.
+	Scopes []string `json:"scopes,omitempty"`
+	// ServiceAccount marks a long-lived token minted for an integration.
+	// Its jti must be checked against the revocation list on every use.
+	ServiceAccount bool `json:"service_account,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// TokenOption adjusts the claims of a generated token.
+type TokenOption func(*Claims)
+
+// ForAudience sets the token's aud claim. An empty audience leaves it unset.
+func ForAudience(audience string) TokenOption {
+	return func(c *Claims) {
+		if audience != "" {
+			c.Audience = jwt.ClaimStrings{audience}
+		}
+	}
+}
+
+// GrantScopes grants the token the given scopes, e.g. "runs:write".
+func GrantScopes(scopes ...string) TokenOption {
+	return func(c *Claims) {
+		if len(scopes) > 0 {
+			c.Scopes = scopes
+		}
+	}
+}
+
+// AsServiceAccount marks the token as a service account token whose jti
+// is id, so it can be looked up and revoked.
+func AsServiceAccount(id uuid.UUID) TokenOption {
+	return func(c *Claims) {
+		c.ServiceAccount = true
+		c.ID = id.String()
+	}
+}
+
+// IssuedAt sets the token's iat and nbf claims to t instead of the time it
+// is generated. The expiry still counts from the generation time unless
+// ExpiresAt is also given.
+func IssuedAt(t time.Time) TokenOption {
+	return func(c *Claims) {
+		c.IssuedAt = jwt.NewNumericDate(t)
+		c.NotBefore = jwt.NewNumericDate(t)
+	}
+}
+
+// ExpiresAt sets the token's exp claim to t, overriding expiryHours, for
+// tokens whose lifetime is not a whole number of hours.
+func ExpiresAt(t time.Time) TokenOption {
+	return func(c *Claims) {
+		c.ExpiresAt = jwt.NewNumericDate(t)
+	}
+}
+
 // GenerateToken creates a signed JWT for the given tenant, user, and role.
-func GenerateToken(secret, issuer string, tenantID, userID uuid.UUID, role string, expiryHours int) (string, error) {
+func GenerateToken(secret, issuer string, tenantID, userID uuid.UUID, role string, expiryHours int, opts ...TokenOption) (string, error) {
+	return GenerateTokenWithClock(clock.Real{}, secret, issuer, tenantID, userID, role, expiryHours, opts...)
+}
+
+// GenerateTokenWithClock is GenerateToken with the issue time taken from clk.
+func GenerateTokenWithClock(clk clock.Clock, secret, issuer string, tenantID, userID uuid.UUID, role string, expiryHours int, opts ...TokenOption) (string, error) {
+	now := clk.Now()
 	claims := Claims{
 		TenantID: tenantID,
 		UserID:   userID,
@@ -25,25 +87,48 @@ func GenerateToken(secret, issuer string, tenantID, userID uuid.UUID, role strin
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    issuer,
 			Subject:   userID.String(),
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(expiryHours) * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Duration(expiryHours) * time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 			ID:        uuid.New().String(),
 		},
 	}
+	for _, opt := range opts {
+		opt(&claims)
+	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(secret))
 }
 
+// WithAudience requires a validated token's aud claim to include audience,
+// so tokens minted for other services sharing the secret are rejected. An
+// empty audience disables the check.
+func WithAudience(audience string) jwt.ParserOption {
+	if audience == "" {
+		return func(*jwt.Parser) {}
+	}
+	return jwt.WithAudience(audience)
+}
+
+// WithIssuer requires a validated token's iss claim to equal issuer. An empty
+// issuer disables the check.
+func WithIssuer(issuer string) jwt.ParserOption {
+	if issuer == "" {
+		return func(*jwt.Parser) {}
+	}
+	return jwt.WithIssuer(issuer)
+}
+
 // ValidateToken parses and validates a JWT, returning the claims.
-func ValidateToken(tokenString, secret string) (*Claims, error) {
+// opts add checks such as WithIssuer and WithAudience.
+func ValidateToken(tokenString, secret string, opts ...jwt.ParserOption) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
 		return []byte(secret), nil
-	})
+	}, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("parse token: %w", err)
 	}
@@ -8,6 +8,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/workforce-ai/site-selection-iq/pkg/clock"
 )
 
 func TestGenerateAndValidate(t *testing.T) {
@@ -197,6 +199,49 @@ func TestGenerateToken_DifferentExpiries(t *testing.T) {
 	}
 }
 
+func TestGenerateTokenWithClock_ExactExpiry(t *testing.T) {
+	secret := "test-secret-key-12345"
+	issued := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(issued)
+
+	tokenString, err := GenerateTokenWithClock(fake, secret, "test-issuer", uuid.New(), uuid.New(), "admin", 24)
+	require.NoError(t, err)
+
+	// Parse relative to the fake clock so the token is not yet expired
+	claims := &Claims{}
+	_, err = jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(secret), nil
+	}, jwt.WithTimeFunc(fake.Now))
+	require.NoError(t, err)
+
+	assert.True(t, issued.Equal(claims.IssuedAt.Time))
+	assert.True(t, issued.Equal(claims.NotBefore.Time))
+	assert.True(t, issued.Add(24*time.Hour).Equal(claims.ExpiresAt.Time))
+
+	// The same token is rejected once the fake clock passes expiry
+	fake.Advance(24*time.Hour + time.Second)
+	_, err = jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+		return []byte(secret), nil
+	}, jwt.WithTimeFunc(fake.Now))
+	assert.ErrorIs(t, err, jwt.ErrTokenExpired)
+}
+
+func TestGenerateToken_IssuedAtAndExpiresAt(t *testing.T) {
+	secret := "test-secret-key-12345"
+	issued := time.Now().Add(-time.Hour).Truncate(time.Second)
+	expires := issued.Add(36*time.Hour + 20*time.Minute)
+
+	tokenString, err := GenerateToken(secret, "test-issuer", uuid.New(), uuid.New(), "admin", 1,
+		IssuedAt(issued), ExpiresAt(expires))
+	require.NoError(t, err)
+
+	claims, err := ValidateToken(tokenString, secret)
+	require.NoError(t, err)
+	assert.True(t, issued.Equal(claims.IssuedAt.Time))
+	assert.True(t, issued.Equal(claims.NotBefore.Time))
+	assert.True(t, expires.Equal(claims.ExpiresAt.Time), "ExpiresAt overrides expiryHours")
+}
+
 func TestGenerateToken_ClaimsStructure(t *testing.T) {
 	// Test that generated token has correct claims structure
 	secret := "test-secret-key-12345"
@@ -353,3 +398,69 @@ func TestValidateToken_SigningMethodValidation(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, tenantID, validatedClaims.TenantID)
 }
+
+func TestValidateToken_Audience(t *testing.T) {
+	secret := "test-secret-key-12345"
+	tenantID := uuid.New()
+	userID := uuid.New()
+
+	scoped, err := GenerateToken(secret, "test-issuer", tenantID, userID, "admin", 1, ForAudience("site-selection-iq"))
+	require.NoError(t, err)
+	unscoped, err := GenerateToken(secret, "test-issuer", tenantID, userID, "admin", 1)
+	require.NoError(t, err)
+
+	t.Run("matching audience", func(t *testing.T) {
+		claims, err := ValidateToken(scoped, secret, WithAudience("site-selection-iq"))
+		require.NoError(t, err)
+		assert.Equal(t, jwt.ClaimStrings{"site-selection-iq"}, claims.Audience)
+	})
+
+	t.Run("mismatched audience", func(t *testing.T) {
+		_, err := ValidateToken(scoped, secret, WithAudience("other-service"))
+		assert.Error(t, err)
+	})
+
+	t.Run("missing audience when required", func(t *testing.T) {
+		_, err := ValidateToken(unscoped, secret, WithAudience("site-selection-iq"))
+		assert.Error(t, err)
+	})
+
+	t.Run("no audience configured accepts any token", func(t *testing.T) {
+		_, err := ValidateToken(scoped, secret, WithAudience(""))
+		assert.NoError(t, err)
+		_, err = ValidateToken(unscoped, secret)
+		assert.NoError(t, err)
+	})
+}
+
+func TestValidateToken_Issuer(t *testing.T) {
+	secret := "test-secret-key-12345"
+	tokenString, err := GenerateToken(secret, "other-issuer", uuid.New(), uuid.New(), "admin", 1)
+	require.NoError(t, err)
+
+	_, err = ValidateToken(tokenString, secret, WithIssuer("workforce-ai"))
+	assert.Error(t, err, "token from a different issuer must be rejected")
+
+	claims, err := ValidateToken(tokenString, secret, WithIssuer("other-issuer"))
+	require.NoError(t, err)
+	assert.Equal(t, "other-issuer", claims.Issuer)
+
+	_, err = ValidateToken(tokenString, secret, WithIssuer(""))
+	assert.NoError(t, err, "no configured issuer skips the check")
+}
+
+func TestGenerateToken_Scopes(t *testing.T) {
+	secret := "test-secret-key-12345"
+
+	scoped, err := GenerateToken(secret, "test-issuer", uuid.New(), uuid.New(), "analyst", 1, GrantScopes("runs:write"))
+	require.NoError(t, err)
+	claims, err := ValidateToken(scoped, secret)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"runs:write"}, claims.Scopes)
+
+	plain, err := GenerateToken(secret, "test-issuer", uuid.New(), uuid.New(), "analyst", 1)
+	require.NoError(t, err)
+	claims, err = ValidateToken(plain, secret)
+	require.NoError(t, err)
+	assert.Empty(t, claims.Scopes)
+}
@@ -0,0 +1,28 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFake_AdvanceAndSet(t *testing.T) {
+	start := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	c := NewFake(start)
+
+	assert.Equal(t, start, c.Now())
+
+	c.Advance(90 * time.Second)
+	assert.Equal(t, start.Add(90*time.Second), c.Now())
+
+	later := start.Add(24 * time.Hour)
+	c.Set(later)
+	assert.Equal(t, later, c.Now())
+}
+
+func TestReal_Now(t *testing.T) {
+	before := time.Now()
+	got := Real{}.Now()
+	assert.False(t, got.Before(before))
+}
@@ -0,0 +1,111 @@
+// Package timing accumulates per-request time spent in named phases (database,
+// scoring) so it can be reported in a Server-Timing response header.
+package timing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Well-known span names.
+const (
+	SpanDB      = "db"
+	SpanScoring = "scoring"
+	SpanTotal   = "total"
+)
+
+type contextKey struct{}
+
+// Recorder sums durations by span name. It is safe for concurrent use.
+type Recorder struct {
+	mu    sync.Mutex
+	order []string
+	spans map[string]*span
+}
+
+type span struct {
+	dur   time.Duration
+	count int
+}
+
+// NewContext returns ctx carrying a new Recorder, and the Recorder.
+func NewContext(ctx context.Context) (context.Context, *Recorder) {
+	rec := &Recorder{spans: make(map[string]*span)}
+	return context.WithValue(ctx, contextKey{}, rec), rec
+}
+
+// FromContext returns the Recorder carried by ctx, or nil if there is none.
+func FromContext(ctx context.Context) *Recorder {
+	rec, _ := ctx.Value(contextKey{}).(*Recorder)
+	return rec
+}
+
+// Add records d against the named span of the Recorder in ctx. It does nothing
+// when ctx carries no Recorder, so callers need not check.
+func Add(ctx context.Context, name string, d time.Duration) {
+	if rec := FromContext(ctx); rec != nil {
+		rec.Add(name, d)
+	}
+}
+
+// Track starts timing the named span and returns a func that stops it.
+// Typical use is defer timing.Track(ctx, timing.SpanScoring)().
+func Track(ctx context.Context, name string) func() {
+	rec := FromContext(ctx)
+	if rec == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() { rec.Add(name, time.Since(start)) }
+}
+
+// Add records d against the named span.
+func (r *Recorder) Add(name string, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.spans[name]
+	if !ok {
+		s = &span{}
+		r.spans[name] = s
+		r.order = append(r.order, name)
+	}
+	s.dur += d
+	s.count++
+}
+
+// Duration returns the total time recorded against the named span.
+func (r *Recorder) Duration(name string) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s, ok := r.spans[name]; ok {
+		return s.dur
+	}
+	return 0
+}
+
+// Header formats the recorded spans, followed by total, as a Server-Timing
+// header value, e.g. `db;dur=4.21;desc="3 calls", total;dur=9.87`.
+// Durations are in milliseconds.
+func (r *Recorder) Header(total time.Duration) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	metrics := make([]string, 0, len(r.order)+1)
+	for _, name := range r.order {
+		s := r.spans[name]
+		calls := "calls"
+		if s.count == 1 {
+			calls = "call"
+		}
+		metrics = append(metrics, fmt.Sprintf(`%s;dur=%s;desc="%d %s"`, name, millis(s.dur), s.count, calls))
+	}
+	metrics = append(metrics, SpanTotal+";dur="+millis(total))
+	return strings.Join(metrics, ", ")
+}
+
+func millis(d time.Duration) string {
+	return fmt.Sprintf("%.2f", float64(d)/float64(time.Millisecond))
+}
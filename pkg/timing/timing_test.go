@@ -0,0 +1,56 @@
+package timing
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder_HeaderSumsSpansInOrder(t *testing.T) {
+	ctx, rec := NewContext(context.Background())
+
+	Add(ctx, SpanScoring, 2*time.Millisecond)
+	Add(ctx, SpanDB, 1500*time.Microsecond)
+	Add(ctx, SpanDB, 500*time.Microsecond)
+
+	assert.Equal(t, 2*time.Millisecond, rec.Duration(SpanDB))
+	assert.Equal(t,
+		`scoring;dur=2.00;desc="1 call", db;dur=2.00;desc="2 calls", total;dur=10.50`,
+		rec.Header(10500*time.Microsecond))
+}
+
+func TestRecorder_HeaderWithNoSpans(t *testing.T) {
+	_, rec := NewContext(context.Background())
+	assert.Equal(t, "total;dur=1.00", rec.Header(time.Millisecond))
+}
+
+func TestAddAndTrack_WithoutRecorderAreNoops(t *testing.T) {
+	ctx := context.Background()
+	assert.Nil(t, FromContext(ctx))
+	assert.NotPanics(t, func() {
+		Add(ctx, SpanDB, time.Second)
+		Track(ctx, SpanScoring)()
+	})
+}
+
+func TestTrack_ConcurrentUse(t *testing.T) {
+	ctx, rec := NewContext(context.Background())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stop := Track(ctx, SpanDB)
+			time.Sleep(time.Millisecond)
+			stop()
+		}()
+	}
+	wg.Wait()
+
+	assert.GreaterOrEqual(t, rec.Duration(SpanDB), 10*time.Millisecond)
+	assert.Contains(t, rec.Header(0), `desc="10 calls"`)
+}